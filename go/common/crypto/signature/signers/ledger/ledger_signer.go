@@ -63,8 +63,11 @@ func NewFactory(config interface{}, roles ...signature.SignerRole) signature.Sig
 }
 
 // EnsureRole ensures that the SignatureFactory is configured for the given
-// role.
+// role and that the role is supported by the Ledger backed signer.
 func (fac *Factory) EnsureRole(role signature.SignerRole) error {
+	if _, ok := roleDerivationRootPaths[role]; !ok {
+		return signature.ErrRoleMismatch
+	}
 	for _, v := range fac.roles {
 		if v == role {
 			return nil
@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"sync"
 
@@ -79,18 +80,10 @@ func (c *DynamicRuntimePolicyChecker) CheckAccessAllowed(
 	c.RLock()
 	defer c.RUnlock()
 
-	peer, ok := peer.FromContext(ctx)
-	if !ok {
-		return errors.New("grpc: failed to obtain connection peer from context")
-	}
-	tlsAuth, ok := peer.AuthInfo.(credentials.TLSInfo)
-	if !ok {
-		return errors.New("grpc: unexpected peer authentication credentials")
+	peerCert, err := PeerCertificateFromContext(ctx)
+	if err != nil {
+		return err
 	}
-	if nPeerCerts := len(tlsAuth.State.PeerCertificates); nPeerCerts != 1 {
-		return fmt.Errorf("grpc: unexpected number of peer certificates: %d", nPeerCerts)
-	}
-	peerCert := tlsAuth.State.PeerCertificates[0]
 	subject := accessctl.SubjectFromX509Certificate(peerCert)
 	policy := c.accessPolicies[runtimeID]
 	if policy == nil || !policy.IsAllowed(subject, method) {
@@ -103,6 +96,23 @@ func (c *DynamicRuntimePolicyChecker) CheckAccessAllowed(
 	return nil
 }
 
+// PeerCertificateFromContext returns the TLS certificate of the connected
+// peer.
+func PeerCertificateFromContext(ctx context.Context) (*x509.Certificate, error) {
+	peer, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("grpc: failed to obtain connection peer from context")
+	}
+	tlsAuth, ok := peer.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, errors.New("grpc: unexpected peer authentication credentials")
+	}
+	if nPeerCerts := len(tlsAuth.State.PeerCertificates); nPeerCerts != 1 {
+		return nil, fmt.Errorf("grpc: unexpected number of peer certificates: %d", nPeerCerts)
+	}
+	return tlsAuth.State.PeerCertificates[0], nil
+}
+
 // NewDynamicRuntimePolicyChecker creates a new dynamic runtime policy checker instance.
 func NewDynamicRuntimePolicyChecker() *DynamicRuntimePolicyChecker {
 	return &DynamicRuntimePolicyChecker{
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultRetryMaxAttempts     = 5
+	defaultRetryInitialInterval = 100 * time.Millisecond
+	defaultRetryMaxInterval     = 5 * time.Second
+)
+
+// RetryConfig is the client retry interceptor configuration.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. If zero, a default value is used.
+	MaxAttempts uint64
+	// InitialInterval is the initial interval between attempts, which grows
+	// exponentially. If zero, a default value is used.
+	InitialInterval time.Duration
+	// MaxInterval is the maximum interval between attempts. If zero, a
+	// default value is used.
+	MaxInterval time.Duration
+}
+
+// isRetryableCode returns true iff the given status code indicates a
+// transient failure.
+func isRetryableCode(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewClientRetryInterceptor creates a new client unary interceptor that
+// retries calls to idempotent methods which fail with a transient error,
+// using exponential backoff.
+//
+// Calls to methods which are not registered or are not marked as idempotent
+// are never retried.
+func NewClientRetryInterceptor(cfg *RetryConfig) grpc.UnaryClientInterceptor {
+	var rc RetryConfig
+	if cfg != nil {
+		rc = *cfg
+	}
+	if rc.MaxAttempts == 0 {
+		rc.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if rc.InitialInterval == 0 {
+		rc.InitialInterval = defaultRetryInitialInterval
+	}
+	if rc.MaxInterval == 0 {
+		rc.MaxInterval = defaultRetryMaxInterval
+	}
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, rsp interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if m, err := GetRegisteredMethod(method); err != nil || !m.IsIdempotent() {
+			return invoker(ctx, method, req, rsp, cc, opts...)
+		}
+
+		sched := backoff.NewExponentialBackOff()
+		sched.InitialInterval = rc.InitialInterval
+		sched.MaxInterval = rc.MaxInterval
+		sched.MaxElapsedTime = 0
+
+		var err error
+		_ = backoff.Retry(func() error {
+			err = invoker(ctx, method, req, rsp, cc, opts...)
+			switch {
+			case err == nil:
+				return nil
+			case isRetryableCode(status.Code(err)):
+				return err
+			default:
+				return backoff.Permanent(err)
+			}
+		}, backoff.WithContext(backoff.WithMaxRetries(sched, rc.MaxAttempts-1), ctx))
+		return err
+	}
+}
+
+// WithClientRetry returns a dial option which configures the client retry
+// interceptor with the given configuration.
+func WithClientRetry(cfg *RetryConfig) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(NewClientRetryInterceptor(cfg))
+}
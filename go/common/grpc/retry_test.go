@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientRetryInterceptor(t *testing.T) {
+	require := require.New(t)
+
+	sn := NewServiceName("RetryTest")
+	idempotent := sn.NewMethodName("Idempotent").WithIdempotent(true)
+	nonIdempotent := sn.NewMethodName("NonIdempotent")
+
+	interceptor := NewClientRetryInterceptor(&RetryConfig{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+	})
+
+	// failingInvoker fails with the given code for the first failures calls.
+	var calls int
+	failingInvoker := func(code codes.Code, failures int) grpc.UnaryInvoker {
+		calls = 0
+		return func(ctx context.Context, method string, req, rsp interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			if calls <= failures {
+				return status.Error(code, "failing")
+			}
+			return nil
+		}
+	}
+	ctx := context.Background()
+
+	// Idempotent methods should be retried on transient errors.
+	err := interceptor(ctx, idempotent.Full(), nil, nil, nil, failingInvoker(codes.Unavailable, 2))
+	require.NoError(err, "idempotent method should succeed after retries")
+	require.Equal(3, calls, "idempotent method should be retried")
+
+	err = interceptor(ctx, idempotent.Full(), nil, nil, nil, failingInvoker(codes.DeadlineExceeded, 1))
+	require.NoError(err, "idempotent method should succeed after retries")
+	require.Equal(2, calls, "idempotent method should be retried")
+
+	// Retries should stop after the maximum number of attempts.
+	err = interceptor(ctx, idempotent.Full(), nil, nil, nil, failingInvoker(codes.Unavailable, 10))
+	require.Equal(codes.Unavailable, status.Code(err), "last error should be returned")
+	require.Equal(3, calls, "idempotent method should be attempted at most MaxAttempts times")
+
+	// Idempotent methods should not be retried on other errors.
+	err = interceptor(ctx, idempotent.Full(), nil, nil, nil, failingInvoker(codes.InvalidArgument, 1))
+	require.Equal(codes.InvalidArgument, status.Code(err), "non-transient error should be returned")
+	require.Equal(1, calls, "non-transient errors should not be retried")
+
+	// Non-idempotent methods should never be retried.
+	err = interceptor(ctx, nonIdempotent.Full(), nil, nil, nil, failingInvoker(codes.Unavailable, 1))
+	require.Equal(codes.Unavailable, status.Code(err), "error should be returned")
+	require.Equal(1, calls, "non-idempotent method should not be retried")
+
+	// Unregistered methods should never be retried.
+	err = interceptor(ctx, "/"+string(sn)+"/Unregistered", nil, nil, nil, failingInvoker(codes.Unavailable, 1))
+	require.Equal(codes.Unavailable, status.Code(err), "error should be returned")
+	require.Equal(1, calls, "unregistered method should not be retried")
+}
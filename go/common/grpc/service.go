@@ -1,10 +1,16 @@
 package grpc
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
 
 // ServicePrefix is a prefix given to all gRPC services defined by oasis-core.
 const ServicePrefix = "oasis-core."
 
+var registeredMethods sync.Map
+
 // ServiceName is a gRPC service name.
 type ServiceName string
 
@@ -15,16 +21,39 @@ func NewServiceName(name string) ServiceName {
 
 // NewMethodName creates a new method name for the given service.
 func (sn ServiceName) NewMethodName(name string) *MethodName {
-	return &MethodName{
-		short: name,
-		full:  fmt.Sprintf("/%s/%s", sn, name),
+	mn := &MethodName{
+		service: sn,
+		short:   name,
+		full:    fmt.Sprintf("/%s/%s", sn, name),
 	}
+	registeredMethods.Store(mn.full, mn)
+	return mn
 }
 
 // MethodName is a gRPC method name.
 type MethodName struct {
-	short string
-	full  string
+	service ServiceName
+	short   string
+	full    string
+
+	idempotent bool
+}
+
+// WithIdempotent configures whether the method is idempotent, meaning that it is
+// safe for clients to automatically retry it.
+func (m *MethodName) WithIdempotent(idempotent bool) *MethodName {
+	m.idempotent = idempotent
+	return m
+}
+
+// IsIdempotent returns true iff the method is safe to retry.
+func (m *MethodName) IsIdempotent() bool {
+	return m.idempotent
+}
+
+// Service returns the name of the service the method belongs to.
+func (m *MethodName) Service() ServiceName {
+	return m.service
 }
 
 // Short returns the short method name.
@@ -36,3 +65,45 @@ func (m *MethodName) Short() string {
 func (m *MethodName) Full() string {
 	return m.full
 }
+
+// Info returns the method's description.
+func (m *MethodName) Info() MethodInfo {
+	return MethodInfo{
+		Service:    m.service,
+		Name:       m.full,
+		Idempotent: m.idempotent,
+	}
+}
+
+// MethodInfo is a serializable description of a registered method.
+type MethodInfo struct {
+	// Service is the name of the service the method belongs to.
+	Service ServiceName `json:"service"`
+	// Name is the full method name.
+	Name string `json:"name"`
+	// Idempotent is true iff the method is safe for clients to retry.
+	Idempotent bool `json:"idempotent"`
+}
+
+// GetRegisteredMethod returns a registered method given its full name.
+func GetRegisteredMethod(name string) (*MethodName, error) {
+	mn, ok := registeredMethods.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("grpc: method not registered: %s", name)
+	}
+	return mn.(*MethodName), nil
+}
+
+// EnumerateRegisteredMethods returns all registered methods, sorted by their
+// full method name.
+func EnumerateRegisteredMethods() []*MethodName {
+	var methods []*MethodName
+	registeredMethods.Range(func(key, value interface{}) bool {
+		methods = append(methods, value.(*MethodName))
+		return true
+	})
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].full < methods[j].full
+	})
+	return methods
+}
@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisteredMethods(t *testing.T) {
+	require := require.New(t)
+
+	sn := NewServiceName("ServiceTest")
+	m2 := sn.NewMethodName("MethodB").WithIdempotent(true)
+	m1 := sn.NewMethodName("MethodA")
+
+	m, err := GetRegisteredMethod(m1.Full())
+	require.NoError(err, "GetRegisteredMethod")
+	require.Equal(m1, m, "registered method should be returned")
+	require.False(m.IsIdempotent(), "method should not be idempotent")
+	require.Equal(sn, m.Service(), "method service should be set")
+	require.Equal(MethodInfo{Service: sn, Name: m1.Full()}, m.Info(), "method info should match")
+
+	m, err = GetRegisteredMethod(m2.Full())
+	require.NoError(err, "GetRegisteredMethod")
+	require.True(m.IsIdempotent(), "idempotency flag should be carried")
+
+	_, err = GetRegisteredMethod("/" + string(sn) + "/MethodC")
+	require.Error(err, "GetRegisteredMethod should fail for unknown methods")
+
+	methods := EnumerateRegisteredMethods()
+	var names []string
+	for _, m := range methods {
+		names = append(names, m.Full())
+	}
+	require.True(sort.StringsAreSorted(names), "methods should be sorted")
+	require.Contains(names, m1.Full())
+	require.Contains(names, m2.Full())
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/tls"
+	"fmt"
 	"path/filepath"
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
@@ -57,12 +58,17 @@ func doLoadOrGenerate(dataDir string, signerFactory signature.SignerFactory, sho
 	var signers []signature.Signer
 	for _, v := range []struct {
 		role  signature.SignerRole
+		name  string
 		pubFn string
 	}{
-		{signature.SignerNode, NodeKeyPubFilename},
-		{signature.SignerP2P, P2PKeyPubFilename},
-		{signature.SignerConsensus, ConsensusKeyPubFilename},
+		{signature.SignerNode, "node", NodeKeyPubFilename},
+		{signature.SignerP2P, "P2P", P2PKeyPubFilename},
+		{signature.SignerConsensus, "consensus", ConsensusKeyPubFilename},
 	} {
+		if err := signerFactory.EnsureRole(v.role); err != nil {
+			return nil, fmt.Errorf("identity: signer factory can't provide the %s key: %w", v.name, err)
+		}
+
 		signer, err := signerFactory.Load(v.role)
 		switch err {
 		case nil:
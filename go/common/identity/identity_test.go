@@ -1,6 +1,7 @@
 package identity
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -32,3 +33,16 @@ func TestLoadOrGenerate(t *testing.T) {
 	// TODO: Check that it always generates a fresh certificate once oasis-core#1541 is done.
 	require.EqualValues(t, identity.TLSCertificate, identity2.TLSCertificate)
 }
+
+func TestLoadOrGenerateMissingRole(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "oasis-identity-test_")
+	require.NoError(t, err, "create data dir")
+	defer os.RemoveAll(dataDir)
+
+	// A factory that can't provide all of the required key roles.
+	factory := fileSigner.NewFactory(dataDir, signature.SignerNode, signature.SignerP2P)
+
+	_, err = LoadOrGenerate(dataDir, factory)
+	require.Error(t, err, "LoadOrGenerate should fail without the consensus role")
+	require.True(t, errors.Is(err, signature.ErrRoleMismatch), "LoadOrGenerate should fail with a role mismatch")
+}
@@ -4,19 +4,23 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	beacon "github.com/oasislabs/oasis-core/go/beacon/api"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/errors"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	genesis "github.com/oasislabs/oasis-core/go/genesis/api"
 	keymanager "github.com/oasislabs/oasis-core/go/keymanager/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
 	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
 	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
@@ -32,16 +36,53 @@ const (
 	VotingPower = 1
 )
 
-// ErrNoCommittedBlocks is the error returned when there are no committed
-// blocks and as such no state can be queried.
-var ErrNoCommittedBlocks = errors.New(moduleName, 1, "consensus: no committed blocks")
+var (
+	// ErrNoCommittedBlocks is the error returned when there are no committed
+	// blocks and as such no state can be queried.
+	ErrNoCommittedBlocks = errors.New(moduleName, 1, "consensus: no committed blocks")
+
+	// ErrVersionNotFound is the error returned when the requested height is
+	// not available (e.g., because it has been pruned).
+	ErrVersionNotFound = errors.New(moduleName, 2, "consensus: version not found")
+
+	// ErrTransactionIndexDisabled is the error returned when looking up a
+	// transaction by hash on a node that does not index transactions.
+	ErrTransactionIndexDisabled = errors.New(moduleName, 3, "consensus: transaction indexing is disabled")
+
+	// ErrTransactionNotFound is the error returned when the requested
+	// transaction is not found.
+	ErrTransactionNotFound = errors.New(moduleName, 4, "consensus: transaction not found")
+
+	// ErrInvalidArgument is the error returned on malformed arguments.
+	ErrInvalidArgument = errors.New(moduleName, 5, "consensus: invalid argument")
+
+	// ErrForbidden is the error returned when the transaction signer is
+	// not authorized to perform an action.
+	ErrForbidden = errors.New(moduleName, 6, "consensus: forbidden")
+)
 
 // ClientBackend is a limited consensus interface used by clients that
 // connect to the local node.
 type ClientBackend interface {
-	// SubmitTx submits a signed consensus transaction.
+	// SubmitTx submits a signed consensus transaction and waits for it to
+	// be included in a block.
 	SubmitTx(ctx context.Context, tx *transaction.SignedTransaction) error
 
+	// SubmitTxNoWait submits a signed consensus transaction without waiting
+	// for it to be included in a block. It only waits for the transaction
+	// to be checked and accepted into the mempool and returns its hash.
+	//
+	// Callers that need to track inclusion should use WaitForTransaction.
+	SubmitTxNoWait(ctx context.Context, tx *transaction.SignedTransaction) (hash.Hash, error)
+
+	// WaitForTransaction waits for a transaction with the given hash to be
+	// included in a block and returns its result.
+	//
+	// Transactions included before the call is made are only found when
+	// the transaction index is enabled, otherwise callers should call this
+	// method before broadcasting the transaction.
+	WaitForTransaction(ctx context.Context, txHash hash.Hash) (*Result, error)
+
 	// StateToGenesis returns the genesis state at the specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error)
 
@@ -55,15 +96,90 @@ type ClientBackend interface {
 	// GetBlock returns a consensus block at a specific height.
 	GetBlock(ctx context.Context, height int64) (*Block, error)
 
+	// GetConsensusParameters returns the consensus parameters that are in
+	// effect at the specified block height.
+	GetConsensusParameters(ctx context.Context, height int64) (*Parameters, error)
+
+	// GetMinGasPrices returns the minimum gas prices accepted by the local
+	// node for transaction admission.
+	GetMinGasPrices(ctx context.Context) (*MinGasPrices, error)
+
+	// GetValidatorSet returns the consensus validator set that is in effect
+	// at the specified block height.
+	GetValidatorSet(ctx context.Context, height int64) (*ValidatorSet, error)
+
+	// GetSignerNonce returns the nonce that should be used by the given
+	// signer for transmitting the next transaction.
+	GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error)
+
+	// GetGenesisDigest returns the digest of the genesis state that the
+	// consensus backend has been initialized with.
+	GetGenesisDigest(ctx context.Context) ([]byte, error)
+
+	// GetGenesisDocument returns the genesis document that the consensus
+	// backend has been initialized with together with the digest of the
+	// resulting genesis state.
+	GetGenesisDocument(ctx context.Context) (*GenesisDocument, error)
+
 	// GetTransactions returns a list of all transactions contained within a
 	// consensus block at a specific height.
 	//
 	// NOTE: Any of these transactions could be invalid.
 	GetTransactions(ctx context.Context, height int64) ([][]byte, error)
 
+	// GetTransaction returns the transaction with the given hash together
+	// with its execution result.
+	//
+	// NOTE: This requires the node to index transactions and will otherwise
+	//       return ErrTransactionIndexDisabled.
+	GetTransaction(ctx context.Context, txHash hash.Hash) (*TransactionWithResult, error)
+
 	// WatchBlocks returns a channel that produces a stream of consensus
 	// blocks as they are being finalized.
 	WatchBlocks(ctx context.Context) (<-chan *Block, pubsub.ClosableSubscription, error)
+
+	// GetRuntimeStates returns the state of all registered runtimes
+	// (including suspended ones) together with their current head blocks
+	// at the specified block height.
+	GetRuntimeStates(ctx context.Context, height int64) ([]*RuntimeState, error)
+}
+
+// RuntimeState is the consensus view of a registered runtime.
+type RuntimeState struct {
+	// Runtime is the runtime descriptor.
+	Runtime *registry.Runtime `json:"runtime"`
+	// Suspended is true iff the runtime is suspended.
+	Suspended bool `json:"suspended,omitempty"`
+	// CurrentBlock is the runtime's current head block. It is nil for
+	// runtimes that are not compute runtimes (e.g., key managers).
+	CurrentBlock *block.Block `json:"current_block"`
+}
+
+// GetSignerNonceRequest is a GetSignerNonce request.
+type GetSignerNonceRequest struct {
+	ID     signature.PublicKey `json:"id"`
+	Height int64               `json:"height"`
+}
+
+// GenesisDocument is the genesis document that the consensus backend has
+// been initialized with.
+type GenesisDocument struct {
+	// Document is the genesis document.
+	Document *genesis.Document `json:"document"`
+	// Digest is the digest of the genesis state, as returned by
+	// GetGenesisDigest.
+	Digest []byte `json:"digest"`
+}
+
+// TransactionWithResult is a transaction that has been included in a block
+// together with its execution result.
+type TransactionWithResult struct {
+	// Transaction is the signed transaction.
+	Transaction transaction.SignedTransaction `json:"transaction"`
+	// Index is the index of the transaction within the block.
+	Index uint32 `json:"index"`
+	// Result is the transaction execution result.
+	Result Result `json:"result"`
 }
 
 // Block is a consensus block.
@@ -77,6 +193,89 @@ type Block struct {
 	Meta cbor.RawMessage `json:"meta"`
 }
 
+// Parameters are the consensus parameters in effect at a specific height.
+type Parameters struct {
+	// Height is the block height the parameters are valid at.
+	Height int64 `json:"height"`
+
+	// MaxTxSize is the maximum transaction size in bytes.
+	MaxTxSize uint64 `json:"max_tx_size"`
+	// MaxBlockSize is the maximum block size in bytes.
+	MaxBlockSize uint64 `json:"max_block_size"`
+	// MaxBlockGas is the maximum amount of gas that can be used by the
+	// transactions in a block.
+	MaxBlockGas transaction.Gas `json:"max_block_gas"`
+	// MaxTxPerBlock is the maximum number of transactions in a block.
+	MaxTxPerBlock uint64 `json:"max_tx_per_block"`
+
+	// GasCosts are the transaction gas costs, keyed by module name.
+	GasCosts map[string]transaction.Costs `json:"gas_costs"`
+}
+
+// MinGasPrices are the minimum gas prices accepted by a node.
+//
+// NOTE: This is local node configuration and not part of consensus.
+type MinGasPrices struct {
+	// MinGasPrice is the global minimum gas price.
+	MinGasPrice quantity.Quantity `json:"min_gas_price"`
+	// MinGasPricePerMethod are the per-method minimum gas prices,
+	// overriding MinGasPrice for the given methods.
+	MinGasPricePerMethod map[transaction.MethodName]quantity.Quantity `json:"min_gas_price_per_method,omitempty"`
+}
+
+// ValidatorSet is the consensus validator set at a specific height.
+type ValidatorSet struct {
+	// Height is the block height the validator set is valid at.
+	Height int64 `json:"height"`
+
+	// Validators are the validators in the set. Only active validators
+	// are part of the validator set.
+	Validators []*ValidatorInfo `json:"validators"`
+}
+
+// ValidatorInfo is information about a consensus validator.
+type ValidatorInfo struct {
+	// ID is the validator consensus (NOT oasis) identifier.
+	ID signature.PublicKey `json:"id"`
+	// Address is the validator's consensus address.
+	Address string `json:"address"`
+
+	// VotingPower is the validator's consensus voting power.
+	VotingPower int64 `json:"voting_power"`
+	// ProposerPriority is the validator's block proposer priority.
+	ProposerPriority int64 `json:"proposer_priority"`
+}
+
+// Result is the result of a transaction that has been included in a block.
+type Result struct {
+	// Height is the height of the block the transaction was included in.
+	Height int64 `json:"height"`
+	// Module is the module that emitted the error, if any.
+	Module string `json:"module,omitempty"`
+	// Code is the error code, where zero means success.
+	Code uint32 `json:"code,omitempty"`
+	// Log is the (non-deterministic) transaction log.
+	Log string `json:"log,omitempty"`
+}
+
+// IsSuccess returns true iff the transaction executed successfully.
+func (r *Result) IsSuccess() bool {
+	return r.Code == 0
+}
+
+// Error returns the transaction execution error or nil if the transaction
+// executed successfully.
+func (r *Result) Error() error {
+	if r.IsSuccess() {
+		return nil
+	}
+	if err := errors.FromCode(r.Module, r.Code); err != nil {
+		return err
+	}
+	// Fallback to an ordinary error.
+	return fmt.Errorf(r.Log)
+}
+
 // Backend is an interface that a consensus backend must provide.
 type Backend interface {
 	ClientBackend
@@ -137,6 +336,19 @@ type Backend interface {
 	Scheduler() scheduler.Backend
 }
 
+// MinGasPriceSetter is a Backend that supports changing the global minimum
+// gas price at runtime.
+//
+// The minimum gas price only applies to local transaction admission (e.g.,
+// mempool checks) and not to consensus validity, so nodes can change it
+// independently, e.g., to increase spam resistance.
+type MinGasPriceSetter interface {
+	Backend
+
+	// SetMinGasPrice sets the global minimum gas price.
+	SetMinGasPrice(ctx context.Context, price *quantity.Quantity) error
+}
+
 // TransactionAuthHandler is the interface for handling transaction authentication
 // (checking nonces and fees).
 type TransactionAuthHandler interface {
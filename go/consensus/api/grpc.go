@@ -5,6 +5,7 @@ import (
 
 	"google.golang.org/grpc"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
@@ -18,14 +19,34 @@ var (
 
 	// methodSubmitTx is the name of the SubmitTx method.
 	methodSubmitTx = serviceName.NewMethodName("SubmitTx")
+	// methodSubmitTxNoWait is the name of the SubmitTxNoWait method.
+	methodSubmitTxNoWait = serviceName.NewMethodName("SubmitTxNoWait")
+	// methodWaitForTransaction is the name of the WaitForTransaction method.
+	methodWaitForTransaction = serviceName.NewMethodName("WaitForTransaction")
 	// methodStateToGenesis is the name of the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethodName("StateToGenesis")
 	// methodWaitEpoch is the name of the WaitEpoch method.
 	methodWaitEpoch = serviceName.NewMethodName("WaitEpoch")
 	// methodGetBlock is the name of the GetBlock method.
 	methodGetBlock = serviceName.NewMethodName("GetBlock")
+	// methodGetConsensusParameters is the name of the GetConsensusParameters method.
+	methodGetConsensusParameters = serviceName.NewMethodName("GetConsensusParameters")
+	// methodGetMinGasPrices is the name of the GetMinGasPrices method.
+	methodGetMinGasPrices = serviceName.NewMethodName("GetMinGasPrices")
+	// methodGetValidatorSet is the name of the GetValidatorSet method.
+	methodGetValidatorSet = serviceName.NewMethodName("GetValidatorSet")
+	// methodGetRuntimeStates is the name of the GetRuntimeStates method.
+	methodGetRuntimeStates = serviceName.NewMethodName("GetRuntimeStates")
+	// methodGetSignerNonce is the name of the GetSignerNonce method.
+	methodGetSignerNonce = serviceName.NewMethodName("GetSignerNonce")
+	// methodGetGenesisDigest is the name of the GetGenesisDigest method.
+	methodGetGenesisDigest = serviceName.NewMethodName("GetGenesisDigest")
+	// methodGetGenesisDocument is the name of the GetGenesisDocument method.
+	methodGetGenesisDocument = serviceName.NewMethodName("GetGenesisDocument")
 	// methodGetTransactions is the name of the GetTransactions method.
 	methodGetTransactions = serviceName.NewMethodName("GetTransactions")
+	// methodGetTransaction is the name of the GetTransaction method.
+	methodGetTransaction = serviceName.NewMethodName("GetTransaction")
 
 	// methodWatchBlocks is the name of the WatchBlocks method.
 	methodWatchBlocks = serviceName.NewMethodName("WatchBlocks")
@@ -39,6 +60,14 @@ var (
 				MethodName: methodSubmitTx.Short(),
 				Handler:    handlerSubmitTx,
 			},
+			{
+				MethodName: methodSubmitTxNoWait.Short(),
+				Handler:    handlerSubmitTxNoWait,
+			},
+			{
+				MethodName: methodWaitForTransaction.Short(),
+				Handler:    handlerWaitForTransaction,
+			},
 			{
 				MethodName: methodStateToGenesis.Short(),
 				Handler:    handlerStateToGenesis,
@@ -51,10 +80,42 @@ var (
 				MethodName: methodGetBlock.Short(),
 				Handler:    handlerGetBlock,
 			},
+			{
+				MethodName: methodGetConsensusParameters.Short(),
+				Handler:    handlerGetConsensusParameters,
+			},
+			{
+				MethodName: methodGetMinGasPrices.Short(),
+				Handler:    handlerGetMinGasPrices,
+			},
+			{
+				MethodName: methodGetValidatorSet.Short(),
+				Handler:    handlerGetValidatorSet,
+			},
+			{
+				MethodName: methodGetRuntimeStates.Short(),
+				Handler:    handlerGetRuntimeStates,
+			},
+			{
+				MethodName: methodGetSignerNonce.Short(),
+				Handler:    handlerGetSignerNonce,
+			},
+			{
+				MethodName: methodGetGenesisDigest.Short(),
+				Handler:    handlerGetGenesisDigest,
+			},
+			{
+				MethodName: methodGetGenesisDocument.Short(),
+				Handler:    handlerGetGenesisDocument,
+			},
 			{
 				MethodName: methodGetTransactions.Short(),
 				Handler:    handlerGetTransactions,
 			},
+			{
+				MethodName: methodGetTransaction.Short(),
+				Handler:    handlerGetTransaction,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -89,6 +150,52 @@ func handlerSubmitTx( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerSubmitTxNoWait( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(transaction.SignedTransaction)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).SubmitTxNoWait(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSubmitTxNoWait.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).SubmitTxNoWait(ctx, req.(*transaction.SignedTransaction))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerWaitForTransaction( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var txHash hash.Hash
+	if err := dec(&txHash); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).WaitForTransaction(ctx, txHash)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodWaitForTransaction.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).WaitForTransaction(ctx, req.(hash.Hash))
+	}
+	return interceptor(ctx, txHash, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -158,6 +265,155 @@ func handlerGetBlock( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetConsensusParameters( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetConsensusParameters(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetConsensusParameters.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetConsensusParameters(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetMinGasPrices( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(Backend).GetMinGasPrices(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetMinGasPrices.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetMinGasPrices(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetValidatorSet( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetValidatorSet(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetValidatorSet.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetValidatorSet(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetRuntimeStates( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetRuntimeStates(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetRuntimeStates.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetRuntimeStates(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetSignerNonce( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(GetSignerNonceRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetSignerNonce(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetSignerNonce.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetSignerNonce(ctx, req.(*GetSignerNonceRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
+func handlerGetGenesisDigest( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(Backend).GetGenesisDigest(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetGenesisDigest.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetGenesisDigest(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerGetGenesisDocument( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(Backend).GetGenesisDocument(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetGenesisDocument.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetGenesisDocument(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 func handlerGetTransactions( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -181,6 +437,29 @@ func handlerGetTransactions( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetTransaction( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var txHash hash.Hash
+	if err := dec(&txHash); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetTransaction(ctx, txHash)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetTransaction.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetTransaction(ctx, req.(hash.Hash))
+	}
+	return interceptor(ctx, txHash, info, handler)
+}
+
 func handlerWatchBlocks(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -223,6 +502,22 @@ func (c *consensusClient) SubmitTx(ctx context.Context, tx *transaction.SignedTr
 	return c.conn.Invoke(ctx, methodSubmitTx.Full(), tx, nil)
 }
 
+func (c *consensusClient) SubmitTxNoWait(ctx context.Context, tx *transaction.SignedTransaction) (hash.Hash, error) {
+	var txHash hash.Hash
+	if err := c.conn.Invoke(ctx, methodSubmitTxNoWait.Full(), tx, &txHash); err != nil {
+		return hash.Hash{}, err
+	}
+	return txHash, nil
+}
+
+func (c *consensusClient) WaitForTransaction(ctx context.Context, txHash hash.Hash) (*Result, error) {
+	var rsp Result
+	if err := c.conn.Invoke(ctx, methodWaitForTransaction.Full(), txHash, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) StateToGenesis(ctx context.Context, height int64) (*genesis.Document, error) {
 	var rsp genesis.Document
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.Full(), height, &rsp); err != nil {
@@ -243,6 +538,62 @@ func (c *consensusClient) GetBlock(ctx context.Context, height int64) (*Block, e
 	return &rsp, nil
 }
 
+func (c *consensusClient) GetConsensusParameters(ctx context.Context, height int64) (*Parameters, error) {
+	var rsp Parameters
+	if err := c.conn.Invoke(ctx, methodGetConsensusParameters.Full(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) GetMinGasPrices(ctx context.Context) (*MinGasPrices, error) {
+	var rsp MinGasPrices
+	if err := c.conn.Invoke(ctx, methodGetMinGasPrices.Full(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) GetValidatorSet(ctx context.Context, height int64) (*ValidatorSet, error) {
+	var rsp ValidatorSet
+	if err := c.conn.Invoke(ctx, methodGetValidatorSet.Full(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
+func (c *consensusClient) GetRuntimeStates(ctx context.Context, height int64) ([]*RuntimeState, error) {
+	var rsp []*RuntimeState
+	if err := c.conn.Invoke(ctx, methodGetRuntimeStates.Full(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *consensusClient) GetSignerNonce(ctx context.Context, req *GetSignerNonceRequest) (uint64, error) {
+	var nonce uint64
+	if err := c.conn.Invoke(ctx, methodGetSignerNonce.Full(), req, &nonce); err != nil {
+		return 0, err
+	}
+	return nonce, nil
+}
+
+func (c *consensusClient) GetGenesisDigest(ctx context.Context) ([]byte, error) {
+	var rsp []byte
+	if err := c.conn.Invoke(ctx, methodGetGenesisDigest.Full(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *consensusClient) GetGenesisDocument(ctx context.Context) (*GenesisDocument, error) {
+	var rsp GenesisDocument
+	if err := c.conn.Invoke(ctx, methodGetGenesisDocument.Full(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) GetTransactions(ctx context.Context, height int64) ([][]byte, error) {
 	var rsp [][]byte
 	if err := c.conn.Invoke(ctx, methodGetTransactions.Full(), height, &rsp); err != nil {
@@ -251,6 +602,14 @@ func (c *consensusClient) GetTransactions(ctx context.Context, height int64) ([]
 	return rsp, nil
 }
 
+func (c *consensusClient) GetTransaction(ctx context.Context, txHash hash.Hash) (*TransactionWithResult, error) {
+	var rsp TransactionWithResult
+	if err := c.conn.Invoke(ctx, methodGetTransaction.Full(), txHash, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *consensusClient) WatchBlocks(ctx context.Context) (<-chan *Block, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
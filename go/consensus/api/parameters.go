@@ -0,0 +1,96 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	"github.com/oasislabs/oasis-core/go/consensus/genesis"
+)
+
+// MethodUpdateParameters is the method name for consensus parameter updates.
+var MethodUpdateParameters = transaction.NewMethodName(moduleName, "UpdateParameters", ParametersUpdate{})
+
+// ParametersUpdate is a consensus parameters update.
+//
+// It contains the new values of all consensus parameters which can be
+// updated on-chain.
+type ParametersUpdate struct {
+	// MaxTxSize is the maximum transaction size in bytes (0 disables the
+	// limit).
+	MaxTxSize uint64 `json:"max_tx_size"`
+	// MaxBlockSize is the maximum block size in bytes.
+	MaxBlockSize uint64 `json:"max_block_size"`
+	// MaxBlockGas is the maximum amount of gas that can be used by the
+	// transactions in a block (0 disables the limit).
+	MaxBlockGas uint64 `json:"max_block_gas"`
+	// MaxTxPerBlock is the maximum number of transactions in a block
+	// (0 disables the limit).
+	MaxTxPerBlock uint64 `json:"max_tx_per_block"`
+	// MaxEvidenceAge is the maximum age of evidence in blocks.
+	MaxEvidenceAge uint64 `json:"max_evidence_age"`
+}
+
+// SanityCheck performs a basic sanity check on the parameters update.
+func (u *ParametersUpdate) SanityCheck() error {
+	if u.MaxBlockSize == 0 {
+		return fmt.Errorf("%w: maximum block size must be non-zero", ErrInvalidArgument)
+	}
+	if u.MaxTxSize > u.MaxBlockSize {
+		return fmt.Errorf("%w: maximum transaction size exceeds maximum block size", ErrInvalidArgument)
+	}
+	if u.MaxEvidenceAge == 0 {
+		return fmt.Errorf("%w: maximum evidence age must be non-zero", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// Apply returns a copy of the given consensus parameters with the update
+// applied.
+func (u *ParametersUpdate) Apply(params *genesis.Parameters) *genesis.Parameters {
+	updated := *params
+	updated.MaxTxSize = u.MaxTxSize
+	updated.MaxBlockSize = u.MaxBlockSize
+	updated.MaxBlockGas = u.MaxBlockGas
+	updated.MaxTxPerBlock = u.MaxTxPerBlock
+	updated.MaxEvidenceAge = u.MaxEvidenceAge
+	return &updated
+}
+
+// NewParametersUpdate returns the current values of all consensus parameters
+// which can be updated on-chain.
+func NewParametersUpdate(params *genesis.Parameters) *ParametersUpdate {
+	return &ParametersUpdate{
+		MaxTxSize:      params.MaxTxSize,
+		MaxBlockSize:   params.MaxBlockSize,
+		MaxBlockGas:    params.MaxBlockGas,
+		MaxTxPerBlock:  params.MaxTxPerBlock,
+		MaxEvidenceAge: params.MaxEvidenceAge,
+	}
+}
+
+// IsUpdateAuthority returns true iff the given public key is allowed to
+// update the consensus parameters.
+func IsUpdateAuthority(params *genesis.Parameters, id signature.PublicKey) bool {
+	for _, v := range params.UpdateAuthorities {
+		if v.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParametersUpdateEvent is the event emitted when the consensus parameters
+// are updated. The update takes effect in the next block.
+type ParametersUpdateEvent struct {
+	// Old are the consensus parameters before the update.
+	Old ParametersUpdate `json:"old"`
+	// New are the consensus parameters after the update.
+	New ParametersUpdate `json:"new"`
+}
+
+// NewUpdateParametersTx creates a new consensus parameters update
+// transaction.
+func NewUpdateParametersTx(nonce uint64, fee *transaction.Fee, update *ParametersUpdate) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodUpdateParameters, update)
+}
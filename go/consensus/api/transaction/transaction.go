@@ -21,6 +21,10 @@ var (
 	// ErrInvalidNonce is the error returned when a nonce is invalid.
 	ErrInvalidNonce = errors.New(moduleName, 1, "transaction: invalid nonce")
 
+	// ErrUnsupportedSignatureScheme is the error returned when a signed
+	// transaction uses an unknown signature scheme.
+	ErrUnsupportedSignatureScheme = errors.New(moduleName, 4, "transaction: unsupported signature scheme")
+
 	// SignatureContext is the context used for signing transactions.
 	SignatureContext = signature.NewContext("oasis-core/consensus: tx", signature.WithChainSeparation())
 
@@ -105,9 +109,42 @@ func NewTransaction(nonce uint64, fee *Fee, method MethodName, body interface{})
 	}
 }
 
+// SignatureScheme is the scheme used to sign a transaction.
+type SignatureScheme uint8
+
+const (
+	// SignatureSchemeDefault is the default signature scheme where the
+	// transaction is signed over SignatureContext.
+	SignatureSchemeDefault SignatureScheme = 0
+)
+
+// String returns a string representation of the signature scheme.
+func (s SignatureScheme) String() string {
+	switch s {
+	case SignatureSchemeDefault:
+		return "default"
+	default:
+		return fmt.Sprintf("[unknown: %d]", uint8(s))
+	}
+}
+
+// IsSupported returns true iff the signature scheme is supported.
+func (s SignatureScheme) IsSupported() bool {
+	switch s {
+	case SignatureSchemeDefault:
+		return true
+	default:
+		return false
+	}
+}
+
 // SignedTransaction is a signed transaction.
 type SignedTransaction struct {
 	signature.Signed
+
+	// Scheme is the signature scheme. It is omitted for the default scheme
+	// so that the encoding of such transactions remains unchanged.
+	Scheme SignatureScheme `json:"scheme,omitempty"`
 }
 
 // PrettyPrint writes a pretty-printed representation of the type
@@ -115,9 +152,16 @@ type SignedTransaction struct {
 func (s SignedTransaction) PrettyPrint(prefix string, w io.Writer) {
 	fmt.Fprintf(w, "%sSigner: %s\n", prefix, s.Signature.PublicKey)
 	fmt.Fprintf(w, "%s        (signature: %s)\n", prefix, s.Signature.Signature)
+	if s.Scheme != SignatureSchemeDefault {
+		fmt.Fprintf(w, "%s        (scheme: %s)\n", prefix, s.Scheme)
+	}
 
 	// Check if signature is valid.
-	if !s.Signature.Verify(SignatureContext, s.Blob) {
+	switch err := s.verify(); err {
+	case nil:
+	case ErrUnsupportedSignatureScheme:
+		fmt.Fprintf(w, "%s        [UNSUPPORTED SIGNATURE SCHEME]\n", prefix)
+	default:
 		fmt.Fprintf(w, "%s        [INVALID SIGNATURE]\n", prefix)
 	}
 
@@ -134,9 +178,28 @@ func (s SignedTransaction) PrettyPrint(prefix string, w io.Writer) {
 	tx.PrettyPrint(prefix+"  ", w)
 }
 
-// Open first verifies the blob signature and then unmarshals the blob.
+// verify verifies the blob signature using the signature scheme of the
+// signed transaction.
+func (s *SignedTransaction) verify() error {
+	switch s.Scheme {
+	case SignatureSchemeDefault:
+		if !s.Signature.Verify(SignatureContext, s.Blob) {
+			return signature.ErrVerifyFailed
+		}
+		return nil
+	default:
+		return ErrUnsupportedSignatureScheme
+	}
+}
+
+// Open first verifies the blob signature using the signature scheme of the
+// signed transaction and then unmarshals the blob.
 func (s *SignedTransaction) Open(tx *Transaction) error { // nolint: interfacer
-	return s.Signed.Open(SignatureContext, tx)
+	if err := s.verify(); err != nil {
+		return err
+	}
+
+	return cbor.Unmarshal(s.Blob, tx)
 }
 
 // Sign signs a transaction.
@@ -4,6 +4,8 @@ package genesis
 import (
 	"fmt"
 	"time"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 )
 
 // Genesis contains various consensus config flags that should be part of the genesis state.
@@ -21,7 +23,13 @@ type Parameters struct {
 	MaxTxSize      uint64 `json:"max_tx_size"`
 	MaxBlockSize   uint64 `json:"max_block_size"`
 	MaxBlockGas    uint64 `json:"max_block_gas"`
+	MaxTxPerBlock  uint64 `json:"max_tx_per_block"`
 	MaxEvidenceAge uint64 `json:"max_evidence_age"`
+
+	// UpdateAuthorities are the public keys that are allowed to update the
+	// consensus parameters. If empty, consensus parameters can not be
+	// updated.
+	UpdateAuthorities []signature.PublicKey `json:"update_authorities,omitempty"`
 }
 
 // SanityCheck does basic sanity checking on the genesis state.
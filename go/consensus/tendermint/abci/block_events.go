@@ -0,0 +1,116 @@
+package abci
+
+import (
+	"strings"
+
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+)
+
+// EventPhase is the block processing phase during which an event was
+// emitted.
+type EventPhase uint8
+
+const (
+	// EventPhaseInitChain is the InitChain phase.
+	EventPhaseInitChain EventPhase = iota
+	// EventPhaseBeginBlock is the BeginBlock phase.
+	EventPhaseBeginBlock
+	// EventPhaseDeliverTx is the DeliverTx phase.
+	EventPhaseDeliverTx
+	// EventPhaseEndBlock is the EndBlock phase.
+	EventPhaseEndBlock
+)
+
+// String returns a string representation of the event phase.
+func (p EventPhase) String() string {
+	switch p {
+	case EventPhaseInitChain:
+		return "InitChain"
+	case EventPhaseBeginBlock:
+		return "BeginBlock"
+	case EventPhaseDeliverTx:
+		return "DeliverTx"
+	case EventPhaseEndBlock:
+		return "EndBlock"
+	default:
+		return "[unknown]"
+	}
+}
+
+// BlockEvent is an event emitted while processing a block.
+type BlockEvent struct {
+	// Phase is the block processing phase during which the event was
+	// emitted.
+	Phase EventPhase
+	// App is the name of the application that emitted the event. It is
+	// empty for events not emitted by an application (e.g., the transaction
+	// hash event).
+	App string
+	// TxHash is the hash of the transaction during which the event was
+	// emitted (only set for the DeliverTx phase).
+	TxHash *hash.Hash
+	// Event is the emitted event.
+	Event types.Event
+}
+
+// BlockEvents are all events emitted while processing a block.
+type BlockEvents struct {
+	// Height is the height of the block. Events emitted during InitChain
+	// are reported at height 0.
+	Height int64
+	// Events are the emitted events in emission order.
+	Events []*BlockEvent
+}
+
+func (mux *abciMux) startBlockEvents(height int64) {
+	mux.pendingBlockEvents = &BlockEvents{Height: height}
+}
+
+func (mux *abciMux) recordBlockEvents(phase EventPhase, txHash *hash.Hash, events []types.Event) {
+	blockEvents := mux.pendingBlockEvents
+	if phase == EventPhaseInitChain {
+		if mux.pendingInitChainEvents == nil {
+			mux.pendingInitChainEvents = &BlockEvents{}
+		}
+		blockEvents = mux.pendingInitChainEvents
+	}
+	if blockEvents == nil {
+		return
+	}
+
+	appEventPrefix := api.EventTypeForApp("")
+	for _, ev := range events {
+		var app string
+		if strings.HasPrefix(ev.Type, appEventPrefix) {
+			app = strings.TrimPrefix(ev.Type, appEventPrefix)
+		}
+		blockEvents.Events = append(blockEvents.Events, &BlockEvent{
+			Phase:  phase,
+			App:    app,
+			TxHash: txHash,
+			Event:  ev,
+		})
+	}
+}
+
+func (mux *abciMux) publishBlockEvents() {
+	for _, blockEvents := range []*BlockEvents{mux.pendingInitChainEvents, mux.pendingBlockEvents} {
+		if blockEvents != nil && mux.blockEventsNotifier != nil {
+			mux.blockEventsNotifier.Broadcast(blockEvents)
+		}
+	}
+	mux.pendingInitChainEvents = nil
+	mux.pendingBlockEvents = nil
+}
+
+func (mux *abciMux) watchBlockEvents() (<-chan *BlockEvents, *pubsub.Subscription) {
+	typedCh := make(chan *BlockEvents)
+	sub := mux.blockEventsNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub
+}
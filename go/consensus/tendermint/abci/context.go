@@ -14,6 +14,10 @@ import (
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 )
 
+// DefaultMaxCheckpointDepth is the default maximum number of state
+// checkpoints that may be open in a context at the same time.
+const DefaultMaxCheckpointDepth = 16
+
 type contextKey struct{}
 
 // ContextMode is a context mode.
@@ -70,16 +74,20 @@ type Context struct {
 	blockHeight int64
 	blockCtx    *BlockContext
 
+	checkpointDepth    int
+	maxCheckpointDepth int
+
 	logger *logging.Logger
 }
 
 // NewMockContext creates a new mock context for use in tests.
 func NewMockContext(mode ContextMode, now time.Time) *Context {
 	return &Context{
-		mode:          mode,
-		currentTime:   now,
-		gasAccountant: NewNopGasAccountant(),
-		logger:        logging.GetLogger("consensus/tendermint/abci").With("mode", mode),
+		mode:               mode,
+		currentTime:        now,
+		gasAccountant:      NewNopGasAccountant(),
+		maxCheckpointDepth: DefaultMaxCheckpointDepth,
+		logger:             logging.GetLogger("consensus/tendermint/abci").With("mode", mode),
 	}
 }
 
@@ -89,12 +97,16 @@ func NewContext(mode ContextMode, now time.Time, appState *ApplicationState) *Co
 	defer appState.blockLock.RUnlock()
 
 	c := &Context{
-		mode:          mode,
-		currentTime:   now,
-		gasAccountant: NewNopGasAccountant(),
-		appState:      appState,
-		blockHeight:   appState.blockHeight,
-		logger:        logging.GetLogger("consensus/tendermint/abci").With("mode", mode),
+		mode:               mode,
+		currentTime:        now,
+		gasAccountant:      NewNopGasAccountant(),
+		appState:           appState,
+		blockHeight:        appState.blockHeight,
+		maxCheckpointDepth: appState.maxCheckpointDepth,
+		logger:             logging.GetLogger("consensus/tendermint/abci").With("mode", mode),
+	}
+	if c.maxCheckpointDepth <= 0 {
+		c.maxCheckpointDepth = DefaultMaxCheckpointDepth
 	}
 
 	switch mode {
@@ -132,7 +144,14 @@ func FromCtx(ctx context.Context) *Context {
 // Close releases all resources associated with this context.
 //
 // After calling this method, the context should no longer be used.
+//
+// All state checkpoints created from the context must have been closed
+// before, otherwise this method panics.
 func (c *Context) Close() {
+	if c.checkpointDepth != 0 {
+		panic(fmt.Errorf("context: %d state checkpoint(s) not closed", c.checkpointDepth))
+	}
+
 	if c.IsSimulation() {
 		c.state.Rollback()
 	}
@@ -289,7 +308,16 @@ func (c *Context) BlockContext() *BlockContext {
 }
 
 // NewStateCheckpoint creates a new state checkpoint.
+//
+// The checkpoint must be closed once it is no longer needed. As leaked
+// checkpoints would otherwise accumulate, this method panics in case the
+// number of open checkpoints would exceed the maximum checkpoint depth.
 func (c *Context) NewStateCheckpoint() *StateCheckpoint {
+	if c.checkpointDepth >= c.maxCheckpointDepth {
+		panic(fmt.Errorf("context: maximum state checkpoint depth (%d) exceeded", c.maxCheckpointDepth))
+	}
+	c.checkpointDepth++
+
 	return &StateCheckpoint{
 		ImmutableTree: *c.State().ImmutableTree,
 		ctx:           c,
@@ -305,6 +333,10 @@ type StateCheckpoint struct {
 
 // Close releases resources associated with the checkpoint.
 func (sc *StateCheckpoint) Close() {
+	if sc.ctx == nil {
+		return
+	}
+	sc.ctx.checkpointDepth--
 	sc.ctx = nil
 }
 
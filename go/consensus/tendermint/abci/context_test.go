@@ -0,0 +1,45 @@
+package abci
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextCheckpointDepth(t *testing.T) {
+	require := require.New(t)
+
+	appState := NewMockApplicationState(MockApplicationStateConfig{
+		MaxCheckpointDepth: 2,
+	})
+	ctx := NewContext(ContextDeliverTx, time.Now(), appState)
+
+	// Nesting up to the maximum depth is allowed.
+	outer := ctx.NewStateCheckpoint()
+	inner := ctx.NewStateCheckpoint()
+	require.Panics(func() { ctx.NewStateCheckpoint() }, "exceeding the maximum depth should panic")
+
+	// Closed checkpoints no longer count towards the depth.
+	inner.Close()
+	inner.Close()
+	inner = ctx.NewStateCheckpoint()
+	inner.Close()
+
+	// Closing the context with open checkpoints should panic.
+	require.Panics(func() { ctx.Close() }, "closing a context with open checkpoints should panic")
+	outer.Close()
+	require.NotPanics(func() { ctx.Close() }, "closing a context with all checkpoints closed should not panic")
+
+	// The default maximum depth is used when not configured.
+	ctx = NewContext(ContextDeliverTx, time.Now(), NewMockApplicationState(MockApplicationStateConfig{}))
+	defer ctx.Close()
+	var checkpoints []*StateCheckpoint
+	for i := 0; i < DefaultMaxCheckpointDepth; i++ {
+		checkpoints = append(checkpoints, ctx.NewStateCheckpoint())
+	}
+	require.Panics(func() { ctx.NewStateCheckpoint() }, "exceeding the default maximum depth should panic")
+	for _, cp := range checkpoints {
+		cp.Close()
+	}
+}
@@ -0,0 +1,249 @@
+package abci
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/tendermint/iavl"
+	dbm "github.com/tendermint/tm-db"
+)
+
+var (
+	// exportMagic is the magic header of an exported ABCI state dump.
+	exportMagic = []byte("OASIS-ABCI-STATE-V1")
+
+	// The key formats below must match the ones used internally by IAVL.
+	iavlNodeKeyFmt = iavl.NewKeyFormat('n', 32) // n<hash>
+	iavlRootKeyFmt = iavl.NewKeyFormat('r', 8)  // r<version>
+)
+
+// ExportTree writes the given tree, read from the database backing it, to
+// the writer.
+//
+// As the root hash of an IAVL tree depends on its shape and on the versions
+// at which its nodes were last updated, the serialized tree nodes are exported
+// instead of the key/value pairs, so that an import reproduces the same root
+// hash. The output consists of a magic header, the tree version and root
+// hash followed by the (hash, serialized node) pairs in pre-order, where each
+// hash and node is prefixed by its length encoded as a big-endian uint32.
+func ExportTree(db dbm.DB, tree *iavl.ImmutableTree, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(exportMagic); err != nil {
+		return fmt.Errorf("abci/export: failed to write header: %w", err)
+	}
+	var version [8]byte
+	binary.BigEndian.PutUint64(version[:], uint64(tree.Version()))
+	if _, err := bw.Write(version[:]); err != nil {
+		return fmt.Errorf("abci/export: failed to write header: %w", err)
+	}
+	rootHash := tree.Hash()
+	if err := writeLengthPrefixed(bw, rootHash); err != nil {
+		return fmt.Errorf("abci/export: failed to write header: %w", err)
+	}
+
+	var pending [][]byte
+	if len(rootHash) > 0 {
+		pending = append(pending, rootHash)
+	}
+	for len(pending) > 0 {
+		hash := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		node := db.Get(iavlNodeKeyFmt.Key(hash))
+		if node == nil {
+			return fmt.Errorf("abci/export: missing node %X", hash)
+		}
+		if err := writeLengthPrefixed(bw, hash); err != nil {
+			return fmt.Errorf("abci/export: failed to write node: %w", err)
+		}
+		if err := writeLengthPrefixed(bw, node); err != nil {
+			return fmt.Errorf("abci/export: failed to write node: %w", err)
+		}
+
+		leftHash, rightHash, err := decodeNodeChildren(node)
+		if err != nil {
+			return fmt.Errorf("abci/export: malformed node %X: %w", hash, err)
+		}
+		if leftHash != nil {
+			pending = append(pending, rightHash, leftHash)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ImportTree reads a tree written by ExportTree into the given database,
+// which must not contain any tree versions, and returns the imported
+// version.
+//
+// Once imported, loading the tree from the database yields a tree with the
+// same version and root hash as the exported tree.
+func ImportTree(r io.Reader, db dbm.DB) (int64, error) {
+	br := bufio.NewReader(r)
+
+	magic := make([]byte, len(exportMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return 0, fmt.Errorf("abci/import: failed to read header: %w", err)
+	}
+	if !bytes.Equal(magic, exportMagic) {
+		return 0, fmt.Errorf("abci/import: invalid header")
+	}
+	var rawVersion [8]byte
+	if _, err := io.ReadFull(br, rawVersion[:]); err != nil {
+		return 0, fmt.Errorf("abci/import: failed to read header: %w", err)
+	}
+	version := int64(binary.BigEndian.Uint64(rawVersion[:]))
+	if version < 1 {
+		return 0, fmt.Errorf("abci/import: invalid version: %d", version)
+	}
+	rootHash, err := readLengthPrefixed(br)
+	if err != nil {
+		return 0, fmt.Errorf("abci/import: failed to read header: %w", err)
+	}
+
+	rootPrefix := iavlRootKeyFmt.Key()
+	it := db.Iterator(rootPrefix, []byte{rootPrefix[0] + 1})
+	hasVersions := it.Valid()
+	it.Close()
+	if hasVersions {
+		return 0, fmt.Errorf("abci/import: state is not empty")
+	}
+
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	// The nodes must form exactly the tree below the root hash.
+	var pending [][]byte
+	if len(rootHash) > 0 {
+		pending = append(pending, rootHash)
+	}
+	for {
+		hash, err := readLengthPrefixed(br)
+		switch err {
+		case nil:
+		case io.EOF:
+			if len(pending) > 0 {
+				return 0, fmt.Errorf("abci/import: missing nodes: %w", io.ErrUnexpectedEOF)
+			}
+			batch.Set(iavlRootKeyFmt.Key(version), rootHash)
+			batch.WriteSync()
+			return version, nil
+		default:
+			return 0, fmt.Errorf("abci/import: failed to read node hash: %w", err)
+		}
+
+		node, err := readLengthPrefixed(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, fmt.Errorf("abci/import: failed to read node: %w", err)
+		}
+
+		if len(pending) == 0 || !bytes.Equal(pending[len(pending)-1], hash) {
+			return 0, fmt.Errorf("abci/import: unexpected node %X", hash)
+		}
+		pending = pending[:len(pending)-1]
+
+		leftHash, rightHash, err := decodeNodeChildren(node)
+		if err != nil {
+			return 0, fmt.Errorf("abci/import: malformed node %X: %w", hash, err)
+		}
+		if leftHash != nil {
+			pending = append(pending, rightHash, leftHash)
+		}
+
+		batch.Set(iavlNodeKeyFmt.Key(hash), node)
+	}
+}
+
+// decodeNodeChildren returns the child hashes of a serialized IAVL node. Both
+// are nil for leaf nodes.
+func decodeNodeChildren(node []byte) ([]byte, []byte, error) {
+	// Node header: height, size and version (varints) followed by the key.
+	height, n := binary.Varint(node)
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("failed to decode height")
+	}
+	node = node[n:]
+	for _, field := range []string{"size", "version"} {
+		if _, n = binary.Varint(node); n <= 0 {
+			return nil, nil, fmt.Errorf("failed to decode %s", field)
+		}
+		node = node[n:]
+	}
+	if _, node = decodeByteSlice(node); node == nil {
+		return nil, nil, fmt.Errorf("failed to decode key")
+	}
+	if height == 0 {
+		return nil, nil, nil
+	}
+
+	// Inner node body: left and right child hashes.
+	leftHash, node := decodeByteSlice(node)
+	if node == nil {
+		return nil, nil, fmt.Errorf("failed to decode left hash")
+	}
+	rightHash, node := decodeByteSlice(node)
+	if node == nil {
+		return nil, nil, fmt.Errorf("failed to decode right hash")
+	}
+	return leftHash, rightHash, nil
+}
+
+// decodeByteSlice decodes an uvarint length-prefixed byte slice and returns
+// it together with the remaining data, which is nil on failure.
+func decodeByteSlice(data []byte) ([]byte, []byte) {
+	length, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < length {
+		return nil, nil
+	}
+	data = data[n:]
+	return data[:length], data[length:]
+}
+
+func writeLengthPrefixed(w io.Writer, data []byte) error {
+	if uint64(len(data)) > math.MaxUint32 {
+		return fmt.Errorf("entry too large")
+	}
+
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	if _, err := w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func readLengthPrefixed(r io.Reader) ([]byte, error) {
+	var length [4]byte
+	if _, err := io.ReadFull(r, length[:]); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint32(length[:]))
+	if _, err := io.ReadFull(r, data); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// ExportState writes the ABCI state at the last committed block height
+// to the writer.
+func (s *ApplicationState) ExportState(w io.Writer) error {
+	tree, err := s.deliverTxTree.GetImmutable(s.BlockHeight())
+	if err != nil {
+		return fmt.Errorf("abci/export: failed to get state at height %d: %w", s.BlockHeight(), err)
+	}
+
+	return ExportTree(s.db, tree, w)
+}
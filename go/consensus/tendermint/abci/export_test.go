@@ -0,0 +1,87 @@
+package abci
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/iavl"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestExportImportState(t *testing.T) {
+	require := require.New(t)
+
+	// Build the source state over multiple versions, so that the shape of
+	// the tree and the versions of its nodes depend on the update history.
+	src := NewMockApplicationState(MockApplicationStateConfig{BlockHeight: 3})
+	for v := 0; v < 3; v++ {
+		for i := 0; i < 100; i += v + 1 {
+			src.deliverTxTree.Set([]byte(fmt.Sprintf("key:%d", i)), []byte(fmt.Sprintf("value:%d:%d", v, i)))
+		}
+		for i := v; i < 100; i += 7 {
+			src.deliverTxTree.Remove([]byte(fmt.Sprintf("key:%d", i)))
+		}
+		_, ver, err := src.deliverTxTree.SaveVersion()
+		require.NoError(err, "SaveVersion")
+		require.EqualValues(v+1, ver, "incorrect version on save")
+	}
+
+	var buf bytes.Buffer
+	err := src.ExportState(&buf)
+	require.NoError(err, "ExportState")
+	exported := append([]byte{}, buf.Bytes()...)
+
+	// Exporting the same state twice should produce an identical dump.
+	buf.Reset()
+	err = src.ExportState(&buf)
+	require.NoError(err, "ExportState")
+	require.Equal(exported, buf.Bytes(), "export should be stable")
+
+	// Importing the dump should reproduce the source state root.
+	db := dbm.NewMemDB()
+	version, err := ImportTree(bytes.NewReader(exported), db)
+	require.NoError(err, "ImportTree")
+	require.EqualValues(3, version, "imported version should match the source")
+
+	imported := iavl.NewMutableTree(db, 128)
+	version, err = imported.Load()
+	require.NoError(err, "Load")
+	require.EqualValues(3, version, "loaded version should match the source")
+	require.Equal(src.deliverTxTree.Hash(), imported.Hash(), "imported state root should match the source")
+
+	var srcEntries, importedEntries []string
+	src.deliverTxTree.Iterate(func(key, value []byte) bool {
+		srcEntries = append(srcEntries, fmt.Sprintf("%s=%s", key, value))
+		return false
+	})
+	imported.Iterate(func(key, value []byte) bool {
+		importedEntries = append(importedEntries, fmt.Sprintf("%s=%s", key, value))
+		return false
+	})
+	require.Equal(srcEntries, importedEntries, "imported state should match the source")
+
+	// Exporting the imported state should yield the original dump.
+	buf.Reset()
+	err = ExportTree(db, imported.ImmutableTree, &buf)
+	require.NoError(err, "ExportTree")
+	require.Equal(exported, buf.Bytes(), "re-exported dump should match")
+
+	// The imported state should be usable for further updates.
+	imported.Set([]byte("key:new"), []byte("value:new"))
+	_, version, err = imported.SaveVersion()
+	require.NoError(err, "SaveVersion")
+	require.EqualValues(4, version, "incorrect version on save")
+
+	// Importing into a non-empty state should fail.
+	_, err = ImportTree(bytes.NewReader(exported), db)
+	require.Error(err, "ImportTree should fail on non-empty state")
+
+	// Importing a corrupted dump should fail.
+	_, err = ImportTree(bytes.NewReader([]byte("invalid header")), dbm.NewMemDB())
+	require.Error(err, "ImportTree should fail on invalid header")
+
+	_, err = ImportTree(bytes.NewReader(exported[:len(exported)-1]), dbm.NewMemDB())
+	require.Error(err, "ImportTree should fail on truncated dump")
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"crypto/sha512"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -29,6 +30,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/version"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	consensusGenesis "github.com/oasislabs/oasis-core/go/consensus/genesis"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/db"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
@@ -41,6 +43,14 @@ const (
 	stateKeyInitChainEvents = "OasisInitChainEvents"
 
 	metricsUpdateInterval = 10 * time.Second
+
+	// drainTimeout is the maximum amount of time to wait for applications
+	// to finish in-flight work on shutdown.
+	drainTimeout = 10 * time.Second
+
+	// DefaultStateCacheSize is the default number of IAVL nodes cached for
+	// each of the state trees.
+	DefaultStateCacheSize = 10000
 )
 
 var (
@@ -50,21 +60,100 @@ var (
 			Help: "Total size of the ABCI database (MiB)",
 		},
 	)
+	abciAppDeadlineExceeded = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_abci_app_deadline_exceeded",
+			Help: "Number of times an application exceeded the soft deadline of a block method",
+		},
+		[]string{"app", "method"},
+	)
+	abciSignatureCacheHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_abci_signature_cache_hits",
+			Help: "Number of transaction signature verifications skipped due to the signature cache",
+		},
+	)
 	abciCollectors = []prometheus.Collector{
 		abciSize,
+		abciAppDeadlineExceeded,
+		abciSignatureCacheHits,
 	}
 
 	metricsOnce sync.Once
 
-	errOversizedTx = fmt.Errorf("mux: oversized transaction")
+	errOversizedTx      = fmt.Errorf("mux: oversized transaction")
+	errMempoolTxExpired = fmt.Errorf("mux: transaction expired in mempool")
+	errTooManyTxs       = fmt.Errorf("mux: too many transactions in block")
+	errNoPendingWork    = fmt.Errorf("mux: no pending work")
+	errMalformedWorkTx  = fmt.Errorf("mux: malformed pending work transaction")
+
+	// pendingWorkTxPrefix is the prefix of raw pending work transactions.
+	pendingWorkTxPrefix = []byte("oasis-core/abci: pending work")
 )
 
+// NewPendingWorkTx creates a new raw pending work transaction for the given
+// block height.
+//
+// Pending work transactions do not change any state and only serve to get
+// a block created at the given height when empty blocks are otherwise
+// suppressed, so that pending work (e.g., an epoch transition) is performed.
+// They are rejected unless pending work was due at the start of the block.
+func NewPendingWorkTx(height int64) []byte {
+	var rawHeight [8]byte
+	binary.BigEndian.PutUint64(rawHeight[:], uint64(height))
+	return append(append([]byte{}, pendingWorkTxPrefix...), rawHeight[:]...)
+}
+
 // ApplicationConfig is the configuration for the consensus application.
 type ApplicationConfig struct {
 	DataDir         string
 	Pruning         PruneConfig
 	HaltEpochHeight epochtime.EpochTime
 	MinGasPrice     uint64
+
+	// MinGasPricePerMethod are the per-method minimum gas prices which
+	// override MinGasPrice for the given methods.
+	MinGasPricePerMethod map[transaction.MethodName]uint64
+
+	// RecoverInconsistentTrees configures the state to be recovered by
+	// reloading the (non-persistent) CheckTx tree from the DeliverTx tree
+	// in case the trees are inconsistent on startup instead of failing.
+	RecoverInconsistentTrees bool
+
+	// MempoolTTL is the number of blocks after which a transaction that
+	// has not been included in a block is evicted from the mempool during
+	// re-check. Zero disables eviction.
+	MempoolTTL uint64
+
+	// RecheckGraceBlocks is the number of blocks following an epoch
+	// transition during which transactions failing re-check are kept in
+	// the mempool instead of being invalidated. Zero disables the grace
+	// window.
+	RecheckGraceBlocks uint64
+
+	// MaxCheckpointDepth is the maximum number of state checkpoints that
+	// may be open in a context at the same time. Zero selects
+	// DefaultMaxCheckpointDepth.
+	MaxCheckpointDepth int
+
+	// AppSoftDeadline is the time an application may spend in BeginBlock
+	// or EndBlock before a warning is emitted. Zero disables the warning.
+	AppSoftDeadline time.Duration
+	// AppHardDeadline is the time an application may spend in BeginBlock
+	// or EndBlock before the node panics instead of stalling consensus.
+	// Zero disables the hard deadline.
+	AppHardDeadline time.Duration
+
+	// SignatureCacheSize is the number of transactions verified during
+	// CheckTx for which the signature is not verified again in DeliverTx.
+	// Each entry holds the raw transaction. Zero disables the cache.
+	SignatureCacheSize uint64
+
+	// StateCacheSize is the number of IAVL nodes cached in memory for each
+	// of the DeliverTx and CheckTx state trees. Larger caches reduce the
+	// number of database reads at the cost of memory, as each cached node
+	// holds its key, value and hashes. Zero selects DefaultStateCacheSize.
+	StateCacheSize int
 }
 
 // TransactionAuthHandler is the interface for ABCI applications that handle
@@ -109,7 +198,9 @@ type Application interface {
 
 	// QueryFactory returns an application-specific query factory that
 	// can be used to construct new queries at specific block heights.
-	QueryFactory() interface{}
+	//
+	// Applications that do not support queries may return nil.
+	QueryFactory() QueryFactory
 
 	// OnRegister is the function that is called when the Application
 	// is registered with the multiplexer instance.
@@ -123,10 +214,12 @@ type Application interface {
 	ExecuteTx(*Context, *transaction.Transaction) error
 
 	// ForeignExecuteTx delivers a transaction of another application for
-	// processing.
+	// processing after it has been executed by that application.
 	//
 	// This can be used to run post-tx hooks when dependencies exist
-	// between applications.
+	// between applications. As the state has already been mutated by
+	// the other application at this point, applications that need to
+	// reject transactions should implement ForeignCheckApplication.
 	ForeignExecuteTx(*Context, Application, *transaction.Transaction) error
 
 	// InitChain initializes the blockchain with validators and other
@@ -158,6 +251,92 @@ type Application interface {
 	// the state bound to the multiplexer.
 }
 
+// Query is an application-specific query at a specific block height.
+type Query interface{}
+
+// QueryFactory is an application-specific query factory.
+//
+// In addition to the generic interface, query factories are expected to
+// provide a QueryAt method returning the application-specific query type.
+type QueryFactory interface {
+	// GenericQueryAt returns the application-specific query for a specific
+	// block height.
+	GenericQueryAt(ctx context.Context, height int64) (Query, error)
+}
+
+// DrainableApplication is an Application that may have in-flight work
+// (e.g., background goroutines spawned while processing transactions)
+// that must be allowed to finish before the ApplicationServer is cleaned
+// up.
+type DrainableApplication interface {
+	Application
+
+	// OnDrain is called after the final Commit when the ApplicationServer
+	// is being cleaned up. The returned channel must be closed once all
+	// in-flight work has finished.
+	OnDrain() <-chan struct{}
+}
+
+// CommitHookApplication is an Application that needs to be notified once
+// a block has been durably committed.
+type CommitHookApplication interface {
+	Application
+
+	// OnCommit is called after each block has been committed, in
+	// application name lexicographic order.
+	//
+	// Note: Errors are logged, as the block has already been committed.
+	OnCommit(blockHeight int64, blockHash []byte) error
+}
+
+// PendingWorkApplication is an Application that may have pending work
+// which requires a new block to be created even if there are no
+// transactions.
+type PendingWorkApplication interface {
+	Application
+
+	// HasPendingWork returns true iff the application has pending work
+	// that should be performed at the specified block height.
+	HasPendingWork(blockHeight int64) bool
+}
+
+// ForeignCheckApplication is an Application that may veto transactions
+// of other applications.
+type ForeignCheckApplication interface {
+	Application
+
+	// ForeignCheckTx checks a transaction of another application before
+	// it is executed by that application. Returning an error rejects the
+	// transaction without it being executed.
+	//
+	// Note: Implementations must not mutate state.
+	ForeignCheckTx(*Context, Application, *transaction.Transaction) error
+}
+
+// StaticGasApplication is an Application that can determine the gas cost
+// of some of its methods without executing a transaction.
+type StaticGasApplication interface {
+	Application
+
+	// StaticGasCost returns the amount of gas required to execute any
+	// transaction invoking the given method at the given block height.
+	//
+	// Only methods whose gas cost is a pure function of the gas cost table
+	// in the consensus parameters are static. For other methods false must
+	// be returned.
+	StaticGasCost(method transaction.MethodName, height int64) (transaction.Gas, bool, error)
+}
+
+// GasCostsApplication is an Application that has configurable gas costs
+// as part of its consensus parameters.
+type GasCostsApplication interface {
+	Application
+
+	// GasCosts returns the name of the module and the gas costs of the
+	// application as of the given state.
+	GasCosts(state *ImmutableState) (string, transaction.Costs, error)
+}
+
 // ApplicationServer implements a tendermint ABCI application + socket server,
 // that multiplexes multiple Oasis-specific "applications".
 type ApplicationServer struct {
@@ -220,11 +399,80 @@ func (a *ApplicationServer) RegisterHaltHook(hook func(ctx context.Context, bloc
 	a.mux.registerHaltHook(hook)
 }
 
+// RegisterPendingWorkHook registers a function to be called after each
+// Commit with the committed block height and the amount of time after which
+// the applications will have pending work that requires a new block to be
+// created. If there is no pending work, the hook is called with ok set to
+// false.
+//
+// The hook is called from the ABCI goroutine and must not block.
+func (a *ApplicationServer) RegisterPendingWorkHook(hook func(blockHeight int64, delay time.Duration, ok bool)) {
+	a.mux.registerPendingWorkHook(hook)
+}
+
+// GenesisDigest returns the digest of the genesis InitChain request that
+// the ABCI state was initialized with or nil if there are no committed
+// blocks.
+func (a *ApplicationServer) GenesisDigest() []byte {
+	return a.mux.state.GenesisDigest()
+}
+
 // Pruner returns the ABCI state pruner.
 func (a *ApplicationServer) Pruner() StatePruner {
 	return a.mux.state.statePruner
 }
 
+// MinGasPrices returns the configured global minimum gas price and the
+// per-method minimum gas prices.
+func (a *ApplicationServer) MinGasPrices() (*quantity.Quantity, map[transaction.MethodName]quantity.Quantity) {
+	return a.mux.state.MinGasPrices()
+}
+
+// SetMinGasPrice replaces the global minimum gas price used for local
+// transaction admission (CheckTx).
+func (a *ApplicationServer) SetMinGasPrice(q *quantity.Quantity) {
+	a.mux.state.SetMinGasPrice(q)
+}
+
+// QueryWithProof returns the value stored under the given key in the state
+// at the given height together with a proof of its inclusion.
+//
+// Unlike the application queries, this always reads the committed state so
+// that the proof is valid against the application hash at the height.
+func (a *ApplicationServer) QueryWithProof(key []byte, height int64) (*ProvenValue, error) {
+	state, err := NewImmutableState(a.mux.state, height)
+	if err != nil {
+		return nil, err
+	}
+
+	value, proof, err := state.GetWithProof(key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, ErrKeyNotFound
+	}
+	return &ProvenValue{
+		Height: state.Snapshot.Version(),
+		Key:    key,
+		Value:  value,
+		Proof:  proof,
+	}, nil
+}
+
+// ConsensusParameters returns the consensus parameters as of the given
+// height.
+//
+// In case no consensus parameters have been stored in the state, nil is
+// returned.
+func (a *ApplicationServer) ConsensusParameters(height int64) (*consensusGenesis.Parameters, error) {
+	state, err := NewImmutableState(a.mux.state, height)
+	if err != nil {
+		return nil, err
+	}
+	return GetConsensusParameters(state.Snapshot)
+}
+
 // SetEpochtime sets the mux epochtime.
 //
 // Epochtime must be set before the multiplexer can be used.
@@ -254,6 +502,13 @@ func (a *ApplicationServer) TransactionAuthHandler() TransactionAuthHandler {
 	return a.mux.state.txAuthHandler
 }
 
+// WatchBlockEvents returns a stream of all events emitted while processing
+// each committed block, including events emitted during InitChain (reported
+// at height 0).
+func (a *ApplicationServer) WatchBlockEvents() (<-chan *BlockEvents, *pubsub.Subscription) {
+	return a.mux.watchBlockEvents()
+}
+
 // WatchInvalidatedTx adds a watcher for when/if the transaction with given
 // hash becomes invalid due to a failed re-check.
 func (a *ApplicationServer) WatchInvalidatedTx(txHash hash.Hash) (<-chan error, pubsub.ClosableSubscription, error) {
@@ -299,13 +554,48 @@ type abciMux struct {
 	currentTime    time.Time
 	maxTxSize      uint64
 	maxBlockGas    transaction.Gas
+	maxTxPerBlock  uint64
+	sigCache       *sigCache
 
 	genesisHooks []func()
 	haltHooks    []func(context.Context, int64, epochtime.EpochTime)
 
+	pendingWorkHooks []func(int64, time.Duration, bool)
+
 	// invalidatedTxs maps transaction hashes (hash.Hash) to a subscriber
 	// waiting for that transaction to become invalid.
 	invalidatedTxs sync.Map
+
+	// mempoolTTL is the number of blocks a transaction may spend in the
+	// mempool before it is evicted during re-check (0 disables eviction).
+	mempoolTTL int64
+	// recheckGraceBlocks is the number of blocks following an epoch
+	// transition during which re-check failures are ignored (0 disables).
+	recheckGraceBlocks int64
+	// appSoftDeadline and appHardDeadline are the per-application
+	// BeginBlock/EndBlock deadlines (0 disables).
+	appSoftDeadline time.Duration
+	appHardDeadline time.Duration
+
+	// blockEventsNotifier notifies subscribers of all events emitted while
+	// processing each committed block.
+	blockEventsNotifier    *pubsub.Broker
+	pendingBlockEvents     *BlockEvents
+	pendingInitChainEvents *BlockEvents
+	// mempoolTxs maps transaction hashes (hash.Hash) to the block height
+	// (int64) at which the transaction was first accepted into the mempool.
+	mempoolTxs sync.Map
+
+	// staticGasLock protects the static gas cost cache which is only valid
+	// for the block height it was populated at.
+	staticGasLock   sync.Mutex
+	staticGasHeight int64
+	staticGasCosts  map[transaction.MethodName]staticGasCost
+}
+
+type staticGasCost struct {
+	gas    transaction.Gas
+	static bool
 }
 
 type invalidatedTxSubscription struct {
@@ -337,6 +627,151 @@ func (mux *abciMux) watchInvalidatedTx(txHash hash.Hash) (<-chan error, pubsub.C
 	return resultCh, sub, nil
 }
 
+// trackMempoolTx records the current block height as the height at which
+// the given transaction was accepted into the mempool.
+func (mux *abciMux) trackMempoolTx(txHash hash.Hash) {
+	if mux.mempoolTTL == 0 {
+		return
+	}
+	mux.mempoolTxs.LoadOrStore(txHash, mux.state.BlockHeight())
+}
+
+// checkMempoolTx checks whether the given transaction has spent more than
+// the configured TTL in the mempool.
+func (mux *abciMux) checkMempoolTx(txHash hash.Hash) error {
+	if mux.mempoolTTL == 0 {
+		return nil
+	}
+	item, exists := mux.mempoolTxs.Load(txHash)
+	if !exists {
+		return nil
+	}
+	if mux.state.BlockHeight()-item.(int64) >= mux.mempoolTTL {
+		return errMempoolTxExpired
+	}
+	return nil
+}
+
+// pruneMempoolTxs stops tracking transactions that are older than the
+// configured TTL.
+//
+// Such transactions have either already been evicted during the re-check
+// following the previous block, or were dropped from the mempool without
+// the application being notified (e.g., when the mempool rejects them after
+// a successful CheckTx).
+func (mux *abciMux) pruneMempoolTxs() {
+	if mux.mempoolTTL == 0 {
+		return
+	}
+	height := mux.state.BlockHeight()
+	mux.mempoolTxs.Range(func(key, value interface{}) bool {
+		if height-value.(int64) > mux.mempoolTTL {
+			mux.mempoolTxs.Delete(key)
+		}
+		return true
+	})
+}
+
+// inRecheckGraceWindow returns true iff an epoch transition happened within
+// the configured number of most recent blocks.
+func (mux *abciMux) inRecheckGraceWindow(ctx *Context) bool {
+	if mux.recheckGraceBlocks == 0 {
+		return false
+	}
+
+	height := mux.state.BlockHeight()
+	prevHeight := height - mux.recheckGraceBlocks
+	if prevHeight < 1 {
+		prevHeight = 1
+	}
+	if prevHeight >= height {
+		return false
+	}
+
+	epoch, err := mux.state.GetEpoch(ctx.Ctx(), height)
+	if err != nil {
+		mux.logger.Error("failed to get epoch for re-check grace window",
+			"err", err,
+			"height", height,
+		)
+		return false
+	}
+	prevEpoch, err := mux.state.GetEpoch(ctx.Ctx(), prevHeight)
+	if err != nil {
+		mux.logger.Error("failed to get epoch for re-check grace window",
+			"err", err,
+			"height", prevHeight,
+		)
+		return false
+	}
+	return epoch != prevEpoch
+}
+
+// updateGasCosts updates the consensus gas costs exposed by the application
+// state from the gas costs of all applications as of the given state.
+func (mux *abciMux) updateGasCosts(state *ImmutableState) error {
+	gasCosts := make(map[string]transaction.Costs)
+	for _, v := range mux.appsByLexOrder {
+		app, ok := v.(GasCostsApplication)
+		if !ok {
+			continue
+		}
+
+		module, costs, err := app.GasCosts(state)
+		if err != nil {
+			return fmt.Errorf("mux: failed to get gas costs of application '%s': %w", app.Name(), err)
+		}
+		gasCosts[module] = costs
+	}
+
+	mux.state.gasCostsLock.Lock()
+	mux.state.gasCosts = gasCosts
+	mux.state.gasCostsLock.Unlock()
+
+	return nil
+}
+
+// setConsensusParameters sets the consensus parameters enforced by the
+// multiplexer.
+func (mux *abciMux) setConsensusParameters(params *consensusGenesis.Parameters) {
+	if mux.maxTxSize = params.MaxTxSize; mux.maxTxSize == 0 {
+		mux.logger.Warn("maximum transaction size enforcement is disabled")
+	}
+	if mux.maxBlockGas = transaction.Gas(params.MaxBlockGas); mux.maxBlockGas == 0 {
+		mux.logger.Warn("maximum block gas enforcement is disabled")
+	}
+	if mux.maxTxPerBlock = params.MaxTxPerBlock; mux.maxTxPerBlock == 0 {
+		mux.logger.Warn("maximum transactions per block enforcement is disabled")
+	}
+}
+
+// reloadConsensusParameters reloads the consensus parameters enforced by the
+// multiplexer from the given state, so that on-chain updates take effect.
+func (mux *abciMux) reloadConsensusParameters(tree *iavl.ImmutableTree) error {
+	params, err := GetConsensusParameters(tree)
+	if err != nil {
+		return err
+	}
+	if params == nil {
+		// Consensus parameters have not been stored, keep the genesis ones.
+		return nil
+	}
+	if params.MaxTxSize == mux.maxTxSize &&
+		transaction.Gas(params.MaxBlockGas) == mux.maxBlockGas &&
+		params.MaxTxPerBlock == mux.maxTxPerBlock {
+		return nil
+	}
+
+	mux.logger.Info("applying updated consensus parameters",
+		"max_tx_size", params.MaxTxSize,
+		"max_block_gas", params.MaxBlockGas,
+		"max_tx_per_block", params.MaxTxPerBlock,
+	)
+	mux.setConsensusParameters(params)
+
+	return nil
+}
+
 func (mux *abciMux) registerGenesisHook(hook func()) {
 	mux.Lock()
 	defer mux.Unlock()
@@ -351,6 +786,13 @@ func (mux *abciMux) registerHaltHook(hook func(context.Context, int64, epochtime
 	mux.haltHooks = append(mux.haltHooks, hook)
 }
 
+func (mux *abciMux) registerPendingWorkHook(hook func(int64, time.Duration, bool)) {
+	mux.Lock()
+	defer mux.Unlock()
+
+	mux.pendingWorkHooks = append(mux.pendingWorkHooks, hook)
+}
+
 func (mux *abciMux) Info(req types.RequestInfo) types.ResponseInfo {
 	return types.ResponseInfo{
 		AppVersion:       version.ConsensusProtocol.ToU64(),
@@ -373,12 +815,7 @@ func (mux *abciMux) InitChain(req types.RequestInitChain) types.ResponseInitChai
 		panic("mux: invalid genesis application state")
 	}
 
-	if mux.maxTxSize = st.Consensus.Parameters.MaxTxSize; mux.maxTxSize == 0 {
-		mux.logger.Warn("maximum transaction size enforcement is disabled")
-	}
-	if mux.maxBlockGas = transaction.Gas(st.Consensus.Parameters.MaxBlockGas); mux.maxBlockGas == 0 {
-		mux.logger.Warn("maximum block gas enforcement is disabled")
-	}
+	mux.setConsensusParameters(&st.Consensus.Parameters)
 
 	b, _ := json.Marshal(st)
 	mux.logger.Debug("Genesis ABCI application state",
@@ -394,10 +831,11 @@ func (mux *abciMux) InitChain(req types.RequestInitChain) types.ResponseInitChai
 	// nothing writes to the state till the Commit() call, along with
 	// clearly separating chain instances based on the initialization
 	// state, forever.
-	tmp := bytes.NewBuffer(nil)
-	_ = types.WriteMessage(&req, tmp)
-	genesisDigest := sha512.Sum512_256(tmp.Bytes())
-	mux.state.deliverTxTree.Set([]byte(stateKeyGenesisDigest), genesisDigest[:])
+	mux.state.deliverTxTree.Set([]byte(stateKeyGenesisDigest), GenesisDigest(req))
+
+	// Store the genesis consensus parameters, so that they can be updated
+	// on-chain.
+	SetConsensusParameters(mux.state.deliverTxTree, &st.Consensus.Parameters)
 
 	resp := mux.BaseApplication.InitChain(req)
 
@@ -450,6 +888,14 @@ func (mux *abciMux) InitChain(req types.RequestInitChain) types.ResponseInitChai
 
 	mux.logger.Debug("InitChain: initializing of applications complete", "num_collected_events", len(ctx.GetEvents()))
 
+	// Expose the genesis gas costs until the first block is committed.
+	if err = mux.updateGasCosts(&ImmutableState{Snapshot: mux.state.deliverTxTree.ImmutableTree}); err != nil {
+		mux.logger.Error("InitChain: failed to update gas costs",
+			"err", err,
+		)
+		panic("mux: InitChain: failed to update gas costs: " + err.Error())
+	}
+
 	// Since returning emitted events doesn't work for InitChain() response yet,
 	// we store those and return them in BeginBlock().
 	evBinary := cbor.Marshal(ctx.GetEvents())
@@ -501,6 +947,15 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 	}
 	mux.lastBeginBlock = blockHeight
 	mux.currentTime = req.Header.Time
+	mux.startBlockEvents(req.Header.Height)
+
+	// Apply any consensus parameter updates from the previous block.
+	if err := mux.reloadConsensusParameters(mux.state.deliverTxTree.ImmutableTree); err != nil {
+		mux.logger.Error("BeginBlock: failed to reload consensus parameters",
+			"err", err,
+		)
+		panic("mux: BeginBlock: failed to reload consensus parameters: " + err.Error())
+	}
 
 	// Create empty block context.
 	mux.state.blockCtx = NewBlockContext()
@@ -509,6 +964,12 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 	} else {
 		mux.state.blockCtx.Set(GasAccountantKey{}, NewNopGasAccountant())
 	}
+	// Determine whether there is pending work due before any of the
+	// applications run, so that pending work transactions in this block can
+	// be validated. The block time is used so that all nodes agree.
+	mux.state.blockCtx.Set(blockPendingWorkKey{}, &blockPendingWork{
+		due: mux.pendingWorkDue(blockHeight, mux.currentTime),
+	})
 	// Create BeginBlock context.
 	ctx := NewContext(ContextBeginBlock, mux.currentTime, mux.state)
 	defer ctx.Close()
@@ -545,7 +1006,11 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 
 	// Dispatch BeginBlock to all applications.
 	for _, app := range mux.appsByLexOrder {
-		if err := app.BeginBlock(ctx, req); err != nil {
+		var err error
+		mux.withAppDeadline(app, "BeginBlock", func() {
+			err = app.BeginBlock(ctx, req)
+		})
+		if err != nil {
 			mux.logger.Error("BeginBlock: fatal error in application",
 				"err", err,
 				"app", app.Name(),
@@ -558,6 +1023,7 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 
 	// Collect and return events from the application's BeginBlock calls.
 	response.Events = ctx.GetEvents()
+	mux.recordBlockEvents(EventPhaseBeginBlock, nil, response.Events)
 
 	// During the first block, also collect and prepend application events
 	// generated during InitChain to BeginBlock events.
@@ -568,6 +1034,7 @@ func (mux *abciMux) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginB
 			_ = cbor.Unmarshal(evBinary, &events)
 
 			response.Events = append(events, response.Events...)
+			mux.recordBlockEvents(EventPhaseInitChain, nil, events)
 
 			mux.state.deliverTxTree.Remove([]byte(stateKeyInitChainEvents))
 		}
@@ -599,12 +1066,34 @@ func (mux *abciMux) decodeTx(ctx *Context, rawTx []byte) (*transaction.Transacti
 		)
 		return nil, nil, err
 	}
-	var tx transaction.Transaction
-	if err := sigTx.Open(&tx); err != nil {
-		ctx.Logger().Error("failed to verify transaction signature",
-			"tx", base64.StdEncoding.EncodeToString(rawTx),
+	if !sigTx.Scheme.IsSupported() {
+		ctx.Logger().Error("unsupported transaction signature scheme",
+			"scheme", sigTx.Scheme,
 		)
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("%w: %s", transaction.ErrUnsupportedSignatureScheme, sigTx.Scheme)
+	}
+	var (
+		tx     transaction.Transaction
+		txHash hash.Hash
+	)
+	txHash.FromBytes(rawTx)
+	if mux.sigCache.isVerified(txHash, rawTx) {
+		// The signature over the exact same transaction was already verified
+		// during CheckTx.
+		abciSignatureCacheHits.Inc()
+		if err := cbor.Unmarshal(sigTx.Blob, &tx); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		if err := sigTx.Open(&tx); err != nil {
+			ctx.Logger().Error("failed to verify transaction signature",
+				"tx", base64.StdEncoding.EncodeToString(rawTx),
+			)
+			return nil, nil, err
+		}
+		if ctx.IsCheckOnly() {
+			mux.sigCache.markVerified(txHash, rawTx)
+		}
 	}
 	if err := tx.SanityCheck(); err != nil {
 		ctx.Logger().Error("bad transaction",
@@ -640,6 +1129,34 @@ func (mux *abciMux) processTx(ctx *Context, tx *transaction.Transaction) error {
 		return fmt.Errorf("mux: unknown method: %s", tx.Method)
 	}
 
+	// Transactions are processed in two phases around the execution by
+	// the application owning the method:
+	//
+	// 1. ForeignCheckTx is run on all other applications implementing
+	//    ForeignCheckApplication, which may veto the transaction before
+	//    any state is mutated by ExecuteTx.
+	// 2. ForeignExecuteTx is run on all other applications after the
+	//    transaction was executed, so they can run their post-tx hooks.
+	for _, foreignApp := range mux.appsByLexOrder {
+		if foreignApp == app {
+			continue
+		}
+
+		checkApp, ok := foreignApp.(ForeignCheckApplication)
+		if !ok {
+			continue
+		}
+		if err := checkApp.ForeignCheckTx(ctx, app, tx); err != nil {
+			ctx.Logger().Debug("transaction vetoed by foreign application",
+				"tx", tx,
+				"app", app.Name(),
+				"foreign_app", foreignApp.Name(),
+				"err", err,
+			)
+			return err
+		}
+	}
+
 	ctx.Logger().Debug("dispatching",
 		"app", app.Name(),
 		"tx", tx,
@@ -649,7 +1166,7 @@ func (mux *abciMux) processTx(ctx *Context, tx *transaction.Transaction) error {
 		return err
 	}
 
-	// Run ForeignDeliverTx on all other applications so they can
+	// Run ForeignExecuteTx on all other applications so they can
 	// run their post-tx hooks.
 	for _, foreignApp := range mux.appsByLexOrder {
 		if foreignApp == app {
@@ -665,6 +1182,10 @@ func (mux *abciMux) processTx(ctx *Context, tx *transaction.Transaction) error {
 }
 
 func (mux *abciMux) executeTx(ctx *Context, rawTx []byte) error {
+	if bytes.HasPrefix(rawTx, pendingWorkTxPrefix) {
+		return mux.executePendingWorkTx(ctx, rawTx)
+	}
+
 	tx, sigTx, err := mux.decodeTx(ctx, rawTx)
 	if err != nil {
 		return err
@@ -676,11 +1197,66 @@ func (mux *abciMux) executeTx(ctx *Context, rawTx []byte) error {
 	return mux.processTx(ctx, tx)
 }
 
+// executePendingWorkTx checks that a pending work transaction is for the
+// height of the block being created and that there is pending work that is
+// due, so that these transactions can not be used to create blocks at will.
+//
+// When delivering transactions, the pending work must have been due at the
+// start of the block (as of the block time) and only a single pending work
+// transaction is accepted in each block.
+func (mux *abciMux) executePendingWorkTx(ctx *Context, rawTx []byte) error {
+	rawHeight := rawTx[len(pendingWorkTxPrefix):]
+	if len(rawHeight) != 8 {
+		return errMalformedWorkTx
+	}
+	height := int64(binary.BigEndian.Uint64(rawHeight))
+	if height != ctx.BlockHeight()+1 {
+		return fmt.Errorf("mux: pending work transaction for height %d, expected %d", height, ctx.BlockHeight()+1)
+	}
+
+	if ctx.IsCheckOnly() {
+		if !mux.pendingWorkDue(ctx.BlockHeight(), time.Now()) {
+			return errNoPendingWork
+		}
+		return nil
+	}
+
+	pw := ctx.BlockContext().Get(blockPendingWorkKey{}).(*blockPendingWork)
+	if !pw.due || pw.delivered {
+		return errNoPendingWork
+	}
+	pw.delivered = true
+	return nil
+}
+
+// blockPendingWork is the pending work state of the current block.
+type blockPendingWork struct {
+	// due is true iff there was pending work due at the start of the block.
+	due bool
+	// delivered is true iff a pending work transaction has already been
+	// delivered in the block.
+	delivered bool
+}
+
+// blockPendingWorkKey is the block context key for the pending work state
+// of the current block.
+type blockPendingWorkKey struct{}
+
+// NewDefault returns a new default value for the given key.
+func (k blockPendingWorkKey) NewDefault() interface{} {
+	return &blockPendingWork{}
+}
+
 func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Transaction) (transaction.Gas, error) {
 	// As opposed to other transaction dispatch entry points (CheckTx/DeliverTx), this method can
 	// be called in parallel to the consensus layer and to other invocations.
 	//
 	// For simulation mode, time will be filled in by NewContext from last block time.
+	// Avoid a full simulation for methods with a static gas cost.
+	if gas, ok := mux.staticGasCost(tx.Method); ok {
+		return gas, nil
+	}
+
 	ctx := NewContext(ContextSimulateTx, time.Time{}, mux.state)
 	defer ctx.Close()
 
@@ -693,11 +1269,73 @@ func (mux *abciMux) EstimateGas(caller signature.PublicKey, tx *transaction.Tran
 	return ctx.Gas().GasUsed(), nil
 }
 
+// staticGasCost returns the gas cost of the given method iff it is static.
+//
+// Note that the static gas cost is returned even for transactions that
+// would fail during execution.
+func (mux *abciMux) staticGasCost(method transaction.MethodName) (transaction.Gas, bool) {
+	app, ok := mux.appsByMethod[method].(StaticGasApplication)
+	if !ok {
+		return 0, false
+	}
+
+	mux.staticGasLock.Lock()
+	defer mux.staticGasLock.Unlock()
+
+	// Invalidate the cache on new blocks as the consensus parameters may
+	// have changed.
+	height := mux.state.BlockHeight()
+	if mux.staticGasCosts == nil || mux.staticGasHeight != height {
+		mux.staticGasHeight = height
+		mux.staticGasCosts = make(map[transaction.MethodName]staticGasCost)
+	}
+	if cost, cached := mux.staticGasCosts[method]; cached {
+		return cost.gas, cost.static
+	}
+
+	gas, static, err := app.StaticGasCost(method, height)
+	if err != nil {
+		mux.logger.Warn("failed to determine static gas cost, falling back to simulation",
+			"err", err,
+			"method", method,
+			"height", height,
+		)
+		return 0, false
+	}
+	mux.staticGasCosts[method] = staticGasCost{gas: gas, static: static}
+
+	return gas, static
+}
+
 func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 	ctx := NewContext(ContextCheckTx, mux.currentTime, mux.state)
 	defer ctx.Close()
 
-	if err := mux.executeTx(ctx, req.Tx); err != nil {
+	var txHash hash.Hash
+	txHash.FromBytes(req.Tx)
+
+	var err error
+	if req.Type == types.CheckTxType_Recheck {
+		// Evict transactions that have been in the mempool for too long
+		// by failing the re-check, which also removes them from the
+		// mempool cache so that they can be resubmitted.
+		err = mux.checkMempoolTx(txHash)
+	}
+	if err == nil {
+		err = mux.executeTx(ctx, req.Tx)
+
+		// Transactions may become transiently invalid at epoch transitions
+		// (e.g., due to changed fees), so give them a chance by deferring
+		// invalidation during the grace window.
+		if err != nil && req.Type == types.CheckTxType_Recheck && mux.inRecheckGraceWindow(ctx) {
+			ctx.Logger().Debug("deferring re-check invalidation during grace window",
+				"err", err,
+				"tx_hash", txHash,
+			)
+			err = nil
+		}
+	}
+	if err != nil {
 		module, code := errors.Code(err)
 
 		if req.Type == types.CheckTxType_Recheck {
@@ -707,8 +1345,7 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 
 			// XXX: The Tendermint mempool should have provisions for this instead
 			//      of us hacking our way through this here.
-			var txHash hash.Hash
-			txHash.FromBytes(req.Tx)
+			mux.mempoolTxs.Delete(txHash)
 
 			if item, exists := mux.invalidatedTxs.Load(txHash); exists {
 				// Notify subscriber.
@@ -731,6 +1368,9 @@ func (mux *abciMux) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
 			GasUsed:   int64(ctx.Gas().GasUsed()),
 		}
 	}
+	if req.Type == types.CheckTxType_New {
+		mux.trackMempoolTx(txHash)
+	}
 
 	return types.ResponseCheckTx{
 		Code:      types.CodeTypeOK,
@@ -743,27 +1383,100 @@ func (mux *abciMux) DeliverTx(req types.RequestDeliverTx) types.ResponseDeliverT
 	ctx := NewContext(ContextDeliverTx, mux.currentTime, mux.state)
 	defer ctx.Close()
 
-	if err := mux.executeTx(ctx, req.Tx); err != nil {
+	var txHash hash.Hash
+	txHash.FromBytes(req.Tx)
+	mux.mempoolTxs.Delete(txHash)
+
+	// Pending work transactions only serve to get the block created, so they
+	// do not count towards the transaction limit and do not emit any events.
+	if bytes.HasPrefix(req.Tx, pendingWorkTxPrefix) {
+		if err := mux.executePendingWorkTx(ctx, req.Tx); err != nil {
+			module, code := errors.Code(err)
+			return types.ResponseDeliverTx{
+				Codespace: module,
+				Code:      code,
+				Log:       err.Error(),
+			}
+		}
+		return types.ResponseDeliverTx{Code: types.CodeTypeOK}
+	}
+
+	// Emit the transaction hash so that transactions can be looked up by
+	// hash. Events are not part of the consensus results hash.
+	txEvent := api.NewTxEvent(txHash)
+	defer mux.sigCache.remove(txHash)
+
+	err := errTooManyTxs
+	if txCount := ctx.BlockContext().Get(blockTxCountKey{}).(*uint64); mux.maxTxPerBlock == 0 || *txCount < mux.maxTxPerBlock {
+		*txCount++
+		err = mux.executeTx(ctx, req.Tx)
+	}
+	if err != nil {
 		module, code := errors.Code(err)
+		mux.recordBlockEvents(EventPhaseDeliverTx, &txHash, []types.Event{txEvent})
 
 		return types.ResponseDeliverTx{
 			Codespace: module,
 			Code:      code,
 			Log:       err.Error(),
+			Events:    []types.Event{txEvent},
 			GasWanted: int64(ctx.Gas().GasWanted()),
 			GasUsed:   int64(ctx.Gas().GasUsed()),
 		}
 	}
 
+	events := append(ctx.GetEvents(), txEvent)
+	mux.recordBlockEvents(EventPhaseDeliverTx, &txHash, events)
+
 	return types.ResponseDeliverTx{
 		Code:      types.CodeTypeOK,
 		Data:      cbor.Marshal(ctx.Data()),
-		Events:    ctx.GetEvents(),
+		Events:    events,
 		GasWanted: int64(ctx.Gas().GasWanted()),
 		GasUsed:   int64(ctx.Gas().GasUsed()),
 	}
 }
 
+// blockTxCountKey is the block context key for the number of transactions
+// delivered in the current block.
+type blockTxCountKey struct{}
+
+// NewDefault returns a new default value for the given key.
+func (k blockTxCountKey) NewDefault() interface{} {
+	return new(uint64)
+}
+
+// withAppDeadline calls fn which runs the given block method of the given
+// application. A warning is emitted in case the call exceeds the soft
+// deadline and the node panics in case it exceeds the hard deadline, so that
+// a stalled application does not silently hang consensus.
+func (mux *abciMux) withAppDeadline(app Application, method string, fn func()) {
+	start := time.Now()
+	if mux.appHardDeadline > 0 {
+		hardDeadline := time.AfterFunc(mux.appHardDeadline, func() {
+			mux.logger.Error("application exceeded hard deadline",
+				"app", app.Name(),
+				"method", method,
+				"deadline", mux.appHardDeadline,
+			)
+			panic(fmt.Sprintf("mux: app '%s' exceeded %s deadline (%s)", app.Name(), method, mux.appHardDeadline))
+		})
+		defer hardDeadline.Stop()
+	}
+
+	fn()
+
+	if elapsed := time.Since(start); mux.appSoftDeadline > 0 && elapsed > mux.appSoftDeadline {
+		mux.logger.Warn("application exceeded soft deadline",
+			"app", app.Name(),
+			"method", method,
+			"elapsed", elapsed,
+			"deadline", mux.appSoftDeadline,
+		)
+		abciAppDeadlineExceeded.With(prometheus.Labels{"app": app.Name(), "method": method}).Inc()
+	}
+}
+
 func (mux *abciMux) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
 	mux.logger.Debug("EndBlock",
 		"req", req,
@@ -792,7 +1505,13 @@ func (mux *abciMux) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
 	// Dispatch EndBlock to all applications.
 	resp := mux.BaseApplication.EndBlock(req)
 	for _, app := range mux.appsByLexOrder {
-		newResp, err := app.EndBlock(ctx, req)
+		var (
+			newResp types.ResponseEndBlock
+			err     error
+		)
+		mux.withAppDeadline(app, "EndBlock", func() {
+			newResp, err = app.EndBlock(ctx, req)
+		})
 		if err != nil {
 			mux.logger.Error("EndBlock: fatal error in application",
 				"err", err,
@@ -807,6 +1526,7 @@ func (mux *abciMux) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
 
 	// Update tags.
 	resp.Events = ctx.GetEvents()
+	mux.recordBlockEvents(EventPhaseEndBlock, nil, resp.Events)
 
 	// Clear block context.
 	mux.state.blockCtx = nil
@@ -825,15 +1545,141 @@ func (mux *abciMux) Commit() types.ResponseCommit {
 		panic(err)
 	}
 
+	blockHeight, blockHash := mux.state.BlockHeight(), mux.state.BlockHash()
+	mux.publishBlockEvents()
+	mux.pruneMempoolTxs()
+
 	mux.logger.Debug("Commit",
-		"block_height", mux.state.BlockHeight(),
-		"block_hash", hex.EncodeToString(mux.state.BlockHash()),
+		"block_height", blockHeight,
+		"block_hash", hex.EncodeToString(blockHash),
 	)
 
-	return types.ResponseCommit{Data: mux.state.BlockHash()}
+	// Applications charge gas based on the exposed gas costs, so continuing
+	// with stale gas costs could cause nodes to diverge.
+	state, err := NewImmutableState(mux.state, blockHeight)
+	if err == nil {
+		err = mux.updateGasCosts(state)
+	}
+	if err != nil {
+		mux.logger.Error("Commit: failed to update gas costs",
+			"err", err,
+			"block_height", blockHeight,
+		)
+		panic("mux: Commit: failed to update gas costs: " + err.Error())
+	}
+
+	for _, v := range mux.appsByLexOrder {
+		app, ok := v.(CommitHookApplication)
+		if !ok {
+			continue
+		}
+
+		if err := app.OnCommit(blockHeight, append([]byte{}, blockHash...)); err != nil {
+			mux.logger.Error("Commit: post-commit hook failed",
+				"app", app.Name(),
+				"block_height", blockHeight,
+				"err", err,
+			)
+		}
+	}
+
+	if len(mux.pendingWorkHooks) > 0 {
+		delay, ok := mux.nextPendingWork(blockHeight, time.Now())
+		for _, hook := range mux.pendingWorkHooks {
+			hook(blockHeight, delay, ok)
+		}
+	}
+
+	return types.ResponseCommit{Data: blockHash}
+}
+
+// pendingWorkDue returns true iff there is pending work that is due at
+// the given time and that requires a new block to be created following the
+// block at the given height.
+func (mux *abciMux) pendingWorkDue(blockHeight int64, now time.Time) bool {
+	delay, ok := mux.nextPendingWork(blockHeight, now)
+	return ok && delay <= 0
+}
+
+// nextPendingWork returns the amount of time after which there will be
+// pending work that requires a new block to be created following the
+// block at the given height, if any.
+func (mux *abciMux) nextPendingWork(blockHeight int64, now time.Time) (time.Duration, bool) {
+	nextHeight := blockHeight + 1
+
+	// Epoch transitions must happen at the scheduled height.
+	if mux.state.timeSource != nil {
+		currentEpoch, err := mux.state.GetEpoch(mux.state.ctx, blockHeight)
+		if err != nil {
+			mux.logger.Error("failed to get current epoch",
+				"err", err,
+			)
+			return 0, true
+		}
+		nextEpoch, err := mux.state.GetEpoch(mux.state.ctx, nextHeight)
+		if err != nil {
+			mux.logger.Error("failed to get epoch at next height",
+				"err", err,
+			)
+			return 0, true
+		}
+		if currentEpoch != nextEpoch {
+			return 0, true
+		}
+	}
+
+	for _, v := range mux.appsByLexOrder {
+		app, ok := v.(PendingWorkApplication)
+		if !ok {
+			continue
+		}
+		if app.HasPendingWork(nextHeight) {
+			return 0, true
+		}
+	}
+
+	// Armed timers need a block once their deadline is reached.
+	deadline, ok := nextTimerDeadline(mux.state.deliverTxTree)
+	if !ok {
+		return 0, false
+	}
+	delay := deadline.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+func (mux *abciMux) doDrain(timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for _, v := range mux.appsByLexOrder {
+		app, ok := v.(DrainableApplication)
+		if !ok {
+			continue
+		}
+
+		mux.logger.Debug("draining application",
+			"app", app.Name(),
+		)
+
+		select {
+		case <-app.OnDrain():
+		case <-timer.C:
+			mux.logger.Error("timed out while draining applications",
+				"app", app.Name(),
+			)
+			return
+		}
+	}
 }
 
 func (mux *abciMux) doCleanup() {
+	// Give applications a chance to finish any in-flight work before
+	// the state is torn down.
+	mux.doDrain(drainTimeout)
+
 	mux.state.doCleanup()
 
 	for _, v := range mux.appsByLexOrder {
@@ -864,6 +1710,18 @@ func (mux *abciMux) doRegister(app Application) error {
 	mux.rebuildAppLexOrdering() // Inefficient but not a lot of apps.
 
 	app.OnRegister(mux.state)
+
+	// Make the gas costs of the application available immediately in case
+	// there is already committed state.
+	if mux.state.BlockHeight() > 0 {
+		state, err := NewImmutableState(mux.state, 0)
+		if err != nil {
+			return err
+		}
+		if err = mux.updateGasCosts(state); err != nil {
+			return err
+		}
+	}
 	mux.logger.Debug("Registered new application",
 		"app", app.Name(),
 	)
@@ -906,12 +1764,23 @@ func newABCIMux(ctx context.Context, cfg *ApplicationConfig) (*abciMux, error) {
 		return nil, err
 	}
 
+	sigCache, err := newSigCache(cfg.SignatureCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
 	mux := &abciMux{
-		logger:         logging.GetLogger("abci-mux"),
-		state:          state,
-		appsByName:     make(map[string]Application),
-		appsByMethod:   make(map[transaction.MethodName]Application),
-		lastBeginBlock: -1,
+		logger:              logging.GetLogger("abci-mux"),
+		state:               state,
+		sigCache:            sigCache,
+		appsByName:          make(map[string]Application),
+		appsByMethod:        make(map[transaction.MethodName]Application),
+		lastBeginBlock:      -1,
+		mempoolTTL:          int64(cfg.MempoolTTL),
+		recheckGraceBlocks:  int64(cfg.RecheckGraceBlocks),
+		appSoftDeadline:     cfg.AppSoftDeadline,
+		appHardDeadline:     cfg.AppHardDeadline,
+		blockEventsNotifier: pubsub.NewBroker(false),
 	}
 
 	mux.logger.Debug("ABCI multiplexer initialized",
@@ -946,7 +1815,13 @@ type ApplicationState struct {
 	haltMode        bool
 	haltEpochHeight epochtime.EpochTime
 
-	minGasPrice quantity.Quantity
+	minGasPrice          quantity.Quantity
+	minGasPricePerMethod map[transaction.MethodName]quantity.Quantity
+
+	maxCheckpointDepth int
+
+	gasCostsLock sync.RWMutex
+	gasCosts     map[string]transaction.Costs
 
 	metricsCloseCh  chan struct{}
 	metricsClosedCh chan struct{}
@@ -1047,6 +1922,59 @@ func (s *ApplicationState) EpochChanged(ctx *Context) (bool, epochtime.EpochTime
 	return true, currentEpoch
 }
 
+// GenesisDigest returns the digest of the genesis InitChain request that
+// the state was initialized with or nil if there are no committed blocks.
+func (s *ApplicationState) GenesisDigest() []byte {
+	blockHeight := s.BlockHeight()
+	if blockHeight == 0 {
+		return nil
+	}
+
+	tree, err := s.deliverTxTree.GetImmutable(blockHeight)
+	if err != nil {
+		s.logger.Error("GenesisDigest: failed to get state",
+			"err", err,
+			"block_height", blockHeight,
+		)
+		return nil
+	}
+	_, digest := tree.Get([]byte(stateKeyGenesisDigest))
+	return digest
+}
+
+// IterateRange iterates over all key/value pairs with the given prefix in
+// ascending key order, as of the last committed block. Iteration stops
+// once fn returns false.
+//
+// The iteration is performed on an immutable snapshot of the state, so it
+// is safe to use concurrently with block processing.
+func (s *ApplicationState) IterateRange(prefix []byte, fn func(key, value []byte) bool) error {
+	blockHeight := s.BlockHeight()
+	if blockHeight == 0 {
+		return consensus.ErrNoCommittedBlocks
+	}
+
+	tree, err := s.deliverTxTree.GetImmutable(blockHeight)
+	if err != nil {
+		return err
+	}
+	tree.IterateRange(prefix, nil, true, func(key, value []byte) bool {
+		if !bytes.HasPrefix(key, prefix) {
+			return true
+		}
+		return !fn(key, value)
+	})
+	return nil
+}
+
+// GenesisDigest computes the digest of a genesis InitChain request.
+func GenesisDigest(req types.RequestInitChain) []byte {
+	tmp := bytes.NewBuffer(nil)
+	_ = types.WriteMessage(&req, tmp)
+	digest := sha512.Sum512_256(tmp.Bytes())
+	return digest[:]
+}
+
 // Genesis returns the ABCI genesis state.
 func (s *ApplicationState) Genesis() *genesis.Document {
 	_, b := s.checkTxTree.Get([]byte(stateKeyGenesisRequest))
@@ -1071,9 +1999,67 @@ func (s *ApplicationState) Genesis() *genesis.Document {
 	return st
 }
 
-// MinGasPrice returns the configured minimum gas price.
-func (s *ApplicationState) MinGasPrice() *quantity.Quantity {
-	return &s.minGasPrice
+// MinGasPrice returns the configured minimum gas price for the given method,
+// falling back to the global minimum gas price if none is configured.
+func (s *ApplicationState) MinGasPrice(method transaction.MethodName) *quantity.Quantity {
+	if q, ok := s.minGasPricePerMethod[method]; ok {
+		return q.Clone()
+	}
+
+	s.blockLock.RLock()
+	defer s.blockLock.RUnlock()
+
+	return s.minGasPrice.Clone()
+}
+
+// SetMinGasPrice replaces the global minimum gas price.
+//
+// The minimum gas price is only enforced during local mempool admission
+// (CheckTx) and never affects consensus validity (DeliverTx), so it can be
+// changed at runtime and may differ between nodes. Per-method minimum gas
+// prices keep overriding the global minimum gas price.
+func (s *ApplicationState) SetMinGasPrice(q *quantity.Quantity) {
+	s.blockLock.Lock()
+	defer s.blockLock.Unlock()
+
+	s.minGasPrice = *q.Clone()
+}
+
+// MinGasPrices returns the configured global minimum gas price and the
+// per-method minimum gas prices.
+func (s *ApplicationState) MinGasPrices() (*quantity.Quantity, map[transaction.MethodName]quantity.Quantity) {
+	var perMethod map[transaction.MethodName]quantity.Quantity
+	if len(s.minGasPricePerMethod) > 0 {
+		perMethod = make(map[transaction.MethodName]quantity.Quantity)
+	}
+	for method, q := range s.minGasPricePerMethod {
+		perMethod[method] = *q.Clone()
+	}
+
+	s.blockLock.RLock()
+	defer s.blockLock.RUnlock()
+
+	return s.minGasPrice.Clone(), perMethod
+}
+
+// GasCosts returns the consensus gas costs of all applications, keyed by
+// module name.
+//
+// The gas costs are updated at block boundaries, so changes to the gas
+// costs made while processing a block only become visible after the block
+// has been committed.
+func (s *ApplicationState) GasCosts() map[string]transaction.Costs {
+	s.gasCostsLock.RLock()
+	defer s.gasCostsLock.RUnlock()
+
+	gasCosts := make(map[string]transaction.Costs)
+	for module, costs := range s.gasCosts {
+		gasCosts[module] = make(transaction.Costs)
+		for op, gas := range costs {
+			gasCosts[module][op] = gas
+		}
+	}
+	return gasCosts
 }
 
 func (s *ApplicationState) doCommit(now time.Time) error {
@@ -1166,6 +2152,34 @@ func (s *ApplicationState) metricsWorker() {
 	}
 }
 
+// ensureConsistentTrees checks that the CheckTx tree is at the same version
+// as the authoritative DeliverTx tree. In case the trees are inconsistent
+// and recovery is enabled, the CheckTx tree (which is never persisted and
+// can always be reconstructed) is reloaded from the DeliverTx tree version.
+func ensureConsistentTrees(logger *logging.Logger, deliverTxTree, checkTxTree *iavl.MutableTree, recover bool) error {
+	blockHeight, blockHash := deliverTxTree.Version(), deliverTxTree.Hash()
+	if blockHeight == checkTxTree.Version() && bytes.Equal(blockHash, checkTxTree.Hash()) {
+		return nil
+	}
+	if !recover {
+		return fmt.Errorf("state: inconsistent trees")
+	}
+
+	logger.Warn("inconsistent trees, reloading CheckTx tree",
+		"block_height", blockHeight,
+		"block_hash", hex.EncodeToString(blockHash),
+		"check_tx_height", checkTxTree.Version(),
+		"check_tx_hash", hex.EncodeToString(checkTxTree.Hash()),
+	)
+	if _, err := checkTxTree.LoadVersion(blockHeight); err != nil {
+		return fmt.Errorf("state: failed to reload CheckTx tree: %w", err)
+	}
+	if blockHeight != checkTxTree.Version() || !bytes.Equal(blockHash, checkTxTree.Hash()) {
+		return fmt.Errorf("state: inconsistent trees after recovery")
+	}
+	return nil
+}
+
 func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*ApplicationState, error) {
 	db, err := db.New(filepath.Join(cfg.DataDir, "abci-mux-state"), false)
 	if err != nil {
@@ -1174,7 +2188,11 @@ func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*Applicat
 
 	// Figure out the latest version/hash if any, and use that
 	// as the block height/hash.
-	deliverTxTree := iavl.NewMutableTree(db, 128)
+	cacheSize := cfg.StateCacheSize
+	if cacheSize <= 0 {
+		cacheSize = DefaultStateCacheSize
+	}
+	deliverTxTree := iavl.NewMutableTree(db, cacheSize)
 	blockHeight, err := deliverTxTree.Load()
 	if err != nil {
 		db.Close()
@@ -1182,16 +2200,16 @@ func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*Applicat
 	}
 	blockHash := deliverTxTree.Hash()
 
-	checkTxTree := iavl.NewMutableTree(db, 128)
-	checkTxBlockHeight, err := checkTxTree.Load()
-	if err != nil {
+	checkTxTree := iavl.NewMutableTree(db, cacheSize)
+	if _, err = checkTxTree.Load(); err != nil {
 		db.Close()
 		return nil, err
 	}
 
-	if blockHeight != checkTxBlockHeight || !bytes.Equal(blockHash, checkTxTree.Hash()) {
+	logger := logging.GetLogger("abci-mux/state")
+	if err = ensureConsistentTrees(logger, deliverTxTree, checkTxTree, cfg.RecoverInconsistentTrees); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("state: inconsistent trees")
+		return nil, err
 	}
 
 	statePruner, err := newStatePruner(&cfg.Pruning, deliverTxTree, blockHeight)
@@ -1204,26 +2222,78 @@ func newApplicationState(ctx context.Context, cfg *ApplicationConfig) (*Applicat
 	if err = minGasPrice.FromInt64(int64(cfg.MinGasPrice)); err != nil {
 		return nil, fmt.Errorf("state: invalid minimum gas price: %w", err)
 	}
+	minGasPricePerMethod := make(map[transaction.MethodName]quantity.Quantity)
+	for method, price := range cfg.MinGasPricePerMethod {
+		var q quantity.Quantity
+		if err = q.FromUint64(price); err != nil {
+			return nil, fmt.Errorf("state: invalid minimum gas price for method '%s': %w", method, err)
+		}
+		minGasPricePerMethod[method] = q
+	}
 
 	s := &ApplicationState{
-		logger:          logging.GetLogger("abci-mux/state"),
-		ctx:             ctx,
-		db:              db,
-		deliverTxTree:   deliverTxTree,
-		checkTxTree:     checkTxTree,
-		statePruner:     statePruner,
-		blockHash:       blockHash,
-		blockHeight:     blockHeight,
-		haltEpochHeight: cfg.HaltEpochHeight,
-		minGasPrice:     minGasPrice,
-		metricsCloseCh:  make(chan struct{}),
-		metricsClosedCh: make(chan struct{}),
+		logger:               logger,
+		ctx:                  ctx,
+		db:                   db,
+		deliverTxTree:        deliverTxTree,
+		checkTxTree:          checkTxTree,
+		statePruner:          statePruner,
+		blockHash:            blockHash,
+		blockHeight:          blockHeight,
+		haltEpochHeight:      cfg.HaltEpochHeight,
+		minGasPrice:          minGasPrice,
+		minGasPricePerMethod: minGasPricePerMethod,
+		maxCheckpointDepth:   cfg.MaxCheckpointDepth,
+		metricsCloseCh:       make(chan struct{}),
+		metricsClosedCh:      make(chan struct{}),
 	}
 	go s.metricsWorker()
 
 	return s, nil
 }
 
+// MockApplicationStateConfig is the configuration for the mock application
+// state.
+type MockApplicationStateConfig struct {
+	// BlockHeight is the last committed block height.
+	BlockHeight int64
+
+	// TimeSource is the epoch time source.
+	TimeSource epochtime.Backend
+
+	// MinGasPrice is the global minimum gas price.
+	MinGasPrice *quantity.Quantity
+	// MinGasPricePerMethod are the per-method minimum gas prices.
+	MinGasPricePerMethod map[transaction.MethodName]quantity.Quantity
+
+	// MaxCheckpointDepth is the maximum state checkpoint depth.
+	MaxCheckpointDepth int
+}
+
+// NewMockApplicationState creates a new in-memory application state for
+// use in tests.
+func NewMockApplicationState(cfg MockApplicationStateConfig) *ApplicationState {
+	db := dbm.NewMemDB()
+
+	s := &ApplicationState{
+		logger:               logging.GetLogger("abci-mux/state"),
+		ctx:                  context.Background(),
+		db:                   db,
+		deliverTxTree:        iavl.NewMutableTree(db, 128),
+		checkTxTree:          iavl.NewMutableTree(db, 128),
+		blockHeight:          cfg.BlockHeight,
+		statePruner:          &nonePruner{},
+		blockCtx:             NewBlockContext(),
+		timeSource:           cfg.TimeSource,
+		minGasPricePerMethod: cfg.MinGasPricePerMethod,
+		maxCheckpointDepth:   cfg.MaxCheckpointDepth,
+	}
+	if cfg.MinGasPrice != nil {
+		s.minGasPrice = *cfg.MinGasPrice.Clone()
+	}
+	return s
+}
+
 func parseGenesisAppState(req types.RequestInitChain) (*genesis.Document, error) {
 	var st genesis.Document
 	if err := json.Unmarshal(req.AppStateBytes, &st); err != nil {
@@ -0,0 +1,1128 @@
+package abci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	consensusGenesis "github.com/oasislabs/oasis-core/go/consensus/genesis"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests/helpers"
+)
+
+type testApplication struct {
+	Application
+
+	name string
+}
+
+func (app *testApplication) Name() string {
+	return app.name
+}
+
+type testDrainableApplication struct {
+	testApplication
+
+	drainCh     chan struct{}
+	drainCalled bool
+}
+
+func (app *testDrainableApplication) OnDrain() <-chan struct{} {
+	app.drainCalled = true
+	return app.drainCh
+}
+
+func TestMuxDrain(t *testing.T) {
+	require := require.New(t)
+
+	drained := &testDrainableApplication{
+		testApplication: testApplication{name: "drained"},
+		drainCh:         make(chan struct{}),
+	}
+	close(drained.drainCh)
+	stuck := &testDrainableApplication{
+		testApplication: testApplication{name: "stuck"},
+		drainCh:         make(chan struct{}),
+	}
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		appsByLexOrder: []Application{
+			drained,
+			// Applications not implementing OnDrain are skipped.
+			&testApplication{name: "plain"},
+			stuck,
+		},
+	}
+
+	start := time.Now()
+	mux.doDrain(100 * time.Millisecond)
+	require.True(drained.drainCalled, "OnDrain should be called")
+	require.True(stuck.drainCalled, "OnDrain should be called")
+	require.True(time.Since(start) >= 100*time.Millisecond, "draining should wait for stuck applications")
+	require.True(time.Since(start) < 5*time.Second, "draining should time out")
+}
+
+type commitEvent struct {
+	app         string
+	blockHeight int64
+	blockHash   []byte
+}
+
+type testCommitHookApplication struct {
+	testApplication
+
+	events *[]commitEvent
+	err    error
+}
+
+func (app *testCommitHookApplication) OnCommit(blockHeight int64, blockHash []byte) error {
+	*app.events = append(*app.events, commitEvent{app.name, blockHeight, blockHash})
+	return app.err
+}
+
+func TestMuxCommitHooks(t *testing.T) {
+	require := require.New(t)
+
+	var events []commitEvent
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state:  NewMockApplicationState(MockApplicationStateConfig{}),
+		appsByLexOrder: []Application{
+			// Errors should not prevent other hooks from firing.
+			&testCommitHookApplication{
+				testApplication: testApplication{name: "a"},
+				events:          &events,
+				err:             fmt.Errorf("post-commit hook failed"),
+			},
+			// Applications not implementing OnCommit are skipped.
+			&testApplication{name: "b"},
+			&testCommitHookApplication{
+				testApplication: testApplication{name: "c"},
+				events:          &events,
+			},
+		},
+	}
+
+	for height := int64(1); height <= 2; height++ {
+		mux.state.deliverTxTree.Set([]byte("key"), []byte(fmt.Sprintf("value:%d", height)))
+		rsp := mux.Commit()
+		require.Len(events, 2, "post-commit hooks should fire")
+		for i, name := range []string{"a", "c"} {
+			require.Equal(name, events[i].app, "post-commit hooks should fire in lex order")
+			require.Equal(height, events[i].blockHeight, "post-commit hook block height should be correct")
+			require.Equal(rsp.Data, events[i].blockHash, "post-commit hook block hash should be correct")
+			require.Equal(mux.state.BlockHash(), events[i].blockHash, "post-commit hook block hash should match state")
+		}
+		events = nil
+	}
+}
+
+type testEpochTimeSource struct {
+	epochtime.Backend
+
+	interval int64
+}
+
+func (ts *testEpochTimeSource) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
+	return epochtime.EpochTime(height / ts.interval), nil
+}
+
+type testPendingWorkApplication struct {
+	testApplication
+
+	pendingAt int64
+}
+
+func (app *testPendingWorkApplication) HasPendingWork(blockHeight int64) bool {
+	return blockHeight == app.pendingAt
+}
+
+func TestMuxNextPendingWork(t *testing.T) {
+	require := require.New(t)
+
+	app := &testPendingWorkApplication{
+		testApplication: testApplication{name: "pending"},
+		pendingAt:       -1,
+	}
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state: NewMockApplicationState(MockApplicationStateConfig{
+			TimeSource: &testEpochTimeSource{interval: 10},
+		}),
+		appsByLexOrder: []Application{
+			app,
+			// Applications not implementing HasPendingWork are skipped.
+			&testApplication{name: "plain"},
+		},
+	}
+	now := time.Unix(1000, 0)
+
+	_, ok := mux.nextPendingWork(5, now)
+	require.False(ok, "there should be no pending work")
+
+	// Epoch transitions at the next height are pending work.
+	delay, ok := mux.nextPendingWork(9, now)
+	require.True(ok, "epoch transitions should be pending work")
+	require.EqualValues(0, delay, "epoch transitions should require an immediate block")
+
+	// Applications can signal pending work at the next height.
+	app.pendingAt = 6
+	delay, ok = mux.nextPendingWork(5, now)
+	require.True(ok, "application pending work should be pending work")
+	require.EqualValues(0, delay, "application pending work should require an immediate block")
+	app.pendingAt = -1
+
+	// Armed timers are pending work once their deadline is reached.
+	ts := timerState{app: 1, kind: 1, id: []byte("timer"), deadline: 1030}
+	mux.state.deliverTxTree.Set(ts.getKey(), []byte{})
+	ts = timerState{app: 1, kind: 1, id: []byte("later timer"), deadline: 1060}
+	mux.state.deliverTxTree.Set(ts.getKey(), []byte{})
+	delay, ok = mux.nextPendingWork(5, now)
+	require.True(ok, "armed timers should be pending work")
+	require.Equal(30*time.Second, delay, "armed timers should require a block at the earliest deadline")
+
+	delay, ok = mux.nextPendingWork(5, now.Add(time.Minute))
+	require.True(ok, "expired timers should be pending work")
+	require.EqualValues(0, delay, "expired timers should require an immediate block")
+}
+
+func TestMuxMempoolTTL(t *testing.T) {
+	require := require.New(t)
+
+	mux := &abciMux{
+		logger:     logging.GetLogger("abci-mux/test"),
+		state:      NewMockApplicationState(MockApplicationStateConfig{}),
+		mempoolTTL: 2,
+	}
+	setHeight := func(height int64) {
+		mux.state.blockLock.Lock()
+		mux.state.blockHeight = height
+		mux.state.blockLock.Unlock()
+	}
+
+	tx := []byte("transaction")
+	var txHash hash.Hash
+	txHash.FromBytes(tx)
+
+	setHeight(10)
+	mux.trackMempoolTx(txHash)
+	setHeight(11)
+	mux.trackMempoolTx(txHash)
+	require.NoError(mux.checkMempoolTx(txHash), "transactions should not expire before the TTL")
+
+	// Expired transactions fail re-checks and are no longer tracked.
+	setHeight(12)
+	require.Equal(errMempoolTxExpired, mux.checkMempoolTx(txHash), "transactions should expire after the TTL")
+	rsp := mux.CheckTx(types.RequestCheckTx{Tx: tx, Type: types.CheckTxType_Recheck})
+	require.False(rsp.IsOK(), "re-check of an expired transaction should fail")
+	require.Equal(errMempoolTxExpired.Error(), rsp.Log, "re-check of an expired transaction should fail with the expiry error")
+	require.NoError(mux.checkMempoolTx(txHash), "expired transactions should no longer be tracked")
+
+	// Transactions dropped from the mempool without a failed re-check are
+	// no longer tracked once they are older than the TTL.
+	setHeight(20)
+	mux.trackMempoolTx(txHash)
+	setHeight(22)
+	mux.pruneMempoolTxs()
+	_, tracked := mux.mempoolTxs.Load(txHash)
+	require.True(tracked, "transactions should be tracked until they could have been evicted")
+	setHeight(23)
+	mux.pruneMempoolTxs()
+	_, tracked = mux.mempoolTxs.Load(txHash)
+	require.False(tracked, "stale transactions should no longer be tracked")
+
+	// Eviction can be disabled.
+	mux.mempoolTTL = 0
+	mux.trackMempoolTx(txHash)
+	setHeight(100)
+	require.NoError(mux.checkMempoolTx(txHash), "transactions should not expire with eviction disabled")
+}
+
+func TestMuxRecheckGraceWindow(t *testing.T) {
+	require := require.New(t)
+
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state: NewMockApplicationState(MockApplicationStateConfig{
+			TimeSource: &testEpochTimeSource{interval: 10},
+		}),
+		recheckGraceBlocks: 2,
+	}
+	setHeight := func(height int64) {
+		mux.state.blockLock.Lock()
+		mux.state.blockHeight = height
+		mux.state.blockLock.Unlock()
+	}
+
+	// Malformed transactions always fail to execute.
+	tx := []byte("transaction")
+	var txHash hash.Hash
+	txHash.FromBytes(tx)
+	recheck := func() (types.ResponseCheckTx, <-chan error) {
+		resultCh := make(chan error, 1)
+		mux.invalidatedTxs.Store(txHash, &invalidatedTxSubscription{
+			mux:      mux,
+			txHash:   txHash,
+			resultCh: resultCh,
+		})
+		return mux.CheckTx(types.RequestCheckTx{Tx: tx, Type: types.CheckTxType_Recheck}), resultCh
+	}
+
+	// Re-check failures within the grace window are deferred.
+	for _, height := range []int64{20, 21} {
+		setHeight(height)
+		rsp, resultCh := recheck()
+		require.True(rsp.IsOK(), "re-check failures should be deferred within the grace window (height %d)", height)
+		require.Len(resultCh, 0, "subscribers should not be notified within the grace window (height %d)", height)
+		mux.invalidatedTxs.Delete(txHash)
+	}
+
+	// Outside of the grace window, transactions are invalidated immediately.
+	setHeight(22)
+	rsp, resultCh := recheck()
+	require.False(rsp.IsOK(), "re-check failures should invalidate transactions outside the grace window")
+	require.Error(<-resultCh, "subscribers should be notified of the invalidation")
+
+	// Initial checks are never deferred.
+	setHeight(20)
+	rsp = mux.CheckTx(types.RequestCheckTx{Tx: tx, Type: types.CheckTxType_New})
+	require.False(rsp.IsOK(), "initial check failures should not be deferred")
+
+	// The grace window can be disabled.
+	mux.recheckGraceBlocks = 0
+	rsp, resultCh = recheck()
+	require.False(rsp.IsOK(), "re-check failures should invalidate transactions with the grace window disabled")
+	require.Error(<-resultCh, "subscribers should be notified of the invalidation")
+}
+
+type testStaticGasApplication struct {
+	testApplication
+
+	costs    transaction.Costs
+	static   bool
+	executed int
+}
+
+func (app *testStaticGasApplication) ExecuteTx(ctx *Context, tx *transaction.Transaction) error {
+	app.executed++
+	if ctx.IsSimulation() {
+		ctx.SetGasAccountant(NewGasAccountant(transaction.Gas(math.MaxUint64)))
+	}
+	return ctx.Gas().UseGas(1, transaction.Op(tx.Method), app.costs)
+}
+
+func (app *testStaticGasApplication) StaticGasCost(method transaction.MethodName, height int64) (transaction.Gas, bool, error) {
+	return app.costs[transaction.Op(method)], app.static, nil
+}
+
+func TestMuxEstimateGasStatic(t *testing.T) {
+	require := require.New(t)
+
+	const method = transaction.MethodName("test.Fixed")
+	app := &testStaticGasApplication{
+		testApplication: testApplication{name: "gas"},
+		costs:           transaction.Costs{transaction.Op(method): 1000},
+	}
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state:  NewMockApplicationState(MockApplicationStateConfig{}),
+		appsByMethod: map[transaction.MethodName]Application{
+			method: app,
+		},
+	}
+	mux.state.deliverTxTree.Set([]byte("key"), []byte("value"))
+	mux.Commit()
+
+	var caller signature.PublicKey
+	tx := &transaction.Transaction{Method: method}
+
+	// Dynamic gas costs require a full simulation.
+	simulated, err := mux.EstimateGas(caller, tx)
+	require.NoError(err, "EstimateGas")
+	require.EqualValues(1000, simulated, "simulated gas should be correct")
+	require.Equal(1, app.executed, "dynamic gas costs should be simulated")
+
+	// Static gas costs should match the simulation without executing.
+	mux.Commit()
+	app.static = true
+	gas, err := mux.EstimateGas(caller, tx)
+	require.NoError(err, "EstimateGas")
+	require.Equal(simulated, gas, "static gas should match simulated gas")
+	require.Equal(1, app.executed, "static gas costs should not be simulated")
+
+	// Static gas costs are cached until the next block.
+	app.costs[transaction.Op(method)] = 2000
+	gas, err = mux.EstimateGas(caller, tx)
+	require.NoError(err, "EstimateGas")
+	require.EqualValues(1000, gas, "static gas costs should be cached")
+	mux.Commit()
+	gas, err = mux.EstimateGas(caller, tx)
+	require.NoError(err, "EstimateGas")
+	require.EqualValues(2000, gas, "static gas costs should be refreshed on new blocks")
+	require.Equal(1, app.executed, "static gas costs should not be simulated")
+}
+
+type testForeignApplication struct {
+	testApplication
+
+	calls *[]string
+	veto  error
+}
+
+func (app *testForeignApplication) ExecuteTx(ctx *Context, tx *transaction.Transaction) error {
+	*app.calls = append(*app.calls, app.name+".ExecuteTx")
+	ctx.State().Set([]byte(app.name), tx.Body)
+	return nil
+}
+
+func (app *testForeignApplication) ForeignExecuteTx(ctx *Context, other Application, tx *transaction.Transaction) error {
+	*app.calls = append(*app.calls, app.name+".ForeignExecuteTx")
+	return nil
+}
+
+type testForeignCheckApplication struct {
+	testForeignApplication
+}
+
+func (app *testForeignCheckApplication) ForeignCheckTx(ctx *Context, other Application, tx *transaction.Transaction) error {
+	*app.calls = append(*app.calls, app.name+".ForeignCheckTx")
+	return app.veto
+}
+
+func TestMuxForeignCheckTx(t *testing.T) {
+	require := require.New(t)
+
+	const method = transaction.MethodName("owner.Method")
+	var calls []string
+	owner := &testForeignApplication{testApplication: testApplication{name: "owner"}, calls: &calls}
+	checker := &testForeignCheckApplication{
+		testForeignApplication: testForeignApplication{testApplication: testApplication{name: "checker"}, calls: &calls},
+	}
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state:  NewMockApplicationState(MockApplicationStateConfig{}),
+		appsByMethod: map[transaction.MethodName]Application{
+			method: owner,
+		},
+		appsByLexOrder: []Application{
+			checker,
+			owner,
+			// Applications not implementing ForeignCheckTx are skipped.
+			&testForeignApplication{testApplication: testApplication{name: "plain"}, calls: &calls},
+		},
+	}
+	tx := &transaction.Transaction{Method: method, Body: []byte("value")}
+
+	// Vetoed transactions should not be executed.
+	checker.veto = fmt.Errorf("vetoed")
+	ctx := NewContext(ContextDeliverTx, time.Now(), mux.state)
+	err := mux.processTx(ctx, tx)
+	require.Equal(checker.veto, err, "processTx should fail with the veto error")
+	require.Equal([]string{"checker.ForeignCheckTx"}, calls, "vetoed transactions should not be executed")
+	_, value := ctx.State().Get([]byte("owner"))
+	require.Nil(value, "vetoed transactions should not mutate state")
+	ctx.Close()
+
+	// Foreign checks run before execution and post-tx hooks after it.
+	calls = nil
+	checker.veto = nil
+	ctx = NewContext(ContextDeliverTx, time.Now(), mux.state)
+	defer ctx.Close()
+	err = mux.processTx(ctx, tx)
+	require.NoError(err, "processTx")
+	require.Equal([]string{
+		"checker.ForeignCheckTx",
+		"owner.ExecuteTx",
+		"checker.ForeignExecuteTx",
+		"plain.ForeignExecuteTx",
+	}, calls, "transaction processing phases should run in order")
+	_, value = ctx.State().Get([]byte("owner"))
+	require.EqualValues(tx.Body, value, "executed transactions should mutate state")
+}
+
+type testGasCostsApplication struct {
+	testApplication
+
+	state *ApplicationState
+}
+
+func (app *testGasCostsApplication) GasCosts(state *ImmutableState) (string, transaction.Costs, error) {
+	_, raw := state.Snapshot.Get([]byte("gas_costs"))
+	if raw == nil {
+		return "", nil, fmt.Errorf("gas costs not present")
+	}
+
+	var costs transaction.Costs
+	if err := cbor.Unmarshal(raw, &costs); err != nil {
+		return "", nil, err
+	}
+	return app.name, costs, nil
+}
+
+func (app *testGasCostsApplication) ExecuteTx(ctx *Context, tx *transaction.Transaction) error {
+	return ctx.Gas().UseGas(1, transaction.Op(tx.Method), app.state.GasCosts()[app.name])
+}
+
+func TestMuxGasCosts(t *testing.T) {
+	require := require.New(t)
+
+	const op = transaction.Op("test.Charged")
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state:  NewMockApplicationState(MockApplicationStateConfig{}),
+	}
+	app := &testGasCostsApplication{
+		testApplication: testApplication{name: "gas"},
+		state:           mux.state,
+	}
+	mux.appsByLexOrder = []Application{
+		app,
+		// Applications not implementing GasCosts are skipped.
+		&testApplication{name: "plain"},
+	}
+	setGasCosts := func(gas transaction.Gas) {
+		mux.state.deliverTxTree.Set([]byte("gas_costs"), cbor.Marshal(transaction.Costs{op: gas}))
+	}
+	chargedGas := func() transaction.Gas {
+		ctx := NewContext(ContextDeliverTx, time.Now(), mux.state)
+		defer ctx.Close()
+		ctx.SetGasAccountant(NewGasAccountant(1000))
+
+		err := app.ExecuteTx(ctx, &transaction.Transaction{Method: transaction.MethodName(op)})
+		require.NoError(err, "ExecuteTx")
+		return ctx.Gas().GasUsed()
+	}
+
+	require.Empty(mux.state.GasCosts(), "there should be no gas costs before the first commit")
+
+	setGasCosts(10)
+	mux.Commit()
+	require.Equal(map[string]transaction.Costs{"gas": {op: 10}}, mux.state.GasCosts(), "gas costs should be exposed after commit")
+	require.EqualValues(10, chargedGas(), "applications should charge the shared gas costs")
+
+	// Returned gas costs are copies.
+	mux.state.GasCosts()["gas"][op] = 100
+	require.EqualValues(10, chargedGas(), "modifying returned gas costs should have no effect")
+
+	// Gas cost changes only take effect at block boundaries.
+	setGasCosts(20)
+	require.EqualValues(10, chargedGas(), "gas cost changes should not take effect before commit")
+	mux.Commit()
+	require.EqualValues(20, chargedGas(), "gas cost changes should take effect after commit")
+
+	// Failing to determine the gas costs should be fatal.
+	mux.state.deliverTxTree.Remove([]byte("gas_costs"))
+	require.Panics(func() { mux.Commit() }, "Commit should panic when gas costs are missing")
+}
+
+func TestMuxMaxTxPerBlock(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	const method = transaction.MethodName("owner.Method")
+	var calls []string
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state:  NewMockApplicationState(MockApplicationStateConfig{}),
+		appsByMethod: map[transaction.MethodName]Application{
+			method: &testForeignApplication{testApplication: testApplication{name: "owner"}, calls: &calls},
+		},
+		maxTxPerBlock: 2,
+	}
+	sigTx, err := transaction.Sign(memorySigner.NewTestSigner("abci mux max tx per block test"), &transaction.Transaction{
+		Method: method,
+		Body:   cbor.Marshal("value"),
+	})
+	require.NoError(err, "Sign")
+	tx := cbor.Marshal(sigTx)
+
+	for block := 0; block < 2; block++ {
+		// The transaction count is reset for every block.
+		mux.state.blockCtx = NewBlockContext()
+
+		for i := 0; i < 2; i++ {
+			rsp := mux.DeliverTx(types.RequestDeliverTx{Tx: tx})
+			require.True(rsp.IsOK(), "transactions up to the maximum should be accepted (block %d): %s", block, rsp.Log)
+		}
+		rsp := mux.DeliverTx(types.RequestDeliverTx{Tx: tx})
+		require.False(rsp.IsOK(), "transactions over the maximum should be rejected (block %d)", block)
+		require.Equal(errTooManyTxs.Error(), rsp.Log, "transactions over the maximum should fail with the too many transactions error")
+	}
+
+	// Zero disables the limit.
+	mux.maxTxPerBlock = 0
+	mux.state.blockCtx = NewBlockContext()
+	for i := 0; i < 10; i++ {
+		rsp := mux.DeliverTx(types.RequestDeliverTx{Tx: tx})
+		require.True(rsp.IsOK(), "transactions should be accepted with the limit disabled: %s", rsp.Log)
+	}
+}
+
+func TestMuxDecodeTxSignatureScheme(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state:  NewMockApplicationState(MockApplicationStateConfig{}),
+	}
+	ctx := NewContext(ContextCheckTx, time.Now(), mux.state)
+	defer ctx.Close()
+
+	sigTx, err := transaction.Sign(memorySigner.NewTestSigner("abci mux signature scheme test"), &transaction.Transaction{
+		Method: transaction.MethodName("owner.Method"),
+		Body:   cbor.Marshal("value"),
+	})
+	require.NoError(err, "Sign")
+	require.Equal(transaction.SignatureSchemeDefault, sigTx.Scheme, "transactions should be signed using the default scheme")
+
+	// The default scheme should not change the transaction encoding.
+	rawTx := cbor.Marshal(sigTx)
+	require.Equal(cbor.Marshal(&sigTx.Signed), rawTx, "default scheme should not be encoded")
+
+	tx, decodedSigTx, err := mux.decodeTx(ctx, rawTx)
+	require.NoError(err, "decodeTx should accept the default scheme")
+	require.Equal(transaction.SignatureSchemeDefault, decodedSigTx.Scheme, "decoded scheme should be the default scheme")
+	require.EqualValues("owner.Method", tx.Method, "decoded transaction should match")
+
+	// Unknown schemes should be rejected.
+	sigTx.Scheme = transaction.SignatureScheme(42)
+	_, _, err = mux.decodeTx(ctx, cbor.Marshal(sigTx))
+	require.True(errors.Is(err, transaction.ErrUnsupportedSignatureScheme), "decodeTx should reject unknown schemes")
+
+	var decoded transaction.Transaction
+	err = sigTx.Open(&decoded)
+	require.Equal(transaction.ErrUnsupportedSignatureScheme, err, "Open should reject unknown schemes")
+}
+
+func newSignatureCacheTestMux(t testing.TB, size uint64) *abciMux {
+	sigCache, err := newSigCache(size)
+	require.NoError(t, err, "newSigCache")
+
+	const method = transaction.MethodName("owner.Method")
+	var calls []string
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state:  NewMockApplicationState(MockApplicationStateConfig{}),
+		appsByMethod: map[transaction.MethodName]Application{
+			method: &testForeignApplication{testApplication: testApplication{name: "owner"}, calls: &calls},
+		},
+		sigCache: sigCache,
+	}
+	mux.state.blockCtx = NewBlockContext()
+	return mux
+}
+
+func TestMuxSignatureCache(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	mux := newSignatureCacheTestMux(t, 16)
+	signer := memorySigner.NewTestSigner("abci mux signature cache test")
+	sigTx, err := transaction.Sign(signer, &transaction.Transaction{
+		Method: transaction.MethodName("owner.Method"),
+		Body:   cbor.Marshal("value"),
+	})
+	require.NoError(err, "Sign")
+	rawTx := cbor.Marshal(sigTx)
+	var txHash hash.Hash
+	txHash.FromBytes(rawTx)
+
+	// Transactions verified in CheckTx should not be verified again.
+	hits := testutil.ToFloat64(abciSignatureCacheHits)
+	rsp := mux.CheckTx(types.RequestCheckTx{Tx: rawTx, Type: types.CheckTxType_New})
+	require.True(rsp.IsOK(), "CheckTx: %s", rsp.Log)
+	require.True(mux.sigCache.isVerified(txHash, rawTx), "checked transactions should be cached")
+	drsp := mux.DeliverTx(types.RequestDeliverTx{Tx: rawTx})
+	require.True(drsp.IsOK(), "DeliverTx: %s", drsp.Log)
+	require.Equal(hits+1, testutil.ToFloat64(abciSignatureCacheHits), "DeliverTx should skip verification")
+	require.False(mux.sigCache.isVerified(txHash, rawTx), "delivered transactions should be removed from the cache")
+
+	// Tampering with the signature or the body of a checked transaction
+	// must still be detected.
+	rsp = mux.CheckTx(types.RequestCheckTx{Tx: rawTx, Type: types.CheckTxType_New})
+	require.True(rsp.IsOK(), "CheckTx: %s", rsp.Log)
+
+	tamperedSig := *sigTx
+	tamperedSig.Signature.Signature[0] ^= 0xff
+	drsp = mux.DeliverTx(types.RequestDeliverTx{Tx: cbor.Marshal(&tamperedSig)})
+	require.False(drsp.IsOK(), "transactions with a tampered signature should be rejected")
+
+	tamperedBody := *sigTx
+	tamperedBody.Blob = cbor.Marshal(&transaction.Transaction{
+		Method: transaction.MethodName("owner.Method"),
+		Body:   cbor.Marshal("tampered"),
+	})
+	drsp = mux.DeliverTx(types.RequestDeliverTx{Tx: cbor.Marshal(&tamperedBody)})
+	require.False(drsp.IsOK(), "transactions with a tampered body should be rejected")
+
+	// Even on a (forced) hash collision, the raw transaction must match.
+	tamperedTx := cbor.Marshal(&tamperedBody)
+	var tamperedHash hash.Hash
+	tamperedHash.FromBytes(tamperedTx)
+	mux.sigCache.markVerified(tamperedHash, rawTx)
+	drsp = mux.DeliverTx(types.RequestDeliverTx{Tx: tamperedTx})
+	require.False(drsp.IsOK(), "cached entries should only match identical transactions")
+
+	// Transactions that were never checked should still be verified.
+	mux = newSignatureCacheTestMux(t, 0)
+	require.Nil(mux.sigCache, "zero size should disable the cache")
+	hits = testutil.ToFloat64(abciSignatureCacheHits)
+	rsp = mux.CheckTx(types.RequestCheckTx{Tx: rawTx, Type: types.CheckTxType_New})
+	require.True(rsp.IsOK(), "CheckTx: %s", rsp.Log)
+	drsp = mux.DeliverTx(types.RequestDeliverTx{Tx: rawTx})
+	require.True(drsp.IsOK(), "DeliverTx: %s", drsp.Log)
+	drsp = mux.DeliverTx(types.RequestDeliverTx{Tx: cbor.Marshal(&tamperedSig)})
+	require.False(drsp.IsOK(), "transactions with a tampered signature should be rejected")
+	require.Equal(hits, testutil.ToFloat64(abciSignatureCacheHits), "verification should not be skipped")
+}
+
+func benchmarkSignatureCache(b *testing.B, size uint64) {
+	genesisTestHelpers.SetTestChainContext()
+
+	mux := newSignatureCacheTestMux(b, size)
+	signer := memorySigner.NewTestSigner("abci mux signature cache benchmark")
+	txs := make([][]byte, 256)
+	for i := range txs {
+		sigTx, err := transaction.Sign(signer, &transaction.Transaction{
+			Nonce:  uint64(i),
+			Method: transaction.MethodName("owner.Method"),
+			Body:   cbor.Marshal("value"),
+		})
+		if err != nil {
+			b.Fatalf("Sign: %s", err)
+		}
+		txs[i] = cbor.Marshal(sigTx)
+	}
+
+	hits := testutil.ToFloat64(abciSignatureCacheHits)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Emulate a transaction being checked on submission and later
+		// delivered in a block.
+		tx := txs[i%len(txs)]
+		if rsp := mux.CheckTx(types.RequestCheckTx{Tx: tx, Type: types.CheckTxType_New}); !rsp.IsOK() {
+			b.Fatalf("CheckTx: %s", rsp.Log)
+		}
+		if rsp := mux.DeliverTx(types.RequestDeliverTx{Tx: tx}); !rsp.IsOK() {
+			b.Fatalf("DeliverTx: %s", rsp.Log)
+		}
+	}
+	b.StopTimer()
+	skipped := testutil.ToFloat64(abciSignatureCacheHits) - hits
+	b.ReportMetric((float64(2*b.N)-skipped)/float64(b.N), "verifications/op")
+}
+
+func BenchmarkSignatureCacheDisabled(b *testing.B) {
+	benchmarkSignatureCache(b, 0)
+}
+
+func BenchmarkSignatureCacheEnabled(b *testing.B) {
+	benchmarkSignatureCache(b, 1024)
+}
+
+func TestMuxAppDeadline(t *testing.T) {
+	require := require.New(t)
+
+	mux := &abciMux{
+		logger:          logging.GetLogger("abci-mux/test"),
+		appSoftDeadline: 10 * time.Millisecond,
+		appHardDeadline: time.Minute,
+	}
+	slowApp := &testApplication{name: "slow"}
+	exceeded := abciAppDeadlineExceeded.WithLabelValues("slow", "EndBlock")
+	before := testutil.ToFloat64(exceeded)
+
+	// Fast calls should not trigger the warning.
+	var called bool
+	mux.withAppDeadline(slowApp, "EndBlock", func() {
+		called = true
+	})
+	require.True(called, "the application method should be called")
+	require.Equal(before, testutil.ToFloat64(exceeded), "fast calls should not exceed the soft deadline")
+
+	// Slow calls should trigger the warning.
+	mux.withAppDeadline(slowApp, "EndBlock", func() {
+		time.Sleep(2 * mux.appSoftDeadline)
+	})
+	require.Equal(before+1, testutil.ToFloat64(exceeded), "slow calls should exceed the soft deadline")
+	require.Equal(0.0, testutil.ToFloat64(abciAppDeadlineExceeded.WithLabelValues("slow", "BeginBlock")), "other methods should not be affected")
+}
+
+type testEventsApplication struct {
+	testApplication
+}
+
+func (app *testEventsApplication) Blessed() bool {
+	return false
+}
+
+func (app *testEventsApplication) emit(ctx *Context, value string) {
+	ctx.EmitEvent(api.NewEventBuilder(app.name).Attribute([]byte("phase"), []byte(value)))
+}
+
+func (app *testEventsApplication) BeginBlock(ctx *Context, req types.RequestBeginBlock) error {
+	app.emit(ctx, "BeginBlock")
+	return nil
+}
+
+func (app *testEventsApplication) ExecuteTx(ctx *Context, tx *transaction.Transaction) error {
+	app.emit(ctx, "DeliverTx")
+	return nil
+}
+
+func (app *testEventsApplication) EndBlock(ctx *Context, req types.RequestEndBlock) (types.ResponseEndBlock, error) {
+	app.emit(ctx, "EndBlock")
+	return types.ResponseEndBlock{}, nil
+}
+
+func TestMuxBlockEvents(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	const method = transaction.MethodName("events.Method")
+	app := &testEventsApplication{testApplication{name: "events"}}
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state: NewMockApplicationState(MockApplicationStateConfig{
+			TimeSource: &testEpochTimeSource{interval: 10},
+		}),
+		appsByLexOrder:      []Application{app},
+		appsByMethod:        map[transaction.MethodName]Application{method: app},
+		blockEventsNotifier: pubsub.NewBroker(false),
+	}
+	mux.state.haltEpochHeight = epochtime.EpochInvalid
+	mux.lastBeginBlock = -1
+	ch, sub := mux.watchBlockEvents()
+	defer sub.Close()
+
+	// InitChain events are stored in state until the first block.
+	initEvent := api.NewEventBuilder(app.name).Attribute([]byte("phase"), []byte("InitChain")).Event()
+	mux.state.deliverTxTree.Set([]byte(stateKeyInitChainEvents), cbor.Marshal([]types.Event{initEvent}))
+
+	sigTx, err := transaction.Sign(memorySigner.NewTestSigner("abci mux block events test"), &transaction.Transaction{
+		Method: method,
+	})
+	require.NoError(err, "Sign")
+	tx := cbor.Marshal(sigTx)
+	var txHash hash.Hash
+	txHash.FromBytes(tx)
+
+	mux.BeginBlock(types.RequestBeginBlock{Header: types.Header{Height: 1}})
+	rsp := mux.DeliverTx(types.RequestDeliverTx{Tx: tx})
+	require.True(rsp.IsOK(), "DeliverTx should succeed: %s", rsp.Log)
+	mux.EndBlock(types.RequestEndBlock{Height: 1})
+	mux.Commit()
+
+	recvBlockEvents := func() *BlockEvents {
+		select {
+		case ev := <-ch:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatalf("failed to receive block events")
+			return nil
+		}
+	}
+
+	// InitChain events should be reported at height 0.
+	blockEvents := recvBlockEvents()
+	require.EqualValues(0, blockEvents.Height, "InitChain events should be reported at height 0")
+	require.Len(blockEvents.Events, 1, "InitChain events should be reported")
+	require.Equal(EventPhaseInitChain, blockEvents.Events[0].Phase, "InitChain event phase should be correct")
+	require.Equal(app.name, blockEvents.Events[0].App, "InitChain event source should be correct")
+	require.Equal(initEvent, blockEvents.Events[0].Event, "InitChain event should be correct")
+
+	// Block events should be reported in emission order.
+	blockEvents = recvBlockEvents()
+	require.EqualValues(1, blockEvents.Height, "block events should be reported at the block height")
+	require.Len(blockEvents.Events, 4, "all block events should be reported")
+	for i, expected := range []struct {
+		phase  EventPhase
+		app    string
+		txHash *hash.Hash
+	}{
+		{EventPhaseBeginBlock, app.name, nil},
+		{EventPhaseDeliverTx, app.name, &txHash},
+		{EventPhaseDeliverTx, "", &txHash},
+		{EventPhaseEndBlock, app.name, nil},
+	} {
+		ev := blockEvents.Events[i]
+		require.Equal(expected.phase, ev.Phase, "event %d phase should be correct", i)
+		require.Equal(expected.app, ev.App, "event %d source should be correct", i)
+		require.Equal(expected.txHash, ev.TxHash, "event %d transaction hash should be correct", i)
+		if expected.app != "" {
+			require.EqualValues(expected.phase.String(), ev.Event.Attributes[0].Value, "event %d should be emitted in the correct phase", i)
+		}
+	}
+}
+
+func TestApplicationStateIterateRange(t *testing.T) {
+	require := require.New(t)
+
+	state := NewMockApplicationState(MockApplicationStateConfig{})
+	err := state.IterateRange([]byte("prefix/"), func(key, value []byte) bool { return true })
+	require.Equal(consensus.ErrNoCommittedBlocks, err, "IterateRange should fail without committed blocks")
+
+	for _, key := range []string{"prefix/b", "other", "prefix/a", "prefix0", "prefix/c", "prefiy"} {
+		state.deliverTxTree.Set([]byte(key), []byte("value of "+key))
+	}
+	err = state.doCommit(time.Now())
+	require.NoError(err, "doCommit")
+
+	// Uncommitted changes should not be visible.
+	state.deliverTxTree.Set([]byte("prefix/uncommitted"), []byte("value"))
+
+	dump := make(map[string]string)
+	var keys []string
+	err = state.IterateRange([]byte("prefix/"), func(key, value []byte) bool {
+		keys = append(keys, string(key))
+		dump[string(key)] = string(value)
+		return true
+	})
+	require.NoError(err, "IterateRange")
+	require.Equal([]string{"prefix/a", "prefix/b", "prefix/c"}, keys, "all keys with the prefix should be iterated in order")
+	for _, key := range keys {
+		require.Equal("value of "+key, dump[key], "values should match")
+	}
+
+	// Iteration stops once the callback returns false.
+	keys = nil
+	err = state.IterateRange([]byte("prefix/"), func(key, value []byte) bool {
+		keys = append(keys, string(key))
+		return len(keys) < 2
+	})
+	require.NoError(err, "IterateRange")
+	require.Equal([]string{"prefix/a", "prefix/b"}, keys, "iteration should stop once the callback returns false")
+}
+
+func TestApplicationServerQueryWithProof(t *testing.T) {
+	require := require.New(t)
+
+	server := &ApplicationServer{
+		mux: &abciMux{
+			logger: logging.GetLogger("abci-mux/test"),
+			state:  NewMockApplicationState(MockApplicationStateConfig{}),
+		},
+	}
+	state := server.mux.state
+	_, err := server.QueryWithProof([]byte("key"), 0)
+	require.Equal(consensus.ErrNoCommittedBlocks, err, "QueryWithProof should fail without committed blocks")
+
+	state.deliverTxTree.Set([]byte("key"), []byte("value 1"))
+	state.deliverTxTree.Set([]byte("other"), []byte("other value"))
+	err = state.doCommit(time.Now())
+	require.NoError(err, "doCommit")
+	rootHash1 := state.BlockHash()
+
+	state.deliverTxTree.Set([]byte("key"), []byte("value 2"))
+	err = state.doCommit(time.Now())
+	require.NoError(err, "doCommit")
+	rootHash2 := state.BlockHash()
+
+	// Queries at the latest height should verify against the latest
+	// application hash.
+	pv, err := server.QueryWithProof([]byte("key"), 0)
+	require.NoError(err, "QueryWithProof")
+	require.EqualValues(2, pv.Height, "the latest height should be used by default")
+	require.Equal([]byte("value 2"), pv.Value, "the latest value should be returned")
+	require.NoError(pv.Verify(rootHash2), "Verify")
+	require.True(errors.Is(pv.Verify(rootHash1), ErrInvalidProof), "proofs should not verify against other application hashes")
+
+	// Queries at past heights should verify against the application hash
+	// at that height.
+	pv, err = server.QueryWithProof([]byte("key"), 1)
+	require.NoError(err, "QueryWithProof")
+	require.EqualValues(1, pv.Height, "the requested height should be used")
+	require.Equal([]byte("value 1"), pv.Value, "the value at the requested height should be returned")
+	require.NoError(pv.Verify(rootHash1), "Verify")
+
+	// Tampered values or keys must not verify.
+	tampered := *pv
+	tampered.Value = []byte("value 2")
+	require.True(errors.Is(tampered.Verify(rootHash1), ErrInvalidProof), "tampered values should not verify")
+	tampered = *pv
+	tampered.Key = []byte("other")
+	require.True(errors.Is(tampered.Verify(rootHash1), ErrInvalidProof), "tampered keys should not verify")
+
+	_, err = server.QueryWithProof([]byte("missing"), 0)
+	require.Equal(ErrKeyNotFound, err, "QueryWithProof should fail for missing keys")
+}
+
+func TestEnsureConsistentTrees(t *testing.T) {
+	require := require.New(t)
+
+	logger := logging.GetLogger("abci-mux/test")
+	db := dbm.NewMemDB()
+	deliverTxTree := iavl.NewMutableTree(db, 128)
+	for i := 0; i < 2; i++ {
+		deliverTxTree.Set([]byte("key"), []byte(fmt.Sprintf("value %d", i)))
+		_, _, err := deliverTxTree.SaveVersion()
+		require.NoError(err, "SaveVersion")
+	}
+
+	checkTxTree := iavl.NewMutableTree(db, 128)
+	_, err := checkTxTree.Load()
+	require.NoError(err, "Load")
+	err = ensureConsistentTrees(logger, deliverTxTree, checkTxTree, false)
+	require.NoError(err, "consistent trees should be accepted")
+
+	// Simulate a stale CheckTx tree.
+	_, err = checkTxTree.LoadVersion(1)
+	require.NoError(err, "LoadVersion")
+	err = ensureConsistentTrees(logger, deliverTxTree, checkTxTree, false)
+	require.Error(err, "inconsistent trees should be rejected without recovery")
+	require.EqualValues(1, checkTxTree.Version(), "CheckTx tree should not be modified without recovery")
+
+	err = ensureConsistentTrees(logger, deliverTxTree, checkTxTree, true)
+	require.NoError(err, "inconsistent trees should be recovered")
+	require.Equal(deliverTxTree.Version(), checkTxTree.Version(), "CheckTx tree version should match after recovery")
+	require.Equal(deliverTxTree.Hash(), checkTxTree.Hash(), "CheckTx tree hash should match after recovery")
+	_, value := checkTxTree.Get([]byte("key"))
+	require.Equal([]byte("value 1"), value, "CheckTx tree should contain the latest state after recovery")
+}
+
+type countingDB struct {
+	dbm.DB
+
+	gets uint64
+}
+
+func (db *countingDB) Get(key []byte) []byte {
+	db.gets++
+	return db.DB.Get(key)
+}
+
+func benchmarkStateCacheSize(b *testing.B, cacheSize int) {
+	const numKeys = 5000
+
+	db := &countingDB{DB: dbm.NewMemDB()}
+	tree := iavl.NewMutableTree(db, cacheSize)
+	for i := 0; i < numKeys; i++ {
+		tree.Set([]byte(fmt.Sprintf("key:%d", i)), []byte(fmt.Sprintf("value:%d", i)))
+	}
+	if _, _, err := tree.SaveVersion(); err != nil {
+		b.Fatalf("SaveVersion: %s", err)
+	}
+
+	// Start with a cold cache as after a restart.
+	tree = iavl.NewMutableTree(db, cacheSize)
+	if _, err := tree.Load(); err != nil {
+		b.Fatalf("Load: %s", err)
+	}
+	db.gets = 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Emulate a hot working set of state keys being read repeatedly.
+		if _, value := tree.Get([]byte(fmt.Sprintf("key:%d", i%(numKeys/2)))); value == nil {
+			b.Fatalf("missing value")
+		}
+	}
+	b.ReportMetric(float64(db.gets)/float64(b.N), "dbgets/op")
+}
+
+func BenchmarkStateCacheSizeSmall(b *testing.B) {
+	benchmarkStateCacheSize(b, 128)
+}
+
+func BenchmarkStateCacheSizeDefault(b *testing.B) {
+	benchmarkStateCacheSize(b, DefaultStateCacheSize)
+}
+
+func TestMuxReloadConsensusParameters(t *testing.T) {
+	require := require.New(t)
+
+	mux := &abciMux{
+		logger:    logging.GetLogger("abci-mux/test"),
+		state:     NewMockApplicationState(MockApplicationStateConfig{}),
+		maxTxSize: 1024,
+	}
+	tree := mux.state.deliverTxTree
+
+	// Without stored parameters, the current ones should be retained.
+	err := mux.reloadConsensusParameters(tree.ImmutableTree)
+	require.NoError(err, "reloadConsensusParameters")
+	require.EqualValues(1024, mux.maxTxSize, "parameters should be retained if none are stored")
+
+	SetConsensusParameters(tree, &consensusGenesis.Parameters{
+		MaxTxSize:     2048,
+		MaxBlockGas:   1000,
+		MaxTxPerBlock: 10,
+	})
+	err = mux.reloadConsensusParameters(tree.ImmutableTree)
+	require.NoError(err, "reloadConsensusParameters")
+	require.EqualValues(2048, mux.maxTxSize, "maximum transaction size should be updated")
+	require.EqualValues(1000, mux.maxBlockGas, "maximum block gas should be updated")
+	require.EqualValues(10, mux.maxTxPerBlock, "maximum transactions per block should be updated")
+}
+
+func TestMuxPendingWorkTx(t *testing.T) {
+	require := require.New(t)
+
+	mux := &abciMux{
+		logger: logging.GetLogger("abci-mux/test"),
+		state: NewMockApplicationState(MockApplicationStateConfig{
+			BlockHeight: 5,
+			TimeSource:  &testEpochTimeSource{interval: 10},
+		}),
+	}
+	checkCtx := NewContext(ContextCheckTx, time.Now(), mux.state)
+	defer checkCtx.Close()
+	deliverCtx := NewContext(ContextDeliverTx, time.Now(), mux.state)
+	defer deliverCtx.Close()
+
+	// Malformed pending work transactions should be rejected.
+	malformed := NewPendingWorkTx(6)
+	err := mux.executeTx(deliverCtx, malformed[:len(malformed)-1])
+	require.Equal(errMalformedWorkTx, err, "malformed pending work transactions should be rejected")
+
+	// Pending work transactions are only valid at the next height.
+	err = mux.executeTx(deliverCtx, NewPendingWorkTx(5))
+	require.Error(err, "pending work transactions for past heights should be rejected")
+	err = mux.executeTx(deliverCtx, NewPendingWorkTx(7))
+	require.Error(err, "pending work transactions for future heights should be rejected")
+
+	// There must be pending work.
+	err = mux.executeTx(checkCtx, NewPendingWorkTx(6))
+	require.Equal(errNoPendingWork, err, "pending work transactions without pending work should be rejected")
+	err = mux.executeTx(deliverCtx, NewPendingWorkTx(6))
+	require.Equal(errNoPendingWork, err, "delivered pending work transactions without pending work should be rejected")
+
+	ts := timerState{app: 1, kind: 1, id: []byte("timer"), deadline: uint64(time.Now().Add(-time.Second).Unix())}
+	mux.state.deliverTxTree.Set(ts.getKey(), []byte{})
+	err = mux.executeTx(checkCtx, NewPendingWorkTx(6))
+	require.NoError(err, "pending work transactions with pending work should be accepted")
+
+	// When delivering, the pending work must have been due at the start of
+	// the block, not just at the time the transaction is delivered.
+	err = mux.executeTx(deliverCtx, NewPendingWorkTx(6))
+	require.Equal(errNoPendingWork, err, "delivered pending work transactions should use the block pending work state")
+
+	mux.state.blockCtx.Set(blockPendingWorkKey{}, &blockPendingWork{due: mux.pendingWorkDue(5, time.Now())})
+	err = mux.executeTx(deliverCtx, NewPendingWorkTx(6))
+	require.NoError(err, "delivered pending work transactions with pending work should be accepted")
+	err = mux.executeTx(deliverCtx, NewPendingWorkTx(6))
+	require.Equal(errNoPendingWork, err, "only a single pending work transaction should be accepted per block")
+}
@@ -0,0 +1,63 @@
+package abci
+
+import (
+	"bytes"
+
+	"github.com/oasislabs/oasis-core/go/common/cache/lru"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+)
+
+// sigCache is a bounded cache of transactions whose signatures have been
+// verified during CheckTx, so that delivering the same transaction does not
+// need to verify the signature again.
+//
+// Entries are keyed by transaction hash, but a lookup only succeeds if the
+// cached raw transaction is byte-for-byte identical to the queried one, so a
+// tampered transaction can never skip verification. As signature validity
+// only depends on the raw transaction, entries never become stale. They are
+// removed once the transaction has been delivered or on eviction.
+//
+// A nil sigCache is valid and caches nothing.
+type sigCache struct {
+	verified *lru.Cache
+}
+
+func (c *sigCache) isVerified(txHash hash.Hash, rawTx []byte) bool {
+	if c == nil {
+		return false
+	}
+	v, ok := c.verified.Get(txHash)
+	if !ok {
+		return false
+	}
+	return bytes.Equal(v.([]byte), rawTx)
+}
+
+func (c *sigCache) markVerified(txHash hash.Hash, rawTx []byte) {
+	if c == nil {
+		return
+	}
+	_ = c.verified.Put(txHash, append([]byte{}, rawTx...))
+}
+
+func (c *sigCache) remove(txHash hash.Hash) {
+	if c == nil {
+		return
+	}
+	c.verified.Remove(txHash)
+}
+
+// newSigCache creates a new signature cache holding up to size verified
+// transactions. A size of zero disables caching.
+func newSigCache(size uint64) (*sigCache, error) {
+	if size == 0 {
+		return nil, nil
+	}
+
+	verified, err := lru.New(lru.Capacity(size, false))
+	if err != nil {
+		return nil, err
+	}
+
+	return &sigCache{verified: verified}, nil
+}
@@ -1,16 +1,34 @@
 package abci
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
 
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	consensusGenesis "github.com/oasislabs/oasis-core/go/consensus/genesis"
 )
 
+// stateKeyConsensusParameters is the state key used for the current consensus
+// parameters.
+//
+// Value is CBOR-serialized consensus parameters.
+const stateKeyConsensusParameters = "OasisConsensusParameters"
+
 var (
 	// ErrNoState is the error returned when state is nil.
 	ErrNoState = errors.New("tendermint: no state available (app not registered?)")
+
+	// ErrInvalidProof is the error returned when a state proof fails
+	// verification.
+	ErrInvalidProof = errors.New("tendermint: invalid state proof")
+
+	// ErrKeyNotFound is the error returned when a queried key does not exist.
+	ErrKeyNotFound = errors.New("tendermint: key not found")
 )
 
 // ImmutableState is an immutable state wrapper.
@@ -38,3 +56,83 @@ func NewImmutableState(state *ApplicationState, version int64) (*ImmutableState,
 
 	return &ImmutableState{Snapshot: snapshot}, nil
 }
+
+// GetWithProof returns the value stored under the given key together with
+// a serialized proof of its inclusion in the state. The proof can be
+// verified against the state root via VerifyProof.
+//
+// In case the key does not exist, a nil value and proof are returned.
+func (s *ImmutableState) GetWithProof(key []byte) ([]byte, []byte, error) {
+	value, proof, err := s.Snapshot.GetWithProof(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if value == nil {
+		return nil, nil, nil
+	}
+
+	return value, iavl.NewIAVLValueOp(key, proof).ProofOp().Data, nil
+}
+
+// VerifyProof verifies that the given value is stored under the given key
+// in the state with the given state root (application hash).
+func VerifyProof(stateRoot, key, value, proof []byte) error {
+	op, err := iavl.IAVLValueOpDecoder(merkle.ProofOp{
+		Type: iavl.ProofOpIAVLValue,
+		Key:  key,
+		Data: proof,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+	roots, err := op.Run([][]byte{value})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+	if !bytes.Equal(roots[0], stateRoot) {
+		return fmt.Errorf("%w: state root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// ProvenValue is a state value together with a proof of its inclusion in
+// the state at a given height.
+type ProvenValue struct {
+	// Height is the block height of the state the value was read from.
+	Height int64 `json:"height"`
+	// Key is the state key.
+	Key []byte `json:"key"`
+	// Value is the value stored under the key.
+	Value []byte `json:"value"`
+	// Proof is the serialized proof of inclusion.
+	Proof []byte `json:"proof"`
+}
+
+// Verify verifies the value against the given state root (the application
+// hash after processing the block at Height).
+func (v *ProvenValue) Verify(stateRoot []byte) error {
+	return VerifyProof(stateRoot, v.Key, v.Value, v.Proof)
+}
+
+// GetConsensusParameters returns the consensus parameters stored in the given
+// state tree.
+//
+// In case no consensus parameters have been stored, nil is returned.
+func GetConsensusParameters(tree *iavl.ImmutableTree) (*consensusGenesis.Parameters, error) {
+	_, raw := tree.Get([]byte(stateKeyConsensusParameters))
+	if raw == nil {
+		return nil, nil
+	}
+
+	var params consensusGenesis.Parameters
+	if err := cbor.Unmarshal(raw, &params); err != nil {
+		return nil, fmt.Errorf("tendermint: corrupted consensus parameters: %w", err)
+	}
+	return &params, nil
+}
+
+// SetConsensusParameters stores the consensus parameters in the given state
+// tree. The multiplexer applies them starting with the next block.
+func SetConsensusParameters(tree *iavl.MutableTree, params *consensusGenesis.Parameters) {
+	tree.Set([]byte(stateKeyConsensusParameters), cbor.Marshal(params))
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/hex"
 	"time"
 
+	"github.com/tendermint/iavl"
+
 	"github.com/oasislabs/oasis-core/go/common/keyformat"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 )
@@ -174,13 +176,58 @@ func (t *Timer) remove(ctx *Context) {
 	}
 }
 
+// PendingTimers returns all armed timers of the given application, ordered
+// by their deadline.
+func PendingTimers(ctx *Context, app Application) []*Timer {
+	var timers []*Timer
+	ctx.State().IterateRange(
+		timerKeyFmt.Encode(),
+		timerKeyFmt.Encode(deadlineDisarmed),
+		true,
+		func(key, value []byte) bool {
+			var ts timerState
+			ts.fromKeyValue(key, value)
+
+			if app.ID() == ts.app {
+				timers = append(timers, &Timer{ID: key, state: &ts})
+			}
+			return false
+		},
+	)
+	return timers
+}
+
+// nextTimerDeadline returns the deadline of the earliest armed timer, if any.
+func nextTimerDeadline(tree *iavl.MutableTree) (deadline time.Time, ok bool) {
+	tree.IterateRange(
+		timerKeyFmt.Encode(),
+		timerKeyFmt.Encode(deadlineDisarmed),
+		true,
+		func(key, value []byte) bool {
+			var ts timerState
+			ts.fromKeyValue(key, value)
+
+			deadline, ok = time.Unix(int64(ts.deadline), 0), true
+			return true
+		},
+	)
+	return
+}
+
 func fireTimers(ctx *Context, app Application) (err error) {
 	// Iterate through all timers which have already expired.
 	ctx.State().IterateRange(
 		timerKeyFmt.Encode(),
 		timerKeyFmt.Encode(uint64(ctx.Now().Unix())+1),
 		true,
-		func(key, value []byte) bool {
+		func(key, _ []byte) bool {
+			// Skip timers that have been cancelled (or rearmed) by any of
+			// the timers that fired before this one.
+			_, value := ctx.State().Get(key)
+			if value == nil {
+				return false
+			}
+
 			var ts timerState
 			ts.fromKeyValue(key, value)
 
@@ -3,8 +3,10 @@ package abci
 import (
 	"encoding/hex"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tendermint/iavl"
 )
 
 func TestTimerKey(t *testing.T) {
@@ -25,3 +27,118 @@ func TestTimerKey(t *testing.T) {
 
 	require.EqualValues(t, timer, dec, "timer state must round-trip")
 }
+
+type timerTestApplication struct {
+	testApplication
+
+	id    uint8
+	fired [][]byte
+}
+
+func (app *timerTestApplication) ID() uint8 {
+	return app.id
+}
+
+func (app *timerTestApplication) FireTimer(ctx *Context, timer *Timer) error {
+	app.fired = append(app.fired, timer.CustomID())
+	return nil
+}
+
+func TestTimerScheduleCancel(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1571157805, 0)
+	appState := NewMockApplicationState(MockApplicationStateConfig{})
+	ctx := NewContext(ContextEndBlock, now, appState)
+	defer ctx.Close()
+
+	app := &timerTestApplication{testApplication: testApplication{name: "timer test"}, id: 0x42}
+	otherApp := &timerTestApplication{testApplication: testApplication{name: "other timer test"}, id: 0x43}
+
+	// Schedule some timers.
+	first := NewTimer(ctx, app, 1, []byte("first"), nil)
+	first.Reset(ctx, 10*time.Second, nil)
+	second := NewTimer(ctx, app, 1, []byte("second"), nil)
+	second.Reset(ctx, 20*time.Second, nil)
+	cancelled := NewTimer(ctx, app, 1, []byte("cancelled"), nil)
+	cancelled.Reset(ctx, 15*time.Second, nil)
+	other := NewTimer(ctx, otherApp, 1, []byte("other"), nil)
+	other.Reset(ctx, 5*time.Second, nil)
+	disarmed := NewTimer(ctx, app, 1, []byte("disarmed"), nil)
+
+	timers := PendingTimers(ctx, app)
+	require.Len(timers, 3, "all armed timers of the application should be pending")
+	for i, id := range []string{"first", "cancelled", "second"} {
+		require.EqualValues(id, timers[i].CustomID(), "pending timers should be ordered by deadline")
+	}
+
+	// Cancel a timer before it fires.
+	cancelled.Stop(ctx)
+	require.Len(PendingTimers(ctx, app), 2, "cancelled timers should not be pending")
+	require.Len(PendingTimers(ctx, otherApp), 1, "timers of other applications should not be affected")
+
+	// Stopping disarmed or already cancelled timers should be a no-op.
+	require.NotPanics(func() { disarmed.Stop(ctx) }, "stopping a disarmed timer should not panic")
+	require.NotPanics(func() { cancelled.Stop(ctx) }, "stopping a cancelled timer should not panic")
+	require.Len(PendingTimers(ctx, app), 2, "stopping disarmed timers should not change pending timers")
+
+	// Save the state and restart.
+	_, version, err := appState.deliverTxTree.SaveVersion()
+	require.NoError(err, "SaveVersion")
+
+	restarted := NewMockApplicationState(MockApplicationStateConfig{})
+	restarted.db = appState.db
+	restarted.deliverTxTree = iavl.NewMutableTree(appState.db, 128)
+	_, err = restarted.deliverTxTree.LoadVersion(version)
+	require.NoError(err, "LoadVersion")
+
+	ctx = NewContext(ContextEndBlock, now.Add(time.Minute), restarted)
+	defer ctx.Close()
+
+	timers = PendingTimers(ctx, app)
+	require.Len(timers, 2, "pending timers should survive a restart")
+	require.EqualValues("first", timers[0].CustomID(), "pending timers should survive a restart")
+	require.EqualValues("second", timers[1].CustomID(), "pending timers should survive a restart")
+
+	err = fireTimers(ctx, app)
+	require.NoError(err, "fireTimers")
+	require.Equal([][]byte{[]byte("first"), []byte("second")}, app.fired, "only non-cancelled timers should fire")
+}
+
+type cancellingTimerTestApplication struct {
+	timerTestApplication
+
+	cancel *Timer
+}
+
+func (app *cancellingTimerTestApplication) FireTimer(ctx *Context, timer *Timer) error {
+	_ = app.timerTestApplication.FireTimer(ctx, timer)
+	app.cancel.Stop(ctx)
+	return nil
+}
+
+func TestTimerCancelWhileFiring(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1571157805, 0)
+	appState := NewMockApplicationState(MockApplicationStateConfig{})
+	ctx := NewContext(ContextEndBlock, now, appState)
+	defer ctx.Close()
+
+	app := &cancellingTimerTestApplication{
+		timerTestApplication: timerTestApplication{testApplication: testApplication{name: "timer test"}, id: 0x42},
+	}
+	first := NewTimer(ctx, app, 1, []byte("first"), nil)
+	first.Reset(ctx, 10*time.Second, nil)
+	second := NewTimer(ctx, app, 1, []byte("second"), nil)
+	second.Reset(ctx, 20*time.Second, nil)
+	app.cancel = second
+
+	// Both timers have expired, but the first one cancels the second one.
+	ctx = NewContext(ContextEndBlock, now.Add(time.Minute), appState)
+	defer ctx.Close()
+
+	err := fireTimers(ctx, app)
+	require.NoError(err, "fireTimers")
+	require.Equal([][]byte{[]byte("first")}, app.fired, "timers cancelled while firing should not fire")
+}
@@ -13,6 +13,7 @@ import (
 	tmtypes "github.com/tendermint/tendermint/types"
 
 	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
@@ -28,6 +29,15 @@ const (
 	LogEventPeerExchangeDisabled = "tendermint/peer_exchange_disabled"
 )
 
+const (
+	// EventTypeTx is the ABCI event type of the event emitted for every
+	// delivered transaction.
+	EventTypeTx = "oasis-tx"
+	// EventKeyTxHash is the EventTypeTx attribute key holding the
+	// hex-encoded transaction hash.
+	EventKeyTxHash = "hash"
+)
+
 // PublicKeyToValidatorUpdate converts an Oasis node public key to a
 // tendermint validator update.
 func PublicKeyToValidatorUpdate(id signature.PublicKey, power int64) types.ValidatorUpdate {
@@ -124,12 +134,35 @@ func EventTypeForApp(eventApp string) string {
 	return "oasis-event-" + eventApp
 }
 
+// NewTxEvent returns the event emitted for a delivered transaction with the
+// given hash.
+func NewTxEvent(txHash hash.Hash) types.Event {
+	return types.Event{
+		Type: EventTypeTx,
+		Attributes: []tmcmn.KVPair{
+			{Key: []byte(EventKeyTxHash), Value: []byte(txHash.String())},
+		},
+	}
+}
+
+// QueryForTxHash generates a transaction index query for the transaction
+// with the given hash.
+func QueryForTxHash(txHash hash.Hash) string {
+	return fmt.Sprintf("%s.%s = '%s'", EventTypeTx, EventKeyTxHash, txHash)
+}
+
 // QueryForApp generates a tmquery.Query for events belonging to the
 // specified App.
 func QueryForApp(eventApp string) tmpubsub.Query {
 	return tmquery.MustParse(fmt.Sprintf("%s EXISTS", EventTypeForApp(eventApp)))
 }
 
+// QueryForEvent generates a tmquery.Query for events with the given key
+// belonging to the specified App.
+func QueryForEvent(eventApp string, eventKey []byte) tmpubsub.Query {
+	return tmquery.MustParse(fmt.Sprintf("%s.%s EXISTS", EventTypeForApp(eventApp), eventKey))
+}
+
 // BlockMeta is the Tendermint-specific per-block metadata that is
 // exposed via the consensus API.
 type BlockMeta struct {
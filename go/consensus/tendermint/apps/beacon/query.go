@@ -14,6 +14,8 @@ type Query interface {
 	Genesis(context.Context) (*beacon.Genesis, error)
 }
 
+var _ abci.QueryFactory = (*QueryFactory)(nil)
+
 // QueryFactory is the beacon query factory.
 type QueryFactory struct {
 	app *beaconApplication
@@ -45,6 +47,11 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	return &beaconQuerier{state}, nil
 }
 
+// GenericQueryAt implements abci.QueryFactory.
+func (sf *QueryFactory) GenericQueryAt(ctx context.Context, height int64) (abci.Query, error) {
+	return sf.QueryAt(ctx, height)
+}
+
 type beaconQuerier struct {
 	state *beaconState.ImmutableState
 }
@@ -53,6 +60,6 @@ func (bq *beaconQuerier) Beacon(ctx context.Context) ([]byte, error) {
 	return bq.state.Beacon()
 }
 
-func (app *beaconApplication) QueryFactory() interface{} {
+func (app *beaconApplication) QueryFactory() abci.QueryFactory {
 	return &QueryFactory{app}
 }
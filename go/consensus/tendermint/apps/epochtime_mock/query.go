@@ -12,6 +12,8 @@ type Query interface {
 	Epoch(context.Context) (epochtime.EpochTime, int64, error)
 }
 
+var _ abci.QueryFactory = (*QueryFactory)(nil)
+
 // QueryFactory is the mock epochtime query factory.
 type QueryFactory struct {
 	app *epochTimeMockApplication
@@ -43,6 +45,11 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	return &epochtimeMockQuerier{state}, nil
 }
 
+// GenericQueryAt implements abci.QueryFactory.
+func (sf *QueryFactory) GenericQueryAt(ctx context.Context, height int64) (abci.Query, error) {
+	return sf.QueryAt(ctx, height)
+}
+
 type epochtimeMockQuerier struct {
 	state *immutableState
 }
@@ -51,6 +58,6 @@ func (eq *epochtimeMockQuerier) Epoch(ctx context.Context) (epochtime.EpochTime,
 	return eq.state.getEpoch()
 }
 
-func (app *epochTimeMockApplication) QueryFactory() interface{} {
+func (app *epochTimeMockApplication) QueryFactory() abci.QueryFactory {
 	return &QueryFactory{app}
 }
@@ -22,4 +22,14 @@ var (
 	// KeyStatusUpdate is an ABCI event attribute key for a key manager
 	// status update (value is a CBOR serialized key manager status).
 	KeyStatusUpdate = []byte("status")
+
+	// KeyStatusReinitialized is an ABCI event attribute key for a forced key
+	// manager re-initialization (value is a CBOR serialized key manager
+	// status).
+	KeyStatusReinitialized = []byte("reinitialized")
+
+	// KeyNodesDropped is an ABCI event attribute key for key manager nodes
+	// being dropped from a key manager's node list (value is a CBOR
+	// serialized list of node dropped events).
+	KeyNodesDropped = []byte("nodes_dropped")
 )
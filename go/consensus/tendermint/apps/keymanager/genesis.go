@@ -44,8 +44,10 @@ func (app *keymanagerApplication) InitChain(ctx *abci.Context, request types.Req
 		}
 	}
 
-	var toEmit []*keymanager.Status
 	state := keymanagerState.NewMutableState(ctx.State())
+	state.SetConsensusParameters(&st.Parameters)
+
+	var toEmit []*keymanager.Status
 	for _, v := range st.Statuses {
 		rt := rtMap[v.ID]
 		if rt == nil {
@@ -90,6 +92,14 @@ func (kq *keymanagerQuerier) Genesis(ctx context.Context) (*keymanager.Genesis,
 		status.Nodes = nil
 	}
 
-	gen := keymanager.Genesis{Statuses: statuses}
+	params, err := kq.state.ConsensusParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	gen := keymanager.Genesis{
+		Parameters: *params,
+		Statuses:   statuses,
+	}
 	return &gen, nil
 }
@@ -9,6 +9,7 @@ import (
 	"golang.org/x/crypto/sha3"
 
 	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
@@ -36,7 +37,7 @@ func (app *keymanagerApplication) ID() uint8 {
 }
 
 func (app *keymanagerApplication) Methods() []transaction.MethodName {
-	return nil
+	return api.Methods
 }
 
 func (app *keymanagerApplication) Blessed() bool {
@@ -61,8 +62,62 @@ func (app *keymanagerApplication) BeginBlock(ctx *abci.Context, request types.Re
 }
 
 func (app *keymanagerApplication) ExecuteTx(ctx *abci.Context, tx *transaction.Transaction) error {
-	// TODO: Add policy support.
-	return errors.New("tendermint/keymanager: transactions not supported yet")
+	state := keymanagerState.NewMutableState(ctx.State())
+
+	switch tx.Method {
+	case api.MethodUpdatePolicy:
+		var sigPol api.SignedPolicySGX
+		if err := cbor.Unmarshal(tx.Body, &sigPol); err != nil {
+			return err
+		}
+
+		return app.updatePolicy(ctx, state, &sigPol)
+	case api.MethodReinitialize:
+		var reinit api.Reinitialize
+		if err := cbor.Unmarshal(tx.Body, &reinit); err != nil {
+			return err
+		}
+
+		return app.reinitialize(ctx, state, &reinit)
+	default:
+		return api.ErrInvalidArgument
+	}
+}
+
+// staticGasOps are the gas operations of methods whose gas cost only depends
+// on the gas cost table.
+var staticGasOps = map[transaction.MethodName]transaction.Op{
+	api.MethodUpdatePolicy: api.GasOpUpdatePolicy,
+	api.MethodReinitialize: api.GasOpReinitialize,
+}
+
+// Implements abci.StaticGasApplication.
+func (app *keymanagerApplication) StaticGasCost(method transaction.MethodName, height int64) (transaction.Gas, bool, error) {
+	op, ok := staticGasOps[method]
+	if !ok {
+		return 0, false, nil
+	}
+
+	state, err := abci.NewImmutableState(app.state, height)
+	if err != nil {
+		return 0, false, err
+	}
+	_, costs, err := app.GasCosts(state)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return costs[op], true, nil
+}
+
+// Implements abci.GasCostsApplication.
+func (app *keymanagerApplication) GasCosts(state *abci.ImmutableState) (string, transaction.Costs, error) {
+	params, err := (&keymanagerState.ImmutableState{ImmutableState: state}).ConsensusParameters()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return api.ModuleName, params.GasCosts, nil
 }
 
 func (app *keymanagerApplication) ForeignExecuteTx(ctx *abci.Context, other abci.Application, tx *transaction.Transaction) error {
@@ -87,7 +142,10 @@ func (app *keymanagerApplication) onEpochChange(ctx *abci.Context, epoch epochti
 	// Recalculate all the key manager statuses.
 	//
 	// Note: This assumes that once a runtime is registered, it never expires.
-	var toEmit []*api.Status
+	var (
+		toEmit  []*api.Status
+		dropped []*api.NodeDroppedEvent
+	)
 	state := keymanagerState.NewMutableState(ctx.State())
 	for _, rt := range runtimes {
 		if rt.Kind != registry.KindKeyManager {
@@ -112,7 +170,8 @@ func (app *keymanagerApplication) onEpochChange(ctx *abci.Context, epoch epochti
 			}
 		}
 
-		newStatus := app.generateStatus(ctx, rt, oldStatus, nodes)
+		newStatus, rtDropped := app.generateStatus(ctx, rt, oldStatus, nodes)
+		dropped = append(dropped, rtDropped...)
 		if forceEmit || !bytes.Equal(cbor.Marshal(oldStatus), cbor.Marshal(newStatus)) {
 			ctx.Logger().Debug("status updated",
 				"id", newStatus.ID,
@@ -135,11 +194,22 @@ func (app *keymanagerApplication) onEpochChange(ctx *abci.Context, epoch epochti
 	if len(toEmit) > 0 {
 		ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal(toEmit)))
 	}
+	if len(dropped) > 0 {
+		ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyNodesDropped, cbor.Marshal(dropped)))
+	}
 
 	return nil
 }
 
-func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *registry.Runtime, oldStatus *api.Status, nodes []*node.Node) *api.Status {
+// generateStatus computes the new status of a key manager, along with the
+// list of previously listed nodes that were dropped from the node list.
+func (app *keymanagerApplication) generateStatus(
+	ctx *abci.Context,
+	kmrt *registry.Runtime,
+	oldStatus *api.Status,
+	nodes []*node.Node,
+) (*api.Status, []*api.NodeDroppedEvent) {
+	excluded := make(map[signature.PublicKey]string)
 	status := &api.Status{
 		ID:            kmrt.ID,
 		IsInitialized: oldStatus.IsInitialized,
@@ -181,6 +251,7 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 				"id", kmrt.ID,
 				"node_id", n.ID,
 			)
+			excluded[n.ID] = api.NodeDroppedReasonTEEMismatch
 			continue
 		}
 
@@ -191,6 +262,7 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 				"id", kmrt.ID,
 				"node_id", n.ID,
 			)
+			excluded[n.ID] = api.NodeDroppedReasonInvalidExtraInfo
 			continue
 		}
 
@@ -206,6 +278,7 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 				"id", kmrt.ID,
 				"node_id", n.ID,
 			)
+			excluded[n.ID] = api.NodeDroppedReasonPolicyChecksumMismatch
 			continue
 		}
 		if policyHash != nodePolicyHash {
@@ -213,6 +286,7 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 				"id", kmrt.ID,
 				"node_id", n.ID,
 			)
+			excluded[n.ID] = api.NodeDroppedReasonPolicyChecksumMismatch
 			continue
 		}
 
@@ -224,6 +298,7 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 					"id", kmrt.ID,
 					"node_id", n.ID,
 				)
+				excluded[n.ID] = api.NodeDroppedReasonSecurityStatusMismatch
 				continue
 			}
 			if !bytes.Equal(initResponse.Checksum, status.Checksum) {
@@ -231,6 +306,7 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 					"id", kmrt.ID,
 					"node_id", n.ID,
 				)
+				excluded[n.ID] = api.NodeDroppedReasonChecksumMismatch
 				continue
 			}
 		} else {
@@ -244,6 +320,7 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 					"id", kmrt.ID,
 					"node_id", n.ID,
 				)
+				excluded[n.ID] = api.NodeDroppedReasonSecurityStatusMismatch
 				continue
 			}
 			status.IsSecure = initResponse.IsSecure
@@ -254,7 +331,34 @@ func (app *keymanagerApplication) generateStatus(ctx *abci.Context, kmrt *regist
 		status.Nodes = append(status.Nodes, n.ID)
 	}
 
-	return status
+	return status, droppedNodes(oldStatus, status, excluded)
+}
+
+// droppedNodes returns the node dropped events for all nodes that were listed
+// in the old status, but are no longer listed in the new status.
+func droppedNodes(oldStatus, newStatus *api.Status, excluded map[signature.PublicKey]string) []*api.NodeDroppedEvent {
+	listed := make(map[signature.PublicKey]bool)
+	for _, id := range newStatus.Nodes {
+		listed[id] = true
+	}
+
+	var dropped []*api.NodeDroppedEvent
+	for _, id := range oldStatus.Nodes {
+		if listed[id] {
+			continue
+		}
+
+		reason, ok := excluded[id]
+		if !ok {
+			reason = api.NodeDroppedReasonNotRegistered
+		}
+		dropped = append(dropped, &api.NodeDroppedEvent{
+			ID:     newStatus.ID,
+			NodeID: id,
+			Reason: reason,
+		})
+	}
+	return dropped
 }
 
 // New constructs a new keymanager application instance.
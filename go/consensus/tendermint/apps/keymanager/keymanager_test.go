@@ -0,0 +1,62 @@
+package keymanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasislabs/oasis-core/go/keymanager/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+)
+
+func TestGenerateStatusDroppedNodes(t *testing.T) {
+	require := require.New(t)
+
+	id := common.NewTestNamespaceFromSeed([]byte("key manager dropped nodes test"))
+	kmrt := &registry.Runtime{
+		ID:          id,
+		Kind:        registry.KindKeyManager,
+		TEEHardware: node.TEEHardwareIntelSGX,
+	}
+
+	teeMismatchID := signature.PublicKey{1}
+	deregisteredID := signature.PublicKey{2}
+	unlistedID := signature.PublicKey{3}
+
+	// Nodes without TEE capabilities are excluded from a SGX key manager.
+	nodes := []*node.Node{
+		{
+			ID:       teeMismatchID,
+			Roles:    node.RoleKeyManager,
+			Runtimes: []*node.Runtime{{ID: id}},
+		},
+		{
+			ID:       unlistedID,
+			Roles:    node.RoleKeyManager,
+			Runtimes: []*node.Runtime{{ID: id}},
+		},
+	}
+	oldStatus := &api.Status{
+		ID:            id,
+		IsInitialized: true,
+		Nodes:         []signature.PublicKey{teeMismatchID, deregisteredID},
+	}
+
+	app := &keymanagerApplication{}
+	ctx := abci.NewMockContext(abci.ContextBeginBlock, time.Now())
+	newStatus, dropped := app.generateStatus(ctx, kmrt, oldStatus, nodes)
+	require.Empty(newStatus.Nodes, "no nodes should be listed")
+	require.EqualValues([]*api.NodeDroppedEvent{
+		{ID: id, NodeID: teeMismatchID, Reason: api.NodeDroppedReasonTEEMismatch},
+		{ID: id, NodeID: deregisteredID, Reason: api.NodeDroppedReasonNotRegistered},
+	}, dropped, "only previously listed nodes should be reported as dropped")
+
+	// Recomputing the status must not report the same nodes again.
+	_, dropped = app.generateStatus(ctx, kmrt, newStatus, nodes)
+	require.Empty(dropped, "no nodes should be reported as dropped without a transition")
+}
@@ -14,8 +14,11 @@ type Query interface {
 	Status(context.Context, common.Namespace) (*keymanager.Status, error)
 	Statuses(context.Context) ([]*keymanager.Status, error)
 	Genesis(context.Context) (*keymanager.Genesis, error)
+	ConsensusParameters(context.Context) (*keymanager.ConsensusParameters, error)
 }
 
+var _ abci.QueryFactory = (*QueryFactory)(nil)
+
 // QueryFactory is the key manager query factory.
 type QueryFactory struct {
 	app *keymanagerApplication
@@ -47,6 +50,11 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	return &keymanagerQuerier{state}, nil
 }
 
+// GenericQueryAt implements abci.QueryFactory.
+func (sf *QueryFactory) GenericQueryAt(ctx context.Context, height int64) (abci.Query, error) {
+	return sf.QueryAt(ctx, height)
+}
+
 type keymanagerQuerier struct {
 	state *keymanagerState.ImmutableState
 }
@@ -59,6 +67,10 @@ func (kq *keymanagerQuerier) Statuses(ctx context.Context) ([]*keymanager.Status
 	return kq.state.Statuses()
 }
 
-func (app *keymanagerApplication) QueryFactory() interface{} {
+func (kq *keymanagerQuerier) ConsensusParameters(ctx context.Context) (*keymanager.ConsensusParameters, error) {
+	return kq.state.ConsensusParameters()
+}
+
+func (app *keymanagerApplication) QueryFactory() abci.QueryFactory {
 	return &QueryFactory{app}
 }
@@ -1,6 +1,8 @@
 package state
 
 import (
+	"fmt"
+
 	"github.com/tendermint/iavl"
 
 	"github.com/oasislabs/oasis-core/go/common"
@@ -15,6 +17,10 @@ var (
 	//
 	// Value is CBOR-serialized key manager status.
 	statusKeyFmt = keyformat.New(0x70, &common.Namespace{})
+	// parametersKeyFmt is the key format used for consensus parameters.
+	//
+	// Value is CBOR-serialized api.ConsensusParameters.
+	parametersKeyFmt = keyformat.New(0x71)
 )
 
 type ImmutableState struct {
@@ -70,6 +76,20 @@ func (st *ImmutableState) Status(id common.Namespace) (*api.Status, error) {
 	return &status, nil
 }
 
+// ConsensusParameters returns the key manager consensus parameters.
+func (st *ImmutableState) ConsensusParameters() (*api.ConsensusParameters, error) {
+	_, raw := st.Snapshot.Get(parametersKeyFmt.Encode())
+	if raw == nil {
+		return nil, fmt.Errorf("tendermint/keymanager: expected consensus parameters to be present in app state")
+	}
+
+	var params api.ConsensusParameters
+	if err := cbor.Unmarshal(raw, &params); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
 func NewImmutableState(state *abci.ApplicationState, version int64) (*ImmutableState, error) {
 	inner, err := abci.NewImmutableState(state, version)
 	if err != nil {
@@ -89,6 +109,11 @@ func (st *MutableState) SetStatus(status *api.Status) {
 	st.tree.Set(statusKeyFmt.Encode(&status.ID), cbor.Marshal(status))
 }
 
+// SetConsensusParameters sets the key manager consensus parameters.
+func (st *MutableState) SetConsensusParameters(params *api.ConsensusParameters) {
+	st.tree.Set(parametersKeyFmt.Encode(), cbor.Marshal(params))
+}
+
 // NewMutableState creates a new mutable key manager state wrapper.
 func NewMutableState(tree *iavl.MutableTree) *MutableState {
 	inner := &abci.ImmutableState{Snapshot: tree.ImmutableTree}
@@ -0,0 +1,175 @@
+package keymanager
+
+import (
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	tmapi "github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	keymanagerState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/keymanager/state"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	"github.com/oasislabs/oasis-core/go/keymanager/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+)
+
+// keyManagerRuntime looks up a key manager runtime by its identifier and
+// returns both the signed and the opened runtime descriptor.
+func keyManagerRuntime(
+	regState *registryState.MutableState,
+	id common.Namespace,
+) (*registry.SignedRuntime, *registry.Runtime, error) {
+	sigRt, err := regState.SignedRuntime(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	rt, err := regState.Runtime(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rt.Kind != registry.KindKeyManager {
+		return nil, nil, api.ErrNoSuchKeyManager
+	}
+	return sigRt, rt, nil
+}
+
+func (app *keymanagerApplication) updatePolicy(
+	ctx *abci.Context,
+	state *keymanagerState.MutableState,
+	sigPol *api.SignedPolicySGX,
+) error {
+	// TODO: In the future we should be using the namespace type for runtime IDs.
+	var id common.Namespace
+	copy(id[:], sigPol.Policy.ID[:])
+
+	regState := registryState.NewMutableState(ctx.State())
+	sigRt, rt, err := keyManagerRuntime(regState, id)
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the policy is valid.
+	oldStatus, err := state.Status(id)
+	if err != nil {
+		ctx.Logger().Error("UpdatePolicy: failed to query key manager status",
+			"id", id,
+			"err", err,
+		)
+		return err
+	}
+	if oldStatus == nil {
+		oldStatus = &api.Status{
+			ID: id,
+		}
+	}
+	if err = api.SanityCheckSignedPolicySGX(oldStatus.Policy, sigPol); err != nil {
+		return err
+	}
+
+	// Make sure the signer of the transaction matches the signer of the
+	// key manager runtime.
+	if !sigRt.Signature.PublicKey.Equal(ctx.TxSigner()) {
+		return api.ErrIncorrectTxSigner
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, api.GasOpUpdatePolicy, app.state.GasCosts()[api.ModuleName]); err != nil {
+		return err
+	}
+
+	// Recompute the status with the new policy, as nodes that do not yet
+	// use the updated policy are no longer eligible.
+	nodes, _ := regState.Nodes()
+	registry.SortNodeList(nodes)
+
+	oldStatus.Policy = sigPol
+	newStatus, dropped := app.generateStatus(ctx, rt, oldStatus, nodes)
+	state.SetStatus(newStatus)
+
+	ctx.Logger().Debug("UpdatePolicy: policy updated",
+		"id", id,
+		"serial", sigPol.Policy.Serial,
+		"nodes", newStatus.Nodes,
+	)
+
+	ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyStatusUpdate, cbor.Marshal([]*api.Status{newStatus})))
+	if len(dropped) > 0 {
+		ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).Attribute(KeyNodesDropped, cbor.Marshal(dropped)))
+	}
+
+	return nil
+}
+
+func (app *keymanagerApplication) reinitialize(
+	ctx *abci.Context,
+	state *keymanagerState.MutableState,
+	reinit *api.Reinitialize,
+) error {
+	regState := registryState.NewMutableState(ctx.State())
+	sigRt, _, err := keyManagerRuntime(regState, reinit.ID)
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the key manager has been initialized.
+	oldStatus, err := state.Status(reinit.ID)
+	if err != nil {
+		return err
+	}
+	if oldStatus == nil || !oldStatus.IsInitialized {
+		return api.ErrNotInitialized
+	}
+
+	// Make sure the signer of the transaction matches the signer of the
+	// key manager runtime.
+	if !sigRt.Signature.PublicKey.Equal(ctx.TxSigner()) {
+		return api.ErrIncorrectTxSigner
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	if err = ctx.Gas().UseGas(1, api.GasOpReinitialize, app.state.GasCosts()[api.ModuleName]); err != nil {
+		return err
+	}
+
+	status, err := resetStatus(state, reinit.ID)
+	if err != nil {
+		return err
+	}
+
+	ctx.Logger().Info("Reinitialize: key manager status reset",
+		"id", reinit.ID,
+	)
+
+	ctx.EmitEvent(tmapi.NewEventBuilder(app.Name()).
+		Attribute(KeyStatusUpdate, cbor.Marshal([]*api.Status{status})).
+		Attribute(KeyStatusReinitialized, cbor.Marshal(status)),
+	)
+
+	return nil
+}
+
+// resetStatus resets the initialization state of a key manager, so that the
+// status (including the master secret checksum) is re-derived from the key
+// manager nodes on the next epoch transition.
+func resetStatus(state *keymanagerState.MutableState, id common.Namespace) (*api.Status, error) {
+	status, err := state.Status(id)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil || !status.IsInitialized {
+		return nil, api.ErrNotInitialized
+	}
+
+	status.IsInitialized = false
+	status.Checksum = nil
+	status.Nodes = nil
+	state.SetStatus(status)
+
+	return status, nil
+}
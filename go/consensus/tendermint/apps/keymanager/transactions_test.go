@@ -0,0 +1,198 @@
+package keymanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/iavl"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	keymanagerState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/keymanager/state"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests/helpers"
+	"github.com/oasislabs/oasis-core/go/keymanager/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+)
+
+func TestResetStatus(t *testing.T) {
+	require := require.New(t)
+
+	db := dbm.NewMemDB()
+	tree := iavl.NewMutableTree(db, 128)
+	state := keymanagerState.NewMutableState(tree)
+
+	id := common.NewTestNamespaceFromSeed([]byte("key manager reset status test"))
+
+	_, err := resetStatus(state, id)
+	require.Equal(api.ErrNotInitialized, err, "resetting an unknown key manager should fail")
+
+	state.SetStatus(&api.Status{
+		ID:            id,
+		IsInitialized: true,
+		IsSecure:      true,
+		Checksum:      []byte("checksum"),
+		Nodes:         []signature.PublicKey{{1}},
+	})
+
+	status, err := resetStatus(state, id)
+	require.NoError(err, "resetStatus")
+	require.False(status.IsInitialized, "status should no longer be initialized")
+	require.True(status.IsSecure, "security status should be retained")
+	require.Nil(status.Checksum, "checksum should be cleared")
+	require.Empty(status.Nodes, "nodes should be cleared")
+
+	stored, err := state.Status(id)
+	require.NoError(err, "Status")
+	require.EqualValues(status, stored, "reset status should be persisted")
+
+	_, err = resetStatus(state, id)
+	require.Equal(api.ErrNotInitialized, err, "resetting an uninitialized key manager should fail")
+
+	// The next status computation must re-derive the status from the nodes.
+	app := &keymanagerApplication{}
+	ctx := abci.NewMockContext(abci.ContextBeginBlock, time.Now())
+	newStatus, dropped := app.generateStatus(ctx, &registry.Runtime{ID: id, Kind: registry.KindKeyManager}, stored, nil)
+	require.False(newStatus.IsInitialized, "status without nodes should remain uninitialized")
+	require.Nil(newStatus.Checksum, "status without nodes should have no checksum")
+	require.Empty(dropped, "no nodes should be reported as dropped after a reset")
+}
+
+func TestUpdatePolicy(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	ctx := abci.NewContext(abci.ContextDeliverTx, time.Now(), appState)
+	defer ctx.Close()
+	ctx.BlockContext().Set(abci.GasAccountantKey{}, abci.NewNopGasAccountant())
+
+	owner := memorySigner.NewTestSigner("key manager update policy test owner")
+	other := memorySigner.NewTestSigner("key manager update policy test other")
+	id := common.NewTestNamespaceFromSeed([]byte("key manager update policy test"))
+
+	// Register the key manager runtime, owned by the owner entity.
+	regState := registryState.NewMutableState(ctx.State())
+	regState.SetEntity(&entity.Entity{ID: owner.Public()}, &entity.SignedEntity{})
+	rt := &registry.Runtime{
+		ID:   id,
+		Kind: registry.KindKeyManager,
+	}
+	sigRt, err := registry.SignRuntime(owner, registry.RegisterRuntimeSignatureContext, rt)
+	require.NoError(err, "SignRuntime")
+	err = regState.SetRuntime(rt, sigRt, false)
+	require.NoError(err, "SetRuntime")
+
+	state := keymanagerState.NewMutableState(ctx.State())
+	state.SetConsensusParameters(&api.ConsensusParameters{})
+
+	signPolicy := func(serial uint32) *api.SignedPolicySGX {
+		sigPol := &api.SignedPolicySGX{
+			Policy: api.PolicySGX{
+				Serial: serial,
+			},
+		}
+		copy(sigPol.Policy.ID[:], id[:])
+		sig, serr := signature.Sign(owner, api.PolicySGXSignatureContext, cbor.Marshal(sigPol.Policy))
+		require.NoError(serr, "Sign")
+		sigPol.Signatures = []signature.Signature{*sig}
+		return sigPol
+	}
+
+	app := &keymanagerApplication{state: appState}
+	execute := func(signer signature.PublicKey, sigPol *api.SignedPolicySGX) error {
+		ctx.SetTxSigner(signer)
+		return app.ExecuteTx(ctx, api.NewUpdatePolicyTx(0, nil, sigPol))
+	}
+
+	// Malformed policies should be rejected.
+	unsigned := signPolicy(1)
+	unsigned.Signatures = nil
+	err = execute(owner.Public(), unsigned)
+	require.Equal(api.ErrInvalidSignature, err, "UpdatePolicy should reject unsigned policies")
+	tampered := signPolicy(1)
+	tampered.Policy.Serial++
+	err = execute(owner.Public(), tampered)
+	require.Equal(api.ErrInvalidSignature, err, "UpdatePolicy should reject policies with invalid signatures")
+
+	// Policy updates not signed by the key manager runtime owner should be
+	// rejected, also when only checking.
+	sigPol := signPolicy(1)
+	checkCtx := abci.NewContext(abci.ContextCheckTx, time.Now(), appState)
+	defer checkCtx.Close()
+	checkRegState := registryState.NewMutableState(checkCtx.State())
+	checkRegState.SetEntity(&entity.Entity{ID: owner.Public()}, &entity.SignedEntity{})
+	err = checkRegState.SetRuntime(rt, sigRt, false)
+	require.NoError(err, "SetRuntime")
+	checkCtx.SetTxSigner(other.Public())
+	err = app.ExecuteTx(checkCtx, api.NewUpdatePolicyTx(0, nil, sigPol))
+	require.Equal(api.ErrIncorrectTxSigner, err, "UpdatePolicy should reject unauthorized signers when checking")
+	err = execute(other.Public(), sigPol)
+	require.Equal(api.ErrIncorrectTxSigner, err, "UpdatePolicy should reject unauthorized signers")
+	status, err := state.Status(id)
+	require.NoError(err, "Status")
+	require.Nil(status, "rejected updates should not change the status")
+
+	// Valid policy updates should be accepted.
+	err = execute(owner.Public(), sigPol)
+	require.NoError(err, "UpdatePolicy")
+	status, err = state.Status(id)
+	require.NoError(err, "Status")
+	require.EqualValues(sigPol, status.Policy, "policy should be updated")
+	require.True(ctx.HasEvent(app.Name(), KeyStatusUpdate), "status update event should be emitted")
+
+	// Policy serials must be strictly increasing.
+	err = execute(owner.Public(), signPolicy(1))
+	require.Equal(api.ErrInvalidArgument, err, "UpdatePolicy should reject stale policies")
+}
+
+func TestReinitialize(t *testing.T) {
+	require := require.New(t)
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	ctx := abci.NewContext(abci.ContextCheckTx, time.Now(), appState)
+	defer ctx.Close()
+
+	owner := memorySigner.NewTestSigner("key manager reinitialize test owner")
+	other := memorySigner.NewTestSigner("key manager reinitialize test other")
+	id := common.NewTestNamespaceFromSeed([]byte("key manager reinitialize test"))
+
+	// Register an initialized key manager runtime, owned by the owner entity.
+	regState := registryState.NewMutableState(ctx.State())
+	regState.SetEntity(&entity.Entity{ID: owner.Public()}, &entity.SignedEntity{})
+	rt := &registry.Runtime{
+		ID:   id,
+		Kind: registry.KindKeyManager,
+	}
+	sigRt, err := registry.SignRuntime(owner, registry.RegisterRuntimeSignatureContext, rt)
+	require.NoError(err, "SignRuntime")
+	err = regState.SetRuntime(rt, sigRt, false)
+	require.NoError(err, "SetRuntime")
+
+	state := keymanagerState.NewMutableState(ctx.State())
+	state.SetStatus(&api.Status{
+		ID:            id,
+		IsInitialized: true,
+	})
+
+	app := &keymanagerApplication{state: appState}
+	execute := func(signer signature.PublicKey) error {
+		ctx.SetTxSigner(signer)
+		return app.ExecuteTx(ctx, api.NewReinitializeTx(0, nil, &api.Reinitialize{ID: id}))
+	}
+
+	// Re-initialization not signed by the key manager runtime owner should
+	// be rejected when checking.
+	err = execute(other.Public())
+	require.Equal(api.ErrIncorrectTxSigner, err, "Reinitialize should reject unauthorized signers when checking")
+
+	err = execute(owner.Public())
+	require.NoError(err, "Reinitialize")
+}
@@ -1,8 +1,10 @@
 package registry
 
 import (
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/entity"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
 )
 
 const (
@@ -10,7 +12,7 @@ const (
 	AppID uint8 = 0x01
 
 	// AppName is the ABCI application name.
-	AppName string = "200_registry"
+	AppName string = registryState.AppName
 )
 
 var (
@@ -26,6 +28,10 @@ var (
 	// descriptor).
 	KeyRuntimeRegistered = []byte("runtime.registered")
 
+	// KeyRuntimeSuspended is the ABCI event attribute for runtime
+	// suspensions (value is a CBOR serialized RuntimeSuspension).
+	KeyRuntimeSuspended = []byte("runtime.suspended")
+
 	// KeyEntityRegistered is the ABCI event attribute for new entity
 	// registrations (value is the CBOR serialized entity descriptor).
 	KeyEntityRegistered = []byte("entity.registered")
@@ -43,6 +49,16 @@ var (
 	// vector of node descriptors).
 	KeyNodesExpired = []byte("nodes.expired")
 
+	// KeyNodesRemoved is the ABCI event attribute for expired nodes
+	// being removed from the registry after the debonding interval
+	// (value is a CBOR serialized vector of node descriptors).
+	KeyNodesRemoved = []byte("nodes.removed")
+
+	// KeyNodeFrozen is the ABCI event attribute for when nodes become
+	// frozen due to misbehavior (value is a CBOR serialized
+	// registry.NodeFrozenEvent).
+	KeyNodeFrozen = registryState.KeyNodeFrozen
+
 	// KeyNodeUnfrozen is the ABCI event attribute for when nodes
 	// become unfrozen (value is CBOR serialized node ID).
 	KeyNodeUnfrozen = []byte("nodes.unfrozen")
@@ -57,3 +73,15 @@ type EntityDeregistration struct {
 	// Deregistered entity.
 	Entity entity.Entity `json:"entity"`
 }
+
+// RuntimeSuspensionReasonUnpaidMaintenance is the runtime suspension reason
+// used when no nodes paid the runtime maintenance fees.
+const RuntimeSuspensionReasonUnpaidMaintenance = "unpaid_maintenance"
+
+// RuntimeSuspension is a runtime suspension.
+type RuntimeSuspension struct {
+	// ID is the suspended runtime ID.
+	ID common.Namespace `json:"id"`
+	// Reason is the reason for the runtime suspension.
+	Reason string `json:"reason"`
+}
@@ -98,7 +98,7 @@ func (app *registryApplication) InitChain(ctx *abci.Context, request types.Reque
 		ctx.Logger().Debug("InitChain: Registering genesis node",
 			"node_owner", v.Signature.PublicKey,
 		)
-		if err := app.registerNode(ctx, state, v); err != nil {
+		if err := app.registerNode(ctx, state, v, false); err != nil {
 			ctx.Logger().Error("InitChain: failed to register node",
 				"err", err,
 				"node", v,
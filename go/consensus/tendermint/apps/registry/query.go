@@ -20,11 +20,18 @@ type Query interface {
 	Node(context.Context, signature.PublicKey) (*node.Node, error)
 	NodeStatus(context.Context, signature.PublicKey) (*registry.NodeStatus, error)
 	Nodes(context.Context) ([]*node.Node, error)
+	NodesWithStatus(context.Context) ([]*registry.NodeWithStatus, error)
+	EntityNodes(context.Context, signature.PublicKey) ([]*node.Node, error)
 	Runtime(context.Context, common.Namespace) (*registry.Runtime, error)
 	Runtimes(context.Context) ([]*registry.Runtime, error)
+	AllRuntimes(context.Context) ([]*registry.Runtime, error)
+	IsRuntimeSuspended(context.Context, common.Namespace) (bool, error)
 	Genesis(context.Context) (*registry.Genesis, error)
+	ConsensusParameters(context.Context) (*registry.ConsensusParameters, error)
 }
 
+var _ abci.QueryFactory = (*QueryFactory)(nil)
+
 // QueryFactory is the registry query factory.
 type QueryFactory struct {
 	app *registryApplication
@@ -56,6 +63,11 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	return &registryQuerier{sf.app, state, height}, nil
 }
 
+// GenericQueryAt implements abci.QueryFactory.
+func (sf *QueryFactory) GenericQueryAt(ctx context.Context, height int64) (abci.Query, error) {
+	return sf.QueryAt(ctx, height)
+}
+
 type registryQuerier struct {
 	app    *registryApplication
 	state  *registryState.ImmutableState
@@ -114,6 +126,50 @@ func (rq *registryQuerier) Nodes(ctx context.Context) ([]*node.Node, error) {
 	return filteredNodes, nil
 }
 
+func (rq *registryQuerier) NodesWithStatus(ctx context.Context) ([]*registry.NodeWithStatus, error) {
+	nodes, err := rq.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := rq.state.NodeStatuses()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*registry.NodeWithStatus
+	for _, n := range nodes {
+		status, ok := statuses[n.ID]
+		if !ok {
+			return nil, fmt.Errorf("tendermint/registry: missing status for node %s", n.ID)
+		}
+		result = append(result, &registry.NodeWithStatus{Node: n, Status: status})
+	}
+	return result, nil
+}
+
+func (rq *registryQuerier) EntityNodes(ctx context.Context, id signature.PublicKey) ([]*node.Node, error) {
+	epoch, err := rq.app.state.GetEpoch(ctx, rq.height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get epoch: %w", err)
+	}
+
+	nodes, err := rq.state.EntityNodes(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter out expired nodes.
+	var filteredNodes []*node.Node
+	for _, n := range nodes {
+		if n.IsExpired(uint64(epoch)) {
+			continue
+		}
+		filteredNodes = append(filteredNodes, n)
+	}
+	return filteredNodes, nil
+}
+
 func (rq *registryQuerier) Runtime(ctx context.Context, id common.Namespace) (*registry.Runtime, error) {
 	return rq.state.Runtime(id)
 }
@@ -122,6 +178,30 @@ func (rq *registryQuerier) Runtimes(ctx context.Context) ([]*registry.Runtime, e
 	return rq.state.Runtimes()
 }
 
-func (app *registryApplication) QueryFactory() interface{} {
+func (rq *registryQuerier) AllRuntimes(ctx context.Context) ([]*registry.Runtime, error) {
+	return rq.state.AllRuntimes()
+}
+
+func (rq *registryQuerier) IsRuntimeSuspended(ctx context.Context, id common.Namespace) (bool, error) {
+	_, err := rq.state.SuspendedRuntime(id)
+	switch err {
+	case nil:
+		return true, nil
+	case registry.ErrNoSuchRuntime:
+		// Make sure the runtime actually exists.
+		if _, err = rq.state.Runtime(id); err != nil {
+			return false, err
+		}
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (rq *registryQuerier) ConsensusParameters(ctx context.Context) (*registry.ConsensusParameters, error) {
+	return rq.state.ConsensusParameters()
+}
+
+func (app *registryApplication) QueryFactory() abci.QueryFactory {
 	return &QueryFactory{app}
 }
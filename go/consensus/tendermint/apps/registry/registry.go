@@ -79,7 +79,14 @@ func (app *registryApplication) ExecuteTx(ctx *abci.Context, tx *transaction.Tra
 			return err
 		}
 
-		return app.registerNode(ctx, state, &sigNode)
+		return app.registerNode(ctx, state, &sigNode, false)
+	case registry.MethodRegisterEntityWithNodes:
+		var ewn registry.EntityWithNodes
+		if err := cbor.Unmarshal(tx.Body, &ewn); err != nil {
+			return err
+		}
+
+		return app.registerEntityWithNodes(ctx, state, &ewn)
 	case registry.MethodUnfreezeNode:
 		var unfreeze registry.UnfreezeNode
 		if err := cbor.Unmarshal(tx.Body, &unfreeze); err != nil {
@@ -110,6 +117,46 @@ func (app *registryApplication) ExecuteTx(ctx *abci.Context, tx *transaction.Tra
 	}
 }
 
+// staticGasOps are the gas operations of methods whose gas cost only depends
+// on the gas cost table.
+//
+// Entity and node registrations are not included as their gas cost depends
+// on the number of nodes and runtime maintenance fees.
+var staticGasOps = map[transaction.MethodName]transaction.Op{
+	registry.MethodDeregisterEntity: registry.GasOpDeregisterEntity,
+	registry.MethodUnfreezeNode:     registry.GasOpUnfreezeNode,
+	registry.MethodRegisterRuntime:  registry.GasOpRegisterRuntime,
+}
+
+// Implements abci.StaticGasApplication.
+func (app *registryApplication) StaticGasCost(method transaction.MethodName, height int64) (transaction.Gas, bool, error) {
+	op, ok := staticGasOps[method]
+	if !ok {
+		return 0, false, nil
+	}
+
+	state, err := abci.NewImmutableState(app.state, height)
+	if err != nil {
+		return 0, false, err
+	}
+	_, costs, err := app.GasCosts(state)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return costs[op], true, nil
+}
+
+// Implements abci.GasCostsApplication.
+func (app *registryApplication) GasCosts(state *abci.ImmutableState) (string, transaction.Costs, error) {
+	params, err := (&registryState.ImmutableState{ImmutableState: state}).ConsensusParameters()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return registry.ModuleName, params.GasCosts, nil
+}
+
 func (app *registryApplication) ForeignExecuteTx(ctx *abci.Context, other abci.Application, tx *transaction.Transaction) error {
 	return nil
 }
@@ -146,7 +193,7 @@ func (app *registryApplication) onRegistryEpochChanged(ctx *abci.Context, regist
 	// period and then removed. This is required so that expired nodes
 	// can still get slashed while inside the debonding interval as
 	// otherwise the nodes could not be resolved.
-	var expiredNodes []*node.Node
+	var expiredNodes, removedNodes []*node.Node
 	for _, node := range nodes {
 		if !node.IsExpired(uint64(registryEpoch)) {
 			continue
@@ -175,6 +222,7 @@ func (app *registryApplication) onRegistryEpochChanged(ctx *abci.Context, regist
 				"node_id", node.ID,
 			)
 			state.RemoveNode(node)
+			removedNodes = append(removedNodes, node)
 		}
 	}
 
@@ -188,6 +236,9 @@ func (app *registryApplication) onRegistryEpochChanged(ctx *abci.Context, regist
 		// so the change is picked up.
 		evb = evb.Attribute(KeyNodesExpired, cbor.Marshal(expiredNodes))
 	}
+	if len(removedNodes) > 0 {
+		evb = evb.Attribute(KeyNodesRemoved, cbor.Marshal(removedNodes))
+	}
 
 	ctx.EmitEvent(evb)
 
@@ -13,13 +13,23 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/keyformat"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	tmcrypto "github.com/oasislabs/oasis-core/go/consensus/tendermint/crypto"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 )
 
+// AppName is the ABCI application name.
+const AppName = "200_registry"
+
 var (
 	_ registry.NodeLookup = (*ImmutableState)(nil)
 
+	// KeyNodeFrozen is the ABCI event attribute for when nodes become
+	// frozen due to misbehavior (value is a CBOR serialized
+	// registry.NodeFrozenEvent).
+	KeyNodeFrozen = []byte("nodes.frozen")
+
 	// signedEntityKeyFmt is the key format used for signed entities.
 	//
 	// Value is CBOR-serialized signed entity.
@@ -217,6 +227,36 @@ func (s *ImmutableState) Nodes() ([]*node.Node, error) {
 	return nodes, nil
 }
 
+// EntityNodes returns all nodes registered by the given entity.
+func (s *ImmutableState) EntityNodes(id signature.PublicKey) ([]*node.Node, error) {
+	var nodeIDs []signature.PublicKey
+	s.Snapshot.IterateRange(
+		signedNodeByEntityKeyFmt.Encode(&id),
+		nil,
+		true,
+		func(key, value []byte) bool {
+			var entityID, nodeID signature.PublicKey
+			if !signedNodeByEntityKeyFmt.Decode(key, &entityID, &nodeID) || !entityID.Equal(id) {
+				return true
+			}
+
+			nodeIDs = append(nodeIDs, nodeID)
+
+			return false
+		},
+	)
+
+	var nodes []*node.Node
+	for _, nodeID := range nodeIDs {
+		node, err := s.Node(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
 func (s *ImmutableState) SignedNodes() ([]*node.SignedNode, error) {
 	var nodes []*node.SignedNode
 	s.Snapshot.IterateRange(
@@ -635,6 +675,32 @@ func (s *MutableState) SetNodeStatus(id signature.PublicKey, status *registry.No
 	return nil
 }
 
+// FreezeNode freezes the node until the given epoch and emits an event
+// recording the reason for the freeze.
+func (s *MutableState) FreezeNode(
+	ctx *abci.Context,
+	id signature.PublicKey,
+	status *registry.NodeStatus,
+	freezeEndTime epochtime.EpochTime,
+	reason registry.FreezeReason,
+) error {
+	status.FreezeEndTime = freezeEndTime
+	if err := s.SetNodeStatus(id, status); err != nil {
+		return err
+	}
+
+	if !ctx.IsCheckOnly() {
+		ev := cbor.Marshal(&registry.NodeFrozenEvent{
+			NodeID:        id,
+			FreezeEndTime: freezeEndTime,
+			Reason:        reason,
+		})
+		ctx.EmitEvent(api.NewEventBuilder(AppName).Attribute(KeyNodeFrozen, ev))
+	}
+
+	return nil
+}
+
 func (s *MutableState) SetConsensusParameters(params *registry.ConsensusParameters) {
 	s.tree.Set(parametersKeyFmt.Encode(), cbor.Marshal(params))
 }
@@ -3,6 +3,7 @@ package registry
 import (
 	"fmt"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/entity"
 	"github.com/oasislabs/oasis-core/go/common/node"
@@ -10,6 +11,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
 	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
@@ -122,10 +124,16 @@ func (app *registryApplication) deregisterEntity(ctx *abci.Context, state *regis
 	return nil
 }
 
+// registerNode registers a node.
+//
+// If ownerSigned is true, the node registration is authorized by the owning
+// entity being the transaction signer instead of by the node registration
+// signer being the transaction signer.
 func (app *registryApplication) registerNode( // nolint: gocyclo
 	ctx *abci.Context,
 	state *registryState.MutableState,
 	sigNode *node.SignedNode,
+	ownerSigned bool,
 ) error {
 	if ctx.IsCheckOnly() {
 		return nil
@@ -178,11 +186,20 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 		}
 	}
 
-	// Make sure the signer of the transaction matches the signer of the node.
+	// Make sure the signer of the transaction matches the signer of the node
+	// (or the owning entity when registering on behalf of the entity).
 	// NOTE: If this is invoked during InitChain then there is no actual transaction
 	//       and thus no transaction signer so we must skip this check.
-	if !ctx.IsInitChain() && !sigNode.Signature.PublicKey.Equal(ctx.TxSigner()) {
-		return registry.ErrIncorrectTxSigner
+	switch {
+	case ctx.IsInitChain():
+	case ownerSigned:
+		if !newNode.EntityID.Equal(ctx.TxSigner()) {
+			return registry.ErrIncorrectTxSigner
+		}
+	default:
+		if !sigNode.Signature.PublicKey.Equal(ctx.TxSigner()) {
+			return registry.ErrIncorrectTxSigner
+		}
 	}
 
 	// Re-check that the entity has at sufficient stake to still be an entity.
@@ -243,19 +260,11 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 
 	// For each runtime the node registers for, require it to pay a maintenance fee for
 	// each epoch the node is registered in.
-	additionalEpochs := newNode.Expiration - uint64(epoch)
+	var currentNode *node.Node
 	if !isNewNode && !isExpiredNode {
-		// Remaining epochs are credited so the node doesn't end up paying twice.
-		// NOTE: This assumes that changing runtimes is not allowed as otherwise we
-		//       would need to account this per-runtime.
-		remainingEpochs := existingNode.Expiration - uint64(epoch)
-		if additionalEpochs > remainingEpochs {
-			additionalEpochs = additionalEpochs - remainingEpochs
-		} else {
-			additionalEpochs = 0
-		}
+		currentNode = existingNode
 	}
-	feeCount := len(paidRuntimes) * int(additionalEpochs)
+	feeCount := maintenanceFeeEpochs(currentNode, newNode, paidRuntimes, epoch)
 	if err = ctx.Gas().UseGas(feeCount, registry.GasOpRuntimeEpochMaintenance, params.GasCosts); err != nil {
 		return err
 	}
@@ -382,6 +391,78 @@ func (app *registryApplication) registerNode( // nolint: gocyclo
 	return nil
 }
 
+// maintenanceFeeEpochs returns the number of runtime maintenance epochs that a
+// node registration needs to pay for, accounted for each runtime separately.
+//
+// If the node is already registered (and not expired), runtimes that it was
+// already registered for are credited with the remaining epochs that were
+// already paid for, so the node doesn't end up paying twice. Runtimes added
+// by the update pay for all epochs until the node expires, while removed
+// runtimes are no longer charged for.
+func maintenanceFeeEpochs(
+	currentNode *node.Node,
+	newNode *node.Node,
+	paidRuntimes []*registry.Runtime,
+	epoch epochtime.EpochTime,
+) int {
+	currentRuntimes := make(map[common.Namespace]bool)
+	if currentNode != nil {
+		for _, rt := range currentNode.Runtimes {
+			currentRuntimes[rt.ID] = true
+		}
+	}
+
+	var count int
+	for _, rt := range paidRuntimes {
+		additionalEpochs := newNode.Expiration - uint64(epoch)
+		if currentRuntimes[rt.ID] {
+			remainingEpochs := currentNode.Expiration - uint64(epoch)
+			if additionalEpochs > remainingEpochs {
+				additionalEpochs = additionalEpochs - remainingEpochs
+			} else {
+				additionalEpochs = 0
+			}
+		}
+		count += int(additionalEpochs)
+	}
+	return count
+}
+
+func (app *registryApplication) registerEntityWithNodes(
+	ctx *abci.Context,
+	state *registryState.MutableState,
+	ewn *registry.EntityWithNodes,
+) error {
+	// Create a new state checkpoint and rollback in case we fail, so that
+	// either the entity and all of its nodes are registered or nothing is.
+	var ok bool
+	sc := ctx.NewStateCheckpoint()
+	defer func() {
+		if !ok {
+			sc.Rollback()
+		}
+		sc.Close()
+	}()
+
+	if err := app.registerEntity(ctx, state, &ewn.Entity); err != nil {
+		return err
+	}
+
+	for _, sigNode := range ewn.Nodes {
+		if err := app.registerNode(ctx, state, sigNode, true); err != nil {
+			ctx.Logger().Error("RegisterEntityWithNodes: failed to register node",
+				"err", err,
+				"signed_node", sigNode,
+			)
+			return err
+		}
+	}
+
+	ok = true
+
+	return nil
+}
+
 func (app *registryApplication) unfreezeNode(
 	ctx *abci.Context,
 	state *registryState.MutableState,
@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	registryTests "github.com/oasislabs/oasis-core/go/registry/tests"
+)
+
+type fixedEpochTimeSource struct {
+	epochtime.Backend
+
+	epoch epochtime.EpochTime
+}
+
+func (ts *fixedEpochTimeSource) GetEpoch(context.Context, int64) (epochtime.EpochTime, error) {
+	return ts.epoch, nil
+}
+
+func TestRegisterEntityWithNodes(t *testing.T) {
+	require := require.New(t)
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{
+		BlockHeight: 1,
+		TimeSource:  &fixedEpochTimeSource{epoch: 1},
+	})
+	ctx := abci.NewContext(abci.ContextDeliverTx, time.Now(), appState)
+	defer ctx.Close()
+
+	app := &registryApplication{state: appState}
+	state := registryState.NewMutableState(ctx.State())
+	state.SetConsensusParameters(&registry.ConsensusParameters{
+		DebugAllowUnroutableAddresses: true,
+		DebugBypassStake:              true,
+	})
+
+	entities, err := registryTests.NewTestEntities([]byte("register entity with nodes test"), 1)
+	require.NoError(err, "NewTestEntities")
+	ent := entities[0]
+	ctx.SetTxSigner(ent.Signer.Public())
+
+	// Use validator nodes, as they do not require any runtimes.
+	testNodes, err := ent.NewTestNodes(1, 1, nil, 10)
+	require.NoError(err, "NewTestNodes")
+	var nodes []*node.Node
+	var sigNodes []*node.SignedNode
+	for _, tn := range testNodes {
+		n := *tn.Node
+		n.Roles = node.RoleValidator
+		n.Consensus.Addresses = []node.ConsensusAddress{
+			{ID: n.Consensus.ID, Address: n.P2P.Addresses[0]},
+		}
+		var sigNode *node.SignedNode
+		sigNode, err = node.SignNode(ent.Signer, registry.RegisterNodeSignatureContext, &n)
+		require.NoError(err, "SignNode")
+
+		nodes = append(nodes, &n)
+		sigNodes = append(sigNodes, sigNode)
+	}
+	invalidNode := *nodes[1]
+	invalidNode.Committee.Certificate = nil
+	sigInvalidNode, err := node.SignNode(ent.Signer, registry.RegisterNodeSignatureContext, &invalidNode)
+	require.NoError(err, "SignNode")
+
+	// One invalid node must cause the whole registration to be rolled back.
+	err = app.registerEntityWithNodes(ctx, state, &registry.EntityWithNodes{
+		Entity: *ent.SignedRegistration,
+		Nodes:  []*node.SignedNode{sigNodes[0], sigInvalidNode},
+	})
+	require.Error(err, "registration with an invalid node should fail")
+
+	state = registryState.NewMutableState(ctx.State())
+	_, err = state.Entity(ent.Entity.ID)
+	require.Equal(registry.ErrNoSuchEntity, err, "entity registration should be rolled back")
+	_, err = state.Node(nodes[0].ID)
+	require.Equal(registry.ErrNoSuchNode, err, "valid node registration should be rolled back")
+
+	// A registration with only valid nodes should register everything.
+	err = app.registerEntityWithNodes(ctx, state, &registry.EntityWithNodes{
+		Entity: *ent.SignedRegistration,
+		Nodes:  sigNodes,
+	})
+	require.NoError(err, "registration with valid nodes should succeed")
+
+	_, err = state.Entity(ent.Entity.ID)
+	require.NoError(err, "entity should be registered")
+	for _, n := range nodes {
+		_, err = state.Node(n.ID)
+		require.NoError(err, "node should be registered")
+	}
+}
+
+func TestMaintenanceFeeEpochs(t *testing.T) {
+	require := require.New(t)
+
+	rt1 := &registry.Runtime{ID: common.NewTestNamespaceFromSeed([]byte("maintenance fee runtime 1"))}
+	rt2 := &registry.Runtime{ID: common.NewTestNamespaceFromSeed([]byte("maintenance fee runtime 2"))}
+
+	currentNode := &node.Node{
+		Expiration: 15,
+		Runtimes:   []*node.Runtime{{ID: rt1.ID}},
+	}
+
+	// New node pays for all epochs of all runtimes.
+	newNode := &node.Node{
+		Expiration: 20,
+		Runtimes:   []*node.Runtime{{ID: rt1.ID}, {ID: rt2.ID}},
+	}
+	require.Equal(20, maintenanceFeeEpochs(nil, newNode, []*registry.Runtime{rt1, rt2}, 10), "new node")
+
+	// Extending the registration pays only for the additional epochs.
+	newNode = &node.Node{
+		Expiration: 20,
+		Runtimes:   []*node.Runtime{{ID: rt1.ID}},
+	}
+	require.Equal(5, maintenanceFeeEpochs(currentNode, newNode, []*registry.Runtime{rt1}, 10), "extended node")
+
+	// Adding a runtime pays for all epochs of the added runtime.
+	newNode = &node.Node{
+		Expiration: 15,
+		Runtimes:   []*node.Runtime{{ID: rt1.ID}, {ID: rt2.ID}},
+	}
+	require.Equal(5, maintenanceFeeEpochs(currentNode, newNode, []*registry.Runtime{rt1, rt2}, 10), "added runtime")
+
+	// Removing a runtime stops charging for it.
+	currentNode = newNode
+	newNode = &node.Node{
+		Expiration: 20,
+		Runtimes:   []*node.Runtime{{ID: rt2.ID}},
+	}
+	require.Equal(5, maintenanceFeeEpochs(currentNode, newNode, []*registry.Runtime{rt2}, 10), "removed runtime")
+	require.Equal(0, maintenanceFeeEpochs(currentNode, currentNode, nil, 10), "removed all runtimes")
+}
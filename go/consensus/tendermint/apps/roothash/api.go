@@ -1,8 +1,11 @@
 package roothash
 
 import (
+	"fmt"
+
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+	roothashState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/roothash/state"
 	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
 )
 
@@ -54,3 +57,25 @@ type ValueExecutionDiscrepancyDetected struct {
 	ID    common.Namespace                           `json:"id"`
 	Event roothash.ExecutionDiscrepancyDetectedEvent `json:"event"`
 }
+
+// VerifyLatestBlockWithProof verifies that the given block is the latest
+// block of the given runtime committed in the consensus state with the
+// given state root (the application hash after processing the consensus
+// block at bp.Height).
+func VerifyLatestBlockWithProof(stateRoot []byte, id common.Namespace, bp *roothash.BlockWithProof) error {
+	if bp == nil || bp.Block == nil {
+		return fmt.Errorf("tendermint/roothash: missing block")
+	}
+
+	runtime, err := roothashState.VerifyRuntimeStateProof(stateRoot, id, bp.RuntimeState, bp.Proof)
+	if err != nil {
+		return err
+	}
+	if runtime.CurrentBlock == nil {
+		return fmt.Errorf("tendermint/roothash: runtime state has no current block")
+	}
+	if blkHash, expectedHash := bp.Block.Header.EncodedHash(), runtime.CurrentBlock.Header.EncodedHash(); !blkHash.Equal(&expectedHash) {
+		return fmt.Errorf("tendermint/roothash: block hash mismatch (expected: %s got: %s)", expectedHash, blkHash)
+	}
+	return nil
+}
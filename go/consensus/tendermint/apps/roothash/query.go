@@ -13,10 +13,14 @@ import (
 // Query is the roothash query interface.
 type Query interface {
 	LatestBlock(context.Context, common.Namespace) (*block.Block, error)
+	LatestBlockWithProof(context.Context, common.Namespace) (*roothash.BlockWithProof, error)
 	GenesisBlock(context.Context, common.Namespace) (*block.Block, error)
 	Genesis(context.Context) (*roothash.Genesis, error)
+	ConsensusParameters(context.Context) (*roothash.ConsensusParameters, error)
 }
 
+var _ abci.QueryFactory = (*QueryFactory)(nil)
+
 // QueryFactory is the roothash query factory.
 type QueryFactory struct {
 	app *rootHashApplication
@@ -48,6 +52,11 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	return &rootHashQuerier{state}, nil
 }
 
+// GenericQueryAt implements abci.QueryFactory.
+func (sf *QueryFactory) GenericQueryAt(ctx context.Context, height int64) (abci.Query, error) {
+	return sf.QueryAt(ctx, height)
+}
+
 type rootHashQuerier struct {
 	state *roothashState.ImmutableState
 }
@@ -60,6 +69,19 @@ func (rq *rootHashQuerier) LatestBlock(ctx context.Context, id common.Namespace)
 	return runtime.CurrentBlock, nil
 }
 
+func (rq *rootHashQuerier) LatestBlockWithProof(ctx context.Context, id common.Namespace) (*roothash.BlockWithProof, error) {
+	runtime, raw, proof, err := rq.state.RuntimeStateWithProof(id)
+	if err != nil {
+		return nil, err
+	}
+	return &roothash.BlockWithProof{
+		Height:       rq.state.Snapshot.Version(),
+		Block:        runtime.CurrentBlock,
+		RuntimeState: raw,
+		Proof:        proof,
+	}, nil
+}
+
 func (rq *rootHashQuerier) GenesisBlock(ctx context.Context, id common.Namespace) (*block.Block, error) {
 	runtime, err := rq.state.RuntimeState(id)
 	if err != nil {
@@ -68,6 +90,10 @@ func (rq *rootHashQuerier) GenesisBlock(ctx context.Context, id common.Namespace
 	return runtime.GenesisBlock, nil
 }
 
-func (app *rootHashApplication) QueryFactory() interface{} {
+func (rq *rootHashQuerier) ConsensusParameters(ctx context.Context) (*roothash.ConsensusParameters, error) {
+	return rq.state.ConsensusParameters()
+}
+
+func (app *rootHashApplication) QueryFactory() abci.QueryFactory {
 	return &QueryFactory{app}
 }
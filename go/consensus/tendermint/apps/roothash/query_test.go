@@ -0,0 +1,79 @@
+package roothash
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	roothashState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/roothash/state"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+)
+
+func TestQueryLatestBlockWithProof(t *testing.T) {
+	require := require.New(t)
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("roothash latest block with proof test"))
+	otherRuntimeID := common.NewTestNamespaceFromSeed([]byte("roothash latest block with proof test: other"))
+
+	genesisBlock := block.NewGenesisBlock(runtimeID, 0)
+	currentBlock := block.NewEmptyBlock(genesisBlock, 10, block.Normal)
+	state := roothashState.NewMutableState(appState.DeliverTxTree())
+	state.SetRuntimeState(&roothashState.RuntimeState{
+		Runtime:      &registry.Runtime{ID: runtimeID},
+		CurrentBlock: currentBlock,
+		GenesisBlock: genesisBlock,
+	})
+	state.SetRuntimeState(&roothashState.RuntimeState{
+		Runtime:      &registry.Runtime{ID: otherRuntimeID},
+		CurrentBlock: genesisBlock,
+		GenesisBlock: genesisBlock,
+	})
+	blockHash, _, err := appState.DeliverTxTree().SaveVersion()
+	require.NoError(err, "SaveVersion")
+
+	q, err := (&QueryFactory{&rootHashApplication{state: appState}}).QueryAt(context.Background(), 1)
+	require.NoError(err, "QueryAt")
+	bp, err := q.LatestBlockWithProof(context.Background(), runtimeID)
+	require.NoError(err, "LatestBlockWithProof")
+	require.EqualValues(1, bp.Height, "consensus height should be correct")
+	require.Equal(currentBlock, bp.Block, "the latest block should be returned")
+
+	err = VerifyLatestBlockWithProof(blockHash, runtimeID, bp)
+	require.NoError(err, "VerifyLatestBlockWithProof")
+
+	// Verification must fail against a different state root.
+	err = VerifyLatestBlockWithProof(append([]byte{}, blockHash[1:]...), runtimeID, bp)
+	require.True(errors.Is(err, abci.ErrInvalidProof), "proofs should not verify against other state roots")
+
+	// Verification must fail for a different runtime.
+	err = VerifyLatestBlockWithProof(blockHash, otherRuntimeID, bp)
+	require.True(errors.Is(err, abci.ErrInvalidProof), "proofs should not verify for other runtimes")
+
+	// Verification must fail for a different block.
+	tampered := *bp
+	tampered.Block = genesisBlock
+	err = VerifyLatestBlockWithProof(blockHash, runtimeID, &tampered)
+	require.Error(err, "proofs should not verify for other blocks")
+
+	// Verification must fail for a tampered runtime state.
+	tampered = *bp
+	tampered.RuntimeState = append([]byte{}, bp.RuntimeState...)
+	tampered.RuntimeState[len(tampered.RuntimeState)-1] ^= 0xff
+	err = VerifyLatestBlockWithProof(blockHash, runtimeID, &tampered)
+	require.True(errors.Is(err, abci.ErrInvalidProof), "proofs should not verify for tampered runtime states")
+
+	// Verification must fail for a malformed proof.
+	tampered = *bp
+	tampered.Proof = []byte("not a proof")
+	err = VerifyLatestBlockWithProof(blockHash, runtimeID, &tampered)
+	require.True(errors.Is(err, abci.ErrInvalidProof), "malformed proofs should not verify")
+
+	_, err = q.LatestBlockWithProof(context.Background(), common.NewTestNamespaceFromSeed([]byte("missing")))
+	require.Error(err, "LatestBlockWithProof should fail for unknown runtimes")
+}
@@ -179,6 +179,14 @@ func (app *rootHashApplication) suspendUnpaidRuntime(
 	rtState.Suspended = true
 	rtState.Round = nil
 
+	ctx.EmitEvent(tmapi.NewEventBuilder(registryapp.AppName).Attribute(
+		registryapp.KeyRuntimeSuspended,
+		cbor.Marshal(&registryapp.RuntimeSuspension{
+			ID:     rtState.Runtime.ID,
+			Reason: registryapp.RuntimeSuspensionReasonUnpaidMaintenance,
+		}),
+	))
+
 	// Emity an empty block signalling that the runtime was suspended.
 	app.emitEmptyBlock(ctx, rtState, block.Suspended)
 
@@ -354,6 +362,42 @@ func (app *rootHashApplication) ExecuteTx(ctx *abci.Context, tx *transaction.Tra
 	}
 }
 
+// staticGasOps are the gas operations of methods whose gas cost only depends
+// on the gas cost table.
+var staticGasOps = map[transaction.MethodName]transaction.Op{
+	roothash.MethodExecutorCommit: roothash.GasOpComputeCommit,
+	roothash.MethodMergeCommit:    roothash.GasOpMergeCommit,
+}
+
+// Implements abci.StaticGasApplication.
+func (app *rootHashApplication) StaticGasCost(method transaction.MethodName, height int64) (transaction.Gas, bool, error) {
+	op, ok := staticGasOps[method]
+	if !ok {
+		return 0, false, nil
+	}
+
+	state, err := abci.NewImmutableState(app.state, height)
+	if err != nil {
+		return 0, false, err
+	}
+	_, costs, err := app.GasCosts(state)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return costs[op], true, nil
+}
+
+// Implements abci.GasCostsApplication.
+func (app *rootHashApplication) GasCosts(state *abci.ImmutableState) (string, transaction.Costs, error) {
+	params, err := (&roothashState.ImmutableState{ImmutableState: state}).ConsensusParameters()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return roothash.ModuleName, params.GasCosts, nil
+}
+
 func (app *rootHashApplication) ForeignExecuteTx(ctx *abci.Context, other abci.Application, tx *transaction.Transaction) error {
 	var st *roothash.Genesis
 	ensureGenesis := func() {
@@ -0,0 +1,79 @@
+package roothash
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	registryapp "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	roothashState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/roothash/state"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+)
+
+func TestSuspendUnpaidRuntime(t *testing.T) {
+	require := require.New(t)
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	ctx := abci.NewContext(abci.ContextEndBlock, time.Now(), appState)
+	defer ctx.Close()
+
+	var entityID signature.PublicKey
+	runtimeID := common.NewTestNamespaceFromSeed([]byte("roothash suspend unpaid runtime test"))
+	rt := &registry.Runtime{ID: runtimeID}
+	sigRt := &registry.SignedRuntime{Signed: signature.Signed{Signature: signature.Signature{PublicKey: entityID}}}
+
+	regState := registryState.NewMutableState(ctx.State())
+	regState.SetEntity(&entity.Entity{ID: entityID}, &entity.SignedEntity{})
+	err := regState.SetRuntime(rt, sigRt, false)
+	require.NoError(err, "SetRuntime")
+
+	app := &rootHashApplication{state: appState}
+	genesisBlock := block.NewGenesisBlock(runtimeID, 0)
+	rtState := &roothashState.RuntimeState{
+		Runtime:      rt,
+		CurrentBlock: genesisBlock,
+		GenesisBlock: genesisBlock,
+		Timer:        *abci.NewTimer(ctx, app, timerKindRound, runtimeID[:], nil),
+	}
+
+	err = app.suspendUnpaidRuntime(ctx, rtState, regState)
+	require.NoError(err, "suspendUnpaidRuntime")
+
+	require.True(rtState.Suspended, "runtime state should be marked as suspended")
+	require.Nil(rtState.Round, "runtime state should not have a round")
+	require.Equal(block.Suspended, rtState.CurrentBlock.Header.HeaderType, "a suspended block should be emitted")
+
+	_, err = regState.SuspendedRuntime(runtimeID)
+	require.NoError(err, "SuspendedRuntime")
+	_, err = regState.Runtime(runtimeID)
+	require.Equal(registry.ErrNoSuchRuntime, err, "suspended runtime should not be active")
+
+	// A runtime suspension event with the reason should be emitted.
+	var events []*registryapp.RuntimeSuspension
+	for _, ev := range ctx.GetEvents() {
+		if ev.GetType() != registryapp.EventType {
+			continue
+		}
+		for _, pair := range ev.GetAttributes() {
+			if !bytes.Equal(pair.GetKey(), registryapp.KeyRuntimeSuspended) {
+				continue
+			}
+			var e registryapp.RuntimeSuspension
+			err = cbor.Unmarshal(pair.GetValue(), &e)
+			require.NoError(err, "cbor.Unmarshal")
+			events = append(events, &e)
+		}
+	}
+	require.Len(events, 1, "a single runtime suspension event should be emitted")
+	require.Equal(runtimeID, events[0].ID, "runtime suspension event should include the runtime ID")
+	require.Equal(registryapp.RuntimeSuspensionReasonUnpaidMaintenance, events[0].Reason, "runtime suspension event should include the reason")
+}
@@ -61,6 +61,39 @@ func (s *ImmutableState) RuntimeState(id common.Namespace) (*RuntimeState, error
 	return &state, err
 }
 
+// RuntimeStateWithProof returns the runtime state together with its
+// serialized form and a serialized proof of its inclusion in the state.
+func (s *ImmutableState) RuntimeStateWithProof(id common.Namespace) (*RuntimeState, []byte, []byte, error) {
+	raw, proof, err := s.GetWithProof(runtimeKeyFmt.Encode(&id))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if raw == nil {
+		return nil, nil, nil, roothash.ErrInvalidRuntime
+	}
+
+	var state RuntimeState
+	if err = cbor.Unmarshal(raw, &state); err != nil {
+		return nil, nil, nil, err
+	}
+	return &state, raw, proof, nil
+}
+
+// VerifyRuntimeStateProof verifies that the given serialized runtime state
+// is included in the state with the given state root and returns the
+// deserialized runtime state.
+func VerifyRuntimeStateProof(stateRoot []byte, id common.Namespace, raw, proof []byte) (*RuntimeState, error) {
+	if err := abci.VerifyProof(stateRoot, runtimeKeyFmt.Encode(&id), raw, proof); err != nil {
+		return nil, err
+	}
+
+	var state RuntimeState
+	if err := cbor.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("tendermint/roothash: malformed runtime state: %w", err)
+	}
+	return &state, nil
+}
+
 func (s *ImmutableState) Runtimes() []*RuntimeState {
 	var runtimes []*RuntimeState
 	s.Snapshot.IterateRange(
@@ -23,4 +23,9 @@ var (
 	// KeyElected is the ABCI event attribute key for the elected
 	// committee types.
 	KeyElected = []byte("elected")
+
+	// KeyParametersUpdate is the ABCI event attribute key for consensus
+	// parameter updates (value is a CBOR-serialized
+	// consensus.ParametersUpdateEvent).
+	KeyParametersUpdate = []byte("parameters.update")
 )
@@ -17,6 +17,8 @@ type Query interface {
 	Genesis(context.Context) (*scheduler.Genesis, error)
 }
 
+var _ abci.QueryFactory = (*QueryFactory)(nil)
+
 // QueryFactory is the scheduler query factory.
 type QueryFactory struct {
 	app *schedulerApplication
@@ -48,6 +50,11 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	return &schedulerQuerier{state}, nil
 }
 
+// GenericQueryAt implements abci.QueryFactory.
+func (sf *QueryFactory) GenericQueryAt(ctx context.Context, height int64) (abci.Query, error) {
+	return sf.QueryAt(ctx, height)
+}
+
 type schedulerQuerier struct {
 	state *schedulerState.ImmutableState
 }
@@ -80,6 +87,6 @@ func (sq *schedulerQuerier) KindsCommittees(ctx context.Context, kinds []schedul
 	return sq.state.KindsCommittees(kinds)
 }
 
-func (app *schedulerApplication) QueryFactory() interface{} {
+func (app *schedulerApplication) QueryFactory() abci.QueryFactory {
 	return &QueryFactory{app}
 }
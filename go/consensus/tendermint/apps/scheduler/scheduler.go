@@ -109,7 +109,7 @@ func (app *schedulerApplication) ID() uint8 {
 }
 
 func (app *schedulerApplication) Methods() []transaction.MethodName {
-	return nil
+	return []transaction.MethodName{consensus.MethodUpdateParameters}
 }
 
 func (app *schedulerApplication) Blessed() bool {
@@ -251,7 +251,17 @@ func (app *schedulerApplication) BeginBlock(ctx *abci.Context, request types.Req
 }
 
 func (app *schedulerApplication) ExecuteTx(ctx *abci.Context, tx *transaction.Transaction) error {
-	return errUnexpectedTransaction
+	switch tx.Method {
+	case consensus.MethodUpdateParameters:
+		var update consensus.ParametersUpdate
+		if err := cbor.Unmarshal(tx.Body, &update); err != nil {
+			return consensus.ErrInvalidArgument
+		}
+
+		return app.updateParameters(ctx, &update)
+	default:
+		return errUnexpectedTransaction
+	}
 }
 
 func (app *schedulerApplication) ForeignExecuteTx(ctx *abci.Context, other abci.Application, tx *transaction.Transaction) error {
@@ -261,6 +271,11 @@ func (app *schedulerApplication) ForeignExecuteTx(ctx *abci.Context, other abci.
 func (app *schedulerApplication) EndBlock(ctx *abci.Context, req types.RequestEndBlock) (types.ResponseEndBlock, error) {
 	var resp types.ResponseEndBlock
 
+	// Propagate any consensus parameter updates to Tendermint.
+	if update := ctx.BlockContext().Get(parametersUpdateKey{}).(*consensus.ParametersUpdate); update != nil {
+		resp.ConsensusParamUpdates = tendermintParamUpdates(update)
+	}
+
 	state := schedulerState.NewMutableState(ctx.State())
 	pendingValidators, err := state.PendingValidators()
 	if err != nil {
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/abci/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+)
+
+// parametersUpdateKey is the block context key for the last consensus
+// parameters update applied in the current block.
+type parametersUpdateKey struct{}
+
+func (pk parametersUpdateKey) NewDefault() interface{} {
+	return (*consensus.ParametersUpdate)(nil)
+}
+
+func (app *schedulerApplication) updateParameters(ctx *abci.Context, update *consensus.ParametersUpdate) error {
+	params, err := abci.GetConsensusParameters(ctx.State().ImmutableTree)
+	if err != nil {
+		ctx.Logger().Error("UpdateParameters: failed to fetch consensus parameters",
+			"err", err,
+		)
+		return err
+	}
+	if params == nil {
+		// The consensus parameters have not been updated since genesis.
+		params = &ctx.AppState().Genesis().Consensus.Parameters
+	}
+
+	if !consensus.IsUpdateAuthority(params, ctx.TxSigner()) {
+		ctx.Logger().Error("UpdateParameters: signer is not an update authority",
+			"signer", ctx.TxSigner(),
+		)
+		return consensus.ErrForbidden
+	}
+
+	if err = update.SanityCheck(); err != nil {
+		return err
+	}
+	if update.MaxBlockSize > tmtypes.MaxBlockSizeBytes {
+		return fmt.Errorf("%w: maximum block size exceeds %d bytes", consensus.ErrInvalidArgument, tmtypes.MaxBlockSizeBytes)
+	}
+
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// The multiplexer applies the updated parameters starting with the next
+	// block, while the Tendermint parameters are updated in EndBlock.
+	abci.SetConsensusParameters(ctx.State(), update.Apply(params))
+	ctx.BlockContext().Set(parametersUpdateKey{}, update)
+
+	ev := &consensus.ParametersUpdateEvent{
+		Old: *consensus.NewParametersUpdate(params),
+		New: *update,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyParametersUpdate, cbor.Marshal(ev)))
+
+	return nil
+}
+
+// tendermintParamUpdates returns the Tendermint consensus parameter updates
+// for the given consensus parameters update.
+func tendermintParamUpdates(update *consensus.ParametersUpdate) *types.ConsensusParams {
+	// Translate special "disable block gas limit" value as Tendermint uses
+	// -1 and we use 0.
+	maxBlockGas := int64(update.MaxBlockGas)
+	if maxBlockGas == 0 {
+		maxBlockGas = -1
+	}
+
+	return &types.ConsensusParams{
+		Block: &types.BlockParams{
+			MaxBytes: int64(update.MaxBlockSize),
+			MaxGas:   maxBlockGas,
+		},
+		Evidence: &types.EvidenceParams{
+			MaxAge: int64(update.MaxEvidenceAge),
+		},
+	}
+}
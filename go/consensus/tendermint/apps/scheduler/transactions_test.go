@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/abci/types"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	consensusGenesis "github.com/oasislabs/oasis-core/go/consensus/genesis"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+)
+
+func TestUpdateParameters(t *testing.T) {
+	require := require.New(t)
+
+	authority := memorySigner.NewTestSigner("consensus parameters update test: authority")
+	other := memorySigner.NewTestSigner("consensus parameters update test: other")
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	ctx := abci.NewContext(abci.ContextDeliverTx, time.Now(), appState)
+	defer ctx.Close()
+
+	app := &schedulerApplication{state: appState}
+	params := &consensusGenesis.Parameters{
+		TimeoutCommit:     1 * time.Second,
+		MaxTxSize:         32 * 1024,
+		MaxBlockSize:      21 * 1024 * 1024,
+		MaxBlockGas:       0,
+		MaxTxPerBlock:     0,
+		MaxEvidenceAge:    100000,
+		UpdateAuthorities: []signature.PublicKey{authority.Public()},
+	}
+	abci.SetConsensusParameters(ctx.State(), params)
+
+	update := &consensus.ParametersUpdate{
+		MaxTxSize:      64 * 1024,
+		MaxBlockSize:   22 * 1024 * 1024,
+		MaxBlockGas:    1000000,
+		MaxTxPerBlock:  1000,
+		MaxEvidenceAge: 50000,
+	}
+	tx := consensus.NewUpdateParametersTx(0, nil, update)
+
+	// Updates by other signers should be rejected.
+	ctx.SetTxSigner(other.Public())
+	err := app.ExecuteTx(ctx, tx)
+	require.True(errors.Is(err, consensus.ErrForbidden), "unauthorized updates should be rejected")
+	stored, err := abci.GetConsensusParameters(ctx.State().ImmutableTree)
+	require.NoError(err, "GetConsensusParameters")
+	require.EqualValues(params, stored, "unauthorized updates should not change the parameters")
+	require.False(ctx.HasEvent(app.Name(), KeyParametersUpdate), "unauthorized updates should not emit events")
+
+	// Unauthorized signers should be rejected before the update is validated.
+	invalid := *update
+	invalid.MaxBlockSize = 0
+	err = app.ExecuteTx(ctx, consensus.NewUpdateParametersTx(0, nil, &invalid))
+	require.True(errors.Is(err, consensus.ErrForbidden), "unauthorized invalid updates should be rejected as forbidden")
+
+	// Invalid updates should be rejected.
+	ctx.SetTxSigner(authority.Public())
+	err = app.ExecuteTx(ctx, consensus.NewUpdateParametersTx(0, nil, &invalid))
+	require.True(errors.Is(err, consensus.ErrInvalidArgument), "invalid updates should be rejected")
+
+	// Updates by an update authority should be accepted.
+	err = app.ExecuteTx(ctx, tx)
+	require.NoError(err, "authorized updates should be accepted")
+	stored, err = abci.GetConsensusParameters(ctx.State().ImmutableTree)
+	require.NoError(err, "GetConsensusParameters")
+	require.EqualValues(update.Apply(params), stored, "the parameters should be updated")
+	require.Equal(params.TimeoutCommit, stored.TimeoutCommit, "other parameters should be retained")
+	require.Equal(params.UpdateAuthorities, stored.UpdateAuthorities, "update authorities should be retained")
+
+	var ev consensus.ParametersUpdateEvent
+	var found bool
+	for _, e := range ctx.GetEvents() {
+		for _, pair := range e.Attributes {
+			if !bytes.Equal(pair.GetKey(), KeyParametersUpdate) {
+				continue
+			}
+			require.NoError(cbor.Unmarshal(pair.GetValue(), &ev), "event should deserialize")
+			found = true
+		}
+	}
+	require.True(found, "authorized updates should emit an event")
+	require.Equal(*consensus.NewParametersUpdate(params), ev.Old, "event should record the old values")
+	require.Equal(*update, ev.New, "event should record the new values")
+
+	// The Tendermint consensus parameters should be updated at the end of
+	// the block.
+	endCtx := abci.NewContext(abci.ContextEndBlock, time.Now(), appState)
+	defer endCtx.Close()
+	resp, err := app.EndBlock(endCtx, types.RequestEndBlock{})
+	require.NoError(err, "EndBlock")
+	require.NotNil(resp.ConsensusParamUpdates, "EndBlock should return consensus parameter updates")
+	require.EqualValues(update.MaxBlockSize, resp.ConsensusParamUpdates.Block.MaxBytes, "block size should be updated")
+	require.EqualValues(update.MaxBlockGas, resp.ConsensusParamUpdates.Block.MaxGas, "block gas should be updated")
+	require.EqualValues(update.MaxEvidenceAge, resp.ConsensusParamUpdates.Evidence.MaxAge, "evidence age should be updated")
+}
@@ -27,5 +27,5 @@ func (app *stakingApplication) GetSignerNonce(ctx context.Context, id signature.
 
 // Implements abci.TransactionAuthHandler.
 func (app *stakingApplication) AuthenticateTx(ctx *abci.Context, tx *transaction.Transaction) error {
-	return stakingState.AuthenticateAndPayFees(ctx, ctx.TxSigner(), tx.Nonce, tx.Fee)
+	return stakingState.AuthenticateAndPayFees(ctx, ctx.TxSigner(), tx.Nonce, tx.Fee, tx.Method)
 }
@@ -0,0 +1,234 @@
+package staking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+func mustQuantity(t *testing.T, n uint64) quantity.Quantity {
+	var q quantity.Quantity
+	require.NoError(t, q.FromUint64(n), "FromUint64")
+	return q
+}
+
+func TestAuthenticateTxMinGasPricePerMethod(t *testing.T) {
+	require := require.New(t)
+
+	minGasPrice := mustQuantity(t, 1)
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{
+		BlockHeight: 1,
+		MinGasPrice: &minGasPrice,
+		MinGasPricePerMethod: map[transaction.MethodName]quantity.Quantity{
+			staking.MethodAddEscrow: mustQuantity(t, 10),
+		},
+	})
+	ctx := abci.NewContext(abci.ContextCheckTx, time.Now(), appState)
+	defer ctx.Close()
+
+	signer := memorySigner.NewTestSigner("staking auth min gas price per method test")
+	ctx.SetTxSigner(signer.Public())
+
+	state := stakingState.NewMutableState(ctx.State())
+	state.SetAccount(signer.Public(), &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: mustQuantity(t, 1000),
+		},
+	})
+
+	app := &stakingApplication{state: appState}
+	newTx := func(method transaction.MethodName, gasPrice uint64) *transaction.Transaction {
+		return &transaction.Transaction{
+			Method: method,
+			Fee: &transaction.Fee{
+				Amount: mustQuantity(t, 5*gasPrice),
+				Gas:    5,
+			},
+		}
+	}
+
+	// A gas price below the per-method floor should be rejected.
+	err := app.AuthenticateTx(ctx, newTx(staking.MethodAddEscrow, 5))
+	require.Equal(transaction.ErrGasPriceTooLow, err, "gas price below per-method floor should be rejected")
+
+	// The same gas price should be accepted for a method without a per-method floor.
+	err = app.AuthenticateTx(ctx, newTx(staking.MethodTransfer, 5))
+	require.NoError(err, "gas price above global floor should be accepted")
+
+	// Gas prices at the per-method floor should be accepted.
+	err = app.AuthenticateTx(ctx, newTx(staking.MethodAddEscrow, 10))
+	require.NoError(err, "gas price at per-method floor should be accepted")
+
+	// The global floor should still apply to other methods.
+	err = app.AuthenticateTx(ctx, newTx(staking.MethodTransfer, 0))
+	require.Equal(transaction.ErrGasPriceTooLow, err, "gas price below global floor should be rejected")
+}
+
+func TestAuthenticateTxSetMinGasPrice(t *testing.T) {
+	require := require.New(t)
+
+	minGasPrice := mustQuantity(t, 1)
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{
+		BlockHeight: 1,
+		MinGasPrice: &minGasPrice,
+	})
+
+	signer := memorySigner.NewTestSigner("staking auth set min gas price test")
+	app := &stakingApplication{state: appState}
+	tx := &transaction.Transaction{
+		Method: staking.MethodTransfer,
+		Fee: &transaction.Fee{
+			Amount: mustQuantity(t, 5*5),
+			Gas:    5,
+		},
+	}
+	authenticate := func(kind abci.ContextMode) error {
+		ctx := abci.NewContext(kind, time.Now(), appState)
+		defer ctx.Close()
+		if !ctx.IsCheckOnly() {
+			ctx.BlockContext().Set(abci.GasAccountantKey{}, abci.NewNopGasAccountant())
+		}
+		ctx.SetTxSigner(signer.Public())
+
+		state := stakingState.NewMutableState(ctx.State())
+		state.SetAccount(signer.Public(), &staking.Account{
+			General: staking.GeneralAccount{
+				Balance: mustQuantity(t, 1000),
+			},
+		})
+		return app.AuthenticateTx(ctx, tx)
+	}
+
+	require.NoError(authenticate(abci.ContextCheckTx), "transaction should be accepted in CheckTx")
+
+	// Raising the minimum gas price should reject the same transaction in
+	// CheckTx, but must not affect its validity in DeliverTx.
+	raised := mustQuantity(t, 10)
+	appState.SetMinGasPrice(&raised)
+	require.Equal(transaction.ErrGasPriceTooLow, authenticate(abci.ContextCheckTx), "transaction should be rejected in CheckTx after raising the minimum gas price")
+	require.NoError(authenticate(abci.ContextDeliverTx), "transaction should remain valid in DeliverTx")
+
+	globalPrice, _ := appState.MinGasPrices()
+	require.Equal(raised, *globalPrice, "MinGasPrices should return the new minimum gas price")
+
+	// Lowering it again should accept the transaction.
+	appState.SetMinGasPrice(&minGasPrice)
+	require.NoError(authenticate(abci.ContextCheckTx), "transaction should be accepted in CheckTx after lowering the minimum gas price")
+}
+
+func TestAuthenticateTxNonceWindow(t *testing.T) {
+	require := require.New(t)
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	ctx := abci.NewContext(abci.ContextDeliverTx, time.Now(), appState)
+	defer ctx.Close()
+	ctx.BlockContext().Set(abci.GasAccountantKey{}, abci.NewNopGasAccountant())
+
+	windowed := memorySigner.NewTestSigner("staking auth nonce window test windowed")
+	strict := memorySigner.NewTestSigner("staking auth nonce window test strict")
+
+	state := stakingState.NewMutableState(ctx.State())
+	state.SetAccount(windowed.Public(), &staking.Account{
+		General: staking.GeneralAccount{
+			Nonce:       10,
+			NonceWindow: 4,
+		},
+	})
+	state.SetAccount(strict.Public(), &staking.Account{
+		General: staking.GeneralAccount{
+			Nonce: 10,
+		},
+	})
+
+	app := &stakingApplication{state: appState}
+	authenticate := func(signer signature.PublicKey, nonce uint64) error {
+		ctx.SetTxSigner(signer)
+		return app.AuthenticateTx(ctx, &transaction.Transaction{Nonce: nonce, Method: staking.MethodTransfer})
+	}
+
+	// Nonces within the window should be accepted out of order.
+	for _, nonce := range []uint64{12, 13, 10} {
+		require.NoError(authenticate(windowed.Public(), nonce), "nonce %d within the window should be accepted", nonce)
+	}
+	acct := state.Account(windowed.Public())
+	require.EqualValues(11, acct.General.Nonce, "account nonce should be the lowest unused nonce")
+
+	// Replays and nonces outside of the window should be rejected.
+	for _, nonce := range []uint64{9, 10, 12, 13, 15} {
+		err := authenticate(windowed.Public(), nonce)
+		require.Equal(transaction.ErrInvalidNonce, err, "nonce %d should be rejected", nonce)
+	}
+
+	// Filling the gap should advance the account nonce past all used nonces.
+	require.NoError(authenticate(windowed.Public(), 11), "nonce 11 should be accepted")
+	acct = state.Account(windowed.Public())
+	require.EqualValues(14, acct.General.Nonce, "account nonce should advance past used nonces")
+	require.EqualValues(0, acct.General.NonceBitmap, "nonce bitmap should be empty")
+	require.NoError(authenticate(windowed.Public(), 17), "nonce 17 should be accepted after the window advanced")
+
+	// The default window should require strictly incrementing nonces.
+	require.Equal(transaction.ErrInvalidNonce, authenticate(strict.Public(), 11), "future nonce should be rejected")
+	require.NoError(authenticate(strict.Public(), 10), "next nonce should be accepted")
+	require.Equal(transaction.ErrInvalidNonce, authenticate(strict.Public(), 10), "replayed nonce should be rejected")
+	acct = state.Account(strict.Public())
+	require.EqualValues(11, acct.General.Nonce, "account nonce should be incremented")
+}
+
+func TestSetNonceWindow(t *testing.T) {
+	require := require.New(t)
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	ctx := abci.NewContext(abci.ContextDeliverTx, time.Now(), appState)
+	defer ctx.Close()
+	ctx.BlockContext().Set(abci.GasAccountantKey{}, abci.NewNopGasAccountant())
+
+	signer := memorySigner.NewTestSigner("staking set nonce window test")
+	ctx.SetTxSigner(signer.Public())
+
+	state := stakingState.NewMutableState(ctx.State())
+	state.SetConsensusParameters(&staking.ConsensusParameters{})
+	state.SetAccount(signer.Public(), &staking.Account{
+		General: staking.GeneralAccount{
+			Nonce: 10,
+		},
+	})
+
+	app := &stakingApplication{state: appState}
+	execute := func(nonce, window uint64) error {
+		tx := staking.NewSetNonceWindowTx(nonce, nil, &staking.SetNonceWindow{NonceWindow: window})
+		if err := app.AuthenticateTx(ctx, tx); err != nil {
+			return err
+		}
+		return app.ExecuteTx(ctx, tx)
+	}
+
+	// Enlarging the window should allow nonces to be used out of order.
+	require.NoError(execute(10, 4), "SetNonceWindow should enlarge the window")
+	acct := state.Account(signer.Public())
+	require.EqualValues(4, acct.General.NonceWindow, "nonce window should be updated")
+	require.NoError(app.AuthenticateTx(ctx, &transaction.Transaction{Nonce: 14, Method: staking.MethodTransfer}), "nonce within the window should be accepted")
+
+	// The window can not be shrunk past used nonces or grow past the maximum.
+	require.Equal(staking.ErrInvalidArgument, execute(11, 2), "SetNonceWindow should not drop used nonces")
+	require.Equal(staking.ErrInvalidArgument, execute(12, staking.MaxNonceWindow+1), "SetNonceWindow should reject oversized windows")
+	acct = state.Account(signer.Public())
+	require.EqualValues(4, acct.General.NonceWindow, "rejected updates should not change the nonce window")
+
+	// Resetting the window should restore strictly incrementing nonces.
+	require.NoError(app.AuthenticateTx(ctx, &transaction.Transaction{Nonce: 13, Method: staking.MethodTransfer}), "nonce 13 should be accepted")
+	acct = state.Account(signer.Public())
+	require.EqualValues(15, acct.General.Nonce, "account nonce should advance past used nonces")
+	require.NoError(execute(15, 1), "SetNonceWindow should reset the window")
+	acct = state.Account(signer.Public())
+	require.EqualValues(0, acct.General.NonceWindow, "default nonce window should not be serialized")
+	require.Equal(transaction.ErrInvalidNonce, app.AuthenticateTx(ctx, &transaction.Transaction{Nonce: 17, Method: staking.MethodTransfer}), "future nonce should be rejected")
+}
@@ -3,10 +3,13 @@ package staking
 import (
 	"fmt"
 
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/quantity"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
 	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
 
 type disbursement struct {
@@ -14,6 +17,63 @@ type disbursement struct {
 	weight int64
 }
 
+// burnFees burns the fraction of the given fees configured by the fee burn
+// consensus parameters, reducing the total supply accordingly. The burned
+// amount is subtracted from fees.
+//
+// A burn event without an owner is emitted for the burned fees.
+func (app *stakingApplication) burnFees(ctx *abci.Context, stakeState *stakingState.MutableState, fees *quantity.Quantity) error {
+	params, err := stakeState.ConsensusParameters()
+	if err != nil {
+		return fmt.Errorf("staking: failed to query consensus parameters: %w", err)
+	}
+	if params.FeeBurnDenominator == 0 || params.FeeBurnNumerator == 0 {
+		// Fee burning is disabled.
+		return nil
+	}
+
+	// burnAmount = fees * FeeBurnNumerator / FeeBurnDenominator
+	var numerator, denominator quantity.Quantity
+	_ = numerator.FromUint64(params.FeeBurnNumerator)
+	_ = denominator.FromUint64(params.FeeBurnDenominator)
+
+	burnAmount := fees.Clone()
+	if err = burnAmount.Mul(&numerator); err != nil {
+		return fmt.Errorf("staking: failed to compute burned fees: %w", err)
+	}
+	if err = burnAmount.Quo(&denominator); err != nil {
+		return fmt.Errorf("staking: failed to compute burned fees: %w", err)
+	}
+
+	totalSupply, err := stakeState.TotalSupply()
+	if err != nil {
+		return fmt.Errorf("staking: failed to query total supply: %w", err)
+	}
+	if err = fees.Sub(burnAmount); err != nil {
+		return fmt.Errorf("staking: failed to burn fees: %w", err)
+	}
+	if err = totalSupply.Sub(burnAmount); err != nil {
+		ctx.Logger().Error("failed to burn fees",
+			"err", err,
+			"amount", burnAmount,
+			"total_supply", totalSupply,
+		)
+		return fmt.Errorf("staking: failed to burn fees: %w", err)
+	}
+	stakeState.SetTotalSupply(totalSupply)
+
+	ctx.Logger().Debug("burned fees",
+		"amount", burnAmount,
+	)
+
+	evt := &staking.BurnEvent{
+		Tokens: *burnAmount,
+	}
+	ctx.EmitEvent(api.NewEventBuilder(app.Name()).Attribute(KeyBurn, cbor.Marshal(evt)))
+
+	return nil
+}
+
 // disburseFees disburses fees.
 //
 // In case of errors the state may be inconsistent.
@@ -33,6 +93,15 @@ func (app *stakingApplication) disburseFees(ctx *abci.Context, signingEntities [
 		return nil
 	}
 
+	// Burn the configured fraction of the fees first.
+	if err = app.burnFees(ctx, stakeState, totalFees); err != nil {
+		return err
+	}
+	if totalFees.IsZero() {
+		// Everything has been burned.
+		return nil
+	}
+
 	var rewardAccounts []disbursement
 	var totalWeight int64
 	for _, entityID := range signingEntities {
@@ -45,13 +114,17 @@ func (app *stakingApplication) disburseFees(ctx *abci.Context, signingEntities [
 		totalWeight += d.weight
 	}
 
-	// Calculate the amount of fees to disburse.
-	var totalWeightQ quantity.Quantity
-	_ = totalWeightQ.FromInt64(totalWeight)
+	// Calculate the amount of fees to disburse. In case there are no signing
+	// entities, everything goes to the common pool.
+	feeShare := quantity.NewQuantity()
+	if totalWeight > 0 {
+		var totalWeightQ quantity.Quantity
+		_ = totalWeightQ.FromInt64(totalWeight)
 
-	feeShare := totalFees.Clone()
-	if err := feeShare.Quo(&totalWeightQ); err != nil {
-		return err
+		feeShare = totalFees.Clone()
+		if err := feeShare.Quo(&totalWeightQ); err != nil {
+			return err
+		}
 	}
 	for _, d := range rewardAccounts {
 		var weightQ quantity.Quantity
@@ -0,0 +1,136 @@
+package staking
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+func TestDisburseFeesBurn(t *testing.T) {
+	require := require.New(t)
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+	ctx := abci.NewContext(abci.ContextBeginBlock, time.Now(), appState)
+	defer ctx.Close()
+
+	signer := memorySigner.NewTestSigner("staking fee burn test")
+
+	// A quarter of the fees should be burned.
+	state := stakingState.NewMutableState(ctx.State())
+	state.SetConsensusParameters(&staking.ConsensusParameters{
+		FeeBurnNumerator:   1,
+		FeeBurnDenominator: 4,
+	})
+	state.SetAccount(signer.Public(), &staking.Account{
+		General: staking.GeneralAccount{
+			Balance: mustQuantity(t, 900),
+		},
+	})
+	totalSupply := mustQuantity(t, 1000)
+	state.SetTotalSupply(&totalSupply)
+	state.SetCommonPool(quantity.NewQuantity())
+	fees := mustQuantity(t, 100)
+	state.SetLastBlockFees(&fees)
+
+	app := &stakingApplication{state: appState}
+	err := app.disburseFees(ctx, nil)
+	require.NoError(err, "disburseFees")
+
+	newTotalSupply, err := state.TotalSupply()
+	require.NoError(err, "TotalSupply")
+	expectedTotalSupply := mustQuantity(t, 975)
+	require.Equal(&expectedTotalSupply, newTotalSupply, "burned fees should reduce the total supply")
+
+	commonPool, err := state.CommonPool()
+	require.NoError(err, "CommonPool")
+	expectedCommonPool := mustQuantity(t, 75)
+	require.Equal(&expectedCommonPool, commonPool, "remaining fees should go to the common pool")
+
+	// Balances plus common pool must still add up to the total supply.
+	total := state.Account(signer.Public()).General.Balance.Clone()
+	require.NoError(total.Add(commonPool), "Add")
+	require.Equal(newTotalSupply, total, "balances should add up to the total supply")
+
+	// A burn event for the burned fees should be emitted.
+	var events []*staking.BurnEvent
+	for _, ev := range ctx.GetEvents() {
+		for _, pair := range ev.GetAttributes() {
+			if !bytes.Equal(pair.GetKey(), KeyBurn) {
+				continue
+			}
+			var e staking.BurnEvent
+			err = cbor.Unmarshal(pair.GetValue(), &e)
+			require.NoError(err, "cbor.Unmarshal")
+			events = append(events, &e)
+		}
+	}
+	require.Len(events, 1, "a single burn event should be emitted")
+	require.Equal(signature.PublicKey{}, events[0].Owner, "burn event should not have an owner")
+	expectedBurned := mustQuantity(t, 25)
+	require.Equal(expectedBurned, events[0].Tokens, "burn event should include the burned fees")
+}
+
+func TestDisburseFeesSigners(t *testing.T) {
+	signers := []signature.PublicKey{
+		memorySigner.NewTestSigner("staking fee disbursement test 1").Public(),
+		memorySigner.NewTestSigner("staking fee disbursement test 2").Public(),
+		memorySigner.NewTestSigner("staking fee disbursement test 3").Public(),
+	}
+
+	for _, tc := range []struct {
+		name               string
+		signers            []signature.PublicKey
+		expectedBalance    uint64
+		expectedCommonPool uint64
+	}{
+		// Fees are split equally between signers, with the remainder going
+		// to the common pool.
+		{"Signers", signers, 33, 1},
+		// Without signers all fees go to the common pool.
+		{"NoSigners", nil, 0, 100},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require := require.New(t)
+
+			appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{BlockHeight: 1})
+			ctx := abci.NewContext(abci.ContextBeginBlock, time.Now(), appState)
+			defer ctx.Close()
+
+			state := stakingState.NewMutableState(ctx.State())
+			state.SetConsensusParameters(&staking.ConsensusParameters{})
+			totalSupply := mustQuantity(t, 100)
+			state.SetTotalSupply(&totalSupply)
+			state.SetCommonPool(quantity.NewQuantity())
+			fees := mustQuantity(t, 100)
+			state.SetLastBlockFees(&fees)
+
+			app := &stakingApplication{state: appState}
+			err := app.disburseFees(ctx, tc.signers)
+			require.NoError(err, "disburseFees")
+
+			expectedBalance := mustQuantity(t, tc.expectedBalance)
+			for _, id := range signers {
+				require.Equal(expectedBalance, state.Account(id).General.Balance, "signer balance")
+			}
+			commonPool, err := state.CommonPool()
+			require.NoError(err, "CommonPool")
+			expectedCommonPool := mustQuantity(t, tc.expectedCommonPool)
+			require.Equal(&expectedCommonPool, commonPool, "common pool")
+
+			newTotalSupply, err := state.TotalSupply()
+			require.NoError(err, "TotalSupply")
+			require.Equal(&totalSupply, newTotalSupply, "total supply should not change without burning")
+			require.False(ctx.HasEvent(app.Name(), KeyBurn), "no burn event should be emitted")
+		})
+	}
+}
@@ -26,8 +26,11 @@ type Query interface {
 	AccountInfo(context.Context, signature.PublicKey) (*staking.Account, error)
 	DebondingDelegations(context.Context, signature.PublicKey) (map[signature.PublicKey][]*staking.DebondingDelegation, error)
 	Genesis(context.Context) (*staking.Genesis, error)
+	ConsensusParameters(context.Context) (*staking.ConsensusParameters, error)
 }
 
+var _ abci.QueryFactory = (*QueryFactory)(nil)
+
 // QueryFactory is the staking query factory.
 type QueryFactory struct {
 	app *stakingApplication
@@ -59,6 +62,11 @@ func (sf *QueryFactory) QueryAt(ctx context.Context, height int64) (Query, error
 	return &stakingQuerier{state}, nil
 }
 
+// GenericQueryAt implements abci.QueryFactory.
+func (sf *QueryFactory) GenericQueryAt(ctx context.Context, height int64) (abci.Query, error) {
+	return sf.QueryAt(ctx, height)
+}
+
 type stakingQuerier struct {
 	state *stakingState.ImmutableState
 }
@@ -100,6 +108,10 @@ func (sq *stakingQuerier) DebondingDelegations(ctx context.Context, id signature
 	return sq.state.DebondingDelegationsFor(id)
 }
 
-func (app *stakingApplication) QueryFactory() interface{} {
+func (sq *stakingQuerier) ConsensusParameters(ctx context.Context) (*staking.ConsensusParameters, error) {
+	return sq.state.ConsensusParameters()
+}
+
+func (app *stakingApplication) QueryFactory() abci.QueryFactory {
 	return &QueryFactory{app}
 }
@@ -11,6 +11,7 @@ import (
 	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
 	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
 
@@ -66,8 +67,10 @@ func (app *stakingApplication) onEvidenceDoubleSign(
 
 	penalty := st[staking.SlashDoubleSigning]
 
-	// Freeze validator to prevent it being slashed again. This also prevents the
-	// validator from being scheduled in the next epoch.
+	// Compute the freeze period which prevents the validator from being slashed
+	// again. This also prevents the validator from being scheduled in the next
+	// epoch.
+	var freezeEndTime epochtime.EpochTime
 	if penalty.FreezeInterval > 0 {
 		var epoch epochtime.EpochTime
 		epoch, err = app.state.GetEpoch(context.Background(), ctx.BlockHeight()+1)
@@ -75,7 +78,7 @@ func (app *stakingApplication) onEvidenceDoubleSign(
 			return err
 		}
 
-		nodeStatus.FreezeEndTime = epoch + penalty.FreezeInterval
+		freezeEndTime = epoch + penalty.FreezeInterval
 	}
 
 	// Slash validator.
@@ -89,13 +92,16 @@ func (app *stakingApplication) onEvidenceDoubleSign(
 		return err
 	}
 
-	if err = regState.SetNodeStatus(node.ID, nodeStatus); err != nil {
-		ctx.Logger().Error("failed to set validator node status",
-			"err", err,
-			"node_id", node.ID,
-			"entity_id", node.EntityID,
-		)
-		return err
+	// Freeze validator.
+	if freezeEndTime > 0 {
+		if err = regState.FreezeNode(ctx, node.ID, nodeStatus, freezeEndTime, registry.FreezeReasonDoubleSigning); err != nil {
+			ctx.Logger().Error("failed to freeze validator node",
+				"err", err,
+				"node_id", node.ID,
+				"entity_id", node.EntityID,
+			)
+			return err
+		}
 	}
 
 	ctx.Logger().Warn("slashed validator for double signing",
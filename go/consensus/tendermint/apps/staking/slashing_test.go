@@ -0,0 +1,120 @@
+package staking
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/entity"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	registryState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/registry/state"
+	stakingState "github.com/oasislabs/oasis-core/go/consensus/tendermint/apps/staking/state"
+	tmcrypto "github.com/oasislabs/oasis-core/go/consensus/tendermint/crypto"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests/helpers"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
+)
+
+type testEpochTimeSource struct {
+	epochtime.Backend
+
+	epoch epochtime.EpochTime
+}
+
+func (ts *testEpochTimeSource) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
+	return ts.epoch, nil
+}
+
+func TestOnEvidenceDoubleSignFreeze(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	appState := abci.NewMockApplicationState(abci.MockApplicationStateConfig{
+		BlockHeight: 1,
+		TimeSource:  &testEpochTimeSource{epoch: 10},
+	})
+	ctx := abci.NewContext(abci.ContextDeliverTx, time.Now(), appState)
+	defer ctx.Close()
+
+	entitySigner := memorySigner.NewTestSigner("staking slashing freeze test entity")
+	nodeSigner := memorySigner.NewTestSigner("staking slashing freeze test node")
+	consensusSigner := memorySigner.NewTestSigner("staking slashing freeze test consensus")
+
+	regState := registryState.NewMutableState(ctx.State())
+	ent := &entity.Entity{ID: entitySigner.Public()}
+	regState.SetEntity(ent, &entity.SignedEntity{})
+	nod := &node.Node{
+		ID:       nodeSigner.Public(),
+		EntityID: ent.ID,
+		Consensus: node.ConsensusInfo{
+			ID: consensusSigner.Public(),
+		},
+	}
+	sigNode, err := node.SignNode(nodeSigner, registry.RegisterNodeSignatureContext, nod)
+	require.NoError(err, "SignNode")
+	err = regState.SetNode(nod, sigNode)
+	require.NoError(err, "SetNode")
+	err = regState.SetNodeStatus(nod.ID, &registry.NodeStatus{})
+	require.NoError(err, "SetNodeStatus")
+
+	stakeState := stakingState.NewMutableState(ctx.State())
+	stakeState.SetConsensusParameters(&staking.ConsensusParameters{
+		Slashing: map[staking.SlashReason]staking.Slash{
+			staking.SlashDoubleSigning: staking.Slash{
+				Amount:         mustQuantity(t, 10),
+				FreezeInterval: 2,
+			},
+		},
+	})
+	stakeState.SetAccount(ent.ID, &staking.Account{
+		Escrow: staking.EscrowAccount{
+			Active: staking.SharePool{
+				Balance:     mustQuantity(t, 100),
+				TotalShares: mustQuantity(t, 100),
+			},
+		},
+	})
+
+	app := &stakingApplication{state: appState}
+	addr := tmcrypto.PublicKeyToTendermint(&nod.Consensus.ID).Address()
+	err = app.onEvidenceDoubleSign(ctx, addr, 1, time.Now(), 1)
+	require.NoError(err, "onEvidenceDoubleSign")
+
+	status, err := regState.NodeStatus(nod.ID)
+	require.NoError(err, "NodeStatus")
+	require.True(status.IsFrozen(), "node should be frozen")
+	require.EqualValues(12, status.FreezeEndTime, "node should be frozen for the freeze interval")
+
+	// A freeze event carrying the reason should be emitted.
+	var events []*registry.NodeFrozenEvent
+	for _, ev := range ctx.GetEvents() {
+		for _, pair := range ev.GetAttributes() {
+			if !bytes.Equal(pair.GetKey(), registryState.KeyNodeFrozen) {
+				continue
+			}
+			var e registry.NodeFrozenEvent
+			err = cbor.Unmarshal(pair.GetValue(), &e)
+			require.NoError(err, "cbor.Unmarshal")
+			events = append(events, &e)
+		}
+	}
+	require.Len(events, 1, "a single freeze event should be emitted")
+	require.Equal(nod.ID, events[0].NodeID, "freeze event node ID should be correct")
+	require.EqualValues(12, events[0].FreezeEndTime, "freeze event end time should be correct")
+	require.Equal(registry.FreezeReasonDoubleSigning, events[0].Reason, "freeze event reason should be double signing")
+
+	// Frozen validators are not slashed (nor frozen) again.
+	ctx = abci.NewContext(abci.ContextDeliverTx, time.Now(), appState)
+	defer ctx.Close()
+	err = app.onEvidenceDoubleSign(ctx, addr, 1, time.Now(), 1)
+	require.NoError(err, "onEvidenceDoubleSign")
+	require.False(ctx.HasEvent(registryState.AppName, registryState.KeyNodeFrozen), "frozen nodes should not be frozen again")
+}
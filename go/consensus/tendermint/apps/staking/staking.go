@@ -114,7 +114,7 @@ func (app *stakingApplication) ExecuteTx(ctx *abci.Context, tx *transaction.Tran
 			return err
 		}
 
-		return app.reclaimEscrow(ctx, state, &reclaim)
+		return app.reclaimEscrow(ctx, state, tx.Nonce, &reclaim)
 	case staking.MethodAmendCommissionSchedule:
 		var amend staking.AmendCommissionSchedule
 		if err := cbor.Unmarshal(tx.Body, &amend); err != nil {
@@ -122,11 +122,58 @@ func (app *stakingApplication) ExecuteTx(ctx *abci.Context, tx *transaction.Tran
 		}
 
 		return app.amendCommissionSchedule(ctx, state, &amend)
+	case staking.MethodSetNonceWindow:
+		var set staking.SetNonceWindow
+		if err := cbor.Unmarshal(tx.Body, &set); err != nil {
+			return err
+		}
+
+		return app.setNonceWindow(ctx, state, &set)
 	default:
 		return staking.ErrInvalidArgument
 	}
 }
 
+// staticGasOps are the gas operations of methods whose gas cost only depends
+// on the gas cost table.
+var staticGasOps = map[transaction.MethodName]transaction.Op{
+	staking.MethodTransfer:                staking.GasOpTransfer,
+	staking.MethodBurn:                    staking.GasOpBurn,
+	staking.MethodAddEscrow:               staking.GasOpAddEscrow,
+	staking.MethodReclaimEscrow:           staking.GasOpReclaimEscrow,
+	staking.MethodAmendCommissionSchedule: staking.GasOpAmendCommissionSchedule,
+	staking.MethodSetNonceWindow:          staking.GasOpSetNonceWindow,
+}
+
+// Implements abci.StaticGasApplication.
+func (app *stakingApplication) StaticGasCost(method transaction.MethodName, height int64) (transaction.Gas, bool, error) {
+	op, ok := staticGasOps[method]
+	if !ok {
+		return 0, false, nil
+	}
+
+	state, err := abci.NewImmutableState(app.state, height)
+	if err != nil {
+		return 0, false, err
+	}
+	_, costs, err := app.GasCosts(state)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return costs[op], true, nil
+}
+
+// Implements abci.GasCostsApplication.
+func (app *stakingApplication) GasCosts(state *abci.ImmutableState) (string, transaction.Costs, error) {
+	params, err := (&stakingState.ImmutableState{ImmutableState: state}).ConsensusParameters()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return staking.ModuleName, params.GasCosts, nil
+}
+
 func (app *stakingApplication) ForeignExecuteTx(ctx *abci.Context, other abci.Application, tx *transaction.Transaction) error {
 	return nil
 }
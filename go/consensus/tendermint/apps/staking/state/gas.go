@@ -33,6 +33,7 @@ func AuthenticateAndPayFees(
 	id signature.PublicKey,
 	nonce uint64,
 	fee *transaction.Fee,
+	method transaction.MethodName,
 ) error {
 	state := NewMutableState(ctx.State())
 
@@ -46,10 +47,11 @@ func AuthenticateAndPayFees(
 
 	// Fetch account and make sure the nonce is correct.
 	account := state.Account(id)
-	if account.General.Nonce != nonce {
+	if !account.General.IsValidNonce(nonce) {
 		logger.Error("invalid account nonce",
 			"account_id", id,
 			"account_nonce", account.General.Nonce,
+			"account_nonce_window", account.General.NonceWindow,
 			"nonce", nonce,
 		)
 		return transaction.ErrInvalidNonce
@@ -73,7 +75,7 @@ func AuthenticateAndPayFees(
 		// NOTE: This is non-deterministic as it is derived from the local validator
 		//       configuration, but as long as it is only done in CheckTx, this is ok.
 		callerGasPrice := fee.GasPrice()
-		if fee.Gas > 0 && callerGasPrice.Cmp(ctx.AppState().MinGasPrice()) < 0 {
+		if fee.Gas > 0 && callerGasPrice.Cmp(ctx.AppState().MinGasPrice(method)) < 0 {
 			return transaction.ErrGasPriceTooLow
 		}
 
@@ -86,7 +88,9 @@ func AuthenticateAndPayFees(
 		return fmt.Errorf("staking: failed to pay fees: %w", err)
 	}
 
-	account.General.Nonce++
+	if err := account.General.UseNonce(nonce); err != nil {
+		return err
+	}
 	state.SetAccount(id, account)
 
 	// Configure gas accountant on the context.
@@ -208,7 +208,7 @@ func (app *stakingApplication) addEscrow(ctx *abci.Context, state *stakingState.
 	return nil
 }
 
-func (app *stakingApplication) reclaimEscrow(ctx *abci.Context, state *stakingState.MutableState, reclaim *staking.ReclaimEscrow) error {
+func (app *stakingApplication) reclaimEscrow(ctx *abci.Context, state *stakingState.MutableState, nonce uint64, reclaim *staking.ReclaimEscrow) error {
 	// No sense if there is nothing to reclaim.
 	if reclaim.Shares.IsZero() {
 		return staking.ErrInvalidArgument
@@ -295,8 +295,10 @@ func (app *stakingApplication) reclaimEscrow(ctx *abci.Context, state *stakingSt
 	}
 
 	// Include the nonce as the final disambiguator to prevent overwriting debonding
-	// delegations.
-	state.SetDebondingDelegation(id, reclaim.Account, to.General.Nonce, &deb)
+	// delegations. The transaction nonce is used (instead of the account nonce)
+	// as with a nonce window the account nonce need not advance on every
+	// transaction, while each transaction nonce can only be used once.
+	state.SetDebondingDelegation(id, reclaim.Account, nonce+1, &deb)
 
 	state.SetDelegation(id, reclaim.Account, delegation)
 	state.SetAccount(id, to)
@@ -345,3 +347,38 @@ func (app *stakingApplication) amendCommissionSchedule(
 
 	return nil
 }
+
+func (app *stakingApplication) setNonceWindow(
+	ctx *abci.Context,
+	state *stakingState.MutableState,
+	setNonceWindow *staking.SetNonceWindow,
+) error {
+	if ctx.IsCheckOnly() {
+		return nil
+	}
+
+	// Charge gas for this transaction.
+	params, err := state.ConsensusParameters()
+	if err != nil {
+		return err
+	}
+	if err = ctx.Gas().UseGas(1, staking.GasOpSetNonceWindow, params.GasCosts); err != nil {
+		return err
+	}
+
+	id := ctx.TxSigner()
+	from := state.Account(id)
+
+	if err = from.General.SetNonceWindow(setNonceWindow.NonceWindow); err != nil {
+		ctx.Logger().Error("SetNonceWindow: nonce window not acceptable",
+			"err", err,
+			"from", id,
+			"nonce_window", setNonceWindow.NonceWindow,
+		)
+		return err
+	}
+
+	state.SetAccount(id, from)
+
+	return nil
+}
@@ -52,7 +52,7 @@ func (app *supplementarySanityApplication) Dependencies() []string {
 	return []string{stakingState.AppName}
 }
 
-func (app *supplementarySanityApplication) QueryFactory() interface{} {
+func (app *supplementarySanityApplication) QueryFactory() abci.QueryFactory {
 	return nil
 }
 
@@ -0,0 +1,78 @@
+package tendermint
+
+import (
+	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/oasislabs/oasis-core/go/common/cache/lru"
+)
+
+// blockCache is a bounded cache of committed blocks and block results,
+// keyed by height.
+//
+// Committed blocks are immutable so entries are never invalidated, they
+// are only dropped on eviction. Only successful responses are cached so
+// queries for heights that are not (or no longer) available always reach
+// Tendermint and return its error.
+//
+// A nil blockCache is valid and caches nothing.
+type blockCache struct {
+	blocks  *lru.Cache
+	results *lru.Cache
+}
+
+func (c *blockCache) getBlock(height int64) *tmtypes.Block {
+	if c == nil {
+		return nil
+	}
+	if v, ok := c.blocks.Get(height); ok {
+		return v.(*tmtypes.Block)
+	}
+	return nil
+}
+
+func (c *blockCache) putBlock(blk *tmtypes.Block) {
+	if c == nil || blk == nil {
+		return
+	}
+	_ = c.blocks.Put(blk.Header.Height, blk)
+}
+
+func (c *blockCache) getResults(height int64) *tmrpctypes.ResultBlockResults {
+	if c == nil {
+		return nil
+	}
+	if v, ok := c.results.Get(height); ok {
+		return v.(*tmrpctypes.ResultBlockResults)
+	}
+	return nil
+}
+
+func (c *blockCache) putResults(results *tmrpctypes.ResultBlockResults) {
+	if c == nil || results == nil {
+		return
+	}
+	_ = c.results.Put(results.Height, results)
+}
+
+// newBlockCache creates a new block cache holding up to size blocks and
+// size block results. A size of zero disables caching.
+func newBlockCache(size uint64) (*blockCache, error) {
+	if size == 0 {
+		return nil, nil
+	}
+
+	blocks, err := lru.New(lru.Capacity(size, false))
+	if err != nil {
+		return nil, err
+	}
+	results, err := lru.New(lru.Capacity(size, false))
+	if err != nil {
+		return nil, err
+	}
+
+	return &blockCache{
+		blocks:  blocks,
+		results: results,
+	}, nil
+}
@@ -0,0 +1,186 @@
+package tendermint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	tmcli "github.com/tendermint/tendermint/rpc/client"
+	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+)
+
+// countingClient is a Tendermint client serving blocks in the range
+// [base, latest] and counting the number of queries.
+type countingClient struct {
+	tmcli.Client
+
+	base, latest int64
+
+	blockQueries   uint64
+	resultsQueries uint64
+}
+
+func (c *countingClient) resolveHeight(height *int64) (int64, error) {
+	if height == nil {
+		return c.latest, nil
+	}
+	if *height < c.base || *height > c.latest {
+		return 0, fmt.Errorf("height %d is not available", *height)
+	}
+	return *height, nil
+}
+
+func (c *countingClient) Block(height *int64) (*tmrpctypes.ResultBlock, error) {
+	atomic.AddUint64(&c.blockQueries, 1)
+	h, err := c.resolveHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return &tmrpctypes.ResultBlock{
+		Block: &tmtypes.Block{Header: tmtypes.Header{Height: h}},
+	}, nil
+}
+
+func (c *countingClient) BlockResults(height *int64) (*tmrpctypes.ResultBlockResults, error) {
+	atomic.AddUint64(&c.resultsQueries, 1)
+	h, err := c.resolveHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return &tmrpctypes.ResultBlockResults{Height: h}, nil
+}
+
+func newBlockCacheTestService(t testing.TB, client tmcli.Client, size uint64) *tendermintService {
+	cache, err := newBlockCache(size)
+	require.NoError(t, err, "newBlockCache")
+
+	startedCh := make(chan struct{})
+	close(startedCh)
+
+	return &tendermintService{
+		ctx:        context.Background(),
+		client:     client,
+		blockCache: cache,
+		startedCh:  startedCh,
+	}
+}
+
+func TestBlockCache(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	client := &countingClient{base: 10, latest: 20}
+	svc := newBlockCacheTestService(t, client, 4)
+
+	// Cache misses hit the client, cache hits do not.
+	for i := 0; i < 3; i++ {
+		blk, err := svc.GetBlock(ctx, 15)
+		require.NoError(err, "GetBlock")
+		require.EqualValues(15, blk.Height, "GetBlock should return the correct height")
+
+		height := int64(15)
+		results, err := svc.GetBlockResults(&height)
+		require.NoError(err, "GetBlockResults")
+		require.EqualValues(15, results.Height, "GetBlockResults should return the correct height")
+	}
+	require.EqualValues(1, client.blockQueries, "repeated block queries should be served from cache")
+	require.EqualValues(1, client.resultsQueries, "repeated block results queries should be served from cache")
+
+	// Latest height queries always hit the client, but populate the cache.
+	_, err := svc.GetBlock(ctx, consensusAPI.HeightLatest)
+	require.NoError(err, "GetBlock(HeightLatest)")
+	_, err = svc.GetBlock(ctx, consensusAPI.HeightLatest)
+	require.NoError(err, "GetBlock(HeightLatest)")
+	require.EqualValues(3, client.blockQueries, "latest block queries should not be served from cache")
+	_, err = svc.GetBlock(ctx, 20)
+	require.NoError(err, "GetBlock")
+	require.EqualValues(3, client.blockQueries, "latest block should be cached by height")
+
+	// Unavailable heights return the underlying error and are never cached.
+	for i := 0; i < 2; i++ {
+		_, err = svc.GetBlock(ctx, 5)
+		require.Error(err, "GetBlock below the available range should fail")
+
+		height := int64(5)
+		_, err = svc.GetBlockResults(&height)
+		require.Error(err, "GetBlockResults below the available range should fail")
+	}
+	require.EqualValues(5, client.blockQueries, "failed block queries should not be cached")
+	require.EqualValues(3, client.resultsQueries, "failed block results queries should not be cached")
+
+	// The cache is bounded.
+	for h := int64(10); h <= 14; h++ {
+		_, err = svc.GetBlock(ctx, h)
+		require.NoError(err, "GetBlock")
+	}
+	queries := client.blockQueries
+	_, err = svc.GetBlock(ctx, 10)
+	require.NoError(err, "GetBlock")
+	require.EqualValues(queries+1, client.blockQueries, "evicted blocks should be queried again")
+
+	// A disabled cache always hits the client.
+	client = &countingClient{base: 10, latest: 20}
+	svc = newBlockCacheTestService(t, client, 0)
+	for i := 0; i < 3; i++ {
+		_, err = svc.GetBlock(ctx, 15)
+		require.NoError(err, "GetBlock")
+	}
+	require.EqualValues(3, client.blockQueries, "disabled cache should not serve blocks")
+}
+
+func TestBlockCacheConcurrent(t *testing.T) {
+	ctx := context.Background()
+	client := &countingClient{base: 1, latest: 100}
+	svc := newBlockCacheTestService(t, client, 16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				height := int64(1 + (i*j)%32)
+				blk, err := svc.GetBlock(ctx, height)
+				require.NoError(t, err, "GetBlock")
+				require.EqualValues(t, height, blk.Height, "GetBlock should return the correct height")
+				results, err := svc.GetBlockResults(&height)
+				require.NoError(t, err, "GetBlockResults")
+				require.EqualValues(t, height, results.Height, "GetBlockResults should return the correct height")
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func benchmarkBlockCache(b *testing.B, size uint64) {
+	ctx := context.Background()
+	client := &countingClient{base: 1, latest: 1000}
+	svc := newBlockCacheTestService(b, client, size)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Emulate clients repeatedly requesting recent heights.
+		height := client.latest - int64(i%32)
+		if _, err := svc.GetBlock(ctx, height); err != nil {
+			b.Fatalf("GetBlock: %s", err)
+		}
+		if _, err := svc.GetBlockResults(&height); err != nil {
+			b.Fatalf("GetBlockResults: %s", err)
+		}
+	}
+	b.ReportMetric(float64(client.blockQueries+client.resultsQueries)/float64(b.N), "rpcs/op")
+}
+
+func BenchmarkBlockCacheDisabled(b *testing.B) {
+	benchmarkBlockCache(b, 0)
+}
+
+func BenchmarkBlockCache(b *testing.B) {
+	benchmarkBlockCache(b, 128)
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/eapache/channels"
 	tmtypes "github.com/tendermint/tendermint/types"
 
 	"github.com/oasislabs/oasis-core/go/common/logging"
@@ -124,15 +123,12 @@ func New(ctx context.Context, service service.TendermintService, interval int64)
 		interval: interval,
 		base:     base,
 		epoch:    base,
+		// Ensure that the epoch at the first block is always broadcasted.
+		lastNotified: api.EpochInvalid,
 	}
-	r.notifier = pubsub.NewBrokerEx(func(ch *channels.InfiniteChannel) {
-		r.RLock()
-		defer r.RUnlock()
-
-		if r.lastNotified == r.epoch {
-			ch.In() <- r.epoch
-		}
-	})
+	// Publishing the last broadcasted epoch from within the broker ensures
+	// that each subscriber observes every transition exactly once.
+	r.notifier = pubsub.NewBroker(true)
 
 	go r.worker(ctx)
 
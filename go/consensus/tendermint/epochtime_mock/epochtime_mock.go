@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/eapache/channels"
 	tmtypes "github.com/tendermint/tendermint/types"
 
 	"github.com/oasislabs/oasis-core/go/common/cbor"
@@ -118,6 +117,20 @@ func (t *tendermintMockBackend) SetEpoch(ctx context.Context, epoch api.EpochTim
 	}
 }
 
+func (t *tendermintMockBackend) AdvanceEpoch(ctx context.Context, increment uint64) (api.EpochTime, error) {
+	epoch, err := t.GetEpoch(ctx, consensus.HeightLatest)
+	if err != nil {
+		return api.EpochInvalid, err
+	}
+
+	epoch += api.EpochTime(increment)
+	if err = t.SetEpoch(ctx, epoch); err != nil {
+		return api.EpochInvalid, err
+	}
+
+	return epoch, nil
+}
+
 func (t *tendermintMockBackend) worker(ctx context.Context) {
 	// Subscribe to blocks which advance the epoch.
 	sub, err := t.service.Subscribe("epochtime-worker", app.QueryApp)
@@ -145,10 +158,16 @@ func (t *tendermintMockBackend) worker(ctx context.Context) {
 		t.Lock()
 		t.epoch = epoch
 		t.currentBlock = height
-		t.notifier.Broadcast(t.epoch)
 		t.Unlock()
 	}
 
+	// Broadcast the initial epoch so that subscribers don't need to wait
+	// for the first transition.
+	t.Lock()
+	t.lastNotified = t.epoch
+	t.notifier.Broadcast(t.epoch)
+	t.Unlock()
+
 	for {
 		var event interface{}
 
@@ -227,15 +246,12 @@ func New(ctx context.Context, service service.TendermintService) (api.SetableBac
 		logger:  logging.GetLogger("epochtime/tendermint_mock"),
 		service: service,
 		querier: a.QueryFactory().(*app.QueryFactory),
+		// Ensure that the first epoch is always broadcasted.
+		lastNotified: api.EpochInvalid,
 	}
-	r.notifier = pubsub.NewBrokerEx(func(ch *channels.InfiniteChannel) {
-		r.RLock()
-		defer r.RUnlock()
-
-		if r.lastNotified == r.epoch {
-			ch.In() <- r.epoch
-		}
-	})
+	// Publishing the last broadcasted epoch from within the broker ensures
+	// that each subscriber observes every transition exactly once.
+	r.notifier = pubsub.NewBroker(true)
 
 	if base := service.GetGenesis().EpochTime.Base; base != 0 {
 		r.logger.Warn("ignoring non-zero base genesis epoch",
@@ -19,6 +19,11 @@ func (s *MuxState) Close() {
 	s.db.Close()
 }
 
+// DB returns the database backing the ABCI mux state.
+func (s *MuxState) DB() dbm.DB {
+	return s.db
+}
+
 // Tree returns the immutable tree representing ABCI mux state.
 func (s *MuxState) Tree() *iavl.ImmutableTree {
 	return s.tree
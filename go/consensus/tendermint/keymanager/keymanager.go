@@ -8,6 +8,7 @@ import (
 
 	"github.com/eapache/channels"
 	"github.com/pkg/errors"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
 	tmtypes "github.com/tendermint/tendermint/types"
 
 	"github.com/oasislabs/oasis-core/go/common"
@@ -64,6 +65,15 @@ func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (
 	return q.Genesis(ctx)
 }
 
+func (tb *tendermintBackend) ConsensusParameters(ctx context.Context, height int64) (*api.ConsensusParameters, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ConsensusParameters(ctx)
+}
+
 func (tb *tendermintBackend) worker(ctx context.Context) {
 	sub, err := tb.service.Subscribe("keymanager-worker", app.QueryApp)
 	if err != nil {
@@ -89,15 +99,25 @@ func (tb *tendermintBackend) worker(ctx context.Context) {
 		switch ev := event.(type) {
 		case tmtypes.EventDataNewBlock:
 			tb.onEventDataNewBlock(ev)
+		case tmtypes.EventDataTx:
+			tb.onEventDataTx(ev)
 		default:
 		}
 	}
 }
 
 func (tb *tendermintBackend) onEventDataNewBlock(ev tmtypes.EventDataNewBlock) {
-	events := ev.ResultBeginBlock.GetEvents()
+	events := append([]abcitypes.Event{}, ev.ResultBeginBlock.GetEvents()...)
 	events = append(events, ev.ResultEndBlock.GetEvents()...)
 
+	tb.onABCIEvents(events)
+}
+
+func (tb *tendermintBackend) onEventDataTx(tx tmtypes.EventDataTx) {
+	tb.onABCIEvents(tx.Result.Events)
+}
+
+func (tb *tendermintBackend) onABCIEvents(events []abcitypes.Event) {
 	for _, tmEv := range events {
 		if tmEv.GetType() != app.EventType {
 			continue
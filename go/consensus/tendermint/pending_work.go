@@ -0,0 +1,114 @@
+package tendermint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tmconfig "github.com/tendermint/tendermint/config"
+	tmmempool "github.com/tendermint/tendermint/mempool"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+)
+
+// configureEmptyBlockMode configures when Tendermint creates empty blocks.
+//
+// The configuration must be set before the Tendermint node is created as it
+// must not be changed afterwards.
+func configureEmptyBlockMode(cfg *tmconfig.ConsensusConfig, mode string, emptyBlockInterval time.Duration) error {
+	switch mode {
+	case emptyBlockModeInterval:
+		cfg.CreateEmptyBlocks = true
+		cfg.CreateEmptyBlocksInterval = emptyBlockInterval
+	case emptyBlockModeOnDemand:
+		// Blocks are only created when there are transactions in the
+		// mempool. Pending work is signalled by submitting pending work
+		// transactions, see pendingWorkTrigger.
+		cfg.CreateEmptyBlocks = false
+		cfg.CreateEmptyBlocksInterval = 0
+	default:
+		return fmt.Errorf("tendermint: unsupported empty block mode: '%s'", mode)
+	}
+	return nil
+}
+
+// pendingWork is pending work following a committed block.
+type pendingWork struct {
+	height int64
+	delay  time.Duration
+	ok     bool
+}
+
+// pendingWorkTrigger gets new blocks created in the on-demand empty block
+// mode by submitting pending work transactions to the local mempool once
+// the applications have pending work.
+type pendingWorkTrigger struct {
+	logger *logging.Logger
+
+	notifyCh chan *pendingWork
+}
+
+// notify is the ABCI pending work hook. It never blocks and only the most
+// recent notification is retained.
+func (p *pendingWorkTrigger) notify(blockHeight int64, delay time.Duration, ok bool) {
+	select {
+	case <-p.notifyCh:
+	default:
+	}
+	p.notifyCh <- &pendingWork{height: blockHeight, delay: delay, ok: ok}
+}
+
+func (p *pendingWorkTrigger) worker(ctx context.Context, mempool tmmempool.Mempool) {
+	var (
+		timer   *time.Timer
+		timerCh <-chan time.Time
+		height  int64
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pw := <-p.notifyCh:
+			if timer != nil {
+				timer.Stop()
+				timer, timerCh = nil, nil
+			}
+			if !pw.ok {
+				continue
+			}
+
+			height = pw.height + 1
+			timer = time.NewTimer(pw.delay)
+			timerCh = timer.C
+		case <-timerCh:
+			timer, timerCh = nil, nil
+
+			p.logger.Debug("submitting pending work transaction",
+				"height", height,
+			)
+
+			tx := tmtypes.Tx(abci.NewPendingWorkTx(height))
+			if err := mempool.CheckTx(tx, nil, tmmempool.TxInfo{}); err != nil {
+				p.logger.Error("failed to submit pending work transaction",
+					"err", err,
+					"height", height,
+				)
+			}
+		}
+	}
+}
+
+func newPendingWorkTrigger() *pendingWorkTrigger {
+	return &pendingWorkTrigger{
+		logger:   logging.GetLogger("consensus/tendermint/pending_work"),
+		notifyCh: make(chan *pendingWork, 1),
+	}
+}
@@ -29,10 +29,11 @@ type tendermintBackend struct {
 	service service.TendermintService
 	querier *app.QueryFactory
 
-	entityNotifier   *pubsub.Broker
-	nodeNotifier     *pubsub.Broker
-	nodeListNotifier *pubsub.Broker
-	runtimeNotifier  *pubsub.Broker
+	entityNotifier      *pubsub.Broker
+	nodeNotifier        *pubsub.Broker
+	nodeRemovalNotifier *pubsub.Broker
+	nodeListNotifier    *pubsub.Broker
+	runtimeNotifier     *pubsub.Broker
 }
 
 func (tb *tendermintBackend) Querier() *app.QueryFactory {
@@ -92,6 +93,24 @@ func (tb *tendermintBackend) GetNodes(ctx context.Context, height int64) ([]*nod
 	return q.Nodes(ctx)
 }
 
+func (tb *tendermintBackend) GetNodesWithStatus(ctx context.Context, height int64) ([]*api.NodeWithStatus, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.NodesWithStatus(ctx)
+}
+
+func (tb *tendermintBackend) GetEntityNodes(ctx context.Context, query *api.IDQuery) ([]*node.Node, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.EntityNodes(ctx, query.ID)
+}
+
 func (tb *tendermintBackend) WatchNodes(ctx context.Context) (<-chan *api.NodeEvent, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.NodeEvent)
 	sub := tb.nodeNotifier.Subscribe()
@@ -100,6 +119,53 @@ func (tb *tendermintBackend) WatchNodes(ctx context.Context) (<-chan *api.NodeEv
 	return typedCh, sub, nil
 }
 
+func (tb *tendermintBackend) WatchNodeRemovals(ctx context.Context) (<-chan *api.NodeEvent, pubsub.ClosableSubscription, error) {
+	typedCh := make(chan *api.NodeEvent)
+	sub := tb.nodeRemovalNotifier.Subscribe()
+	sub.Unwrap(typedCh)
+
+	return typedCh, sub, nil
+}
+
+func (tb *tendermintBackend) WatchNodesByRole(ctx context.Context, roles node.RolesMask) (<-chan *api.NodeEvent, pubsub.ClosableSubscription, error) {
+	nodeCh := make(chan *api.NodeEvent)
+	nodeSub := tb.nodeNotifier.Subscribe()
+	nodeSub.Unwrap(nodeCh)
+
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+	typedCh := make(chan *api.NodeEvent)
+	go func() {
+		defer close(typedCh)
+		defer nodeSub.Close()
+
+		for {
+			var (
+				ev *api.NodeEvent
+				ok bool
+			)
+			select {
+			case ev, ok = <-nodeCh:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			if !ev.Node.HasRoles(roles) {
+				continue
+			}
+
+			select {
+			case typedCh <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return typedCh, sub, nil
+}
+
 func (tb *tendermintBackend) WatchNodeList(ctx context.Context) (<-chan *api.NodeList, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.NodeList)
 	sub := tb.nodeListNotifier.Subscribe()
@@ -117,6 +183,15 @@ func (tb *tendermintBackend) GetRuntime(ctx context.Context, query *api.Namespac
 	return q.Runtime(ctx, query.ID)
 }
 
+func (tb *tendermintBackend) IsRuntimeSuspended(ctx context.Context, query *api.NamespaceQuery) (bool, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return false, err
+	}
+
+	return q.IsRuntimeSuspended(ctx, query.ID)
+}
+
 func (tb *tendermintBackend) WatchRuntimes(ctx context.Context) (<-chan *api.Runtime, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.Runtime)
 	sub := tb.runtimeNotifier.Subscribe()
@@ -132,6 +207,15 @@ func (tb *tendermintBackend) GetNodeList(ctx context.Context, height int64) (*ap
 func (tb *tendermintBackend) Cleanup() {
 }
 
+func (tb *tendermintBackend) GetAllRuntimes(ctx context.Context, height int64) ([]*api.Runtime, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.AllRuntimes(ctx)
+}
+
 func (tb *tendermintBackend) GetRuntimes(ctx context.Context, height int64) ([]*api.Runtime, error) {
 	q, err := tb.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -150,6 +234,15 @@ func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (
 	return q.Genesis(ctx)
 }
 
+func (tb *tendermintBackend) ConsensusParameters(ctx context.Context, height int64) (*api.ConsensusParameters, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ConsensusParameters(ctx)
+}
+
 func (tb *tendermintBackend) worker(ctx context.Context) {
 	// Subscribe to transactions which modify state.
 	sub, err := tb.service.Subscribe("registry-worker", app.QueryApp)
@@ -215,6 +308,23 @@ func (tb *tendermintBackend) onABCIEvents(ctx context.Context, events []abcitype
 					tb.nodeNotifier.Broadcast(&api.NodeEvent{
 						Node:           node,
 						IsRegistration: false,
+						Reason:         api.NodeDeregistrationReasonExpired,
+					})
+				}
+			} else if bytes.Equal(pair.GetKey(), app.KeyNodesRemoved) {
+				var nodes []*node.Node
+				if err := cbor.Unmarshal(pair.GetValue(), &nodes); err != nil {
+					tb.logger.Error("worker: failed to get removed nodes from tag",
+						"err", err,
+					)
+					continue
+				}
+
+				for _, node := range nodes {
+					tb.nodeRemovalNotifier.Broadcast(&api.NodeEvent{
+						Node:           node,
+						IsRegistration: false,
+						Reason:         api.NodeDeregistrationReasonRemoved,
 					})
 				}
 			} else if bytes.Equal(pair.GetKey(), app.KeyRuntimeRegistered) {
@@ -310,12 +420,13 @@ func New(ctx context.Context, service service.TendermintService) (api.Backend, e
 	}
 
 	tb := &tendermintBackend{
-		logger:           logging.GetLogger("registry/tendermint"),
-		service:          service,
-		querier:          a.QueryFactory().(*app.QueryFactory),
-		entityNotifier:   pubsub.NewBroker(false),
-		nodeNotifier:     pubsub.NewBroker(false),
-		nodeListNotifier: pubsub.NewBroker(true),
+		logger:              logging.GetLogger("registry/tendermint"),
+		service:             service,
+		querier:             a.QueryFactory().(*app.QueryFactory),
+		entityNotifier:      pubsub.NewBroker(false),
+		nodeNotifier:        pubsub.NewBroker(false),
+		nodeRemovalNotifier: pubsub.NewBroker(false),
+		nodeListNotifier:    pubsub.NewBroker(true),
 	}
 	tb.runtimeNotifier = pubsub.NewBrokerEx(func(ch *channels.InfiniteChannel) {
 		wr := ch.In()
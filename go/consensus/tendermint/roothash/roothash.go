@@ -93,6 +93,15 @@ func (tb *tendermintBackend) GetLatestBlock(ctx context.Context, id common.Names
 	return tb.getLatestBlockAt(ctx, id, height)
 }
 
+func (tb *tendermintBackend) GetLatestBlockWithProof(ctx context.Context, id common.Namespace, height int64) (*api.BlockWithProof, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.LatestBlockWithProof(ctx, id)
+}
+
 func (tb *tendermintBackend) getLatestBlockAt(ctx context.Context, id common.Namespace, height int64) (*block.Block, error) {
 	q, err := tb.querier.QueryAt(ctx, height)
 	if err != nil {
@@ -197,6 +206,15 @@ func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (
 	return q.Genesis(ctx)
 }
 
+func (tb *tendermintBackend) ConsensusParameters(ctx context.Context, height int64) (*api.ConsensusParameters, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ConsensusParameters(ctx)
+}
+
 func (tb *tendermintBackend) Cleanup() {
 	tb.closeOnce.Do(func() {
 		<-tb.closedCh
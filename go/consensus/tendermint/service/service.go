@@ -8,6 +8,7 @@ import (
 	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
 	tmtypes "github.com/tendermint/tendermint/types"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
 	"github.com/oasislabs/oasis-core/go/common/service"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
@@ -46,20 +47,45 @@ type TendermintService interface {
 	// at a specific height.
 	GetBlockResults(height *int64) (*tmrpctypes.ResultBlockResults, error)
 
+	// QueryWithProof returns the value stored under the given key in the
+	// consensus state at the given height together with a proof of its
+	// inclusion, verifiable against the application hash.
+	QueryWithProof(ctx context.Context, key []byte, height int64) (*abci.ProvenValue, error)
+
 	// WatchTendermintBlocks returns a stream of Tendermint blocks as they are
 	// returned via the `EventDataNewBlock` query.
 	WatchTendermintBlocks() (<-chan *tmtypes.Block, *pubsub.Subscription)
 
+	// WatchBlockEvents returns a stream of all events emitted while
+	// processing each committed block together with their source.
+	WatchBlockEvents() (<-chan *abci.BlockEvents, *pubsub.Subscription)
+
 	// Subscribe subscribes to tendermint events.
 	Subscribe(subscriber string, query tmpubsub.Query) (tmtypes.Subscription, error)
 
 	// Unsubscribe unsubscribes from tendermint events.
 	Unsubscribe(subscriber string, query tmpubsub.Query) error
 
+	// WatchEvents returns a stream of events with the given key emitted by
+	// the given ABCI application (see api.NewEventBuilder).
+	WatchEvents(ctx context.Context, app string, eventKey []byte) (<-chan *EventWithHeight, pubsub.ClosableSubscription, error)
+
 	// Pruner returns the ABCI state pruner.
 	Pruner() abci.StatePruner
 }
 
+// EventWithHeight is an ABCI application event together with the height
+// of the block in which it was emitted.
+type EventWithHeight struct {
+	// Height is the height of the block in which the event was emitted.
+	Height int64
+	// TxHash is the hash of the transaction that emitted the event or nil
+	// if the event was not emitted by a transaction.
+	TxHash *hash.Hash
+	// Value is the CBOR-encoded event value.
+	Value []byte
+}
+
 // GenesisProvider is a tendermint specific genesis document provider.
 type GenesisProvider interface {
 	GetTendermintGenesisDocument() (*tmtypes.GenesisDoc, error)
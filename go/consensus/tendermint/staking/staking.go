@@ -80,6 +80,24 @@ func (tb *tendermintBackend) AccountInfo(ctx context.Context, query *api.OwnerQu
 	return q.AccountInfo(ctx, query.Owner)
 }
 
+func (tb *tendermintBackend) EffectiveCommission(ctx context.Context, query *api.EffectiveCommissionQuery) (*api.EffectiveCommission, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := q.AccountInfo(ctx, query.Owner)
+	if err != nil {
+		return nil, err
+	}
+	params, err := q.ConsensusParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.Escrow.CommissionSchedule.EffectiveCommission(&params.CommissionScheduleRules, query.Epoch)
+}
+
 func (tb *tendermintBackend) DebondingDelegations(ctx context.Context, query *api.OwnerQuery) (map[signature.PublicKey][]*api.DebondingDelegation, error) {
 	q, err := tb.querier.QueryAt(ctx, query.Height)
 	if err != nil {
@@ -89,6 +107,19 @@ func (tb *tendermintBackend) DebondingDelegations(ctx context.Context, query *ap
 	return q.DebondingDelegations(ctx, query.Owner)
 }
 
+func (tb *tendermintBackend) DebondingInfo(ctx context.Context, query *api.DebondingInfoQuery) ([]*api.DebondingDelegation, error) {
+	q, err := tb.querier.QueryAt(ctx, query.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	debs, err := q.DebondingDelegations(ctx, query.Delegator)
+	if err != nil {
+		return nil, err
+	}
+	return debs[query.Escrow], nil
+}
+
 func (tb *tendermintBackend) WatchTransfers(ctx context.Context) (<-chan *api.TransferEvent, pubsub.ClosableSubscription, error) {
 	typedCh := make(chan *api.TransferEvent)
 	sub := tb.transferNotifier.Subscribe()
@@ -122,6 +153,15 @@ func (tb *tendermintBackend) StateToGenesis(ctx context.Context, height int64) (
 	return q.Genesis(ctx)
 }
 
+func (tb *tendermintBackend) ConsensusParameters(ctx context.Context, height int64) (*api.ConsensusParameters, error) {
+	q, err := tb.querier.QueryAt(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ConsensusParameters(ctx)
+}
+
 func (tb *tendermintBackend) Cleanup() {
 	<-tb.closedCh
 }
@@ -1,12 +1,15 @@
 package tendermint
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,6 +19,7 @@ import (
 	"github.com/spf13/viper"
 	tmabcitypes "github.com/tendermint/tendermint/abci/types"
 	tmconfig "github.com/tendermint/tendermint/config"
+	tmed "github.com/tendermint/tendermint/crypto/ed25519"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmmempool "github.com/tendermint/tendermint/mempool"
@@ -24,6 +28,7 @@ import (
 	tmproxy "github.com/tendermint/tendermint/proxy"
 	tmcli "github.com/tendermint/tendermint/rpc/client"
 	tmrpctypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmstate "github.com/tendermint/tendermint/state"
 	tmtypes "github.com/tendermint/tendermint/types"
 
 	beaconAPI "github.com/oasislabs/oasis-core/go/beacon/api"
@@ -36,6 +41,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
 	cmservice "github.com/oasislabs/oasis-core/go/common/service"
 	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
@@ -62,6 +68,7 @@ import (
 	registryAPI "github.com/oasislabs/oasis-core/go/registry/api"
 	"github.com/oasislabs/oasis-core/go/roothash"
 	roothashAPI "github.com/oasislabs/oasis-core/go/roothash/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
 	schedulerAPI "github.com/oasislabs/oasis-core/go/scheduler/api"
 	stakingAPI "github.com/oasislabs/oasis-core/go/staking/api"
 )
@@ -76,6 +83,12 @@ const (
 	cfgABCIPruneStrategy = "tendermint.abci.prune.strategy"
 	cfgABCIPruneNumKept  = "tendermint.abci.prune.num_kept"
 
+	cfgABCIAppSoftDeadline = "tendermint.abci.app_soft_deadline"
+	cfgABCIAppHardDeadline = "tendermint.abci.app_hard_deadline"
+	cfgABCIStateCacheSize  = "tendermint.abci.state_cache_size"
+
+	cfgABCISignatureCacheSize = "tendermint.abci.signature_cache_size"
+
 	// CfgSentryUpstreamAddress defines nodes for which we act as a sentry for.
 	CfgSentryUpstreamAddress = "tendermint.sentry.upstream_address"
 
@@ -94,21 +107,61 @@ const (
 	CfgDebugP2PAddrBookLenient = "tendermint.debug.addr_book_lenient"
 	// CfgP2PDebugAllowDuplicateIP allows multiple connections from the same IP.
 	CfgDebugP2PAllowDuplicateIP = "tendermint.debug.allow_duplicate_ip"
+	// CfgDebugABCIRecoverInconsistentTrees configures recovering from
+	// inconsistent ABCI state trees on startup.
+	CfgDebugABCIRecoverInconsistentTrees = "tendermint.debug.abci_recover_inconsistent_trees"
 
 	// CfgConsensusMinGasPrice configures the minimum gas price for this validator.
 	CfgConsensusMinGasPrice = "consensus.tendermint.min_gas_price"
+	// CfgConsensusMinGasPricePerMethod configures per-method minimum gas
+	// prices for this validator (in the form of method=price).
+	CfgConsensusMinGasPricePerMethod = "consensus.tendermint.min_gas_price_per_method"
+	// CfgConsensusEmptyBlockMode configures when empty blocks are created.
+	CfgConsensusEmptyBlockMode = "consensus.tendermint.empty_block_mode"
+	// CfgConsensusBlockCacheSize configures the number of recent blocks
+	// and block results cached for queries.
+	CfgConsensusBlockCacheSize = "consensus.tendermint.block_cache_size"
+	// CfgConsensusTxIndex configures the transaction indexer used to look
+	// up transactions by hash.
+	CfgConsensusTxIndex = "consensus.tendermint.tx_index"
+	// CfgConsensusMempoolCacheSize configures the number of recently seen
+	// transactions remembered by the mempool to reject duplicates.
+	CfgConsensusMempoolCacheSize = "consensus.tendermint.mempool.cache_size"
+	// CfgConsensusMempoolMaxTxsBytes configures the maximum total size of
+	// all transactions in the mempool.
+	CfgConsensusMempoolMaxTxsBytes = "consensus.tendermint.mempool.max_txs_bytes"
+	// CfgConsensusMempoolTTL configures the number of blocks after which
+	// transactions still in the mempool are evicted on re-check.
+	CfgConsensusMempoolTTL = "consensus.tendermint.mempool.ttl"
+	// CfgConsensusMempoolRecheckGraceBlocks configures the number of blocks
+	// following an epoch transition during which transactions failing
+	// re-check are kept in the mempool.
+	CfgConsensusMempoolRecheckGraceBlocks = "consensus.tendermint.mempool.recheck_grace_blocks"
 	// CfgConsensusSubmissionGasPrice configures the gas price used when submitting transactions.
 	CfgConsensusSubmissionGasPrice = "consensus.tendermint.submission.gas_price"
 	// CfgConsensusSubmissionMaxFee configures the maximum fee that can be set.
 	CfgConsensusSubmissionMaxFee = "consensus.tendermint.submission.max_fee"
 
+	// emptyBlockModeInterval creates empty blocks at the configured
+	// genesis empty block interval.
+	emptyBlockModeInterval = "interval"
+	// emptyBlockModeOnDemand only creates empty blocks when applications
+	// have pending work.
+	emptyBlockModeOnDemand = "on_demand"
+
+	// txIndexerNull disables transaction indexing.
+	txIndexerNull = "null"
+	// txIndexerKV indexes transactions in the Tendermint database.
+	txIndexerKV = "kv"
+
 	// StateDir is the name of the directory located inside the node's data
 	// directory which contains the tendermint state.
 	StateDir = "tendermint"
 )
 
 var (
-	_ service.TendermintService = (*tendermintService)(nil)
+	_ service.TendermintService      = (*tendermintService)(nil)
+	_ consensusAPI.MinGasPriceSetter = (*tendermintService)(nil)
 
 	// Flags has the configuration flags.
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
@@ -162,13 +215,17 @@ type tendermintService struct {
 
 	cmservice.BaseBackgroundService
 
-	ctx           context.Context
-	svcMgr        *cmbackground.ServiceManager
-	mux           *abci.ApplicationServer
-	node          *tmnode.Node
-	client        tmcli.Client
+	ctx        context.Context
+	svcMgr     *cmbackground.ServiceManager
+	mux        *abci.ApplicationServer
+	node       *tmnode.Node
+	client     tmcli.Client
+	blockCache *blockCache
+
+	txIndexer     string
 	blockNotifier *pubsub.Broker
 	failMonitor   *failMonitor
+	pendingWork   *pendingWorkTrigger
 
 	beacon          beaconAPI.Backend
 	epochtime       epochtimeAPI.Backend
@@ -226,6 +283,9 @@ func (t *tendermintService) Start() error {
 		}
 		go t.syncWorker()
 		go t.worker()
+		if t.pendingWork != nil {
+			go t.pendingWork.worker(t.ctx, t.node.Mempool())
+		}
 	case false:
 		close(t.syncedCh)
 	}
@@ -399,6 +459,21 @@ func (t *tendermintService) StateToGenesis(ctx context.Context, blockHeight int6
 		return nil, err
 	}
 
+	// Export the current consensus parameters, which may have been updated
+	// on-chain since genesis.
+	consensusGenesis := genesisDoc.Consensus
+	consensusParams, err := t.mux.ConsensusParameters(blockHeight)
+	if err != nil {
+		t.Logger.Error("failed to query consensus parameters",
+			"err", err,
+			"block_height", blockHeight,
+		)
+		return nil, err
+	}
+	if consensusParams != nil {
+		consensusGenesis.Parameters = *consensusParams
+	}
+
 	return &genesisAPI.Document{
 		// XXX: Tendermint doesn't support restoring from non-0 height.
 		// https://github.com/tendermint/tendermint/issues/2543
@@ -413,7 +488,7 @@ func (t *tendermintService) StateToGenesis(ctx context.Context, blockHeight int6
 		KeyManager: *keymanagerGenesis,
 		Scheduler:  *schedulerGenesis,
 		Beacon:     genesisDoc.Beacon,
-		Consensus:  genesisDoc.Consensus,
+		Consensus:  consensusGenesis,
 	}, nil
 }
 
@@ -486,6 +561,74 @@ func (t *tendermintService) SubmitTx(ctx context.Context, tx *transaction.Signed
 	}
 }
 
+func (t *tendermintService) SubmitTxNoWait(ctx context.Context, tx *transaction.SignedTransaction) (hash.Hash, error) {
+	// Make sure that the Tendermint service has started so that we
+	// have the mempool available.
+	select {
+	case <-t.startedCh:
+	case <-t.ctx.Done():
+		return hash.Hash{}, t.ctx.Err()
+	case <-ctx.Done():
+		return hash.Hash{}, ctx.Err()
+	}
+
+	data := cbor.Marshal(tx)
+
+	var txHash hash.Hash
+	txHash.FromBytes(data)
+
+	if err := t.broadcastTxRaw(data); err != nil {
+		return hash.Hash{}, err
+	}
+	return txHash, nil
+}
+
+func (t *tendermintService) WaitForTransaction(ctx context.Context, txHash hash.Hash) (*consensusAPI.Result, error) {
+	// Subscribe to all transactions being included in a block as the
+	// Tendermint transaction hash differs from ours.
+	subID := t.newSubscriberID()
+	txSub, err := t.Subscribe(subID, tmtypes.EventQueryTx)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Unsubscribe(subID, tmtypes.EventQueryTx) // nolint: errcheck
+
+	// The transaction may have already been included in a block before we
+	// subscribed, so check the transaction index.
+	tx, err := t.GetTransaction(ctx, txHash)
+	switch err {
+	case nil:
+		return &tx.Result, nil
+	case consensusAPI.ErrTransactionNotFound, consensusAPI.ErrTransactionIndexDisabled:
+	default:
+		return nil, err
+	}
+
+	for {
+		select {
+		case v := <-txSub.Out():
+			ev := v.Data().(tmtypes.EventDataTx)
+
+			var h hash.Hash
+			h.FromBytes(ev.Tx)
+			if !h.Equal(&txHash) {
+				continue
+			}
+
+			return &consensusAPI.Result{
+				Height: ev.Height,
+				Module: ev.Result.GetCodespace(),
+				Code:   ev.Result.GetCode(),
+				Log:    ev.Result.GetLog(),
+			}, nil
+		case <-txSub.Cancelled():
+			return nil, context.Canceled
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
 func (t *tendermintService) broadcastTxRaw(data []byte) error {
 	// We could use t.client.BroadcastTxSync but that is annoying as it
 	// doesn't give you the right fields when CheckTx fails.
@@ -587,10 +730,92 @@ func (t *tendermintService) Unsubscribe(subscriber string, query tmpubsub.Query)
 	return fmt.Errorf("tendermint: unsubscribe called with no backing service")
 }
 
+func (t *tendermintService) WatchEvents(ctx context.Context, app string, eventKey []byte) (<-chan *service.EventWithHeight, pubsub.ClosableSubscription, error) {
+	query := api.QueryForEvent(app, eventKey)
+	subID := t.newSubscriberID()
+	sub, err := t.Subscribe(subID, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, csub := pubsub.NewContextSubscription(ctx)
+	ch := make(chan *service.EventWithHeight)
+	go func() {
+		defer close(ch)
+		defer t.Unsubscribe(subID, query) // nolint: errcheck
+
+		eventType := api.EventTypeForApp(app)
+		for {
+			var events []*service.EventWithHeight
+			select {
+			case msg := <-sub.Out():
+				switch ev := msg.Data().(type) {
+				case tmtypes.EventDataNewBlock:
+					tmEvents := append([]tmabcitypes.Event{}, ev.ResultBeginBlock.GetEvents()...)
+					tmEvents = append(tmEvents, ev.ResultEndBlock.GetEvents()...)
+					events = eventsWithHeight(tmEvents, eventType, eventKey, ev.Block.Header.Height, nil)
+				case tmtypes.EventDataTx:
+					var txHash hash.Hash
+					txHash.FromBytes(ev.Tx)
+					events = eventsWithHeight(ev.Result.Events, eventType, eventKey, ev.Height, &txHash)
+				default:
+				}
+			case <-sub.Cancelled():
+				return
+			case <-ctx.Done():
+				return
+			}
+
+			for _, ev := range events {
+				select {
+				case ch <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, csub, nil
+}
+
+// eventsWithHeight extracts the values of events with the given type and
+// key from a list of ABCI events.
+func eventsWithHeight(
+	events []tmabcitypes.Event,
+	eventType string,
+	eventKey []byte,
+	height int64,
+	txHash *hash.Hash,
+) []*service.EventWithHeight {
+	var result []*service.EventWithHeight
+	for _, ev := range events {
+		if ev.GetType() != eventType {
+			continue
+		}
+		for _, pair := range ev.GetAttributes() {
+			if !bytes.Equal(pair.GetKey(), eventKey) {
+				continue
+			}
+			result = append(result, &service.EventWithHeight{
+				Height: height,
+				TxHash: txHash,
+				Value:  pair.GetValue(),
+			})
+		}
+	}
+	return result
+}
+
 func (t *tendermintService) Pruner() abci.StatePruner {
 	return t.mux.Pruner()
 }
 
+func (t *tendermintService) SetMinGasPrice(ctx context.Context, price *quantity.Quantity) error {
+	t.mux.SetMinGasPrice(price)
+	return nil
+}
+
 func (t *tendermintService) RegisterApplication(app abci.Application) error {
 	return t.mux.Register(app)
 }
@@ -667,6 +892,167 @@ func (t *tendermintService) GetBlock(ctx context.Context, height int64) (*consen
 	return api.NewBlock(blk), nil
 }
 
+func (t *tendermintService) GetConsensusParameters(ctx context.Context, height int64) (*consensusAPI.Parameters, error) {
+	blk, err := t.GetTendermintBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	height = blk.Header.Height
+
+	registryParams, err := t.registry.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to query registry consensus parameters: %w", err)
+	}
+	roothashParams, err := t.roothash.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to query roothash consensus parameters: %w", err)
+	}
+	stakingParams, err := t.staking.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to query staking consensus parameters: %w", err)
+	}
+	keymanagerParams, err := t.keymanager.ConsensusParameters(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to query keymanager consensus parameters: %w", err)
+	}
+
+	params, err := t.mux.ConsensusParameters(height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to query consensus parameters: %w", err)
+	}
+	if params == nil {
+		// The consensus parameters have not been updated since genesis.
+		params = &t.genesis.Consensus.Parameters
+	}
+
+	return &consensusAPI.Parameters{
+		Height:        height,
+		MaxTxSize:     params.MaxTxSize,
+		MaxBlockSize:  params.MaxBlockSize,
+		MaxBlockGas:   transaction.Gas(params.MaxBlockGas),
+		MaxTxPerBlock: params.MaxTxPerBlock,
+		GasCosts: map[string]transaction.Costs{
+			registryAPI.ModuleName:   registryParams.GasCosts,
+			roothashAPI.ModuleName:   roothashParams.GasCosts,
+			stakingAPI.ModuleName:    stakingParams.GasCosts,
+			keymanagerAPI.ModuleName: keymanagerParams.GasCosts,
+		},
+	}, nil
+}
+
+func (t *tendermintService) GetMinGasPrices(ctx context.Context) (*consensusAPI.MinGasPrices, error) {
+	minGasPrice, minGasPricePerMethod := t.mux.MinGasPrices()
+
+	return &consensusAPI.MinGasPrices{
+		MinGasPrice:          *minGasPrice,
+		MinGasPricePerMethod: minGasPricePerMethod,
+	}, nil
+}
+
+func (t *tendermintService) GetValidatorSet(ctx context.Context, height int64) (*consensusAPI.ValidatorSet, error) {
+	// Make sure that the Tendermint service has started so that we
+	// have the client interface available.
+	select {
+	case <-t.startedCh:
+	case <-t.ctx.Done():
+		return nil, t.ctx.Err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var tmHeight *int64
+	if height != consensusAPI.HeightLatest {
+		tmHeight = &height
+	}
+	result, err := t.client.Validators(tmHeight)
+	if err != nil {
+		if _, ok := err.(tmstate.ErrNoValSetForHeight); ok {
+			return nil, consensusAPI.ErrVersionNotFound
+		}
+		return nil, fmt.Errorf("tendermint: validator set query failed: %w", err)
+	}
+
+	vs := &consensusAPI.ValidatorSet{
+		Height:     result.BlockHeight,
+		Validators: make([]*consensusAPI.ValidatorInfo, 0, len(result.Validators)),
+	}
+	for _, v := range result.Validators {
+		pk, ok := v.PubKey.(tmed.PubKeyEd25519)
+		if !ok {
+			return nil, fmt.Errorf("tendermint: unsupported validator public key type: %T", v.PubKey)
+		}
+		vs.Validators = append(vs.Validators, &consensusAPI.ValidatorInfo{
+			ID:               crypto.PublicKeyFromTendermint(&pk),
+			Address:          v.Address.String(),
+			VotingPower:      v.VotingPower,
+			ProposerPriority: v.ProposerPriority,
+		})
+	}
+	return vs, nil
+}
+
+func (t *tendermintService) GetRuntimeStates(ctx context.Context, height int64) ([]*consensusAPI.RuntimeState, error) {
+	blk, err := t.GetTendermintBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	height = blk.Header.Height
+
+	runtimes, err := t.registry.GetAllRuntimes(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to query registry runtimes: %w", err)
+	}
+	activeRuntimes, err := t.registry.GetRuntimes(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: failed to query registry runtimes: %w", err)
+	}
+	active := make(map[common.Namespace]bool, len(activeRuntimes))
+	for _, rt := range activeRuntimes {
+		active[rt.ID] = true
+	}
+
+	states := make([]*consensusAPI.RuntimeState, 0, len(runtimes))
+	for _, rt := range runtimes {
+		// Only compute runtimes have blocks.
+		var currentBlk *block.Block
+		if rt.IsCompute() {
+			if currentBlk, err = t.roothash.GetLatestBlock(ctx, rt.ID, height); err != nil {
+				return nil, fmt.Errorf("tendermint: failed to query latest block of runtime %s: %w", rt.ID, err)
+			}
+		}
+
+		states = append(states, &consensusAPI.RuntimeState{
+			Runtime:      rt,
+			Suspended:    !active[rt.ID],
+			CurrentBlock: currentBlk,
+		})
+	}
+	return states, nil
+}
+
+func (t *tendermintService) GetSignerNonce(ctx context.Context, req *consensusAPI.GetSignerNonceRequest) (uint64, error) {
+	return t.mux.TransactionAuthHandler().GetSignerNonce(ctx, req.ID, req.Height)
+}
+
+func (t *tendermintService) GetGenesisDigest(ctx context.Context) ([]byte, error) {
+	digest := t.mux.GenesisDigest()
+	if digest == nil {
+		return nil, consensusAPI.ErrNoCommittedBlocks
+	}
+	return digest, nil
+}
+
+func (t *tendermintService) GetGenesisDocument(ctx context.Context) (*consensusAPI.GenesisDocument, error) {
+	digest, err := t.GetGenesisDigest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &consensusAPI.GenesisDocument{
+		Document: t.genesis,
+		Digest:   digest,
+	}, nil
+}
+
 func (t *tendermintService) GetTransactions(ctx context.Context, height int64) ([][]byte, error) {
 	blk, err := t.GetTendermintBlock(ctx, height)
 	if err != nil {
@@ -680,6 +1066,47 @@ func (t *tendermintService) GetTransactions(ctx context.Context, height int64) (
 	return txs, nil
 }
 
+func (t *tendermintService) GetTransaction(ctx context.Context, txHash hash.Hash) (*consensusAPI.TransactionWithResult, error) {
+	if t.txIndexer == txIndexerNull {
+		return nil, consensusAPI.ErrTransactionIndexDisabled
+	}
+
+	// Make sure that the Tendermint service has started so that we
+	// have the client interface available.
+	select {
+	case <-t.startedCh:
+	case <-t.ctx.Done():
+		return nil, t.ctx.Err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	result, err := t.client.TxSearch(api.QueryForTxHash(txHash), false, 1, 1)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint: transaction query failed: %w", err)
+	}
+	if len(result.Txs) == 0 {
+		return nil, consensusAPI.ErrTransactionNotFound
+	}
+	tx := result.Txs[0]
+
+	var sigTx transaction.SignedTransaction
+	if err = cbor.Unmarshal(tx.Tx, &sigTx); err != nil {
+		return nil, fmt.Errorf("tendermint: malformed transaction: %w", err)
+	}
+
+	return &consensusAPI.TransactionWithResult{
+		Transaction: sigTx,
+		Index:       tx.Index,
+		Result: consensusAPI.Result{
+			Height: tx.Height,
+			Module: tx.TxResult.GetCodespace(),
+			Code:   tx.TxResult.GetCode(),
+			Log:    tx.TxResult.GetLog(),
+		},
+	}, nil
+}
+
 func (t *tendermintService) WatchBlocks(ctx context.Context) (<-chan *consensusAPI.Block, pubsub.ClosableSubscription, error) {
 	ch, sub := t.WatchTendermintBlocks()
 	mapCh := make(chan *consensusAPI.Block)
@@ -791,12 +1218,16 @@ func (t *tendermintService) GetTendermintBlock(ctx context.Context, height int64
 	if height == consensusAPI.HeightLatest {
 		tmHeight = nil
 	} else {
+		if blk := t.blockCache.getBlock(height); blk != nil {
+			return blk, nil
+		}
 		tmHeight = &height
 	}
 	result, err := t.client.Block(tmHeight)
 	if err != nil {
 		return nil, fmt.Errorf("tendermint: block query failed: %w", err)
 	}
+	t.blockCache.putBlock(result.Block)
 	return result.Block, nil
 }
 
@@ -808,15 +1239,26 @@ func (t *tendermintService) GetHeight(ctx context.Context) (int64, error) {
 	return blk.Header.Height, nil
 }
 
+func (t *tendermintService) QueryWithProof(ctx context.Context, key []byte, height int64) (*abci.ProvenValue, error) {
+	return t.mux.QueryWithProof(key, height)
+}
+
 func (t *tendermintService) GetBlockResults(height *int64) (*tmrpctypes.ResultBlockResults, error) {
 	if t.client == nil {
 		panic("client not available yet")
 	}
 
+	if height != nil {
+		if result := t.blockCache.getResults(*height); result != nil {
+			return result, nil
+		}
+	}
+
 	result, err := t.client.BlockResults(height)
 	if err != nil {
 		return nil, fmt.Errorf("tendermint: block results query failed: %w", err)
 	}
+	t.blockCache.putResults(result)
 
 	return result, nil
 }
@@ -829,6 +1271,10 @@ func (t *tendermintService) WatchTendermintBlocks() (<-chan *tmtypes.Block, *pub
 	return typedCh, sub
 }
 
+func (t *tendermintService) WatchBlockEvents() (<-chan *abci.BlockEvents, *pubsub.Subscription) {
+	return t.mux.WatchBlockEvents()
+}
+
 func (t *tendermintService) ConsensusKey() signature.PublicKey {
 	return t.consensusSigner.Public()
 }
@@ -873,11 +1319,26 @@ func (t *tendermintService) lazyInit() error {
 	pruneNumKept := int64(viper.GetInt(cfgABCIPruneNumKept))
 	pruneCfg.NumKept = pruneNumKept
 
+	minGasPricePerMethod, err := parseMinGasPricePerMethod(viper.GetStringSlice(CfgConsensusMinGasPricePerMethod))
+	if err != nil {
+		return fmt.Errorf("tendermint: failed to configure per-method minimum gas prices: %w", err)
+	}
+
 	appConfig := &abci.ApplicationConfig{
-		DataDir:         t.dataDir,
-		Pruning:         pruneCfg,
-		HaltEpochHeight: t.genesis.HaltEpoch,
-		MinGasPrice:     viper.GetUint64(CfgConsensusMinGasPrice),
+		DataDir:              t.dataDir,
+		Pruning:              pruneCfg,
+		HaltEpochHeight:      t.genesis.HaltEpoch,
+		MinGasPrice:          viper.GetUint64(CfgConsensusMinGasPrice),
+		MinGasPricePerMethod: minGasPricePerMethod,
+		MempoolTTL:           viper.GetUint64(CfgConsensusMempoolTTL),
+		RecheckGraceBlocks:   viper.GetUint64(CfgConsensusMempoolRecheckGraceBlocks),
+		AppSoftDeadline:      viper.GetDuration(cfgABCIAppSoftDeadline),
+		AppHardDeadline:      viper.GetDuration(cfgABCIAppHardDeadline),
+		StateCacheSize:       viper.GetInt(cfgABCIStateCacheSize),
+		SignatureCacheSize:   viper.GetUint64(cfgABCISignatureCacheSize),
+	}
+	if cmflags.DebugDontBlameOasis() {
+		appConfig.RecoverInconsistentTrees = viper.GetBool(CfgDebugABCIRecoverInconsistentTrees)
 	}
 	t.mux, err = abci.NewApplicationServer(t.ctx, appConfig)
 	if err != nil {
@@ -900,11 +1361,25 @@ func (t *tendermintService) lazyInit() error {
 	emptyBlockInterval := t.genesis.Consensus.Parameters.EmptyBlockInterval
 	tenderConfig.Consensus.TimeoutCommit = timeoutCommit
 	tenderConfig.Consensus.SkipTimeoutCommit = t.genesis.Consensus.Parameters.SkipTimeoutCommit
-	tenderConfig.Consensus.CreateEmptyBlocks = true
-	tenderConfig.Consensus.CreateEmptyBlocksInterval = emptyBlockInterval
+	emptyBlockMode := viper.GetString(CfgConsensusEmptyBlockMode)
+	if err = configureEmptyBlockMode(tenderConfig.Consensus, emptyBlockMode, emptyBlockInterval); err != nil {
+		return err
+	}
+	if emptyBlockMode == emptyBlockModeOnDemand {
+		t.pendingWork = newPendingWorkTrigger()
+		t.mux.RegisterPendingWorkHook(t.pendingWork.notify)
+	}
+	if err = configureMempool(tenderConfig.Mempool, t.genesis.Consensus.Parameters.MaxTxSize); err != nil {
+		return fmt.Errorf("tendermint: failed to configure mempool: %w", err)
+	}
 	tenderConfig.Instrumentation.Prometheus = true
 	tenderConfig.Instrumentation.PrometheusListenAddr = ""
-	tenderConfig.TxIndex.Indexer = "null"
+	tenderConfig.TxIndex.Indexer = t.txIndexer
+	if t.txIndexer != txIndexerNull {
+		// Only index transactions by their (Oasis) hash. The index is stored
+		// in a database created by the node's DB provider.
+		tenderConfig.TxIndex.IndexTags = api.EventTypeTx + "." + api.EventKeyTxHash
+	}
 	tenderConfig.P2P.ListenAddress = viper.GetString(CfgCoreListenAddress)
 	tenderConfig.P2P.ExternalAddress = viper.GetString(cfgCoreExternalAddress)
 	tenderConfig.P2P.PexReactor = !viper.GetBool(CfgP2PDisablePeerExchange)
@@ -914,11 +1389,11 @@ func (t *tendermintService) lazyInit() error {
 	// lowercasing the whole string is ok.
 	tenderConfig.P2P.PersistentPeers = strings.ToLower(strings.Join(viper.GetStringSlice(CfgP2PPersistentPeer), ","))
 	tenderConfig.P2P.SeedMode = viper.GetBool(CfgP2PSeedMode)
-	// Seed Ids need to be Lowecase as p2p/transport.go:MultiplexTransport.upgrade()
-	// uses a case sensitive string comparision to validate public keys.
-	// Since Seeds is expected to be in comma-delimited ID@host:port format,
-	// lowercasing the whole string is ok.
-	tenderConfig.P2P.Seeds = strings.ToLower(strings.Join(viper.GetStringSlice(CfgP2PSeed), ","))
+	seeds, err := seedsToTendermint(viper.GetStringSlice(CfgP2PSeed))
+	if err != nil {
+		return fmt.Errorf("tendermint: failed to configure seeds: %w", err)
+	}
+	tenderConfig.P2P.Seeds = seeds
 	tenderConfig.P2P.AddrBookStrict = !(viper.GetBool(CfgDebugP2PAddrBookLenient) && cmflags.DebugDontBlameOasis())
 	tenderConfig.P2P.AllowDuplicateIP = viper.GetBool(CfgDebugP2PAllowDuplicateIP) && cmflags.DebugDontBlameOasis()
 	tenderConfig.RPC.ListenAddress = ""
@@ -966,6 +1441,12 @@ func (t *tendermintService) lazyInit() error {
 		)
 		return err
 	}
+	if err = t.verifyGenesisDigest(tmGenDoc); err != nil {
+		t.Logger.Error("genesis digest verification failed",
+			"err", err,
+		)
+		return err
+	}
 	tendermintGenesisProvider := func() (*tmtypes.GenesisDoc, error) {
 		return tmGenDoc, nil
 	}
@@ -1081,6 +1562,101 @@ func genesisToTendermint(d *genesisAPI.Document) (*tmtypes.GenesisDoc, error) {
 	return &doc, nil
 }
 
+// seedsToTendermint converts a list of seed nodes to Tendermint's seed
+// configuration, deduplicating the entries by node ID and rejecting any
+// malformed entries.
+func seedsToTendermint(seeds []string) (string, error) {
+	var (
+		seedIDs = make(map[tmp2p.ID]bool)
+		result  []string
+	)
+	for _, seed := range seeds {
+		// Seed IDs need to be lowercase as p2p/transport.go:MultiplexTransport.upgrade()
+		// uses a case sensitive string comparision to validate public keys.
+		// Since seeds are expected to be in ID@host:port format, lowercasing
+		// the whole string is ok.
+		seed = strings.ToLower(strings.TrimSpace(seed))
+		if seed == "" {
+			continue
+		}
+
+		addr, err := tmp2p.NewNetAddressString(seed)
+		if err != nil {
+			return "", fmt.Errorf("malformed seed '%s': %w", seed, err)
+		}
+		if seedIDs[addr.ID] {
+			continue
+		}
+		seedIDs[addr.ID] = true
+		result = append(result, seed)
+	}
+
+	return strings.Join(result, ","), nil
+}
+
+// parseMinGasPricePerMethod parses a list of method=price per-method minimum
+// gas price entries.
+func parseMinGasPricePerMethod(entries []string) (map[transaction.MethodName]uint64, error) {
+	result := make(map[transaction.MethodName]uint64)
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed entry '%s'", entry)
+		}
+
+		method := transaction.MethodName(strings.TrimSpace(kv[0]))
+		if err := method.SanityCheck(); err != nil {
+			return nil, fmt.Errorf("malformed method in entry '%s': %w", entry, err)
+		}
+		price, err := strconv.ParseUint(strings.TrimSpace(kv[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed price in entry '%s': %w", entry, err)
+		}
+		result[method] = price
+	}
+	return result, nil
+}
+
+// genesisInitChainRequest returns the InitChain request that Tendermint will
+// generate for the given genesis document.
+//
+// NOTE: This must be kept in sync with tendermint/consensus/replay.go.
+func genesisInitChainRequest(doc *tmtypes.GenesisDoc) tmabcitypes.RequestInitChain {
+	validators := make([]*tmtypes.Validator, 0, len(doc.Validators))
+	for _, val := range doc.Validators {
+		validators = append(validators, tmtypes.NewValidator(val.PubKey, val.Power))
+	}
+
+	return tmabcitypes.RequestInitChain{
+		Time:            doc.GenesisTime,
+		ChainId:         doc.ChainID,
+		ConsensusParams: tmtypes.TM2PB.ConsensusParams(doc.ConsensusParams),
+		Validators:      tmtypes.TM2PB.ValidatorUpdates(tmtypes.NewValidatorSet(validators)),
+		AppStateBytes:   doc.AppState,
+	}
+}
+
+// verifyGenesisDigest verifies that the ABCI state (if any) has been
+// initialized from the given genesis document.
+func (t *tendermintService) verifyGenesisDigest(doc *tmtypes.GenesisDoc) error {
+	return checkGenesisDigest(t.mux.GenesisDigest(), doc)
+}
+
+// checkGenesisDigest verifies that the stored genesis digest (if any) matches
+// the digest of the given genesis document.
+func checkGenesisDigest(stored []byte, doc *tmtypes.GenesisDoc) error {
+	if stored == nil {
+		// Not yet initialized.
+		return nil
+	}
+
+	expected := abci.GenesisDigest(genesisInitChainRequest(doc))
+	if !bytes.Equal(stored, expected) {
+		return fmt.Errorf("tendermint: genesis document does not match existing state (expected digest: %X, state digest: %X)", expected, stored)
+	}
+	return nil
+}
+
 func (t *tendermintService) getTendermintGenesis() (*tmtypes.GenesisDoc, error) {
 	var (
 		tmGenDoc *tmtypes.GenesisDoc
@@ -1191,6 +1767,13 @@ func New(ctx context.Context, dataDir string, identity *identity.Identity, genes
 		dataDir:               dataDir,
 		startedCh:             make(chan struct{}),
 		syncedCh:              make(chan struct{}),
+		txIndexer:             viper.GetString(CfgConsensusTxIndex),
+	}
+
+	switch t.txIndexer {
+	case txIndexerNull, txIndexerKV:
+	default:
+		return nil, fmt.Errorf("tendermint: unsupported transaction indexer: '%s'", t.txIndexer)
 	}
 
 	// Create the submission manager.
@@ -1200,9 +1783,41 @@ func New(ctx context.Context, dataDir string, identity *identity.Identity, genes
 	}
 	t.submissionMgr = consensusAPI.NewSubmissionManager(t, pd, viper.GetUint64(CfgConsensusSubmissionMaxFee))
 
+	if t.blockCache, err = newBlockCache(viper.GetUint64(CfgConsensusBlockCacheSize)); err != nil {
+		return nil, fmt.Errorf("tendermint: failed to create block cache: %w", err)
+	}
+
 	return t, t.initialize()
 }
 
+// configureMempool applies the mempool configuration flags to the given
+// Tendermint mempool configuration.
+//
+// Transactions larger than the consensus MaxTxSize are rejected by the
+// application during CheckTx, so the total mempool size must be able to
+// hold at least one such transaction. Re-checking is always enabled as
+// it is what drives TTL-based eviction.
+func configureMempool(cfg *tmconfig.MempoolConfig, maxTxSize uint64) error {
+	cacheSize := viper.GetUint64(CfgConsensusMempoolCacheSize)
+	maxTxsBytes := viper.GetUint64(CfgConsensusMempoolMaxTxsBytes)
+
+	if cacheSize > math.MaxInt32 {
+		return fmt.Errorf("cache size too large: %d", cacheSize)
+	}
+	if maxTxsBytes > math.MaxInt64 {
+		return fmt.Errorf("maximum size of all transactions too large: %d", maxTxsBytes)
+	}
+	if maxTxsBytes < maxTxSize {
+		return fmt.Errorf("maximum size of all transactions (%d) is smaller than the maximum transaction size (%d)", maxTxsBytes, maxTxSize)
+	}
+
+	cfg.Recheck = true
+	cfg.CacheSize = int(cacheSize)
+	cfg.MaxTxsBytes = int64(maxTxsBytes)
+
+	return nil
+}
+
 func initDataDir(dataDir string) error {
 	subDirs := []string{
 		configDir,
@@ -1333,6 +1948,10 @@ func init() {
 	Flags.String(cfgCoreExternalAddress, "", "tendermint address advertised to other nodes")
 	Flags.String(cfgABCIPruneStrategy, abci.PruneDefault, "ABCI state pruning strategy")
 	Flags.Int64(cfgABCIPruneNumKept, 3600, "ABCI state versions kept (when applicable)")
+	Flags.Duration(cfgABCIAppSoftDeadline, 1*time.Second, "time an ABCI application may spend in BeginBlock/EndBlock before a warning is emitted (0 disables)")
+	Flags.Duration(cfgABCIAppHardDeadline, 5*time.Minute, "time an ABCI application may spend in BeginBlock/EndBlock before the node panics (0 disables)")
+	Flags.Int(cfgABCIStateCacheSize, abci.DefaultStateCacheSize, "number of IAVL nodes cached for each ABCI state tree (larger values use more memory)")
+	Flags.Uint64(cfgABCISignatureCacheSize, 4096, "number of transactions verified in CheckTx whose signatures are not re-verified in DeliverTx (0 disables)")
 	Flags.StringSlice(CfgSentryUpstreamAddress, []string{}, "Tendermint nodes for which we act as sentry of the form ID@ip:port")
 	Flags.StringSlice(CfgP2PPersistentPeer, []string{}, "Tendermint persistent peer(s) of the form ID@ip:port")
 	Flags.Bool(CfgP2PDisablePeerExchange, false, "Disable Tendermint's peer-exchange reactor")
@@ -1341,13 +1960,23 @@ func init() {
 	Flags.Bool(cfgLogDebug, false, "enable tendermint debug logs (very verbose)")
 	Flags.Bool(CfgDebugP2PAddrBookLenient, false, "allow non-routable addresses")
 	Flags.Bool(CfgDebugP2PAllowDuplicateIP, false, "Allow multiple connections from the same IP")
+	Flags.Bool(CfgDebugABCIRecoverInconsistentTrees, false, "recover from inconsistent ABCI state trees on startup by reloading the CheckTx tree")
 	Flags.Uint64(CfgConsensusMinGasPrice, 0, "minimum gas price")
+	Flags.String(CfgConsensusEmptyBlockMode, emptyBlockModeInterval, "empty block creation mode (interval, on_demand)")
+	Flags.StringSlice(CfgConsensusMinGasPricePerMethod, []string{}, "per-method minimum gas prices (method=price)")
+	Flags.Uint64(CfgConsensusBlockCacheSize, 128, "number of recent blocks and block results to cache (0 disables)")
+	Flags.String(CfgConsensusTxIndex, txIndexerNull, "transaction indexer used for lookups by hash (null, kv)")
+	Flags.Uint64(CfgConsensusMempoolCacheSize, 10000, "number of recently seen transactions cached by the mempool (0 disables)")
+	Flags.Uint64(CfgConsensusMempoolMaxTxsBytes, 1024*1024*1024, "maximum total size of all transactions in the mempool")
+	Flags.Uint64(CfgConsensusMempoolTTL, 0, "number of blocks after which transactions are evicted from the mempool (0 disables)")
+	Flags.Uint64(CfgConsensusMempoolRecheckGraceBlocks, 0, "number of blocks after an epoch transition during which transactions failing re-check are kept in the mempool (0 disables)")
 	Flags.Uint64(CfgConsensusSubmissionGasPrice, 0, "gas price used when submitting consensus transactions")
 	Flags.Uint64(CfgConsensusSubmissionMaxFee, 0, "maximum transaction fee when submitting consensus transactions")
 
 	_ = Flags.MarkHidden(cfgLogDebug)
 	_ = Flags.MarkHidden(CfgDebugP2PAddrBookLenient)
 	_ = Flags.MarkHidden(CfgDebugP2PAllowDuplicateIP)
+	_ = Flags.MarkHidden(CfgDebugABCIRecoverInconsistentTrees)
 
 	_ = viper.BindPFlags(Flags)
 	Flags.AddFlagSet(db.Flags)
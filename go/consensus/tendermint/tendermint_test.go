@@ -0,0 +1,345 @@
+package tendermint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	tmkvstore "github.com/tendermint/tendermint/abci/example/kvstore"
+	tmabcitypes "github.com/tendermint/tendermint/abci/types"
+	tmconfig "github.com/tendermint/tendermint/config"
+	tmed "github.com/tendermint/tendermint/crypto/ed25519"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	tmmempool "github.com/tendermint/tendermint/mempool"
+	tmnode "github.com/tendermint/tendermint/node"
+	tmp2p "github.com/tendermint/tendermint/p2p"
+	tmprivval "github.com/tendermint/tendermint/privval"
+	tmproxy "github.com/tendermint/tendermint/proxy"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/api"
+)
+
+func TestSeedsToTendermint(t *testing.T) {
+	require := require.New(t)
+
+	const (
+		idA = "0123456789abcdef0123456789abcdef01234567"
+		idB = "76543210fedcba9876543210fedcba9876543210"
+	)
+
+	seeds, err := seedsToTendermint([]string{
+		idA + "@127.0.0.1:26656",
+		"",
+		// Duplicate seed IDs (regardless of case and address) are dropped.
+		"0123456789ABCDEF0123456789ABCDEF01234567@127.0.0.2:26656",
+		" " + idB + "@127.0.0.3:26656 ",
+		idA + "@127.0.0.1:26656",
+	})
+	require.NoError(err, "seedsToTendermint")
+	require.Equal(idA+"@127.0.0.1:26656,"+idB+"@127.0.0.3:26656", seeds, "seeds should be deduplicated")
+
+	seeds, err = seedsToTendermint(nil)
+	require.NoError(err, "seedsToTendermint(nil)")
+	require.Empty(seeds, "no seeds should result in an empty seed list")
+
+	for _, malformed := range []string{
+		"127.0.0.1:26656",
+		"notanid@127.0.0.1:26656",
+		idA + "@127.0.0.1",
+		idA + "@127.0.0.1:notaport",
+	} {
+		_, err = seedsToTendermint([]string{idB + "@127.0.0.3:26656", malformed})
+		require.Error(err, "malformed seed '%s' should be rejected", malformed)
+	}
+}
+
+func TestParseMinGasPricePerMethod(t *testing.T) {
+	require := require.New(t)
+
+	prices, err := parseMinGasPricePerMethod([]string{"staking.Transfer=10", " registry.RegisterNode = 100 "})
+	require.NoError(err, "parseMinGasPricePerMethod")
+	require.Equal(map[transaction.MethodName]uint64{
+		"staking.Transfer":      10,
+		"registry.RegisterNode": 100,
+	}, prices, "per-method minimum gas prices should be parsed")
+
+	prices, err = parseMinGasPricePerMethod(nil)
+	require.NoError(err, "parseMinGasPricePerMethod(nil)")
+	require.Empty(prices, "no entries should result in no per-method minimum gas prices")
+
+	for _, malformed := range []string{
+		"staking.Transfer",
+		"=10",
+		"staking.Transfer=",
+		"staking.Transfer=-1",
+		"staking.Transfer=ten",
+	} {
+		_, err = parseMinGasPricePerMethod([]string{malformed})
+		require.Error(err, "malformed entry '%s' should be rejected", malformed)
+	}
+}
+
+func newTestGenesisDoc(appState string) *tmtypes.GenesisDoc {
+	return &tmtypes.GenesisDoc{
+		GenesisTime:     time.Unix(1574858284, 0).UTC(),
+		ChainID:         "genesis digest test",
+		ConsensusParams: tmtypes.DefaultConsensusParams(),
+		Validators: []tmtypes.GenesisValidator{
+			{PubKey: tmed.GenPrivKeyFromSecret([]byte("validator")).PubKey(), Power: 10},
+		},
+		AppState: []byte(appState),
+	}
+}
+
+func TestGenesisInitChainRequest(t *testing.T) {
+	require := require.New(t)
+
+	doc := newTestGenesisDoc(`{"app":"state"}`)
+	req := genesisInitChainRequest(doc)
+	require.Equal(doc.ChainID, req.ChainId, "chain ID should match")
+	require.Equal(doc.GenesisTime, req.Time, "genesis time should match")
+	require.EqualValues(doc.AppState, req.AppStateBytes, "app state should match")
+	require.Len(req.Validators, 1, "validators should match")
+	require.EqualValues(10, req.Validators[0].Power, "validator power should match")
+
+	digest := abci.GenesisDigest(req)
+	require.Equal(digest, abci.GenesisDigest(genesisInitChainRequest(newTestGenesisDoc(`{"app":"state"}`))), "digest should be deterministic")
+	require.NotEqual(digest, abci.GenesisDigest(genesisInitChainRequest(newTestGenesisDoc(`{"app":"other"}`))), "digest should depend on the genesis document")
+}
+
+func TestCheckGenesisDigest(t *testing.T) {
+	require := require.New(t)
+
+	doc := newTestGenesisDoc(`{"app":"state"}`)
+	stored := abci.GenesisDigest(genesisInitChainRequest(doc))
+
+	err := checkGenesisDigest(nil, doc)
+	require.NoError(err, "uninitialized state should be accepted")
+
+	err = checkGenesisDigest(stored, doc)
+	require.NoError(err, "matching genesis document should be accepted")
+
+	err = checkGenesisDigest(stored, newTestGenesisDoc(`{"app":"other"}`))
+	require.Error(err, "different genesis document should be rejected")
+	require.Contains(err.Error(), "genesis document does not match existing state", "error should be descriptive")
+}
+
+func TestEventsWithHeight(t *testing.T) {
+	require := require.New(t)
+
+	eventKey := []byte("transfer")
+	events := []tmabcitypes.Event{
+		api.NewEventBuilder("staking").Attribute(eventKey, []byte("first")).Attribute([]byte("burn"), []byte("other")).Event(),
+		api.NewEventBuilder("registry").Attribute(eventKey, []byte("other app")).Event(),
+		api.NewEventBuilder("staking").Attribute(eventKey, []byte("second")).Event(),
+	}
+
+	var txHash hash.Hash
+	txHash.FromBytes([]byte("tx"))
+	evs := eventsWithHeight(events, api.EventTypeForApp("staking"), eventKey, 42, &txHash)
+	require.Len(evs, 2, "only matching events should be returned")
+	for i, value := range []string{"first", "second"} {
+		require.EqualValues(42, evs[i].Height, "event height should be set")
+		require.Equal(&txHash, evs[i].TxHash, "event transaction hash should be set")
+		require.EqualValues(value, evs[i].Value, "event value should match")
+	}
+
+	evs = eventsWithHeight(events, api.EventTypeForApp("scheduler"), eventKey, 42, nil)
+	require.Empty(evs, "events of other apps should be ignored")
+
+	// The query should match exactly the events with the given key.
+	query := api.QueryForEvent("staking", eventKey)
+	for _, tc := range []struct {
+		key     string
+		matches bool
+	}{
+		{"transfer", true},
+		{"burn", false},
+	} {
+		matches, err := query.Matches(map[string][]string{
+			api.EventTypeForApp("staking") + "." + tc.key: {"value"},
+		})
+		require.NoError(err, "Matches")
+		require.Equal(tc.matches, matches, "query should match events with key '%s': %t", tc.key, tc.matches)
+	}
+}
+
+func TestTxIndexQuery(t *testing.T) {
+	require := require.New(t)
+
+	var txHash, otherHash hash.Hash
+	txHash.FromBytes([]byte("tx"))
+	otherHash.FromBytes([]byte("other tx"))
+
+	// The indexed tag of the transaction event should match the query.
+	ev := api.NewTxEvent(txHash)
+	require.Len(ev.Attributes, 1, "transaction event should have a single attribute")
+	tags := map[string][]string{
+		ev.Type + "." + string(ev.Attributes[0].Key): {string(ev.Attributes[0].Value)},
+	}
+	require.Contains(tags, api.EventTypeTx+"."+api.EventKeyTxHash, "transaction hash tag should be indexed")
+
+	query, err := tmquery.New(api.QueryForTxHash(txHash))
+	require.NoError(err, "query should parse")
+	matches, err := query.Matches(tags)
+	require.NoError(err, "Matches")
+	require.True(matches, "query should match the transaction")
+
+	query, err = tmquery.New(api.QueryForTxHash(otherHash))
+	require.NoError(err, "query should parse")
+	matches, err = query.Matches(tags)
+	require.NoError(err, "Matches")
+	require.False(matches, "query should not match other transactions")
+}
+
+func TestConfigureMempool(t *testing.T) {
+	require := require.New(t)
+
+	const cacheSize = 16
+	viper.Set(CfgConsensusMempoolCacheSize, cacheSize)
+	viper.Set(CfgConsensusMempoolMaxTxsBytes, 1024)
+	defer func() {
+		viper.Set(CfgConsensusMempoolCacheSize, 10000)
+		viper.Set(CfgConsensusMempoolMaxTxsBytes, 1024*1024*1024)
+	}()
+
+	// The mempool must be able to hold a maximum size transaction.
+	cfg := tmconfig.DefaultMempoolConfig()
+	err := configureMempool(cfg, 2048)
+	require.Error(err, "configureMempool should reject a mempool smaller than the maximum transaction size")
+
+	cfg = tmconfig.DefaultMempoolConfig()
+	err = configureMempool(cfg, 1024)
+	require.NoError(err, "configureMempool")
+	require.EqualValues(cacheSize, cfg.CacheSize, "cache size should be configured")
+	require.EqualValues(1024, cfg.MaxTxsBytes, "maximum size of all transactions should be configured")
+	require.True(cfg.Recheck, "re-checking should be enabled")
+
+	// Make sure that Tendermint honors the configured cache size.
+	cfg.MaxTxsBytes = 1024 * 1024
+	appConn, err := tmproxy.NewLocalClientCreator(tmkvstore.NewKVStoreApplication()).NewABCIClient()
+	require.NoError(err, "NewABCIClient")
+	require.NoError(appConn.Start(), "Start")
+	defer appConn.Stop() // nolint: errcheck
+	mempool := tmmempool.NewCListMempool(cfg, appConn, 0)
+
+	tx := func(i int) []byte {
+		return []byte(fmt.Sprintf("tx%d=%d", i, i))
+	}
+	for i := 0; i <= cacheSize; i++ {
+		err = mempool.CheckTx(tx(i), nil, tmmempool.TxInfo{})
+		require.NoError(err, "CheckTx(%d)", i)
+	}
+	err = mempool.CheckTx(tx(cacheSize), nil, tmmempool.TxInfo{})
+	require.Equal(tmmempool.ErrTxInCache, err, "recent transactions should be rejected as duplicates")
+	err = mempool.CheckTx(tx(0), nil, tmmempool.TxInfo{})
+	require.NoError(err, "transactions evicted from the cache should not be rejected as duplicates")
+}
+
+func TestEmptyBlockModeOnDemand(t *testing.T) {
+	require := require.New(t)
+
+	cfg := tmconfig.ResetTestRoot("oasis-empty-block-mode-test")
+	defer os.RemoveAll(cfg.RootDir)
+	cfg.P2P.ListenAddress = "tcp://127.0.0.1:0"
+	cfg.RPC.ListenAddress = ""
+	err := configureEmptyBlockMode(cfg.Consensus, "invalid", time.Second)
+	require.Error(err, "configureEmptyBlockMode should reject unsupported modes")
+	err = configureEmptyBlockMode(cfg.Consensus, emptyBlockModeOnDemand, time.Second)
+	require.NoError(err, "configureEmptyBlockMode")
+
+	nodeKey, err := tmp2p.LoadOrGenNodeKey(cfg.NodeKeyFile())
+	require.NoError(err, "LoadOrGenNodeKey")
+	node, err := tmnode.NewNode(cfg,
+		tmprivval.LoadOrGenFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile()),
+		nodeKey,
+		tmproxy.NewLocalClientCreator(tmkvstore.NewKVStoreApplication()),
+		tmnode.DefaultGenesisDocProviderFunc(cfg),
+		tmnode.DefaultDBProvider,
+		tmnode.DefaultMetricsProvider(cfg.Instrumentation),
+		tmlog.NewNopLogger(),
+	)
+	require.NoError(err, "NewNode")
+	require.NoError(node.Start(), "Start")
+	defer func() {
+		_ = node.Stop()
+		node.Wait()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trigger := newPendingWorkTrigger()
+	go trigger.worker(ctx, node.Mempool())
+
+	// waitIdle waits for the chain to stop creating blocks and returns the
+	// latest height.
+	waitIdle := func() int64 {
+		const idlePeriod = 500 * time.Millisecond
+		deadline := time.Now().Add(10 * time.Second)
+		height := node.BlockStore().Height()
+		for {
+			time.Sleep(idlePeriod)
+			newHeight := node.BlockStore().Height()
+			if newHeight > 0 && newHeight == height {
+				return height
+			}
+			require.True(time.Now().Before(deadline), "chain should become idle")
+			height = newHeight
+		}
+	}
+
+	// Without pending work, no empty blocks should be created.
+	height := waitIdle()
+	time.Sleep(2 * time.Second)
+	require.Equal(height, node.BlockStore().Height(), "no empty blocks should be created without pending work")
+
+	// Pending work should get a block created once it is due.
+	trigger.notify(height, 200*time.Millisecond, true)
+	deadline := time.Now().Add(5 * time.Second)
+	for node.BlockStore().Height() <= height {
+		require.True(time.Now().Before(deadline), "block should be created for pending work")
+		time.Sleep(10 * time.Millisecond)
+	}
+	block := node.BlockStore().LoadBlock(height + 1)
+	require.Len(block.Txs, 1, "block should contain the pending work transaction")
+	require.EqualValues(abci.NewPendingWorkTx(height+1), block.Txs[0], "block should contain the pending work transaction")
+
+	// Pending work that is no longer pending should not get a block created.
+	height = waitIdle()
+	trigger.notify(height, 200*time.Millisecond, true)
+	trigger.notify(height, 0, false)
+	time.Sleep(time.Second)
+	require.Equal(height, node.BlockStore().Height(), "no block should be created after pending work is gone")
+}
+
+func TestSubmitTxNoWaitBeforeStart(t *testing.T) {
+	require := require.New(t)
+
+	svcCtx, svcCancel := context.WithCancel(context.Background())
+	defer svcCancel()
+	srv := &tendermintService{
+		ctx:       svcCtx,
+		startedCh: make(chan struct{}),
+	}
+	tx := &transaction.SignedTransaction{}
+
+	// Submitting before the service has started should wait for it to start.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := srv.SubmitTxNoWait(ctx, tx)
+	require.Equal(context.DeadlineExceeded, err, "SubmitTxNoWait should wait for the service to start")
+
+	// Submitting should be aborted when the service is stopped.
+	svcCancel()
+	_, err = srv.SubmitTxNoWait(context.Background(), tx)
+	require.Equal(context.Canceled, err, "SubmitTxNoWait should be aborted when the service is stopped")
+}
@@ -8,7 +8,14 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	keymanager "github.com/oasislabs/oasis-core/go/keymanager/api"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+	staking "github.com/oasislabs/oasis-core/go/staking/api"
 )
 
 const (
@@ -33,6 +40,15 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 	_, err = backend.GetTransactions(ctx, consensus.HeightLatest)
 	require.NoError(err, "GetTransactions")
 
+	digest, err := backend.GetGenesisDigest(ctx)
+	require.NoError(err, "GetGenesisDigest")
+	require.Len(digest, 32, "genesis digest should be a SHA-512/256 digest")
+
+	genesisDoc, err := backend.GetGenesisDocument(ctx)
+	require.NoError(err, "GetGenesisDocument")
+	require.NotNil(genesisDoc.Document, "returned genesis document should not be nil")
+	require.Equal(digest, genesisDoc.Digest, "genesis document digest should match GetGenesisDigest")
+
 	blockCh, blockSub, err := backend.WatchBlocks(ctx)
 	require.NoError(err, "WatchBlocks")
 	defer blockSub.Close()
@@ -48,4 +64,80 @@ func ConsensusImplementationTests(t *testing.T, backend consensus.ClientBackend)
 			t.Fatalf("failed to receive consensus block")
 		}
 	}
+
+	params, err := backend.GetConsensusParameters(ctx, blk.Height)
+	require.NoError(err, "GetConsensusParameters")
+	require.Equal(blk.Height, params.Height, "consensus parameters height should match the block height")
+	require.Equal(genesisDoc.Document.Consensus.Parameters.MaxTxSize, params.MaxTxSize, "consensus parameters should match genesis before any updates")
+	for _, module := range []string{registry.ModuleName, roothash.ModuleName, staking.ModuleName, keymanager.ModuleName} {
+		require.Contains(params.GasCosts, module, "consensus parameters should contain %s gas costs", module)
+	}
+
+	_, err = backend.GetMinGasPrices(ctx)
+	require.NoError(err, "GetMinGasPrices")
+
+	vs, err := backend.GetValidatorSet(ctx, consensus.HeightLatest)
+	require.NoError(err, "GetValidatorSet(HeightLatest)")
+	require.NotEmpty(vs.Validators, "validator set should not be empty")
+	for _, v := range vs.Validators {
+		require.True(v.VotingPower > 0, "validator voting power should be positive")
+		require.NotEmpty(v.Address, "validator address should not be empty")
+	}
+
+	vs, err = backend.GetValidatorSet(ctx, blk.Height)
+	require.NoError(err, "GetValidatorSet")
+	require.Equal(blk.Height, vs.Height, "validator set height should match the block height")
+	require.NotEmpty(vs.Validators, "validator set should not be empty")
+
+	rtStates, err := backend.GetRuntimeStates(ctx, blk.Height)
+	require.NoError(err, "GetRuntimeStates")
+	require.NotEmpty(rtStates, "runtime states should not be empty")
+	if full, ok := backend.(consensus.Backend); ok {
+		// The combined view should match the individual backend queries.
+		var runtimes []*registry.Runtime
+		runtimes, err = full.Registry().GetAllRuntimes(ctx, blk.Height)
+		require.NoError(err, "GetAllRuntimes")
+		require.Len(rtStates, len(runtimes), "runtime states should include all runtimes")
+		for i, rtState := range rtStates {
+			require.EqualValues(runtimes[i], rtState.Runtime, "runtime descriptor should match")
+
+			var suspended bool
+			suspended, err = full.Registry().IsRuntimeSuspended(ctx, &registry.NamespaceQuery{ID: rtState.Runtime.ID, Height: blk.Height})
+			require.NoError(err, "IsRuntimeSuspended")
+			require.Equal(suspended, rtState.Suspended, "runtime suspension status should match")
+
+			if !rtState.Runtime.IsCompute() {
+				require.Nil(rtState.CurrentBlock, "non-compute runtimes should not have a head block")
+				continue
+			}
+
+			var latestBlk *block.Block
+			latestBlk, err = full.RootHash().GetLatestBlock(ctx, rtState.Runtime.ID, blk.Height)
+			require.NoError(err, "GetLatestBlock")
+			require.EqualValues(latestBlk, rtState.CurrentBlock, "runtime head block should match")
+		}
+	}
+
+	// Look up the most recent transaction by hash.
+	var txHash hash.Hash
+	_, err = backend.GetTransaction(ctx, txHash)
+	require.Equal(consensus.ErrTransactionNotFound, err, "GetTransaction should fail for unknown transactions")
+	for height := blk.Height; height > 0; height-- {
+		var txs [][]byte
+		txs, err = backend.GetTransactions(ctx, height)
+		require.NoError(err, "GetTransactions")
+		if len(txs) == 0 {
+			continue
+		}
+
+		txHash.FromBytes(txs[len(txs)-1])
+		var tx *consensus.TransactionWithResult
+		tx, err = backend.GetTransaction(ctx, txHash)
+		require.NoError(err, "GetTransaction")
+		require.Equal(height, tx.Result.Height, "transaction height should match")
+		require.EqualValues(len(txs)-1, tx.Index, "transaction index should match")
+		require.EqualValues(txs[len(txs)-1], cbor.Marshal(tx.Transaction), "transaction should match")
+		return
+	}
+	t.Fatalf("failed to find any transactions")
 }
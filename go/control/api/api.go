@@ -4,7 +4,11 @@ package api
 import (
 	"context"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/errors"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
 
@@ -23,6 +27,61 @@ type NodeController interface {
 	// IsSynced checks whether the node has finished syncing.
 	// TODO: These should be replaced with IsReady (see oasis-core#2130).
 	IsSynced(ctx context.Context) (bool, error)
+
+	// GetStatus returns the current status overview of the node.
+	//
+	// The status is suitable for use as a readiness probe as it does not
+	// block waiting for the node to finish syncing or registering.
+	GetStatus(ctx context.Context) (*Status, error)
+}
+
+// Status is the current status overview of the node.
+type Status struct {
+	// Consensus is the status of the consensus layer.
+	Consensus ConsensusStatus `json:"consensus"`
+
+	// Registration is the node registration status. It is nil in case the
+	// node does not register (e.g., because no workers are enabled).
+	Registration *RegistrationStatus `json:"registration,omitempty"`
+
+	// Ready is true iff the consensus layer has finished syncing and the
+	// node has completed its initial registration (if it registers).
+	Ready bool `json:"ready"`
+}
+
+// ConsensusStatus is the status of the consensus layer.
+type ConsensusStatus struct {
+	// Synced is true iff the consensus layer has finished syncing.
+	Synced bool `json:"synced"`
+
+	// LatestHeight is the height of the latest block. It is zero in case no
+	// blocks are available yet.
+	LatestHeight int64 `json:"latest_height"`
+
+	// LatestEpoch is the epoch at the latest block.
+	LatestEpoch epochtime.EpochTime `json:"latest_epoch"`
+}
+
+// RegistrationStatus is the node registration status.
+type RegistrationStatus struct {
+	// Registered is true iff the node has completed its initial registration.
+	Registered bool `json:"registered"`
+
+	// Roles are the statuses of the roles provided by the enabled workers.
+	// The node only (re-)registers while all of the roles are available.
+	Roles []RoleStatus `json:"roles"`
+}
+
+// RoleStatus is the status of a role provided by a worker.
+type RoleStatus struct {
+	// Role is the provided role.
+	Role node.RolesMask `json:"role"`
+
+	// RuntimeID is the runtime the role is provided for, if any.
+	RuntimeID *common.Namespace `json:"runtime_id,omitempty"`
+
+	// Available is true iff the worker is ready to service the role.
+	Available bool `json:"available"`
 }
 
 // Shutdownable is an interface the node presents for shutting itself down.
@@ -31,11 +90,19 @@ type Shutdownable interface {
 	RequestShutdown() <-chan struct{}
 }
 
+// RegistrationStatusProvider is an interface for querying the node
+// registration status.
+type RegistrationStatusProvider interface {
+	// GetRegistrationStatus returns the node registration status or nil in
+	// case the node does not register.
+	GetRegistrationStatus(ctx context.Context) (*RegistrationStatus, error)
+}
+
 // DebugModuleName is the module name for the debug controller service.
 const DebugModuleName = "control/debug"
 
 // ErrIncompatibleBackend is the error raised when the current epochtime
-// backend does not support manually setting the current epoch.
+// or consensus backend does not support the requested operation.
 var ErrIncompatibleBackend = errors.New(DebugModuleName, 1, "debug: incompatible backend")
 
 // DebugController is a debug-only controller useful during tests.
@@ -48,4 +115,15 @@ type DebugController interface {
 
 	// WaitNodesRegistered waits for the given number of nodes to register.
 	WaitNodesRegistered(ctx context.Context, count int) error
+
+	// GetMethodCatalogue returns the descriptions of all gRPC methods known
+	// to the node, sorted by their full method name.
+	GetMethodCatalogue(ctx context.Context) ([]cmnGrpc.MethodInfo, error)
+
+	// SetMinGasPrice sets the global minimum gas price required for
+	// transactions to be admitted into the local mempool.
+	//
+	// NOTE: This only affects local transaction admission (CheckTx), not
+	//       consensus validity of transactions in blocks (DeliverTx).
+	SetMinGasPrice(ctx context.Context, price quantity.Quantity) error
 }
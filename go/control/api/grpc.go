@@ -18,6 +18,8 @@ var (
 	methodWaitSync = serviceName.NewMethodName("WaitSync")
 	// methodIsSynced is the name of the IsSynced method.
 	methodIsSynced = serviceName.NewMethodName("IsSynced")
+	// methodGetStatus is the name of the GetStatus method.
+	methodGetStatus = serviceName.NewMethodName("GetStatus").WithIdempotent(true)
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -36,6 +38,10 @@ var (
 				MethodName: methodIsSynced.Short(),
 				Handler:    handlerIsSynced,
 			},
+			{
+				MethodName: methodGetStatus.Short(),
+				Handler:    handlerGetStatus,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -102,6 +108,25 @@ func handlerIsSynced( // nolint: golint
 	return interceptor(ctx, nil, info, handler)
 }
 
+func handlerGetStatus( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(NodeController).GetStatus(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetStatus.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NodeController).GetStatus(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
 // RegisterService registers a new node controller service with the given gRPC server.
 func RegisterService(server *grpc.Server, service NodeController) {
 	server.RegisterService(&serviceDesc, service)
@@ -127,6 +152,14 @@ func (c *nodeControllerClient) IsSynced(ctx context.Context) (bool, error) {
 	return rsp, nil
 }
 
+func (c *nodeControllerClient) GetStatus(ctx context.Context) (*Status, error) {
+	var rsp Status
+	if err := c.conn.Invoke(ctx, methodGetStatus.Full(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 // NewNodeControllerClient creates a new gRPC node controller client service.
 func NewNodeControllerClient(c *grpc.ClientConn) NodeController {
 	return &nodeControllerClient{c}
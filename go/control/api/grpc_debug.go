@@ -6,6 +6,7 @@ import (
 	"google.golang.org/grpc"
 
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
 
@@ -17,6 +18,10 @@ var (
 	methodSetEpoch = debugServiceName.NewMethodName("SetEpoch")
 	// methodWaitNodesRegistered is the name of the WaitNodesRegistered method.
 	methodWaitNodesRegistered = debugServiceName.NewMethodName("WaitNodesRegistered")
+	// methodGetMethodCatalogue is the name of the GetMethodCatalogue method.
+	methodGetMethodCatalogue = debugServiceName.NewMethodName("GetMethodCatalogue").WithIdempotent(true)
+	// methodSetMinGasPrice is the name of the SetMinGasPrice method.
+	methodSetMinGasPrice = debugServiceName.NewMethodName("SetMinGasPrice")
 
 	// debugServiceDesc is the gRPC service descriptor.
 	debugServiceDesc = grpc.ServiceDesc{
@@ -31,6 +36,14 @@ var (
 				MethodName: methodWaitNodesRegistered.Short(),
 				Handler:    handlerWaitNodesRegistered,
 			},
+			{
+				MethodName: methodGetMethodCatalogue.Short(),
+				Handler:    handlerGetMethodCatalogue,
+			},
+			{
+				MethodName: methodSetMinGasPrice.Short(),
+				Handler:    handlerSetMinGasPrice,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -82,6 +95,48 @@ func handlerWaitNodesRegistered( // nolint: golint
 	return interceptor(ctx, count, info, handler)
 }
 
+func handlerGetMethodCatalogue( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	if interceptor == nil {
+		return srv.(DebugController).GetMethodCatalogue(ctx)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetMethodCatalogue.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugController).GetMethodCatalogue(ctx)
+	}
+	return interceptor(ctx, nil, info, handler)
+}
+
+func handlerSetMinGasPrice( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var price quantity.Quantity
+	if err := dec(&price); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(DebugController).SetMinGasPrice(ctx, price)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetMinGasPrice.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(DebugController).SetMinGasPrice(ctx, req.(quantity.Quantity))
+	}
+	return interceptor(ctx, price, info, handler)
+}
+
 // RegisterDebugService registers a new debug controller service with the given gRPC server.
 func RegisterDebugService(server *grpc.Server, service DebugController) {
 	server.RegisterService(&debugServiceDesc, service)
@@ -99,6 +154,18 @@ func (c *debugControllerClient) WaitNodesRegistered(ctx context.Context, count i
 	return c.conn.Invoke(ctx, methodWaitNodesRegistered.Full(), count, nil)
 }
 
+func (c *debugControllerClient) GetMethodCatalogue(ctx context.Context) ([]cmnGrpc.MethodInfo, error) {
+	var rsp []cmnGrpc.MethodInfo
+	if err := c.conn.Invoke(ctx, methodGetMethodCatalogue.Full(), nil, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *debugControllerClient) SetMinGasPrice(ctx context.Context, price quantity.Quantity) error {
+	return c.conn.Invoke(ctx, methodSetMinGasPrice.Full(), price, nil)
+}
+
 // NewDebugControllerClient creates a new gRPC debug controller client service.
 func NewDebugControllerClient(c *grpc.ClientConn) DebugController {
 	return &debugControllerClient{c}
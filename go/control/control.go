@@ -3,14 +3,16 @@ package control
 
 import (
 	"context"
+	"fmt"
 
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/control/api"
 )
 
 type nodeController struct {
-	node      api.Shutdownable
-	consensus consensus.Backend
+	node         api.Shutdownable
+	consensus    consensus.Backend
+	registration api.RegistrationStatusProvider
 }
 
 func (c *nodeController) RequestShutdown(ctx context.Context, wait bool) error {
@@ -46,10 +48,52 @@ func (c *nodeController) IsSynced(ctx context.Context) (bool, error) {
 	}
 }
 
+func (c *nodeController) GetStatus(ctx context.Context) (*api.Status, error) {
+	var status api.Status
+	select {
+	case <-c.consensus.Synced():
+		status.Consensus.Synced = true
+	default:
+	}
+
+	// Block information may not be available until the consensus layer has
+	// finished syncing, so only treat failures as errors afterwards.
+	blk, err := c.consensus.GetBlock(ctx, consensus.HeightLatest)
+	switch err {
+	case nil:
+		status.Consensus.LatestHeight = blk.Height
+		status.Consensus.LatestEpoch, err = c.consensus.EpochTime().GetEpoch(ctx, blk.Height)
+		if err != nil {
+			return nil, fmt.Errorf("control: failed to get epoch: %w", err)
+		}
+	default:
+		if status.Consensus.Synced {
+			return nil, fmt.Errorf("control: failed to get latest block: %w", err)
+		}
+	}
+
+	if c.registration != nil {
+		if status.Registration, err = c.registration.GetRegistrationStatus(ctx); err != nil {
+			return nil, fmt.Errorf("control: failed to get registration status: %w", err)
+		}
+	}
+
+	status.Ready = status.Consensus.Synced && (status.Registration == nil || status.Registration.Registered)
+
+	return &status, nil
+}
+
 // New creates a new oasis-node controller.
-func New(node api.Shutdownable, consensus consensus.Backend) api.NodeController {
+//
+// The registration status provider is optional and may be nil.
+func New(
+	node api.Shutdownable,
+	consensus consensus.Backend,
+	registration api.RegistrationStatusProvider,
+) api.NodeController {
 	return &nodeController{
-		node:      node,
-		consensus: consensus,
+		node:         node,
+		consensus:    consensus,
+		registration: registration,
 	}
 }
@@ -0,0 +1,134 @@
+package control
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/control/api"
+	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
+)
+
+type testEpochTime struct {
+	epochtime.Backend
+}
+
+func (ts *testEpochTime) GetEpoch(ctx context.Context, height int64) (epochtime.EpochTime, error) {
+	return epochtime.EpochTime(height / 10), nil
+}
+
+type testConsensus struct {
+	consensus.Backend
+
+	syncedCh chan struct{}
+	height   int64
+}
+
+func (c *testConsensus) Synced() <-chan struct{} {
+	return c.syncedCh
+}
+
+func (c *testConsensus) GetBlock(ctx context.Context, height int64) (*consensus.Block, error) {
+	if c.height == 0 {
+		return nil, consensus.ErrNoCommittedBlocks
+	}
+	return &consensus.Block{Height: c.height}, nil
+}
+
+func (c *testConsensus) EpochTime() epochtime.Backend {
+	return &testEpochTime{}
+}
+
+type testRegistration struct {
+	status *api.RegistrationStatus
+}
+
+func (r *testRegistration) GetRegistrationStatus(ctx context.Context) (*api.RegistrationStatus, error) {
+	return r.status, nil
+}
+
+func TestGetStatus(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-control-test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	cons := &testConsensus{syncedCh: make(chan struct{})}
+	reg := &testRegistration{
+		status: &api.RegistrationStatus{
+			Roles: []api.RoleStatus{{Role: node.RoleStorageWorker}},
+		},
+	}
+
+	// Serve the node controller over a local socket.
+	socketPath := filepath.Join(dir, "internal.sock")
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "internal",
+		Path: socketPath,
+	})
+	require.NoError(err, "NewServer")
+	api.RegisterService(grpcServer.Server(), New(nil, cons, reg))
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	defer grpcServer.Stop()
+
+	conn, err := cmnGrpc.Dial("unix:"+socketPath, grpc.WithInsecure())
+	require.NoError(err, "Dial")
+	defer conn.Close()
+	client := api.NewNodeControllerClient(conn)
+
+	ctx := context.Background()
+
+	// Before any blocks are available.
+	status, err := client.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.False(status.Consensus.Synced, "consensus should not be synced")
+	require.EqualValues(0, status.Consensus.LatestHeight, "latest height should not be available")
+	require.False(status.Ready, "node should not be ready before syncing")
+
+	// Syncing.
+	cons.height = 25
+	status, err = client.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.False(status.Consensus.Synced, "consensus should not be synced")
+	require.EqualValues(25, status.Consensus.LatestHeight, "latest height should be reported")
+	require.EqualValues(2, status.Consensus.LatestEpoch, "latest epoch should be reported")
+	require.False(status.Ready, "node should not be ready before syncing")
+
+	// Synced, but not yet registered.
+	close(cons.syncedCh)
+	status, err = client.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.True(status.Consensus.Synced, "consensus should be synced")
+	require.Equal(reg.status, status.Registration, "registration status should be reported")
+	require.False(status.Ready, "node should not be ready before registering")
+
+	// Registered.
+	reg.status.Registered = true
+	reg.status.Roles[0].Available = true
+	status, err = client.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.Equal(reg.status, status.Registration, "registration status should be reported")
+	require.True(status.Ready, "node should be ready after syncing and registering")
+
+	// Nodes that do not register are ready once synced.
+	reg.status = nil
+	status, err = client.GetStatus(ctx)
+	require.NoError(err, "GetStatus")
+	require.Nil(status.Registration, "registration status should not be reported")
+	require.True(status.Ready, "node should be ready after syncing")
+
+	// Once synced, failing to get the latest block is an error.
+	cons.height = 0
+	_, err = client.GetStatus(ctx)
+	require.Error(err, "GetStatus should fail when the latest block is unavailable after syncing")
+}
@@ -3,6 +3,9 @@ package control
 import (
 	"context"
 
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/quantity"
+	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
 	"github.com/oasislabs/oasis-core/go/control/api"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
@@ -11,6 +14,7 @@ import (
 type debugController struct {
 	timeSource epochtime.Backend
 	registry   registry.Backend
+	consensus  consensus.Backend
 }
 
 func (c *debugController) SetEpoch(ctx context.Context, epoch epochtime.EpochTime) error {
@@ -61,10 +65,28 @@ Loop:
 	return nil
 }
 
+func (c *debugController) GetMethodCatalogue(ctx context.Context) ([]cmnGrpc.MethodInfo, error) {
+	var catalogue []cmnGrpc.MethodInfo
+	for _, m := range cmnGrpc.EnumerateRegisteredMethods() {
+		catalogue = append(catalogue, m.Info())
+	}
+	return catalogue, nil
+}
+
+func (c *debugController) SetMinGasPrice(ctx context.Context, price quantity.Quantity) error {
+	setter, ok := c.consensus.(consensus.MinGasPriceSetter)
+	if !ok {
+		return api.ErrIncompatibleBackend
+	}
+
+	return setter.SetMinGasPrice(ctx, &price)
+}
+
 // New creates a new oasis-node debug controller.
-func NewDebug(timeSource epochtime.Backend, registry registry.Backend) api.DebugController {
+func NewDebug(timeSource epochtime.Backend, registry registry.Backend, consensus consensus.Backend) api.DebugController {
 	return &debugController{
 		timeSource: timeSource,
 		registry:   registry,
+		consensus:  consensus,
 	}
 }
@@ -0,0 +1,62 @@
+package control
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/control/api"
+)
+
+func TestDebugGetMethodCatalogue(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-control-debug-test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	// Serve the debug controller over a local socket.
+	socketPath := filepath.Join(dir, "internal.sock")
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "internal",
+		Path: socketPath,
+	})
+	require.NoError(err, "NewServer")
+	api.RegisterDebugService(grpcServer.Server(), NewDebug(nil, nil, nil))
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	defer grpcServer.Stop()
+
+	conn, err := cmnGrpc.Dial("unix:"+socketPath, grpc.WithInsecure())
+	require.NoError(err, "Dial")
+	defer conn.Close()
+	client := api.NewDebugControllerClient(conn)
+
+	catalogue, err := client.GetMethodCatalogue(context.Background())
+	require.NoError(err, "GetMethodCatalogue")
+
+	methods := make(map[string]cmnGrpc.MethodInfo)
+	var names []string
+	for _, m := range catalogue {
+		methods[m.Name] = m
+		names = append(names, m.Name)
+	}
+	require.True(sort.StringsAreSorted(names), "catalogue should be sorted")
+	require.Len(methods, len(cmnGrpc.EnumerateRegisteredMethods()), "catalogue should include all registered methods")
+
+	serviceName := cmnGrpc.NewServiceName("DebugController")
+	m, ok := methods["/"+string(serviceName)+"/GetMethodCatalogue"]
+	require.True(ok, "catalogue should include itself")
+	require.Equal(serviceName, m.Service, "method service should be included")
+	require.True(m.Idempotent, "idempotency flag should be included")
+	m, ok = methods["/"+string(serviceName)+"/SetEpoch"]
+	require.True(ok, "catalogue should include other methods")
+	require.False(m.Idempotent, "idempotency flag should be included")
+}
@@ -46,6 +46,11 @@ type SetableBackend interface {
 
 	// SetEpoch sets the current epoch.
 	SetEpoch(context.Context, EpochTime) error
+
+	// AdvanceEpoch advances the current epoch by the specified number of
+	// epochs, blocking until the transition has been observed, and returns
+	// the new epoch.
+	AdvanceEpoch(context.Context, uint64) (EpochTime, error)
 }
 
 // Genesis is the initial genesis state for allowing configurable timekeeping.
@@ -51,6 +51,29 @@ func EpochtimeSetableImplementationTest(t *testing.T, backend api.Backend) {
 	e, err = timeSource.GetEpoch(context.Background(), consensus.HeightLatest)
 	require.NoError(err, "GetEpoch after set")
 	require.Equal(epoch, e, "GetEpoch after set, epoch")
+
+	// Late subscribers should receive the current epoch immediately, and
+	// existing subscribers should not observe the transition again.
+	lateCh, lateSub := timeSource.WatchEpochs()
+	defer lateSub.Close()
+	select {
+	case e = <-lateCh:
+		require.Equal(epoch, e, "WatchEpochs initial (late subscriber)")
+	case <-time.After(recvTimeout):
+		t.Fatalf("failed to receive current epoch on WatchEpochs (late subscriber)")
+	}
+	select {
+	case e = <-ch:
+		t.Fatalf("received duplicate epoch notification: %d", e)
+	default:
+	}
+
+	advanced, err := timeSource.AdvanceEpoch(context.Background(), 2)
+	require.NoError(err, "AdvanceEpoch")
+	require.Equal(epoch+2, advanced, "AdvanceEpoch should return the new epoch")
+	e, err = timeSource.GetEpoch(context.Background(), consensus.HeightLatest)
+	require.NoError(err, "GetEpoch after advance")
+	require.Equal(advanced, e, "GetEpoch after advance, epoch")
 }
 
 // MustAdvanceEpoch advances the epoch by the specified increment, and returns
@@ -61,12 +84,8 @@ func MustAdvanceEpoch(t *testing.T, backend api.SetableBackend, increment uint64
 	ctx, cancel := context.WithTimeout(context.Background(), recvTimeout)
 	defer cancel()
 
-	epoch, err := backend.GetEpoch(ctx, consensus.HeightLatest)
-	require.NoError(err, "GetEpoch")
-
-	epoch = epoch + api.EpochTime(increment)
-	err = backend.SetEpoch(ctx, epoch)
-	require.NoError(err, "SetEpoch")
+	epoch, err := backend.AdvanceEpoch(ctx, increment)
+	require.NoError(err, "AdvanceEpoch")
 
 	return epoch
 }
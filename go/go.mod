@@ -8,6 +8,7 @@ replace (
 )
 
 require (
+	github.com/DataDog/zstd v1.4.1
 	github.com/RoaringBitmap/roaring v0.4.18 // indirect
 	github.com/blevesearch/bleve v0.8.0
 	github.com/blevesearch/blevex v0.0.0-20180227211930-4b158bb555a3 // indirect
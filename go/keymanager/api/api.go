@@ -14,6 +14,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 )
 
@@ -33,6 +34,33 @@ var (
 	// exist.
 	ErrNoSuchKeyManager = errors.New(ModuleName, 1, "keymanager: no such key manager")
 
+	// ErrInvalidArgument is the error returned on malformed arguments.
+	ErrInvalidArgument = errors.New(ModuleName, 2, "keymanager: invalid argument")
+
+	// ErrInvalidSignature is the error returned when a policy signature is
+	// invalid.
+	ErrInvalidSignature = errors.New(ModuleName, 3, "keymanager: invalid signature")
+
+	// ErrIncorrectTxSigner is the error returned when the signer of the
+	// transaction is not authorized to update the key manager policy.
+	ErrIncorrectTxSigner = errors.New(ModuleName, 4, "keymanager: incorrect tx signer")
+
+	// ErrNotInitialized is the error returned when a key manager is not
+	// initialized.
+	ErrNotInitialized = errors.New(ModuleName, 5, "keymanager: not initialized")
+
+	// MethodUpdatePolicy is the method name for policy updates.
+	MethodUpdatePolicy = transaction.NewMethodName(ModuleName, "UpdatePolicy", SignedPolicySGX{})
+	// MethodReinitialize is the method name for forcing key manager
+	// re-initialization.
+	MethodReinitialize = transaction.NewMethodName(ModuleName, "Reinitialize", Reinitialize{})
+
+	// Methods is the list of all methods supported by the key manager backend.
+	Methods = []transaction.MethodName{
+		MethodUpdatePolicy,
+		MethodReinitialize,
+	}
+
 	// TestPublicKey is the insecure hardcoded key manager public key, used
 	// in insecure builds when a RAK is unavailable.
 	TestPublicKey signature.PublicKey
@@ -65,6 +93,41 @@ type Status struct {
 	Policy *SignedPolicySGX `json:"policy"`
 }
 
+// Reasons for a key manager node being dropped from the node list.
+const (
+	// NodeDroppedReasonNotRegistered is the reason used when the node is no
+	// longer registered for the key manager runtime.
+	NodeDroppedReasonNotRegistered = "not_registered"
+	// NodeDroppedReasonTEEMismatch is the reason used when the node's TEE
+	// hardware does not match the key manager runtime.
+	NodeDroppedReasonTEEMismatch = "tee_mismatch"
+	// NodeDroppedReasonInvalidExtraInfo is the reason used when the node's
+	// initialization response is invalid.
+	NodeDroppedReasonInvalidExtraInfo = "invalid_extra_info"
+	// NodeDroppedReasonPolicyChecksumMismatch is the reason used when the
+	// node's policy checksum does not match the key manager policy.
+	NodeDroppedReasonPolicyChecksumMismatch = "policy_checksum_mismatch"
+	// NodeDroppedReasonChecksumMismatch is the reason used when the node's
+	// master secret checksum does not match the key manager checksum.
+	NodeDroppedReasonChecksumMismatch = "checksum_mismatch"
+	// NodeDroppedReasonSecurityStatusMismatch is the reason used when the
+	// node's security status does not match the key manager security status.
+	NodeDroppedReasonSecurityStatusMismatch = "security_status_mismatch"
+)
+
+// NodeDroppedEvent is the event emitted when a previously listed node is
+// dropped from a key manager's node list.
+type NodeDroppedEvent struct {
+	// ID is the runtime ID of the key manager.
+	ID common.Namespace `json:"id"`
+
+	// NodeID is the ID of the dropped node.
+	NodeID signature.PublicKey `json:"node_id"`
+
+	// Reason is the reason for the node being dropped.
+	Reason string `json:"reason"`
+}
+
 // Backend is a key manager management implementation.
 type Backend interface {
 	// GetStatus returns a key manager status by key manager ID.
@@ -81,6 +144,27 @@ type Backend interface {
 
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(context.Context, int64) (*Genesis, error)
+
+	// ConsensusParameters returns the key manager consensus parameters at
+	// the specified block height.
+	ConsensusParameters(context.Context, int64) (*ConsensusParameters, error)
+}
+
+// NewUpdatePolicyTx creates a new policy update transaction.
+func NewUpdatePolicyTx(nonce uint64, fee *transaction.Fee, sigPol *SignedPolicySGX) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodUpdatePolicy, sigPol)
+}
+
+// Reinitialize is a request to force a key manager to re-initialize,
+// rotating the master secret.
+type Reinitialize struct {
+	// ID is the runtime ID of the key manager.
+	ID common.Namespace `json:"id"`
+}
+
+// NewReinitializeTx creates a new key manager re-initialization transaction.
+func NewReinitializeTx(nonce uint64, fee *transaction.Fee, reinit *Reinitialize) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodReinitialize, reinit)
 }
 
 // InitResponse is the initialization RPC response, returned as part of a
@@ -138,9 +222,32 @@ func VerifyExtraInfo(logger *logging.Logger, rt *registry.Runtime, nodeRt *node.
 
 // Genesis is the key manager management genesis state.
 type Genesis struct {
+	// Parameters are the key manager consensus parameters.
+	Parameters ConsensusParameters `json:"params"`
+
 	Statuses []*Status `json:"statuses,omitempty"`
 }
 
+// ConsensusParameters are the key manager consensus parameters.
+type ConsensusParameters struct {
+	// GasCosts are the key manager transaction gas costs.
+	GasCosts transaction.Costs `json:"gas_costs,omitempty"`
+}
+
+const (
+	// GasOpUpdatePolicy is the gas operation identifier for policy updates.
+	GasOpUpdatePolicy transaction.Op = "update_policy"
+	// GasOpReinitialize is the gas operation identifier for forced
+	// re-initialization.
+	GasOpReinitialize transaction.Op = "reinitialize"
+)
+
+// DefaultGasCosts are the "default" gas costs for operations.
+var DefaultGasCosts = transaction.Costs{
+	GasOpUpdatePolicy: 1000,
+	GasOpReinitialize: 1000,
+}
+
 // SanityCheckStatuses examines the statuses table.
 func SanityCheckStatuses(statuses []*Status) error {
 	for _, status := range statuses {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/oasislabs/oasis-core/go/common/cbor"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/sgx"
 )
@@ -42,3 +43,37 @@ type SignedPolicySGX struct {
 
 	Signatures []signature.Signature `json:"signatures"`
 }
+
+// SanityCheckSignedPolicySGX verifies a signed SGX key manager access control
+// policy update against the currently active policy (if any).
+func SanityCheckSignedPolicySGX(currentSigPol, newSigPol *SignedPolicySGX) error {
+	if newSigPol == nil {
+		return ErrInvalidArgument
+	}
+	if len(newSigPol.Signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	raw := cbor.Marshal(newSigPol.Policy)
+	for _, sig := range newSigPol.Signatures {
+		if !sig.PublicKey.IsValid() {
+			return ErrInvalidSignature
+		}
+		if !sig.Verify(PolicySGXSignatureContext, raw) {
+			return ErrInvalidSignature
+		}
+	}
+
+	if currentSigPol != nil {
+		// The policy must be for the same key manager and its serial must
+		// be strictly increasing.
+		if !currentSigPol.Policy.ID.Equal(newSigPol.Policy.ID) {
+			return ErrInvalidArgument
+		}
+		if newSigPol.Policy.Serial <= currentSigPol.Policy.Serial {
+			return ErrInvalidArgument
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+)
+
+func signPolicy(t *testing.T, policy PolicySGX) *SignedPolicySGX {
+	sigPol := &SignedPolicySGX{Policy: policy}
+	for _, signer := range TestSigners[1:] {
+		sig, err := signature.Sign(signer, PolicySGXSignatureContext, cbor.Marshal(policy))
+		require.NoError(t, err, "Sign")
+		sigPol.Signatures = append(sigPol.Signatures, *sig)
+	}
+	return sigPol
+}
+
+func TestSanityCheckSignedPolicySGX(t *testing.T) {
+	require := require.New(t)
+
+	var id, otherID signature.PublicKey
+	id[0] = 1
+	otherID[0] = 2
+
+	current := signPolicy(t, PolicySGX{Serial: 1, ID: id})
+	require.NoError(SanityCheckSignedPolicySGX(nil, current), "initial policy")
+
+	require.Error(SanityCheckSignedPolicySGX(current, nil), "missing policy should be rejected")
+	require.Error(SanityCheckSignedPolicySGX(current, &SignedPolicySGX{Policy: PolicySGX{Serial: 2, ID: id}}),
+		"unsigned policy should be rejected")
+
+	tampered := signPolicy(t, PolicySGX{Serial: 2, ID: id})
+	tampered.Policy.Serial = 3
+	require.Equal(ErrInvalidSignature, SanityCheckSignedPolicySGX(current, tampered), "tampered policy should be rejected")
+
+	require.Equal(ErrInvalidArgument, SanityCheckSignedPolicySGX(current, signPolicy(t, PolicySGX{Serial: 1, ID: id})),
+		"non-increasing serial should be rejected")
+	require.Equal(ErrInvalidArgument, SanityCheckSignedPolicySGX(current, signPolicy(t, PolicySGX{Serial: 2, ID: otherID})),
+		"policy for a different key manager should be rejected")
+
+	require.NoError(SanityCheckSignedPolicySGX(current, signPolicy(t, PolicySGX{Serial: 2, ID: id})), "valid policy update")
+}
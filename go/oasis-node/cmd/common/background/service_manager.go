@@ -7,27 +7,69 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	"github.com/oasislabs/oasis-core/go/common/service"
 )
 
+// defaultStopTimeout is the default time to wait for the services of a
+// shutdown phase to terminate before moving on to the next phase.
+const defaultStopTimeout = 10 * time.Second
+
+// ShutdownPhase is a service shutdown phase.
+//
+// On shutdown services are stopped phase by phase in the order below, and
+// the services of a phase are given a chance to terminate before the next
+// phase is started. Within a phase, services are stopped in registration
+// order.
+type ShutdownPhase uint8
+
+const (
+	// PhaseWorkers contains the workers, which are stopped first as they
+	// depend on everything else.
+	PhaseWorkers ShutdownPhase = iota
+	// PhaseServices contains the services that are neither workers nor
+	// part of the consensus layer.
+	PhaseServices
+	// PhaseConsensus contains the consensus layer, which is stopped last.
+	PhaseConsensus
+)
+
+type managedService struct {
+	service.BackgroundService
+
+	phase       ShutdownPhase
+	cleanupOnly bool
+}
+
 // ServiceManager manages a group of background services.
 type ServiceManager struct {
 	Ctx      context.Context
 	cancelFn context.CancelFunc
 	logger   *logging.Logger
 
-	services []service.BackgroundService
+	services []*managedService
 	termCh   chan service.BackgroundService
 	termSvc  service.BackgroundService
 
-	stopCh chan struct{}
+	stopCh      chan struct{}
+	stopTimeout time.Duration
 }
 
-// Register registers a background service.
+// Register registers a background service in the PhaseServices shutdown
+// phase.
 func (m *ServiceManager) Register(srv service.BackgroundService) {
-	m.services = append(m.services, srv)
+	m.RegisterInPhase(srv, PhaseServices)
+}
+
+// RegisterInPhase registers a background service in the given shutdown
+// phase.
+func (m *ServiceManager) RegisterInPhase(srv service.BackgroundService, phase ShutdownPhase) {
+	m.services = append(m.services, &managedService{
+		BackgroundService: srv,
+		phase:             phase,
+	})
 	go func() {
 		<-srv.Quit()
 		select {
@@ -39,11 +81,20 @@ func (m *ServiceManager) Register(srv service.BackgroundService) {
 
 // RegisterCleanupOnly registers a cleanup only background service.
 func (m *ServiceManager) RegisterCleanupOnly(svc service.CleanupAble, name string) {
-	m.services = append(m.services, service.NewCleanupOnlyService(svc, name))
+	m.services = append(m.services, &managedService{
+		BackgroundService: service.NewCleanupOnlyService(svc, name),
+		phase:             PhaseServices,
+		cleanupOnly:       true,
+	})
 }
 
 // Wait waits for interruption via Stop, SIGINT, SIGTERM, or any of
 // the registered services to terminate, and stops all services.
+//
+// Services are stopped in shutdown phase order (see ShutdownPhase). Before
+// stopping the services of the next phase, Wait waits (up to a timeout)
+// for the services of the current phase to terminate so that, e.g., the
+// workers never observe a stopped consensus layer.
 func (m *ServiceManager) Wait() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
@@ -60,9 +111,38 @@ func (m *ServiceManager) Wait() {
 	// Cancel the context before stopping the services.
 	m.cancelFn()
 
+	for phase := PhaseWorkers; phase <= PhaseConsensus; phase++ {
+		m.stopPhase(phase, phase != PhaseConsensus)
+	}
+}
+
+// stopPhase stops all services in the given shutdown phase and optionally
+// waits for them to terminate.
+func (m *ServiceManager) stopPhase(phase ShutdownPhase, wait bool) {
+	var stopped []*managedService
 	for _, svc := range m.services {
-		if svc != m.termSvc {
-			svc.Stop()
+		if svc.phase != phase || svc.BackgroundService == m.termSvc {
+			continue
+		}
+		svc.Stop()
+		if !svc.cleanupOnly {
+			stopped = append(stopped, svc)
+		}
+	}
+	if !wait {
+		return
+	}
+
+	// Wait for each service separately, so that a service failing to stop
+	// in time does not prevent waiting for the remaining services.
+	for _, svc := range stopped {
+		select {
+		case <-svc.Quit():
+		case <-time.After(m.stopTimeout):
+			m.logger.Warn("timed out waiting for service to stop",
+				"svc", svc.Name(),
+				"phase", phase,
+			)
 		}
 	}
 }
@@ -92,10 +172,11 @@ func NewServiceManager(logger *logging.Logger) *ServiceManager {
 	ctx, cancelFn := context.WithCancel(context.Background())
 
 	return &ServiceManager{
-		Ctx:      ctx,
-		cancelFn: cancelFn,
-		logger:   logger,
-		termCh:   make(chan service.BackgroundService),
-		stopCh:   make(chan struct{}),
+		Ctx:         ctx,
+		cancelFn:    cancelFn,
+		logger:      logger,
+		termCh:      make(chan service.BackgroundService),
+		stopCh:      make(chan struct{}),
+		stopTimeout: defaultStopTimeout,
 	}
 }
@@ -0,0 +1,115 @@
+package background
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/service"
+)
+
+type eventLog struct {
+	sync.Mutex
+
+	events []string
+}
+
+func (l *eventLog) add(event string) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.events = append(l.events, event)
+}
+
+// testService is a background service that terminates a while after
+// being stopped, recording both events.
+type testService struct {
+	service.BaseBackgroundService
+
+	log       *eventLog
+	stopDelay time.Duration
+	quitCh    chan struct{}
+}
+
+func (s *testService) Stop() {
+	s.log.add("stop " + s.Name())
+	go func() {
+		time.Sleep(s.stopDelay)
+		s.log.add("quit " + s.Name())
+		close(s.quitCh)
+	}()
+}
+
+func (s *testService) Quit() <-chan struct{} {
+	return s.quitCh
+}
+
+func newTestService(name string, log *eventLog, stopDelay time.Duration) *testService {
+	return &testService{
+		BaseBackgroundService: *service.NewBaseBackgroundService(name),
+		log:                   log,
+		stopDelay:             stopDelay,
+		quitCh:                make(chan struct{}),
+	}
+}
+
+type testCleanupAble struct{}
+
+func (testCleanupAble) Cleanup() {}
+
+func TestServiceManagerShutdownPhases(t *testing.T) {
+	require := require.New(t)
+
+	var log eventLog
+	m := NewServiceManager(logging.GetLogger("background/test"))
+
+	// Register services out of phase order.
+	consensus := newTestService("consensus", &log, 0)
+	m.RegisterInPhase(consensus, PhaseConsensus)
+	m.Register(newTestService("grpc", &log, 0))
+	m.RegisterCleanupOnly(testCleanupAble{}, "cleanup only")
+	m.RegisterInPhase(newTestService("worker", &log, 50*time.Millisecond), PhaseWorkers)
+
+	m.Stop()
+	m.Wait()
+	<-consensus.Quit()
+
+	require.Equal([]string{
+		"stop worker",
+		"quit worker",
+		"stop grpc",
+		"quit grpc",
+		"stop consensus",
+		"quit consensus",
+	}, log.events, "services should be stopped and drained in phase order")
+}
+
+func TestServiceManagerStopTimeout(t *testing.T) {
+	require := require.New(t)
+
+	var log eventLog
+	m := NewServiceManager(logging.GetLogger("background/test"))
+	m.stopTimeout = 50 * time.Millisecond
+
+	stuck := newTestService("stuck", &log, time.Hour)
+	m.RegisterInPhase(stuck, PhaseWorkers)
+	slow := newTestService("slow", &log, 80*time.Millisecond)
+	m.RegisterInPhase(slow, PhaseWorkers)
+	consensus := newTestService("consensus", &log, 0)
+	m.RegisterInPhase(consensus, PhaseConsensus)
+
+	m.Stop()
+	m.Wait()
+	<-consensus.Quit()
+
+	require.Equal([]string{
+		"stop stuck",
+		"stop slow",
+		"quit slow",
+		"stop consensus",
+		"quit consensus",
+	}, log.events, "stuck services should not block shutdown or waiting for other services")
+}
@@ -68,15 +68,25 @@ func DataDirOrPwd() (string, error) {
 
 // SignerFactory returns the appropriate SignerFactory based on flags.
 func SignerFactory(signerBackend string, signerDir string) (signature.SignerFactory, error) {
+	return newSignerFactory(signerBackend, signerDir, signature.SignerEntity)
+}
+
+// NodeSignerFactory returns the appropriate SignerFactory for the node
+// identity keys based on flags.
+func NodeSignerFactory(signerBackend string, dataDir string) (signature.SignerFactory, error) {
+	return newSignerFactory(signerBackend, dataDir, signature.SignerNode, signature.SignerP2P, signature.SignerConsensus)
+}
+
+func newSignerFactory(signerBackend string, signerDir string, roles ...signature.SignerRole) (signature.SignerFactory, error) {
 	switch signerBackend {
 	case ledgerSigner.SignerName:
 		config := ledgerSigner.FactoryConfig{
 			Address: flags.SignerLedgerAddress(),
 			Index:   flags.SignerLedgerIndex(),
 		}
-		return ledgerSigner.NewFactory(&config, signature.SignerEntity), nil
+		return ledgerSigner.NewFactory(&config, roles...), nil
 	case fileSigner.SignerName:
-		return fileSigner.NewFactory(signerDir, signature.SignerEntity), nil
+		return fileSigner.NewFactory(signerDir, roles...), nil
 	default:
 		return nil, fmt.Errorf("unsupported signer backend: %s", signerBackend)
 	}
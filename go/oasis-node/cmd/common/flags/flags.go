@@ -16,6 +16,9 @@ const (
 	// CfgDebugTestEntity is the command line flag to enable the debug test
 	// entity.
 	CfgDebugTestEntity = "debug.test_entity"
+	// CfgDebugAllowUnroutableAddresses is the command line flag to allow
+	// unroutable node addresses.
+	CfgDebugAllowUnroutableAddresses = "registry.debug.allow_unroutable_addresses"
 	// CfgGenesisFile is the flag used to specify a genesis file.
 	CfgGenesisFile = "genesis.file"
 	// CfgConsensusValidator is the flag used to opt-in to being a validator.
@@ -33,6 +36,10 @@ const (
 	cfgSignerLedgerAddress = "signer.ledger.address"
 	cfgSignerLedgerIndex   = "signer.ledger.index"
 
+	// CfgNodeSigner is the flag used to specify the backend of the node
+	// identity (node, P2P and consensus key) signer.
+	CfgNodeSigner = "node.signer"
+
 	// CfgDryRun is the flag used to specify a dry-run of an operation.
 	CfgDryRun = "dry_run"
 )
@@ -46,8 +53,13 @@ var (
 	RetriesFlags = flag.NewFlagSet("", flag.ContinueOnError)
 	// DebugTestEntityFlags has the test entity enable flag.
 	DebugTestEntityFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	// DebugAllowUnroutableAddressesFlags has the allow unroutable addresses
+	// flag.
+	DebugAllowUnroutableAddressesFlags = flag.NewFlagSet("", flag.ContinueOnError)
 	// SignerFlags has the signer-related flags.
 	SignerFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	// NodeSignerFlags has the node signer flag.
+	NodeSignerFlags = flag.NewFlagSet("", flag.ContinueOnError)
 	// GenesisFileFlags has the genesis file flag.
 	GenesisFileFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
@@ -86,11 +98,22 @@ func DebugTestEntity() bool {
 	return DebugDontBlameOasis() && viper.GetBool(CfgDebugTestEntity)
 }
 
+// DebugAllowUnroutableAddresses returns true iff the allow unroutable
+// addresses flag is set.
+func DebugAllowUnroutableAddresses() bool {
+	return viper.GetBool(CfgDebugAllowUnroutableAddresses)
+}
+
 // Signer returns the configured signer backend name.
 func Signer() string {
 	return viper.GetString(CfgSigner)
 }
 
+// NodeSigner returns the configured node signer backend name.
+func NodeSigner() string {
+	return viper.GetString(CfgNodeSigner)
+}
+
 // SignerDir returns the directory with the entity files, (and the signer keys for file-based signer).
 func SignerDirOrPwd() (string, error) {
 	signerDir := viper.GetString(CfgSignerDir)
@@ -140,11 +163,16 @@ func init() {
 	DebugTestEntityFlags.Bool(CfgDebugTestEntity, false, "use the test entity (UNSAFE)")
 	_ = DebugTestEntityFlags.MarkHidden(CfgDebugTestEntity)
 
+	DebugAllowUnroutableAddressesFlags.Bool(CfgDebugAllowUnroutableAddresses, false, "allow unroutable addreses (UNSAFE)")
+	_ = DebugAllowUnroutableAddressesFlags.MarkHidden(CfgDebugAllowUnroutableAddresses)
+
 	SignerFlags.StringP(CfgSigner, "s", "file", "signer backend [file, ledger]")
 	SignerFlags.String(CfgSignerDir, "", "path to directory containing the entity files. If file signer backend is being used, the directory must also contain the private key. If blank, defaults to the working directory.")
 	SignerFlags.String(cfgSignerLedgerAddress, "", "Ledger signer: select Ledger device based on this specified address. If blank, any available Ledger device will be connected to.")
 	SignerFlags.Uint32(cfgSignerLedgerIndex, 0, "Ledger signer: address index used to derive address on Ledger device")
 
+	NodeSignerFlags.String(CfgNodeSigner, "file", "node identity signer backend [file]")
+
 	GenesisFileFlags.StringP(CfgGenesisFile, "g", "genesis.json", "path to genesis file")
 
 	DebugDontBlameOasisFlag.Bool(CfgDebugDontBlameOasis, false, "Enable debug/unsafe/insecure options")
@@ -157,7 +185,9 @@ func init() {
 		ForceFlags,
 		RetriesFlags,
 		DebugTestEntityFlags,
+		DebugAllowUnroutableAddressesFlags,
 		SignerFlags,
+		NodeSignerFlags,
 		GenesisFileFlags,
 		ConsensusValidatorFlag,
 		DebugDontBlameOasisFlag,
@@ -75,6 +75,14 @@ var (
 		Run: doForceFinalize,
 	}
 
+	storageMaintenanceModeCmd = &cobra.Command{
+		Use:       "maintenance-mode (enable|disable)",
+		Short:     "enter or exit read-only maintenance mode, rejecting all updates",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: []string{"enable", "disable"},
+		Run:       doMaintenanceMode,
+	}
+
 	logger = logging.GetLogger("cmd/storage")
 )
 
@@ -278,6 +286,24 @@ func doForceFinalize(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doMaintenanceMode(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	conn, _ := cmdControl.DoConnect(cmd)
+	storageWorkerClient := storageWorkerAPI.NewStorageWorkerClient(conn)
+	defer conn.Close()
+
+	err := storageWorkerClient.SetMaintenanceMode(ctx, &storageWorkerAPI.SetMaintenanceModeRequest{
+		Enabled: args[0] == "enable",
+	})
+	if err != nil {
+		logger.Error("failed to set maintenance mode",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
 // Register registers the storage sub-command and all of its children.
 func Register(parentCmd *cobra.Command) {
 	storageCheckRootsCmd.Flags().AddFlagSet(storageClient.Flags)
@@ -289,13 +315,20 @@ func Register(parentCmd *cobra.Command) {
 	storageForceFinalizeCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
 	storageForceFinalizeCmd.PersistentFlags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 
+	storageMaintenanceModeCmd.PersistentFlags().AddFlagSet(cmdGrpc.ClientFlags)
+
 	storageExportCmd.Flags().AddFlagSet(storage.Flags)
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.GenesisFileFlags)
 	storageExportCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 	storageExportCmd.Flags().AddFlagSet(storageExportFlags)
 
+	storageVerifyCmd.Flags().AddFlagSet(storage.Flags)
+	storageVerifyCmd.Flags().Uint64Var(&verifyRound, "round", verifyRoundAll, "the finalized round to verify; default all")
+
 	storageCmd.AddCommand(storageCheckRootsCmd)
 	storageCmd.AddCommand(storageForceFinalizeCmd)
+	storageCmd.AddCommand(storageMaintenanceModeCmd)
 	storageCmd.AddCommand(storageExportCmd)
+	storageCmd.AddCommand(storageVerifyCmd)
 	parentCmd.AddCommand(storageCmd)
 }
@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	runtimeRegistry "github.com/oasislabs/oasis-core/go/runtime/registry"
+	storageAPI "github.com/oasislabs/oasis-core/go/storage/api"
+)
+
+// verifyRoundAll is the round value which causes all finalized rounds to be
+// verified.
+const verifyRoundAll = math.MaxUint64
+
+var (
+	verifyRound uint64
+
+	storageVerifyCmd = &cobra.Command{
+		Use:   "verify [runtime-id (hex)...]",
+		Short: "verify the integrity of the local storage state; default all runtimes",
+		Args: func(cmd *cobra.Command, args []string) error {
+			for _, arg := range args {
+				if err := ValidateRuntimeIDStr(arg); err != nil {
+					return fmt.Errorf("malformed runtime id '%v': %v", arg, err)
+				}
+			}
+
+			return nil
+		},
+		Run: doVerify,
+	}
+)
+
+func doVerify(cmd *cobra.Command, args []string) {
+	var ok bool
+	defer func() {
+		if !ok {
+			os.Exit(1)
+		}
+	}()
+
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	dataDir := cmdCommon.DataDir()
+	if dataDir == "" {
+		logger.Error("data directory must be set")
+		return
+	}
+
+	var runtimeIDs []common.Namespace
+	for _, arg := range args {
+		var id common.Namespace
+		_ = id.UnmarshalHex(arg)
+		runtimeIDs = append(runtimeIDs, id)
+	}
+	if len(runtimeIDs) == 0 {
+		var err error
+		if runtimeIDs, err = localRuntimes(dataDir); err != nil {
+			logger.Error("failed to enumerate local runtimes",
+				"err", err,
+			)
+			return
+		}
+	}
+
+	var numIssues int
+	for _, id := range runtimeIDs {
+		issues, err := verifyRuntime(dataDir, id)
+		if err != nil {
+			return
+		}
+		numIssues += len(issues)
+	}
+	if numIssues > 0 {
+		logger.Error("storage verification found issues",
+			"num_issues", numIssues,
+		)
+		return
+	}
+
+	logger.Info("storage verification completed without issues")
+
+	ok = true
+}
+
+func localRuntimes(dataDir string) ([]common.Namespace, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(dataDir, runtimeRegistry.RuntimesDir))
+	if err != nil {
+		return nil, err
+	}
+
+	var runtimeIDs []common.Namespace
+	for _, entry := range entries {
+		var id common.Namespace
+		if !entry.IsDir() || id.UnmarshalHex(entry.Name()) != nil {
+			continue
+		}
+		runtimeIDs = append(runtimeIDs, id)
+	}
+	return runtimeIDs, nil
+}
+
+func verifyRuntime(dataDir string, id common.Namespace) ([]*storageAPI.VerifyIssue, error) {
+	dataDir = filepath.Join(dataDir, runtimeRegistry.RuntimesDir, id.String())
+
+	// Make sure not to create a new runtime state directory as a side effect.
+	if _, err := os.Stat(dataDir); err != nil {
+		logger.Error("failed to access runtime state directory",
+			"err", err,
+			"runtime_id", id,
+		)
+		return nil, err
+	}
+
+	// Initialize the storage backend.
+	storageBackend, err := newDirectStorageBackend(dataDir, id)
+	if err != nil {
+		logger.Error("failed to construct storage backend",
+			"err", err,
+		)
+		return nil, err
+	}
+	defer storageBackend.Cleanup()
+
+	localBackend, ok := storageBackend.(storageAPI.LocalBackend)
+	if !ok {
+		logger.Error("storage backend does not support verification")
+		return nil, storageAPI.ErrUnsupported
+	}
+
+	request := &storageAPI.VerifyRequest{
+		Namespace: id,
+	}
+	if verifyRound != verifyRoundAll {
+		request.Round = &verifyRound
+	}
+
+	logger.Info("verifying storage",
+		"runtime_id", id,
+	)
+
+	issues, err := localBackend.Verify(context.Background(), request)
+	if err != nil {
+		logger.Error("failed to verify storage",
+			"err", err,
+			"runtime_id", id,
+		)
+		return nil, err
+	}
+	for _, issue := range issues {
+		logger.Error("storage integrity issue",
+			"runtime_id", id,
+			"round", issue.Root.Round,
+			"root", issue.Root.Hash,
+			"node", issue.Node,
+			"err", issue.Err,
+		)
+	}
+
+	return issues, nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -14,13 +15,17 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint/abci"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/crypto"
+	tmDB "github.com/oasislabs/oasis-core/go/consensus/tendermint/db"
 	"github.com/oasislabs/oasis-core/go/consensus/tendermint/inspector"
 	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
+	cmdFlags "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 )
 
 var (
-	stateFilename string
+	stateFilename  string
+	exportFilename string
 
 	tmCmd = &cobra.Command{
 		Use:   "tendermint",
@@ -33,6 +38,18 @@ var (
 		Run:   doDumpMuxState,
 	}
 
+	tmExportMuxStateCmd = &cobra.Command{
+		Use:   "export-abci-mux-state",
+		Short: "export ABCI mux state",
+		Run:   doExportMuxState,
+	}
+
+	tmImportMuxStateCmd = &cobra.Command{
+		Use:   "import-abci-mux-state",
+		Short: "import exported ABCI mux state into an empty state file",
+		Run:   doImportMuxState,
+	}
+
 	tmShowNodeIDCmd = &cobra.Command{
 		Use:   "show-node-id",
 		Short: "otuputs tendermint node id",
@@ -83,6 +100,87 @@ func doDumpMuxState(cmd *cobra.Command, args []string) {
 	fmt.Printf("%s\n", buf.Bytes())
 }
 
+func doExportMuxState(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	logger := logging.GetLogger("cmd/debug/tendermint/export-abci-mux-state")
+
+	if !cmdFlags.DebugDontBlameOasis() {
+		logger.Error("refusing to export ABCI mux state without debug flags set")
+		os.Exit(1)
+	}
+
+	state, err := inspector.OpenMuxState(stateFilename)
+	if err != nil {
+		logger.Error("failed to open ABCI mux state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer state.Close()
+
+	f, err := os.Create(exportFilename)
+	if err != nil {
+		logger.Error("failed to create export file",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err = abci.ExportTree(state.DB(), state.Tree(), f); err != nil {
+		logger.Error("failed to export ABCI mux state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+}
+
+func doImportMuxState(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	logger := logging.GetLogger("cmd/debug/tendermint/import-abci-mux-state")
+
+	if !cmdFlags.DebugDontBlameOasis() {
+		logger.Error("refusing to import ABCI mux state without debug flags set")
+		os.Exit(1)
+	}
+
+	f, err := os.Open(exportFilename)
+	if err != nil {
+		logger.Error("failed to open export file",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	db, err := tmDB.New(stateFilename, true)
+	if err != nil {
+		logger.Error("failed to open ABCI mux state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	version, err := abci.ImportTree(f, db)
+	if err != nil {
+		logger.Error("failed to import ABCI mux state",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	logger.Info("imported ABCI mux state",
+		"version", version,
+	)
+}
+
 func showNodeID(cmd *cobra.Command, args []string) {
 	if err := cmdCommon.Init(); err != nil {
 		cmdCommon.EarlyLogAndExit(err)
@@ -105,7 +203,15 @@ func showNodeID(cmd *cobra.Command, args []string) {
 // Register registers the tendermint sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	tmDumpMuxStateCmd.Flags().StringVarP(&stateFilename, "state", "s", "abci-mux-state.bolt.db", "ABCI mux state file to dump")
+	tmExportMuxStateCmd.Flags().StringVarP(&stateFilename, "state", "s", "abci-mux-state.bolt.db", "ABCI mux state file to export")
+	tmExportMuxStateCmd.Flags().StringVarP(&exportFilename, "output", "o", "abci-mux-state.export", "output file")
+	tmExportMuxStateCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
+	tmImportMuxStateCmd.Flags().StringVarP(&stateFilename, "state", "s", "abci-mux-state.bolt.db", "ABCI mux state file to import into")
+	tmImportMuxStateCmd.Flags().StringVarP(&exportFilename, "input", "i", "abci-mux-state.export", "input file")
+	tmImportMuxStateCmd.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 	tmCmd.AddCommand(tmShowNodeIDCmd)
 	tmCmd.AddCommand(tmDumpMuxStateCmd)
+	tmCmd.AddCommand(tmExportMuxStateCmd)
+	tmCmd.AddCommand(tmImportMuxStateCmd)
 	parentCmd.AddCommand(tmCmd)
 }
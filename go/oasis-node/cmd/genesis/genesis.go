@@ -52,7 +52,6 @@ const (
 	cfgHaltEpoch          = "halt.epoch"
 
 	// Registry config flags.
-	cfgRegistryDebugAllowUnroutableAddresses = "registry.debug.allow_unroutable_addresses"
 	CfgRegistryDebugAllowRuntimeRegistration = "registry.debug.allow_runtime_registration"
 	CfgRegistryDebugAllowTestRuntimes        = "registry.debug.allow_test_runtimes"
 	cfgRegistryDebugBypassStake              = "registry.debug.bypass_stake" // nolint: gosec
@@ -78,7 +77,9 @@ const (
 	cfgConsensusMaxTxSizeBytes     = "consensus.tendermint.max_tx_size"
 	cfgConsensusMaxBlockSizeBytes  = "consensus.tendermint.max_block_size"
 	cfgConsensusMaxBlockGas        = "consensus.tendermint.max_block_gas"
+	cfgConsensusMaxTxPerBlock      = "consensus.tendermint.max_tx_per_block"
 	cfgConsensusMaxEvidenceAge     = "consensus.tendermint.max_evidence_age"
+	cfgConsensusUpdateAuthority    = "consensus.update_authority"
 
 	// Consensus backend config flag.
 	cfgConsensusBackend = "consensus.backend"
@@ -215,9 +216,21 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 			MaxTxSize:          uint64(viper.GetSizeInBytes(cfgConsensusMaxTxSizeBytes)),
 			MaxBlockSize:       uint64(viper.GetSizeInBytes(cfgConsensusMaxBlockSizeBytes)),
 			MaxBlockGas:        viper.GetUint64(cfgConsensusMaxBlockGas),
+			MaxTxPerBlock:      viper.GetUint64(cfgConsensusMaxTxPerBlock),
 			MaxEvidenceAge:     viper.GetUint64(cfgConsensusMaxEvidenceAge),
 		},
 	}
+	for _, v := range viper.GetStringSlice(cfgConsensusUpdateAuthority) {
+		var pk signature.PublicKey
+		if err := pk.UnmarshalText([]byte(v)); err != nil {
+			logger.Error("failed to parse consensus update authority",
+				"err", err,
+				"update_authority", v,
+			)
+			return
+		}
+		doc.Consensus.Parameters.UpdateAuthorities = append(doc.Consensus.Parameters.UpdateAuthorities, pk)
+	}
 
 	// Ensure consistency/sanity.
 	if err := doc.SanityCheck(); err != nil {
@@ -243,7 +256,7 @@ func doInitGenesis(cmd *cobra.Command, args []string) {
 func AppendRegistryState(doc *genesis.Document, entities, runtimes, nodes []string, l *logging.Logger) error {
 	regSt := registry.Genesis{
 		Parameters: registry.ConsensusParameters{
-			DebugAllowUnroutableAddresses: viper.GetBool(cfgRegistryDebugAllowUnroutableAddresses),
+			DebugAllowUnroutableAddresses: flags.DebugAllowUnroutableAddresses(),
 			DebugAllowRuntimeRegistration: viper.GetBool(CfgRegistryDebugAllowRuntimeRegistration),
 			DebugAllowTestRuntimes:        viper.GetBool(CfgRegistryDebugAllowTestRuntimes),
 			DebugBypassStake:              viper.GetBool(cfgRegistryDebugBypassStake),
@@ -458,7 +471,11 @@ func AppendRootHashState(doc *genesis.Document, exports []string, l *logging.Log
 // AppendKeyManagerState appends the key manager genesis state given a vector of
 // key manager statuses.
 func AppendKeyManagerState(doc *genesis.Document, statuses []string, l *logging.Logger) error {
-	var kmSt keymanager.Genesis
+	kmSt := keymanager.Genesis{
+		Parameters: keymanager.ConsensusParameters{
+			GasCosts: keymanager.DefaultGasCosts,
+		},
+	}
 
 	for _, v := range statuses {
 		b, err := ioutil.ReadFile(v)
@@ -689,11 +706,9 @@ func init() {
 	initGenesisFlags.Uint64(cfgHaltEpoch, math.MaxUint64, "genesis halt epoch height")
 
 	// Registry config flags.
-	initGenesisFlags.Bool(cfgRegistryDebugAllowUnroutableAddresses, false, "allow unroutable addreses (UNSAFE)")
 	initGenesisFlags.Bool(CfgRegistryDebugAllowRuntimeRegistration, false, "enable non-genesis runtime registration (UNSAFE)")
 	initGenesisFlags.Bool(CfgRegistryDebugAllowTestRuntimes, false, "enable test runtime registration")
 	initGenesisFlags.Bool(cfgRegistryDebugBypassStake, false, "bypass all stake checks and operations (UNSAFE)")
-	_ = initGenesisFlags.MarkHidden(cfgRegistryDebugAllowUnroutableAddresses)
 	_ = initGenesisFlags.MarkHidden(CfgRegistryDebugAllowRuntimeRegistration)
 	_ = initGenesisFlags.MarkHidden(CfgRegistryDebugAllowTestRuntimes)
 	_ = initGenesisFlags.MarkHidden(cfgRegistryDebugBypassStake)
@@ -723,7 +738,9 @@ func init() {
 	initGenesisFlags.String(cfgConsensusMaxTxSizeBytes, "32kb", "tendermint maximum transaction size (in bytes)")
 	initGenesisFlags.String(cfgConsensusMaxBlockSizeBytes, "21mb", "tendermint maximum block size (in bytes)")
 	initGenesisFlags.Uint64(cfgConsensusMaxBlockGas, 0, "tendermint max gas used per block")
+	initGenesisFlags.Uint64(cfgConsensusMaxTxPerBlock, 0, "tendermint max transactions per block")
 	initGenesisFlags.Uint64(cfgConsensusMaxEvidenceAge, 100000, "tendermint max evidence age (in blocks)")
+	initGenesisFlags.StringSlice(cfgConsensusUpdateAuthority, nil, "public key allowed to update consensus parameters (can specify multiple)")
 
 	// Consensus backend flag.
 	initGenesisFlags.String(cfgConsensusBackend, tendermint.BackendName, "consensus backend")
@@ -732,6 +749,7 @@ func init() {
 	initGenesisFlags.StringSlice(cfgEntity, nil, "path to entity registration file")
 	_ = viper.BindPFlag(viperEntity, initGenesisFlags.Lookup(cfgEntity))
 	initGenesisFlags.AddFlagSet(flags.DebugTestEntityFlags)
+	initGenesisFlags.AddFlagSet(flags.DebugAllowUnroutableAddressesFlags)
 	initGenesisFlags.AddFlagSet(flags.GenesisFileFlags)
 	initGenesisFlags.AddFlagSet(flags.DebugDontBlameOasisFlag)
 }
@@ -7,8 +7,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
-	fileSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/file"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
 	cmdCommon "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common"
@@ -42,9 +40,14 @@ func doNodeInit(cmd *cobra.Command, args []string) {
 	}
 
 	// Provision the node identity.
-	nodeSignerFactory := fileSigner.NewFactory(dataDir, signature.SignerNode, signature.SignerP2P, signature.SignerConsensus)
-	_, err := identity.LoadOrGenerate(dataDir, nodeSignerFactory)
+	nodeSignerFactory, err := cmdCommon.NodeSignerFactory(cmdFlags.NodeSigner(), dataDir)
 	if err != nil {
+		logger.Error("failed to create node signer factory",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	if _, err = identity.LoadOrGenerate(dataDir, nodeSignerFactory); err != nil {
 		logger.Error("failed to load or generate node identity",
 			"err", err,
 		)
@@ -57,6 +60,7 @@ func doNodeInit(cmd *cobra.Command, args []string) {
 // Register registers the client sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	identityInitCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
+	identityInitCmd.Flags().AddFlagSet(cmdFlags.NodeSignerFlags)
 	identityCmd.AddCommand(identityInitCmd)
 
 	parentCmd.AddCommand(identityCmd)
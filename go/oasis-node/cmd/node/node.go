@@ -15,8 +15,6 @@ import (
 	beacon "github.com/oasislabs/oasis-core/go/beacon/api"
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crash"
-	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
-	fileSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/file"
 	"github.com/oasislabs/oasis-core/go/common/grpc"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
@@ -245,8 +243,8 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 		)
 		return err
 	}
-	n.svcMgr.Register(n.CommonWorker.Grpc)
-	n.svcMgr.Register(n.CommonWorker)
+	n.svcMgr.RegisterInPhase(n.CommonWorker.Grpc, background.PhaseWorkers)
+	n.svcMgr.RegisterInPhase(n.CommonWorker, background.PhaseWorkers)
 
 	workerCommonCfg := n.CommonWorker.GetConfig()
 
@@ -273,7 +271,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 		)
 		return err
 	}
-	n.svcMgr.Register(n.RegistrationWorker)
+	n.svcMgr.RegisterInPhase(n.RegistrationWorker, background.PhaseWorkers)
 
 	// Initialize the key manager worker.
 	n.KeymanagerWorker, err = workerKeymanager.New(
@@ -286,7 +284,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 	if err != nil {
 		return err
 	}
-	n.svcMgr.Register(n.KeymanagerWorker)
+	n.svcMgr.RegisterInPhase(n.KeymanagerWorker, background.PhaseWorkers)
 
 	// Initialize the storage worker.
 	n.StorageWorker, err = workerStorage.New(
@@ -299,7 +297,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 	if err != nil {
 		return err
 	}
-	n.svcMgr.Register(n.StorageWorker)
+	n.svcMgr.RegisterInPhase(n.StorageWorker, background.PhaseWorkers)
 
 	// Initialize the merge worker.
 	n.MergeWorker, err = merge.New(
@@ -309,7 +307,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 	if err != nil {
 		return err
 	}
-	n.svcMgr.Register(n.MergeWorker)
+	n.svcMgr.RegisterInPhase(n.MergeWorker, background.PhaseWorkers)
 
 	// Initialize the executor worker.
 	n.ExecutorWorker, err = executor.New(
@@ -321,7 +319,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 	if err != nil {
 		return err
 	}
-	n.svcMgr.Register(n.ExecutorWorker)
+	n.svcMgr.RegisterInPhase(n.ExecutorWorker, background.PhaseWorkers)
 
 	// Initialize the sentry worker.
 	n.SentryWorker, err = workerSentry.New(
@@ -331,7 +329,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 	if err != nil {
 		return err
 	}
-	n.svcMgr.Register(n.SentryWorker)
+	n.svcMgr.RegisterInPhase(n.SentryWorker, background.PhaseWorkers)
 
 	// Initialize the transaction scheduler.
 	n.TransactionSchedulerWorker, err = txnscheduler.New(
@@ -342,7 +340,7 @@ func (n *Node) initWorkers(logger *logging.Logger) error {
 	if err != nil {
 		return err
 	}
-	n.svcMgr.Register(n.TransactionSchedulerWorker)
+	n.svcMgr.RegisterInPhase(n.TransactionSchedulerWorker, background.PhaseWorkers)
 
 	return nil
 }
@@ -510,8 +508,13 @@ func newNode(testNode bool) (*Node, error) {
 	}
 
 	// Generate/Load the node identity.
-	// TODO/hsm: Configure factory dynamically.
-	signerFactory := fileSigner.NewFactory(dataDir, signature.SignerNode, signature.SignerP2P, signature.SignerConsensus)
+	signerFactory, err := cmdCommon.NodeSignerFactory(flags.NodeSigner(), dataDir)
+	if err != nil {
+		logger.Error("failed to create node signer factory",
+			"err", err,
+		)
+		return nil, err
+	}
 	node.Identity, err = identity.LoadOrGenerate(dataDir, signerFactory)
 	if err != nil {
 		logger.Error("failed to load/generate identity",
@@ -590,7 +593,7 @@ func newNode(testNode bool) (*Node, error) {
 			)
 			return nil, err
 		}
-		node.svcMgr.Register(node.svcTmntSeed)
+		node.svcMgr.RegisterInPhase(node.svcTmntSeed, background.PhaseConsensus)
 	} else {
 		// Initialize Tendermint service.
 		node.svcTmnt, err = tendermint.New(node.svcMgr.Ctx, dataDir, node.Identity, node.Genesis)
@@ -600,7 +603,7 @@ func newNode(testNode bool) (*Node, error) {
 			)
 			return nil, err
 		}
-		node.svcMgr.Register(node.svcTmnt)
+		node.svcMgr.RegisterInPhase(node.svcTmnt, background.PhaseConsensus)
 		node.Consensus = node.svcTmnt
 		node.Epochtime = node.Consensus.EpochTime()
 		node.Beacon = node.Consensus.Beacon()
@@ -731,11 +734,11 @@ func newNode(testNode bool) (*Node, error) {
 	}
 
 	// Initialize and start the node controller.
-	node.NodeController = control.New(node, node.Consensus)
+	node.NodeController = control.New(node, node.Consensus, node.RegistrationWorker)
 	controlAPI.RegisterService(node.grpcInternal.Server(), node.NodeController)
 	if flags.DebugDontBlameOasis() {
 		// Initialize and start the debug controller if we are in debug mode.
-		node.DebugController = control.NewDebug(node.Epochtime, node.Registry)
+		node.DebugController = control.NewDebug(node.Epochtime, node.Registry, node.Consensus)
 		controlAPI.RegisterDebugService(node.grpcInternal.Server(), node.DebugController)
 	}
 
@@ -777,6 +780,7 @@ func init() {
 	Flags.AddFlagSet(flags.DebugTestEntityFlags)
 	Flags.AddFlagSet(flags.ConsensusValidatorFlag)
 	Flags.AddFlagSet(flags.GenesisFileFlags)
+	Flags.AddFlagSet(flags.NodeSignerFlags)
 
 	// Backend initialization flags.
 	for _, v := range []*flag.FlagSet{
@@ -4,6 +4,7 @@ package node
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -15,8 +16,9 @@ import (
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
 
+	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
-	fileSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/file"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
 	"github.com/oasislabs/oasis-core/go/common/entity"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
@@ -38,11 +40,19 @@ const (
 	CfgRole             = "node.role"
 	CfgSelfSigned       = "node.is_self_signed"
 	CfgNodeRuntimeID    = "node.runtime.id"
+	CfgCheckAddresses   = "node.check_addresses"
 
-	optRoleComputeWorker = "compute-worker"
-	optRoleStorageWorker = "storage-worker"
-	optRoleKeyManager    = "key-manager"
-	optRoleValidator     = "validator"
+	cfgListEntityID   = "entity-id"
+	cfgListWithStatus = "with-status"
+
+	cfgStatusNodeID = "node-id"
+
+	optRoleComputeWorker        = "compute-worker"
+	optRoleStorageWorker        = "storage-worker"
+	optRoleTransactionScheduler = "transaction-scheduler"
+	optRoleKeyManager           = "key-manager"
+	optRoleMergeWorker          = "merge-worker"
+	optRoleValidator            = "validator"
 
 	NodeGenesisFilename = "node_genesis.json"
 
@@ -50,7 +60,9 @@ const (
 )
 
 var (
-	flags = flag.NewFlagSet("", flag.ContinueOnError)
+	flags       = flag.NewFlagSet("", flag.ContinueOnError)
+	listFlags   = flag.NewFlagSet("", flag.ContinueOnError)
+	statusFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	nodeCmd = &cobra.Command{
 		Use:   "node",
@@ -69,6 +81,12 @@ var (
 		Run:   doList,
 	}
 
+	statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "show the status (e.g., freeze state) of a registered node",
+		Run:   doStatus,
+	}
+
 	logger = logging.GetLogger("cmd/registry/node")
 )
 
@@ -97,6 +115,7 @@ func doInit(cmd *cobra.Command, args []string) {
 		)
 		os.Exit(1)
 	}
+	isDryRun := cmdFlags.DryRun()
 
 	// Get the entity ID or entity.
 	var (
@@ -144,8 +163,19 @@ func doInit(cmd *cobra.Command, args []string) {
 	}
 
 	// Provision the node identity.
-	nodeSignerFactory := fileSigner.NewFactory(dataDir, signature.SignerNode, signature.SignerP2P, signature.SignerConsensus)
-	nodeIdentity, err := identity.LoadOrGenerate(dataDir, nodeSignerFactory)
+	nodeSignerFactory, err := cmdCommon.NodeSignerFactory(cmdFlags.NodeSigner(), dataDir)
+	if err != nil {
+		logger.Error("failed to create node signer factory",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	var nodeIdentity *identity.Identity
+	if isDryRun {
+		nodeIdentity, err = loadDryRunIdentity(dataDir, nodeSignerFactory)
+	} else {
+		nodeIdentity, err = identity.LoadOrGenerate(dataDir, nodeSignerFactory)
+	}
 	if err != nil {
 		logger.Error("failed to load or generate node identity",
 			"err", err,
@@ -153,7 +183,7 @@ func doInit(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	if isSelfSigned {
+	if isSelfSigned && !isDryRun {
 		signer, err = nodeSignerFactory.Load(signature.SignerNode)
 		if err != nil {
 			// Should never happen.
@@ -248,6 +278,22 @@ func doInit(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if viper.GetBool(CfgCheckAddresses) {
+		allowUnroutable := cmdFlags.DebugAllowUnroutableAddresses() && cmdFlags.DebugDontBlameOasis()
+		if err = checkAddresses(n, allowUnroutable); err != nil {
+			logger.Error("node address check failed",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+	}
+
+	if isDryRun {
+		b, _ := json.MarshalIndent(n, "", "  ")
+		fmt.Printf("%s\n", b)
+		return
+	}
+
 	// Sign and write out the genesis node registration.
 	signed, err := node.SignNode(signer, registry.RegisterGenesisNodeSignatureContext, n)
 	if err != nil {
@@ -265,12 +311,70 @@ func doInit(cmd *cobra.Command, args []string) {
 	}
 }
 
+// loadDryRunIdentity loads the node identity from the data directory
+// without modifying it, or generates an ephemeral identity if none exists.
+func loadDryRunIdentity(dataDir string, signerFactory signature.SignerFactory) (*identity.Identity, error) {
+	nodeIdentity, err := identity.Load(dataDir, signerFactory)
+	if !errors.Is(err, os.ErrNotExist) {
+		return nodeIdentity, err
+	}
+
+	tmpDir, err := ioutil.TempDir("", "oasis-node-init-dry-run")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	return identity.LoadOrGenerate(tmpDir, memorySigner.NewFactory())
+}
+
+// checkAddresses verifies the node's addresses using the same rules that
+// the registry applies on node registration.
+func checkAddresses(n *node.Node, allowUnroutable bool) error {
+	checkAddress := func(kind string, addr node.Address) error {
+		if err := common.IsAddrPort(addr.String()); err != nil {
+			return fmt.Errorf("node: malformed %s address '%s': %w", kind, addr, err)
+		}
+		if err := registry.VerifyAddress(addr, allowUnroutable); err != nil {
+			return fmt.Errorf("node: unroutable %s address '%s'", kind, addr)
+		}
+		return nil
+	}
+
+	if len(n.Committee.Addresses) > 0 {
+		if _, err := n.Committee.ParseCertificate(); err != nil {
+			return fmt.Errorf("node: malformed committee certificate: %w", err)
+		}
+	}
+	for _, addr := range n.Committee.Addresses {
+		if err := checkAddress("committee", addr); err != nil {
+			return err
+		}
+	}
+	for _, addr := range n.P2P.Addresses {
+		if err := checkAddress("P2P", addr); err != nil {
+			return err
+		}
+	}
+	for _, addr := range n.Consensus.Addresses {
+		if !addr.ID.IsValid() {
+			return fmt.Errorf("node: invalid consensus address ID '%s'", addr.ID)
+		}
+		if err := checkAddress("consensus", addr.Address); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func argsToRolesMask() (node.RolesMask, error) {
 	var rolesMask node.RolesMask
 	for _, v := range viper.GetStringSlice(CfgRole) {
 		v = strings.ToLower(v)
 		switch v {
-		case optRoleComputeWorker:
+		case optRoleComputeWorker, optRoleTransactionScheduler, optRoleMergeWorker:
+			// Transaction scheduler and merge workers register as compute
+			// workers, so they share the same role.
 			rolesMask |= node.RoleComputeWorker
 		case optRoleStorageWorker:
 			rolesMask |= node.RoleStorageWorker
@@ -290,10 +394,37 @@ func doList(cmd *cobra.Command, args []string) {
 		cmdCommon.EarlyLogAndExit(err)
 	}
 
+	var entityID *signature.PublicKey
+	if idStr := viper.GetString(cfgListEntityID); idStr != "" {
+		entityID = new(signature.PublicKey)
+		if err := entityID.UnmarshalHex(idStr); err != nil {
+			logger.Error("malformed entity ID",
+				"err", err,
+			)
+			os.Exit(1)
+		}
+	}
+
 	conn, client := doConnect(cmd)
 	defer conn.Close()
 
-	nodes, err := client.GetNodes(context.Background(), consensus.HeightLatest)
+	if viper.GetBool(cfgListWithStatus) {
+		doListWithStatus(client, entityID)
+		return
+	}
+
+	var (
+		nodes []*node.Node
+		err   error
+	)
+	if entityID != nil {
+		nodes, err = client.GetEntityNodes(context.Background(), &registry.IDQuery{
+			ID:     *entityID,
+			Height: consensus.HeightLatest,
+		})
+	} else {
+		nodes, err = client.GetNodes(context.Background(), consensus.HeightLatest)
+	}
 	if err != nil {
 		logger.Error("failed to query nodes",
 			"err", err,
@@ -315,27 +446,95 @@ func doList(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doListWithStatus(client registry.Backend, entityID *signature.PublicKey) {
+	nodes, err := client.GetNodesWithStatus(context.Background(), consensus.HeightLatest)
+	if err != nil {
+		logger.Error("failed to query nodes",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	for _, n := range nodes {
+		if entityID != nil && !n.Node.EntityID.Equal(*entityID) {
+			continue
+		}
+
+		var s string
+		switch cmdFlags.Verbose() {
+		case true:
+			b, _ := json.Marshal(n)
+			s = string(b)
+		default:
+			b, _ := json.Marshal(n.Status)
+			s = n.Node.ID.String() + " " + string(b)
+		}
+
+		fmt.Printf("%v\n", s)
+	}
+}
+
+func doStatus(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	var nodeID signature.PublicKey
+	if err := nodeID.UnmarshalHex(viper.GetString(cfgStatusNodeID)); err != nil {
+		logger.Error("malformed node ID",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	conn, client := doConnect(cmd)
+	defer conn.Close()
+
+	status, err := client.GetNodeStatus(context.Background(), &registry.IDQuery{
+		ID:     nodeID,
+		Height: consensus.HeightLatest,
+	})
+	if err != nil {
+		logger.Error("failed to query node status",
+			"err", err,
+			"node_id", nodeID,
+		)
+		os.Exit(1)
+	}
+
+	b, _ := json.Marshal(status)
+	fmt.Printf("%s\n", b)
+}
+
 // Register registers the node sub-command and all of it's children.
 func Register(parentCmd *cobra.Command) {
 	for _, v := range []*cobra.Command{
 		initCmd,
 		listCmd,
+		statusCmd,
 	} {
 		nodeCmd.AddCommand(v)
 	}
 
 	listCmd.Flags().AddFlagSet(cmdFlags.VerboseFlags)
+	listCmd.Flags().AddFlagSet(listFlags)
+	statusCmd.Flags().AddFlagSet(statusFlags)
 
 	for _, v := range []*cobra.Command{
 		initCmd,
 	} {
 		v.Flags().AddFlagSet(cmdFlags.DebugTestEntityFlags)
+		v.Flags().AddFlagSet(cmdFlags.DebugAllowUnroutableAddressesFlags)
+		v.Flags().AddFlagSet(cmdFlags.DebugDontBlameOasisFlag)
 		v.Flags().AddFlagSet(cmdFlags.SignerFlags)
+		v.Flags().AddFlagSet(cmdFlags.NodeSignerFlags)
+		v.Flags().AddFlagSet(cmdFlags.DryRunFlag)
 		v.Flags().AddFlagSet(flags)
 	}
 
 	for _, v := range []*cobra.Command{
 		listCmd,
+		statusCmd,
 	} {
 		v.Flags().AddFlagSet(cmdGrpc.ClientFlags)
 	}
@@ -352,6 +551,14 @@ func init() {
 	flags.StringSlice(CfgRole, nil, "Role(s) of the node.  Supported values are \"compute-worker\", \"storage-worker\", \"transaction-scheduler\", \"key-manager\", \"merge-worker\", and \"validator\"")
 	flags.Bool(CfgSelfSigned, false, "Node registration should be self-signed")
 	flags.StringSlice(CfgNodeRuntimeID, nil, "Hex Encoded Runtime ID(s) of the node.")
+	flags.Bool(CfgCheckAddresses, false, "Verify that the node's addresses are valid and routable")
 
 	_ = viper.BindPFlags(flags)
+
+	listFlags.String(cfgListEntityID, "", "Only list nodes belonging to the given entity ID")
+	listFlags.Bool(cfgListWithStatus, false, "Include node statuses")
+	_ = viper.BindPFlags(listFlags)
+
+	statusFlags.String(cfgStatusNodeID, "", "ID of the node to show the status of")
+	_ = viper.BindPFlags(statusFlags)
 }
@@ -0,0 +1,197 @@
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	fileSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/file"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/identity"
+	"github.com/oasislabs/oasis-core/go/common/node"
+)
+
+func TestArgsToRolesMask(t *testing.T) {
+	require := require.New(t)
+
+	for _, tc := range []struct {
+		roles        []string
+		expectedMask node.RolesMask
+		committee    bool
+	}{
+		{[]string{optRoleComputeWorker}, node.RoleComputeWorker, true},
+		{[]string{optRoleStorageWorker}, node.RoleStorageWorker, true},
+		{[]string{optRoleTransactionScheduler}, node.RoleComputeWorker, true},
+		{[]string{optRoleKeyManager}, node.RoleKeyManager, true},
+		{[]string{optRoleMergeWorker}, node.RoleComputeWorker, true},
+		{[]string{optRoleValidator}, node.RoleValidator, false},
+		{[]string{"Validator"}, node.RoleValidator, false},
+		{[]string{optRoleStorageWorker, optRoleValidator}, node.RoleStorageWorker | node.RoleValidator, true},
+	} {
+		viper.Set(CfgRole, tc.roles)
+		mask, err := argsToRolesMask()
+		require.NoError(err, "argsToRolesMask(%v)", tc.roles)
+		require.Equal(tc.expectedMask, mask, "argsToRolesMask(%v)", tc.roles)
+		require.Equal(tc.committee, mask&maskCommitteeMember != 0, "committee member role (%v)", tc.roles)
+	}
+
+	viper.Set(CfgRole, []string{"invalid-role"})
+	_, err := argsToRolesMask()
+	require.Error(err, "argsToRolesMask should fail for unsupported roles")
+}
+
+func TestLoadDryRunIdentity(t *testing.T) {
+	require := require.New(t)
+
+	dataDir, err := ioutil.TempDir("", "oasis-node-init-test_")
+	require.NoError(err, "create data dir")
+	defer os.RemoveAll(dataDir)
+
+	factory := fileSigner.NewFactory(dataDir, signature.SignerNode, signature.SignerP2P, signature.SignerConsensus)
+
+	// Without an existing identity, an ephemeral one should be generated
+	// without touching the data directory.
+	ephemeral, err := loadDryRunIdentity(dataDir, factory)
+	require.NoError(err, "loadDryRunIdentity")
+	require.NotNil(ephemeral.NodeSigner, "ephemeral identity should have a node signer")
+	files, err := ioutil.ReadDir(dataDir)
+	require.NoError(err, "ReadDir")
+	require.Empty(files, "dry run should not write to the data directory")
+
+	// With an existing identity, it should be used.
+	existing, err := identity.LoadOrGenerate(dataDir, factory)
+	require.NoError(err, "LoadOrGenerate")
+	loaded, err := loadDryRunIdentity(dataDir, factory)
+	require.NoError(err, "loadDryRunIdentity")
+	require.Equal(existing.NodeSigner.Public(), loaded.NodeSigner.Public(), "existing identity should be loaded")
+	require.NotEqual(ephemeral.NodeSigner.Public(), loaded.NodeSigner.Public(), "ephemeral identity should not be persisted")
+}
+
+func TestCheckAddresses(t *testing.T) {
+	require := require.New(t)
+
+	// Make sure that invalid public keys are rejected.
+	signature.BuildPublicKeyBlacklist(true)
+
+	dataDir, err := ioutil.TempDir("", "oasis-node-init-test_")
+	require.NoError(err, "create data dir")
+	defer os.RemoveAll(dataDir)
+
+	ident, err := identity.LoadOrGenerate(dataDir, memorySigner.NewFactory())
+	require.NoError(err, "LoadOrGenerate")
+
+	mustAddress := func(s string) node.Address {
+		var addr node.Address
+		require.NoError(addr.UnmarshalText([]byte(s)), "UnmarshalText(%s)", s)
+		return addr
+	}
+
+	for _, tc := range []struct {
+		msg             string
+		node            node.Node
+		allowUnroutable bool
+		valid           bool
+	}{
+		{
+			"no addresses",
+			node.Node{},
+			false,
+			true,
+		},
+		{
+			"routable addresses",
+			node.Node{
+				Committee: node.CommitteeInfo{
+					Certificate: ident.TLSCertificate.Certificate[0],
+					Addresses:   []node.Address{mustAddress("8.8.8.8:9100")},
+				},
+				P2P: node.P2PInfo{
+					Addresses: []node.Address{mustAddress("8.8.8.8:9200")},
+				},
+				Consensus: node.ConsensusInfo{
+					Addresses: []node.ConsensusAddress{
+						{ID: ident.P2PSigner.Public(), Address: mustAddress("8.8.4.4:26656")},
+					},
+				},
+			},
+			false,
+			true,
+		},
+		{
+			"unroutable P2P address",
+			node.Node{
+				P2P: node.P2PInfo{
+					Addresses: []node.Address{mustAddress("127.0.0.1:9200")},
+				},
+			},
+			false,
+			false,
+		},
+		{
+			"unroutable P2P address (allowed)",
+			node.Node{
+				P2P: node.P2PInfo{
+					Addresses: []node.Address{mustAddress("127.0.0.1:9200")},
+				},
+			},
+			true,
+			true,
+		},
+		{
+			"private consensus address",
+			node.Node{
+				Consensus: node.ConsensusInfo{
+					Addresses: []node.ConsensusAddress{
+						{ID: ident.P2PSigner.Public(), Address: mustAddress("192.168.0.1:26656")},
+					},
+				},
+			},
+			false,
+			false,
+		},
+		{
+			"invalid consensus address ID",
+			node.Node{
+				Consensus: node.ConsensusInfo{
+					Addresses: []node.ConsensusAddress{
+						{Address: mustAddress("8.8.4.4:26656")},
+					},
+				},
+			},
+			true,
+			false,
+		},
+		{
+			"missing port",
+			node.Node{
+				P2P: node.P2PInfo{
+					Addresses: []node.Address{mustAddress("8.8.8.8:0")},
+				},
+			},
+			true,
+			false,
+		},
+		{
+			"malformed committee certificate",
+			node.Node{
+				Committee: node.CommitteeInfo{
+					Certificate: []byte("not a certificate"),
+					Addresses:   []node.Address{mustAddress("8.8.8.8:9100")},
+				},
+			},
+			false,
+			false,
+		},
+	} {
+		err = checkAddresses(&tc.node, tc.allowUnroutable)
+		if tc.valid {
+			require.NoError(err, "checkAddresses (%s)", tc.msg)
+		} else {
+			require.Error(err, "checkAddresses (%s)", tc.msg)
+		}
+	}
+}
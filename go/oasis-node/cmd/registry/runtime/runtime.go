@@ -2,12 +2,14 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -67,13 +69,18 @@ const (
 	CfgTxnSchedulerMaxBatchSize      = "runtime.txn_scheduler.batching.max_batch_size"
 	CfgTxnSchedulerMaxBatchSizeBytes = "runtime.txn_scheduler.batching.max_batch_size_bytes"
 
+	// Check update flags.
+	cfgCheckUpdateCurrent  = "runtime.check_update.current"
+	cfgCheckUpdateProposed = "runtime.check_update.proposed"
+
 	runtimeGenesisFilename = "runtime_genesis.json"
 )
 
 var (
-	outputFlags   = flag.NewFlagSet("", flag.ContinueOnError)
-	runtimeFlags  = flag.NewFlagSet("", flag.ContinueOnError)
-	registerFlags = flag.NewFlagSet("", flag.ContinueOnError)
+	outputFlags      = flag.NewFlagSet("", flag.ContinueOnError)
+	runtimeFlags     = flag.NewFlagSet("", flag.ContinueOnError)
+	registerFlags    = flag.NewFlagSet("", flag.ContinueOnError)
+	checkUpdateFlags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	runtimeCmd = &cobra.Command{
 		Use:   "runtime",
@@ -98,6 +105,12 @@ var (
 		Run:   doList,
 	}
 
+	checkUpdateCmd = &cobra.Command{
+		Use:   "check_update",
+		Short: "check whether a runtime descriptor update would be accepted",
+		Run:   doCheckUpdate,
+	}
+
 	logger = logging.GetLogger("cmd/registry/runtime")
 )
 
@@ -211,6 +224,133 @@ func doList(cmd *cobra.Command, args []string) {
 	}
 }
 
+func doCheckUpdate(cmd *cobra.Command, args []string) {
+	if err := cmdCommon.Init(); err != nil {
+		cmdCommon.EarlyLogAndExit(err)
+	}
+
+	current, err := loadSignedRuntime(viper.GetString(cfgCheckUpdateCurrent))
+	if err != nil {
+		logger.Error("failed to load current runtime descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+	proposed, err := loadSignedRuntime(viper.GetString(cfgCheckUpdateProposed))
+	if err != nil {
+		logger.Error("failed to load proposed runtime descriptor",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	check, err := checkRuntimeUpdate(current, proposed)
+	if err != nil {
+		logger.Error("failed to check runtime descriptor update",
+			"err", err,
+		)
+		os.Exit(1)
+	}
+
+	for _, field := range check.ChangedFields {
+		fmt.Printf("changed: %s\n", field)
+	}
+	if check.Err != nil {
+		fmt.Printf("update would be rejected: %s\n", check.Err)
+		os.Exit(1)
+	}
+	fmt.Printf("update would be accepted\n")
+}
+
+// runtimeUpdateCheck is the result of checking a runtime descriptor update.
+type runtimeUpdateCheck struct {
+	// ChangedFields are the names of the top-level descriptor fields that
+	// differ between the current and the proposed descriptor.
+	ChangedFields []string
+	// Err is the reason why the update would be rejected (nil if the
+	// update would be accepted).
+	Err error
+}
+
+// checkRuntimeUpdate checks whether the proposed runtime descriptor would be
+// accepted as an update of the current runtime descriptor, using the same
+// verification as the registry application.
+func checkRuntimeUpdate(current, proposed *registry.SignedRuntime) (*runtimeUpdateCheck, error) {
+	var currentRt, proposedRt registry.Runtime
+	if err := cbor.Unmarshal(current.Blob, &currentRt); err != nil {
+		return nil, fmt.Errorf("malformed current runtime descriptor: %w", err)
+	}
+	if err := cbor.Unmarshal(proposed.Blob, &proposedRt); err != nil {
+		return nil, fmt.Errorf("malformed proposed runtime descriptor: %w", err)
+	}
+
+	changed, err := changedRuntimeFields(&currentRt, &proposedRt)
+	if err != nil {
+		return nil, err
+	}
+	check := &runtimeUpdateCheck{ChangedFields: changed}
+
+	// Updates are always submitted via transactions.
+	if err = proposed.Open(registry.RegisterRuntimeSignatureContext, &proposedRt); err != nil {
+		check.Err = fmt.Errorf("%w: %s", registry.ErrInvalidSignature, err)
+		return check, nil
+	}
+	check.Err = registry.VerifyRuntimeUpdate(logger, current, proposed, &proposedRt)
+
+	return check, nil
+}
+
+func changedRuntimeFields(current, proposed *registry.Runtime) ([]string, error) {
+	toFields := func(rt *registry.Runtime) (map[string]json.RawMessage, error) {
+		raw, err := json.Marshal(rt)
+		if err != nil {
+			return nil, err
+		}
+		var fields map[string]json.RawMessage
+		if err = json.Unmarshal(raw, &fields); err != nil {
+			return nil, err
+		}
+		return fields, nil
+	}
+
+	currentFields, err := toFields(current)
+	if err != nil {
+		return nil, err
+	}
+	proposedFields, err := toFields(proposed)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for field, value := range currentFields {
+		if !bytes.Equal(value, proposedFields[field]) {
+			changed = append(changed, field)
+		}
+	}
+	for field := range proposedFields {
+		if _, ok := currentFields[field]; !ok {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+func loadSignedRuntime(filename string) (*registry.SignedRuntime, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var sigRt registry.SignedRuntime
+	if err = json.Unmarshal(b, &sigRt); err != nil {
+		return nil, err
+	}
+	return &sigRt, nil
+}
+
 func runtimeFromFlags() (*registry.Runtime, signature.Signer, error) {
 	var id common.Namespace
 	if err := id.UnmarshalHex(viper.GetString(CfgID)); err != nil {
@@ -415,6 +555,7 @@ func Register(parentCmd *cobra.Command) {
 		initGenesisCmd,
 		registerCmd,
 		listCmd,
+		checkUpdateCmd,
 	} {
 		runtimeCmd.AddCommand(v)
 	}
@@ -436,6 +577,8 @@ func Register(parentCmd *cobra.Command) {
 
 	registerCmd.Flags().AddFlagSet(runtimeFlags)
 
+	checkUpdateCmd.Flags().AddFlagSet(checkUpdateFlags)
+
 	parentCmd.AddCommand(runtimeCmd)
 }
 
@@ -479,4 +622,8 @@ func init() {
 
 	registerFlags.AddFlagSet(cmdFlags.DebugTestEntityFlags)
 	registerFlags.AddFlagSet(cmdConsensus.TxFlags)
+
+	checkUpdateFlags.String(cfgCheckUpdateCurrent, "", "Path to the current (on-chain) signed runtime descriptor")
+	checkUpdateFlags.String(cfgCheckUpdateProposed, "", "Path to the proposed signed runtime descriptor")
+	_ = viper.BindPFlags(checkUpdateFlags)
 }
@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests/helpers"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+)
+
+func TestCheckRuntimeUpdate(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	owner := memorySigner.NewTestSigner("runtime check update test: owner")
+	other := memorySigner.NewTestSigner("runtime check update test: other")
+
+	newRuntime := func() *registry.Runtime {
+		rt := &registry.Runtime{
+			ID:           common.NewTestNamespaceFromSeed([]byte("runtime check update test")),
+			Kind:         registry.KindCompute,
+			Executor:     registry.ExecutorParameters{GroupSize: 3},
+			Merge:        registry.MergeParameters{GroupSize: 3},
+			TxnScheduler: registry.TxnSchedulerParameters{GroupSize: 1},
+			Storage:      registry.StorageParameters{GroupSize: 1},
+		}
+		rt.Genesis.StateRoot.Empty()
+		return rt
+	}
+
+	current, err := registry.SignRuntime(owner, registry.RegisterGenesisRuntimeSignatureContext, newRuntime())
+	require.NoError(err, "SignRuntime")
+
+	// Changing committee sizes should be accepted.
+	rt := newRuntime()
+	rt.Executor.GroupSize = 2
+	rt.Merge.GroupSize = 2
+	proposed, err := registry.SignRuntime(owner, registry.RegisterRuntimeSignatureContext, rt)
+	require.NoError(err, "SignRuntime")
+	check, err := checkRuntimeUpdate(current, proposed)
+	require.NoError(err, "checkRuntimeUpdate")
+	require.NoError(check.Err, "update should be accepted")
+	require.Equal([]string{"executor", "merge"}, check.ChangedFields, "changed fields should be reported")
+
+	// Changing the runtime kind should be rejected.
+	rt = newRuntime()
+	rt.Kind = registry.KindKeyManager
+	proposed, err = registry.SignRuntime(owner, registry.RegisterRuntimeSignatureContext, rt)
+	require.NoError(err, "SignRuntime")
+	check, err = checkRuntimeUpdate(current, proposed)
+	require.NoError(err, "checkRuntimeUpdate")
+	require.True(errors.Is(check.Err, registry.ErrRuntimeUpdateNotAllowed), "kind change should be rejected")
+	require.Contains(check.Err.Error(), "kind changed", "rejection reason should be reported")
+	require.Equal([]string{"kind"}, check.ChangedFields, "changed fields should be reported")
+
+	// Changing the runtime owner should be rejected.
+	proposed, err = registry.SignRuntime(other, registry.RegisterRuntimeSignatureContext, newRuntime())
+	require.NoError(err, "SignRuntime")
+	check, err = checkRuntimeUpdate(current, proposed)
+	require.NoError(err, "checkRuntimeUpdate")
+	require.True(errors.Is(check.Err, registry.ErrRuntimeUpdateNotAllowed), "owner change should be rejected")
+	require.Contains(check.Err.Error(), "owner changed", "rejection reason should be reported")
+	require.Empty(check.ChangedFields, "no descriptor fields should be changed")
+
+	// Updates signed with a different signature context should be rejected.
+	proposed, err = registry.SignRuntime(owner, registry.RegisterEntitySignatureContext, newRuntime())
+	require.NoError(err, "SignRuntime")
+	check, err = checkRuntimeUpdate(current, proposed)
+	require.NoError(err, "checkRuntimeUpdate")
+	require.True(errors.Is(check.Err, registry.ErrInvalidSignature), "updates with an invalid signature should be rejected")
+}
@@ -15,11 +15,15 @@ import (
 
 	beaconTests "github.com/oasislabs/oasis-core/go/beacon/tests"
 	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
 	fileSigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/file"
 	"github.com/oasislabs/oasis-core/go/common/entity"
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
 	consensusAPI "github.com/oasislabs/oasis-core/go/consensus/api"
+	"github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	"github.com/oasislabs/oasis-core/go/consensus/tendermint"
 	consensusTests "github.com/oasislabs/oasis-core/go/consensus/tests"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	epochtimeTests "github.com/oasislabs/oasis-core/go/epochtime/tests"
@@ -64,8 +68,9 @@ var (
 	}{
 		{"log.level.default", "DEBUG"},
 		{cmdCommonFlags.CfgConsensusValidator, true},
+		{tendermint.CfgConsensusTxIndex, "kv"},
 		{cmdCommonFlags.CfgDebugDontBlameOasis, true},
-		{storage.CfgBackend, "badger"},
+		{storage.CfgBackend, "memory"},
 		{compute.CfgWorkerEnabled, true},
 		{workerCommon.CfgRuntimeBackend, "mock"},
 		{workerCommon.CfgRuntimeLoader, "mock-runtime"},
@@ -234,6 +239,8 @@ func TestNode(t *testing.T) {
 		{"DeregisterTestEntityRuntime", testDeregisterEntityRuntime},
 
 		{"Consensus", testConsensus},
+		{"ConsensusWaitForTransaction", testConsensusWaitForTransaction},
+		{"ConsensusSubmitTxNoWait", testConsensusSubmitTxNoWait},
 		{"ConsensusClient", testConsensusClient},
 		{"EpochTime", testEpochTime},
 		{"Beacon", testBeacon},
@@ -335,7 +342,167 @@ WaitLoop:
 }
 
 func testConsensus(t *testing.T, node *testNode) {
+	require := require.New(t)
+
+	// Register a key manager runtime so that runtime states include a
+	// runtime without blocks.
+	entities, err := registryTests.NewTestEntities([]byte("testConsensusEntity"), 1)
+	require.NoError(err, "NewTestEntities")
+	ent := entities[0]
+	err = ent.Register(node.Consensus)
+	require.NoError(err, "register entity")
+	km, err := registryTests.NewTestRuntime([]byte("testConsensusKM"), ent, true)
+	require.NoError(err, "NewTestRuntime")
+	km.Runtime.Kind = registry.KindKeyManager
+	km.MustRegister(t, node.Registry, node.Consensus)
+	err = ent.Deregister(node.Consensus)
+	require.NoError(err, "deregister entity")
+
 	consensusTests.ConsensusImplementationTests(t, node.Consensus)
+
+	rtStates, err := node.Consensus.GetRuntimeStates(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetRuntimeStates")
+	var found bool
+	for _, rtState := range rtStates {
+		if rtState.Runtime.ID.Equal(&km.Runtime.ID) {
+			require.Nil(rtState.CurrentBlock, "key manager runtime should not have a head block")
+			found = true
+		}
+	}
+	require.True(found, "runtime states should include the key manager runtime")
+}
+
+func testConsensusWaitForTransaction(t *testing.T, node *testNode) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Prepare a self-transfer transaction.
+	tx := staking.NewTransferTx(0, nil, &staking.Transfer{To: node.entitySigner.Public()})
+	nonce, err := node.Consensus.TransactionAuthHandler().GetSignerNonce(ctx, node.entitySigner.Public(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetSignerNonce")
+	tx.Nonce = nonce
+	gas, err := node.Consensus.EstimateGas(ctx, node.entitySigner.Public(), tx)
+	require.NoError(err, "EstimateGas")
+	tx.Fee = &transaction.Fee{Gas: gas}
+	sigTx, err := transaction.Sign(node.entitySigner, tx)
+	require.NoError(err, "Sign")
+
+	var txHash hash.Hash
+	txHash.FromBytes(cbor.Marshal(sigTx))
+
+	blockCh, blockSub, err := node.Consensus.WatchBlocks(ctx)
+	require.NoError(err, "WatchBlocks")
+	defer blockSub.Close()
+
+	// Start waiting for the transaction before it is submitted.
+	type waitResult struct {
+		result *consensusAPI.Result
+		err    error
+	}
+	resultCh := make(chan waitResult, 1)
+	go func() {
+		result, werr := node.Consensus.WaitForTransaction(ctx, txHash)
+		resultCh <- waitResult{result, werr}
+	}()
+
+	// Give the waiter some time to subscribe.
+	select {
+	case <-blockCh:
+	case <-ctx.Done():
+		t.Fatalf("failed to receive consensus block")
+	}
+
+	err = node.Consensus.SubmitTx(ctx, sigTx)
+	require.NoError(err, "SubmitTx")
+
+	select {
+	case res := <-resultCh:
+		require.NoError(res.err, "WaitForTransaction")
+		require.True(res.result.IsSuccess(), "transaction should succeed")
+		require.NoError(res.result.Error(), "transaction should succeed")
+		require.True(res.result.Height > 0, "transaction should be included in a block")
+
+		// Waiting for an already included transaction should return its
+		// result from the transaction index.
+		result, werr := node.Consensus.WaitForTransaction(ctx, txHash)
+		require.NoError(werr, "WaitForTransaction (already included)")
+		require.Equal(res.result, result, "already included transaction should have the same result")
+	case <-ctx.Done():
+		t.Fatalf("failed to wait for transaction")
+	}
+
+	// Waiting for an unknown transaction should respect context cancellation.
+	var unknownHash hash.Hash
+	unknownHash.FromBytes([]byte("unknown transaction"))
+	waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer waitCancel()
+	_, err = node.Consensus.WaitForTransaction(waitCtx, unknownHash)
+	require.Equal(context.DeadlineExceeded, err, "WaitForTransaction should fail on context cancellation")
+}
+
+func testConsensusSubmitTxNoWait(t *testing.T, node *testNode) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Prepare a self-transfer transaction.
+	tx := staking.NewTransferTx(0, nil, &staking.Transfer{To: node.entitySigner.Public()})
+	nonce, err := node.Consensus.TransactionAuthHandler().GetSignerNonce(ctx, node.entitySigner.Public(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetSignerNonce")
+	tx.Nonce = nonce
+	gas, err := node.Consensus.EstimateGas(ctx, node.entitySigner.Public(), tx)
+	require.NoError(err, "EstimateGas")
+	tx.Fee = &transaction.Fee{Gas: gas}
+	sigTx, err := transaction.Sign(node.entitySigner, tx)
+	require.NoError(err, "Sign")
+
+	var expectedHash hash.Hash
+	expectedHash.FromBytes(cbor.Marshal(sigTx))
+
+	blockCh, blockSub, err := node.Consensus.WatchBlocks(ctx)
+	require.NoError(err, "WatchBlocks")
+	defer blockSub.Close()
+
+	// Start waiting for the transaction before it is submitted.
+	type waitResult struct {
+		result *consensusAPI.Result
+		err    error
+	}
+	resultCh := make(chan waitResult, 1)
+	go func() {
+		result, werr := node.Consensus.WaitForTransaction(ctx, expectedHash)
+		resultCh <- waitResult{result, werr}
+	}()
+
+	// Give the waiter some time to subscribe.
+	select {
+	case <-blockCh:
+	case <-ctx.Done():
+		t.Fatalf("failed to receive consensus block")
+	}
+
+	start := time.Now()
+	txHash, err := node.Consensus.SubmitTxNoWait(ctx, sigTx)
+	require.NoError(err, "SubmitTxNoWait")
+	require.True(time.Since(start) < time.Second, "SubmitTxNoWait should return promptly")
+	require.Equal(expectedHash, txHash, "SubmitTxNoWait should return the transaction hash")
+
+	// The transaction should eventually be included in a block.
+	select {
+	case res := <-resultCh:
+		require.NoError(res.err, "WaitForTransaction")
+		require.True(res.result.IsSuccess(), "transaction should succeed")
+		require.True(res.result.Height > 0, "transaction should be included in a block")
+	case <-ctx.Done():
+		t.Fatalf("failed to wait for transaction")
+	}
+
+	// Submitting the same transaction again should fail the checks.
+	_, err = node.Consensus.SubmitTxNoWait(ctx, sigTx)
+	require.Error(err, "SubmitTxNoWait should fail for invalid transactions")
 }
 
 func testConsensusClient(t *testing.T, node *testNode) {
@@ -345,6 +512,31 @@ func testConsensusClient(t *testing.T, node *testNode) {
 
 	client := consensusAPI.NewConsensusClient(conn)
 	consensusTests.ConsensusImplementationTests(t, client)
+
+	// Consensus parameters obtained via the client should match the local ones.
+	params, err := client.GetConsensusParameters(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(t, err, "GetConsensusParameters")
+	localParams, err := node.Consensus.GetConsensusParameters(context.Background(), params.Height)
+	require.NoError(t, err, "GetConsensusParameters")
+	require.EqualValues(t, localParams, params, "consensus parameters should match")
+
+	// Local minimum gas prices obtained via the client should match as well.
+	minGasPrices, err := client.GetMinGasPrices(context.Background())
+	require.NoError(t, err, "GetMinGasPrices")
+	localMinGasPrices, err := node.Consensus.GetMinGasPrices(context.Background())
+	require.NoError(t, err, "GetMinGasPrices")
+	require.EqualValues(t, localMinGasPrices, minGasPrices, "minimum gas prices should match")
+
+	// Genesis document obtained via the client should match the one the
+	// local node booted from.
+	genesisDoc, err := client.GetGenesisDocument(context.Background())
+	require.NoError(t, err, "GetGenesisDocument")
+	localDigest, err := node.Consensus.GetGenesisDigest(context.Background())
+	require.NoError(t, err, "GetGenesisDigest")
+	require.Equal(t, localDigest, genesisDoc.Digest, "genesis digest should match")
+	localGenesisDoc, err := node.Genesis.GetGenesisDocument()
+	require.NoError(t, err, "Genesis.GetGenesisDocument")
+	require.Equal(t, localGenesisDoc.Hash(), genesisDoc.Document.Hash(), "genesis document should match")
 }
 
 func testEpochTime(t *testing.T, node *testNode) {
@@ -135,6 +135,9 @@ var (
 	MethodUnfreezeNode = transaction.NewMethodName(ModuleName, "UnfreezeNode", UnfreezeNode{})
 	// MethodRegisterRuntime is the method name for registering runtimes.
 	MethodRegisterRuntime = transaction.NewMethodName(ModuleName, "RegisterRuntime", SignedRuntime{})
+	// MethodRegisterEntityWithNodes is the method name for atomic entity and
+	// node registrations.
+	MethodRegisterEntityWithNodes = transaction.NewMethodName(ModuleName, "RegisterEntityWithNodes", EntityWithNodes{})
 
 	// Methods is the list of all methods supported by the registry backend.
 	Methods = []transaction.MethodName{
@@ -143,6 +146,7 @@ var (
 		MethodRegisterNode,
 		MethodUnfreezeNode,
 		MethodRegisterRuntime,
+		MethodRegisterEntityWithNodes,
 	}
 
 	// RuntimesRequiredRoles are the Node roles that require runtimes.
@@ -183,10 +187,30 @@ type Backend interface {
 	// GetNodes gets a list of all registered nodes.
 	GetNodes(context.Context, int64) ([]*node.Node, error)
 
+	// GetNodesWithStatus gets a list of all registered nodes together
+	// with their statuses.
+	GetNodesWithStatus(context.Context, int64) ([]*NodeWithStatus, error)
+
+	// GetEntityNodes gets a list of all registered nodes belonging to
+	// the given entity.
+	GetEntityNodes(context.Context, *IDQuery) ([]*node.Node, error)
+
 	// WatchNodes returns a channel that produces a stream of
 	// NodeEvent on node registration changes.
 	WatchNodes(context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error)
 
+	// WatchNodeRemovals returns a channel that produces a stream of
+	// NodeEvent when expired nodes are removed from the registry after
+	// the debonding interval.
+	//
+	// The nodes will have already been deregistered via WatchNodes.
+	WatchNodeRemovals(context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error)
+
+	// WatchNodesByRole returns a channel that produces a stream of
+	// NodeEvent on registration changes of nodes having any of the
+	// given roles.
+	WatchNodesByRole(context.Context, node.RolesMask) (<-chan *NodeEvent, pubsub.ClosableSubscription, error)
+
 	// WatchNodeList returns a channel that produces a stream of NodeList.
 	// Upon subscription, the node list for the current epoch will be sent
 	// immediately if available.
@@ -202,6 +226,14 @@ type Backend interface {
 	// block height.
 	GetRuntimes(context.Context, int64) ([]*Runtime, error)
 
+	// GetAllRuntimes returns all registered Runtimes, including the
+	// suspended ones, at the specified block height.
+	GetAllRuntimes(context.Context, int64) ([]*Runtime, error)
+
+	// IsRuntimeSuspended returns true iff the given runtime is currently
+	// suspended (e.g., due to unpaid maintenance fees).
+	IsRuntimeSuspended(context.Context, *NamespaceQuery) (bool, error)
+
 	// GetNodeList returns the NodeList at the specified block height.
 	GetNodeList(context.Context, int64) (*NodeList, error)
 
@@ -212,6 +244,10 @@ type Backend interface {
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(context.Context, int64) (*Genesis, error)
 
+	// ConsensusParameters returns the registry consensus parameters at
+	// the specified block height.
+	ConsensusParameters(context.Context, int64) (*ConsensusParameters, error)
+
 	// Cleanup cleans up the registry backend.
 	Cleanup()
 }
@@ -253,6 +289,25 @@ func NewRegisterRuntimeTx(nonce uint64, fee *transaction.Fee, sigRt *SignedRunti
 	return transaction.NewTransaction(nonce, fee, MethodRegisterRuntime, sigRt)
 }
 
+// NewRegisterEntityWithNodesTx creates a new atomic entity and node
+// registration transaction.
+func NewRegisterEntityWithNodesTx(nonce uint64, fee *transaction.Fee, ewn *EntityWithNodes) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodRegisterEntityWithNodes, ewn)
+}
+
+// EntityWithNodes is a request to atomically register an entity together
+// with a set of its nodes.
+//
+// Either the entity and all of the nodes are registered or none of them are.
+// The transaction must be signed by the entity.
+type EntityWithNodes struct {
+	// Entity is the signed entity registration.
+	Entity entity.SignedEntity `json:"entity"`
+
+	// Nodes are the signed node registrations.
+	Nodes []*node.SignedNode `json:"nodes"`
+}
+
 // EntityEvent is the event that is returned via WatchEntities to signify
 // entity registration changes and updates.
 type EntityEvent struct {
@@ -260,11 +315,42 @@ type EntityEvent struct {
 	IsRegistration bool
 }
 
+// NodeDeregistrationReason is the reason why a node has been deregistered.
+type NodeDeregistrationReason uint8
+
+const (
+	// NodeDeregistrationReasonNone is used for registration events.
+	NodeDeregistrationReasonNone NodeDeregistrationReason = 0
+	// NodeDeregistrationReasonExpired is used when a node has expired.
+	NodeDeregistrationReasonExpired NodeDeregistrationReason = 1
+	// NodeDeregistrationReasonRemoved is used when an expired node has
+	// been removed from the registry after the debonding interval.
+	NodeDeregistrationReasonRemoved NodeDeregistrationReason = 2
+)
+
+// String returns a string representation of the node deregistration reason.
+func (r NodeDeregistrationReason) String() string {
+	switch r {
+	case NodeDeregistrationReasonNone:
+		return "none"
+	case NodeDeregistrationReasonExpired:
+		return "expired"
+	case NodeDeregistrationReasonRemoved:
+		return "removed"
+	default:
+		return "[unknown]"
+	}
+}
+
 // NodeEvent is the event that is returned via WatchNodes to signify node
-// registration changes and updates.
+// registration changes and updates, and via WatchNodeRemovals to signify
+// node removals.
 type NodeEvent struct {
 	Node           *node.Node
 	IsRegistration bool
+	// Reason is the reason for a deregistration (only set for
+	// deregistration events).
+	Reason NodeDeregistrationReason
 }
 
 // NodeList is a per-epoch immutable node list.
@@ -735,32 +821,21 @@ func verifyAddresses(params *ConsensusParameters, addressRequired bool, addresse
 	return nil
 }
 
-// sortRuntimeList sorts the given runtime list to ensure a canonical order.
-func sortRuntimeList(runtimes []*node.Runtime) {
-	sort.Slice(runtimes, func(i, j int) bool {
-		return bytes.Compare(runtimes[i].ID[:], runtimes[j].ID[:]) == -1
-	})
-}
-
 // verifyNodeRuntimeChanges verifies node runtime changes.
+//
+// Runtimes may be added or removed, but the capabilities of runtimes that
+// remain registered must not change.
 func verifyNodeRuntimeChanges(logger *logging.Logger, currentRuntimes []*node.Runtime, newRuntimes []*node.Runtime) bool {
-	sortRuntimeList(currentRuntimes)
-	sortRuntimeList(newRuntimes)
-	if len(currentRuntimes) != len(newRuntimes) {
-		logger.Error("RegisterNode: trying to update runtimes, length mismatch",
-			"current_runtimes", currentRuntimes,
-			"new_runtimes", newRuntimes,
-		)
-		return false
+	currentRuntimeMap := make(map[common.Namespace]*node.Runtime)
+	for _, currentRuntime := range currentRuntimes {
+		currentRuntimeMap[currentRuntime.ID] = currentRuntime
 	}
-	for i, currentRuntime := range currentRuntimes {
-		newRuntime := newRuntimes[i]
-		if !currentRuntime.ID.Equal(&newRuntime.ID) {
-			logger.Error("RegisterNode: trying to update runtimes, runtime ID changed",
-				"current_runtime", currentRuntime,
-				"new_runtime", newRuntime,
-			)
-			return false
+
+	for _, newRuntime := range newRuntimes {
+		currentRuntime, ok := currentRuntimeMap[newRuntime.ID]
+		if !ok {
+			// Runtime was added.
+			continue
 		}
 		if !verifyRuntimeCapabilities(logger, &currentRuntime.Capabilities, &newRuntime.Capabilities) {
 			curRtJSON, _ := json.Marshal(currentRuntime)
@@ -1002,7 +1077,7 @@ func VerifyRuntimeUpdate(logger *logging.Logger, currentSigRt, newSigRt *SignedR
 			"current_owner", currentSigRt.Signature.PublicKey,
 			"new_owner", newSigRt.Signature.PublicKey,
 		)
-		return ErrRuntimeUpdateNotAllowed
+		return fmt.Errorf("%w: runtime owner changed", ErrRuntimeUpdateNotAllowed)
 	}
 
 	var currentRt Runtime
@@ -1018,25 +1093,25 @@ func VerifyRuntimeUpdate(logger *logging.Logger, currentSigRt, newSigRt *SignedR
 			"current_id", currentRt.ID.String(),
 			"new_id", newRt.ID.String(),
 		)
-		return ErrRuntimeUpdateNotAllowed
+		return fmt.Errorf("%w: runtime ID changed", ErrRuntimeUpdateNotAllowed)
 	}
 	if currentRt.Kind != newRt.Kind {
 		logger.Error("RegisterRuntime: trying to update runtime kind",
 			"current_kind", currentRt.Kind,
 			"new_kind", newRt.Kind,
 		)
-		return ErrRuntimeUpdateNotAllowed
+		return fmt.Errorf("%w: runtime kind changed", ErrRuntimeUpdateNotAllowed)
 	}
 	if !currentRt.Genesis.Equal(&newRt.Genesis) {
 		logger.Error("RegisterRuntime: trying to update genesis")
-		return ErrRuntimeUpdateNotAllowed
+		return fmt.Errorf("%w: runtime genesis changed", ErrRuntimeUpdateNotAllowed)
 	}
 	if (currentRt.KeyManager == nil) != (newRt.KeyManager == nil) {
 		logger.Error("RegisterRuntime: trying to change key manager",
 			"current_km", currentRt.KeyManager,
 			"new_km", newRt.KeyManager,
 		)
-		return ErrRuntimeUpdateNotAllowed
+		return fmt.Errorf("%w: key manager changed", ErrRuntimeUpdateNotAllowed)
 	}
 	// Both descriptors must either have the key manager set or not.
 	if currentRt.KeyManager != nil && !currentRt.KeyManager.Equal(newRt.KeyManager) {
@@ -1044,7 +1119,7 @@ func VerifyRuntimeUpdate(logger *logging.Logger, currentSigRt, newSigRt *SignedR
 			"current_km", currentRt.KeyManager,
 			"new_km", newRt.KeyManager,
 		)
-		return ErrRuntimeUpdateNotAllowed
+		return fmt.Errorf("%w: key manager changed", ErrRuntimeUpdateNotAllowed)
 	}
 	return nil
 }
@@ -1212,7 +1287,9 @@ func SanityCheckRuntimes(runtimes []*SignedRuntime) (map[common.Namespace]*Runti
 			}
 
 			// Check runtime's Transaction scheduler committee parameters.
-			if rt.TxnScheduler.Algorithm != TxnSchedulerAlgorithmBatching {
+			switch rt.TxnScheduler.Algorithm {
+			case TxnSchedulerAlgorithmBatching, TxnSchedulerAlgorithmWeighted:
+			default:
 				return nil, fmt.Errorf("registry: sanity check failed: invalid txn scheduler algorithm")
 			}
 
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
+)
+
+func TestVerifyNodeUpdateRuntimes(t *testing.T) {
+	require := require.New(t)
+
+	logger := logging.GetLogger("registry/api/tests")
+	rt1 := common.NewTestNamespaceFromSeed([]byte("verify node update runtime 1"))
+	rt2 := common.NewTestNamespaceFromSeed([]byte("verify node update runtime 2"))
+
+	currentNode := &node.Node{
+		Roles:    node.RoleComputeWorker,
+		Runtimes: []*node.Runtime{{ID: rt1}},
+	}
+
+	newNode := *currentNode
+	newNode.Runtimes = []*node.Runtime{{ID: rt1}, {ID: rt2}}
+	require.NoError(VerifyNodeUpdate(logger, currentNode, &newNode), "adding a runtime should be allowed")
+
+	newNode.Runtimes = []*node.Runtime{{ID: rt2}}
+	require.NoError(VerifyNodeUpdate(logger, currentNode, &newNode), "removing a runtime should be allowed")
+
+	newNode.Runtimes = []*node.Runtime{
+		{
+			ID: rt1,
+			Capabilities: node.Capabilities{
+				TEE: &node.CapabilityTEE{Hardware: node.TEEHardwareIntelSGX},
+			},
+		},
+	}
+	require.Equal(ErrNodeUpdateNotAllowed, VerifyNodeUpdate(logger, currentNode, &newNode), "changing runtime capabilities should not be allowed")
+}
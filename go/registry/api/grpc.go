@@ -25,19 +25,33 @@ var (
 	methodGetNodeStatus = serviceName.NewMethodName("GetNodeStatus")
 	// methodGetNodes is the name of the GetNodes method.
 	methodGetNodes = serviceName.NewMethodName("GetNodes")
+	// methodGetNodesWithStatus is the name of the GetNodesWithStatus method.
+	methodGetNodesWithStatus = serviceName.NewMethodName("GetNodesWithStatus")
+	// methodGetEntityNodes is the name of the GetEntityNodes method.
+	methodGetEntityNodes = serviceName.NewMethodName("GetEntityNodes")
 	// methodGetRuntime is the name of the GetRuntime method.
 	methodGetRuntime = serviceName.NewMethodName("GetRuntime")
 	// methodGetRuntimes is the name of the GetRuntimes method.
 	methodGetRuntimes = serviceName.NewMethodName("GetRuntimes")
+	// methodGetAllRuntimes is the name of the GetAllRuntimes method.
+	methodGetAllRuntimes = serviceName.NewMethodName("GetAllRuntimes")
+	// methodIsRuntimeSuspended is the name of the IsRuntimeSuspended method.
+	methodIsRuntimeSuspended = serviceName.NewMethodName("IsRuntimeSuspended")
 	// methodGetNodeList is the name of the GetNodeList method.
 	methodGetNodeList = serviceName.NewMethodName("GetNodeList")
 	// methodStateToGenesis is the name of the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethodName("StateToGenesis")
+	// methodConsensusParameters is the name of the ConsensusParameters method.
+	methodConsensusParameters = serviceName.NewMethodName("ConsensusParameters")
 
 	// methodWatchEntities is the name of the WatchEntities method.
 	methodWatchEntities = serviceName.NewMethodName("WatchEntities")
 	// methodWatchNodes is the name of the WatchNodes method.
 	methodWatchNodes = serviceName.NewMethodName("WatchNodes")
+	// methodWatchNodesByRole is the name of the WatchNodesByRole method.
+	methodWatchNodesByRole = serviceName.NewMethodName("WatchNodesByRole")
+	// methodWatchNodeRemovals is the name of the WatchNodeRemovals method.
+	methodWatchNodeRemovals = serviceName.NewMethodName("WatchNodeRemovals")
 	// methodWatchNodeList is the name of the WatchNodeList method.
 	methodWatchNodeList = serviceName.NewMethodName("WatchNodeList")
 	// methodWatchRuntimes is the name of the WatchRuntimes method.
@@ -68,6 +82,14 @@ var (
 				MethodName: methodGetNodes.Short(),
 				Handler:    handlerGetNodes,
 			},
+			{
+				MethodName: methodGetNodesWithStatus.Short(),
+				Handler:    handlerGetNodesWithStatus,
+			},
+			{
+				MethodName: methodGetEntityNodes.Short(),
+				Handler:    handlerGetEntityNodes,
+			},
 			{
 				MethodName: methodGetRuntime.Short(),
 				Handler:    handlerGetRuntime,
@@ -76,6 +98,14 @@ var (
 				MethodName: methodGetRuntimes.Short(),
 				Handler:    handlerGetRuntimes,
 			},
+			{
+				MethodName: methodGetAllRuntimes.Short(),
+				Handler:    handlerGetAllRuntimes,
+			},
+			{
+				MethodName: methodIsRuntimeSuspended.Short(),
+				Handler:    handlerIsRuntimeSuspended,
+			},
 			{
 				MethodName: methodGetNodeList.Short(),
 				Handler:    handlerGetNodeList,
@@ -84,6 +114,10 @@ var (
 				MethodName: methodStateToGenesis.Short(),
 				Handler:    handlerStateToGenesis,
 			},
+			{
+				MethodName: methodConsensusParameters.Short(),
+				Handler:    handlerConsensusParameters,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -106,6 +140,16 @@ var (
 				Handler:       handlerWatchRuntimes,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodWatchNodesByRole.Short(),
+				Handler:       handlerWatchNodesByRole,
+				ServerStreams: true,
+			},
+			{
+				StreamName:    methodWatchNodeRemovals.Short(),
+				Handler:       handlerWatchNodeRemovals,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -225,6 +269,52 @@ func handlerGetNodes( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerGetNodesWithStatus( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetNodesWithStatus(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetNodesWithStatus.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetNodesWithStatus(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
+func handlerGetEntityNodes( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query IDQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetEntityNodes(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetEntityNodes.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetEntityNodes(ctx, req.(*IDQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerGetRuntime( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -248,6 +338,52 @@ func handlerGetRuntime( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerIsRuntimeSuspended( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query NamespaceQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).IsRuntimeSuspended(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodIsRuntimeSuspended.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).IsRuntimeSuspended(ctx, req.(*NamespaceQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
+func handlerGetAllRuntimes( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).GetAllRuntimes(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodGetAllRuntimes.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).GetAllRuntimes(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerGetRuntimes( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -317,6 +453,29 @@ func handlerStateToGenesis( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerConsensusParameters( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).ConsensusParameters(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodConsensusParameters.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).ConsensusParameters(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerWatchEntities(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -373,6 +532,63 @@ func handlerWatchNodes(srv interface{}, stream grpc.ServerStream) error {
 	}
 }
 
+func handlerWatchNodeRemovals(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(nil); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchNodeRemovals(ctx)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func handlerWatchNodesByRole(srv interface{}, stream grpc.ServerStream) error {
+	var roles node.RolesMask
+	if err := stream.RecvMsg(&roles); err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	ch, sub, err := srv.(Backend).WatchNodesByRole(ctx, roles)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.SendMsg(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func handlerWatchNodeList(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -513,6 +729,22 @@ func (c *registryClient) GetNodes(ctx context.Context, height int64) ([]*node.No
 	return rsp, nil
 }
 
+func (c *registryClient) GetNodesWithStatus(ctx context.Context, height int64) ([]*NodeWithStatus, error) {
+	var rsp []*NodeWithStatus
+	if err := c.conn.Invoke(ctx, methodGetNodesWithStatus.Full(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (c *registryClient) GetEntityNodes(ctx context.Context, query *IDQuery) ([]*node.Node, error) {
+	var rsp []*node.Node
+	if err := c.conn.Invoke(ctx, methodGetEntityNodes.Full(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *registryClient) WatchNodes(ctx context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -548,6 +780,76 @@ func (c *registryClient) WatchNodes(ctx context.Context) (<-chan *NodeEvent, pub
 	return ch, sub, nil
 }
 
+func (c *registryClient) WatchNodeRemovals(ctx context.Context) (<-chan *NodeEvent, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[5], methodWatchNodeRemovals.Full())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(nil); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *NodeEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev NodeEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
+func (c *registryClient) WatchNodesByRole(ctx context.Context, roles node.RolesMask) (<-chan *NodeEvent, pubsub.ClosableSubscription, error) {
+	ctx, sub := pubsub.NewContextSubscription(ctx)
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[4], methodWatchNodesByRole.Full())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = stream.SendMsg(roles); err != nil {
+		return nil, nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan *NodeEvent)
+	go func() {
+		defer close(ch)
+
+		for {
+			var ev NodeEvent
+			if serr := stream.RecvMsg(&ev); serr != nil {
+				return
+			}
+
+			select {
+			case ch <- &ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, sub, nil
+}
+
 func (c *registryClient) WatchNodeList(ctx context.Context) (<-chan *NodeList, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
@@ -591,6 +893,14 @@ func (c *registryClient) GetRuntime(ctx context.Context, query *NamespaceQuery)
 	return &rsp, nil
 }
 
+func (c *registryClient) GetAllRuntimes(ctx context.Context, height int64) ([]*Runtime, error) {
+	var rsp []*Runtime
+	if err := c.conn.Invoke(ctx, methodGetAllRuntimes.Full(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *registryClient) GetRuntimes(ctx context.Context, height int64) ([]*Runtime, error) {
 	var rsp []*Runtime
 	if err := c.conn.Invoke(ctx, methodGetRuntimes.Full(), height, &rsp); err != nil {
@@ -599,6 +909,14 @@ func (c *registryClient) GetRuntimes(ctx context.Context, height int64) ([]*Runt
 	return rsp, nil
 }
 
+func (c *registryClient) IsRuntimeSuspended(ctx context.Context, query *NamespaceQuery) (bool, error) {
+	var rsp bool
+	if err := c.conn.Invoke(ctx, methodIsRuntimeSuspended.Full(), query, &rsp); err != nil {
+		return false, err
+	}
+	return rsp, nil
+}
+
 func (c *registryClient) GetNodeList(ctx context.Context, height int64) (*NodeList, error) {
 	var rsp NodeList
 	if err := c.conn.Invoke(ctx, methodGetNodeList.Full(), height, &rsp); err != nil {
@@ -650,6 +968,14 @@ func (c *registryClient) StateToGenesis(ctx context.Context, height int64) (*Gen
 	return &rsp, nil
 }
 
+func (c *registryClient) ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error) {
+	var rsp ConsensusParameters
+	if err := c.conn.Invoke(ctx, methodConsensusParameters.Full(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *registryClient) Cleanup() {
 }
 
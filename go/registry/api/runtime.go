@@ -44,6 +44,8 @@ const (
 
 	// TxnSchedulerAlgorithmBatching is the name of the batching algorithm.
 	TxnSchedulerAlgorithmBatching = "batching"
+	// TxnSchedulerAlgorithmWeighted is the name of the weighted batching algorithm.
+	TxnSchedulerAlgorithmWeighted = "weighted"
 )
 
 // String returns a string representation of a runtime kind.
@@ -110,16 +112,16 @@ type TxnSchedulerParameters struct {
 	// Algorithm is the transaction scheduling algorithm.
 	Algorithm string `json:"algorithm"`
 
-	// BatchFlushTimeout denotes, if using the "batching" algorithm, how long to
-	// wait for a scheduled batch.
+	// BatchFlushTimeout denotes, if using the "batching" or "weighted"
+	// algorithm, how long to wait for a scheduled batch.
 	BatchFlushTimeout time.Duration `json:"batch_flush_timeout"`
 
-	// MaxBatchSize denotes, if using the "batching" algorithm, what is the max
-	// size of a batch.
+	// MaxBatchSize denotes, if using the "batching" or "weighted" algorithm,
+	// what is the max size of a batch.
 	MaxBatchSize uint64 `json:"max_batch_size"`
 
-	// MaxBatchSizeBytes denotes, if using the "batching" algorithm, what is the
-	// max size of a batch in bytes.
+	// MaxBatchSizeBytes denotes, if using the "batching" or "weighted"
+	// algorithm, what is the max size of a batch in bytes.
 	MaxBatchSizeBytes uint64 `json:"max_batch_size_bytes"`
 }
 
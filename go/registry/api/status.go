@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/node"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 )
 
@@ -35,6 +36,54 @@ func (ns *NodeStatus) Unfreeze() {
 	ns.FreezeEndTime = 0
 }
 
+// NodeWithStatus is a node descriptor together with the node's status.
+type NodeWithStatus struct {
+	// Node is the node descriptor.
+	Node *node.Node `json:"node"`
+	// Status is the node's status.
+	Status *NodeStatus `json:"status"`
+}
+
+// FreezeReason is the reason why a node was frozen.
+type FreezeReason uint8
+
+const (
+	// FreezeReasonDoubleSigning is the freeze reason used when a node
+	// was frozen due to double signing.
+	FreezeReasonDoubleSigning FreezeReason = 1
+	// FreezeReasonUnavailability is the freeze reason used when a node
+	// was frozen due to being unavailable.
+	FreezeReasonUnavailability FreezeReason = 2
+	// FreezeReasonInvalidCommit is the freeze reason used when a node
+	// was frozen due to submitting an invalid commitment.
+	FreezeReasonInvalidCommit FreezeReason = 3
+)
+
+// String returns a string representation of a FreezeReason.
+func (r FreezeReason) String() string {
+	switch r {
+	case FreezeReasonDoubleSigning:
+		return "double-signing"
+	case FreezeReasonUnavailability:
+		return "unavailability"
+	case FreezeReasonInvalidCommit:
+		return "invalid-commit"
+	default:
+		return "[unknown freeze reason]"
+	}
+}
+
+// NodeFrozenEvent is the event emitted when a node is frozen due to
+// misbehavior.
+type NodeFrozenEvent struct {
+	// NodeID is the identifier of the frozen node.
+	NodeID signature.PublicKey `json:"node_id"`
+	// FreezeEndTime is the epoch when the node can become unfrozen.
+	FreezeEndTime epochtime.EpochTime `json:"freeze_end_time"`
+	// Reason is the reason why the node was frozen.
+	Reason FreezeReason `json:"reason"`
+}
+
 // UnfreezeNode is a request to unfreeze a frozen node.
 type UnfreezeNode struct {
 	NodeID signature.PublicKey `json:"node_id"`
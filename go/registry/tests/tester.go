@@ -134,6 +134,14 @@ func testRegistryEntityNodes( // nolint: gocyclo
 	require.NoError(t, err, "WatchNodes")
 	defer nodeSub.Close()
 
+	nodeRemovalCh, nodeRemovalSub, err := backend.WatchNodeRemovals(context.Background())
+	require.NoError(t, err, "WatchNodeRemovals")
+	defer nodeRemovalSub.Close()
+
+	storageNodeCh, storageNodeSub, err := backend.WatchNodesByRole(context.Background(), node.RoleStorageWorker)
+	require.NoError(t, err, "WatchNodesByRole")
+	defer storageNodeSub.Close()
+
 	t.Run("NodeRegistration", func(t *testing.T) {
 		require := require.New(t)
 
@@ -201,6 +209,12 @@ func testRegistryEntityNodes( // nolint: gocyclo
 				require.NoError(err, "GetNode")
 				require.EqualValues(v.Node, nod, "retrieved node")
 
+				var nodStatus *api.NodeStatus
+				nodStatus, err = backend.GetNodeStatus(context.Background(), &api.IDQuery{ID: v.Node.ID, Height: consensusAPI.HeightLatest})
+				require.NoError(err, "GetNodeStatus")
+				require.False(nodStatus.ExpirationProcessed, "ExpirationProcessed should be false")
+				require.False(nodStatus.IsFrozen(), "IsFrozen() should return false")
+
 				err = v.Register(consensus, v.SignedInvalidRegistration11)
 				require.Error(err, "register node with duplicate p2p id")
 				require.Equal(err, api.ErrInvalidArgument)
@@ -217,7 +231,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 				require.NoError(err, "Re-registering a node with different address should work")
 
 				err = v.Register(consensus, v.SignedInvalidReRegistration)
-				require.Error(err, "Re-registering a node with different runtimes should fail")
+				require.Error(err, "Re-registering a node with an unknown runtime should fail")
 				require.Equal(err, api.ErrInvalidArgument)
 
 				select {
@@ -231,6 +245,35 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		}
 	})
 
+	t.Run("WatchNodesByRole", func(t *testing.T) {
+		require := require.New(t)
+
+		// Only events for storage nodes should be delivered, in order.
+		for _, vec := range nodes {
+			for _, v := range vec {
+				if !v.Node.HasRoles(node.RoleStorageWorker) {
+					continue
+				}
+
+				for _, expected := range []*node.Node{v.Node, v.UpdatedNode} {
+					select {
+					case ev := <-storageNodeCh:
+						require.EqualValues(expected, ev.Node, "storage node event")
+						require.True(ev.IsRegistration, "event is registration")
+					case <-time.After(recvTimeout):
+						t.Fatalf("failed to receive storage node registration event")
+					}
+				}
+			}
+		}
+
+		select {
+		case ev := <-storageNodeCh:
+			t.Fatalf("received unexpected node event: %+v", ev)
+		case <-time.After(time.Second):
+		}
+	})
+
 	getExpectedNodeList := func() []*node.Node {
 		// Derive the expected node list.
 		l := make([]*node.Node, 0, numNodes)
@@ -253,6 +296,37 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		registeredNodes, nerr := backend.GetNodes(context.Background(), consensusAPI.HeightLatest)
 		require.NoError(nerr, "GetNodes")
 		require.EqualValues(expectedNodeList, registeredNodes, "node list")
+
+		nodesWithStatus, nerr := backend.GetNodesWithStatus(context.Background(), consensusAPI.HeightLatest)
+		require.NoError(nerr, "GetNodesWithStatus")
+		require.Len(nodesWithStatus, len(registeredNodes), "node list with statuses")
+		for i, n := range nodesWithStatus {
+			require.EqualValues(registeredNodes[i], n.Node, "node list with statuses should match node list")
+
+			nodeStatus, serr := backend.GetNodeStatus(context.Background(), &api.IDQuery{ID: n.Node.ID, Height: consensusAPI.HeightLatest})
+			require.NoError(serr, "GetNodeStatus")
+			require.EqualValues(nodeStatus, n.Status, "node list statuses should match GetNodeStatus")
+		}
+	})
+
+	t.Run("EntityNodes", func(t *testing.T) {
+		require := require.New(t)
+
+		for i, entity := range entities {
+			var expectedNodes []*node.Node
+			for _, v := range nodes[i] {
+				expectedNodes = append(expectedNodes, v.UpdatedNode)
+			}
+			api.SortNodeList(expectedNodes)
+
+			entityNodes, nerr := backend.GetEntityNodes(context.Background(), &api.IDQuery{ID: entity.Entity.ID, Height: consensusAPI.HeightLatest})
+			require.NoError(nerr, "GetEntityNodes")
+			api.SortNodeList(entityNodes)
+			require.EqualValues(expectedNodes, entityNodes, "entity node list")
+			for _, n := range entityNodes {
+				require.Equal(entity.Entity.ID, n.EntityID, "node should belong to entity")
+			}
+		}
 	})
 
 	t.Run("NodeUnfreeze", func(t *testing.T) {
@@ -288,6 +362,10 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		require.Error(err, "UnfreezeNode (with invalid node)")
 		require.Equal(err, api.ErrNoSuchNode)
 
+		_, err = backend.GetNodeStatus(context.Background(), &api.IDQuery{ID: unfreeze.NodeID, Height: consensusAPI.HeightLatest})
+		require.Error(err, "GetNodeStatus (with invalid node)")
+		require.Equal(err, api.ErrNoSuchNode)
+
 		// Try to unfreeze a node using the node signing key (should fail
 		// as unfreeze must be signed by entity signing key).
 		tx = api.NewUnfreezeNodeTx(0, nil, &api.UnfreezeNode{
@@ -298,6 +376,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		require.Equal(err, api.ErrBadEntityForNode)
 	})
 
+	var expiredNodes []*TestNode
 	t.Run("NodeExpiration", func(t *testing.T) {
 		require := require.New(t)
 
@@ -312,6 +391,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 			select {
 			case ev := <-nodeCh:
 				require.False(ev.IsRegistration, "event is deregistration")
+				require.Equal(api.NodeDeregistrationReasonExpired, ev.Reason, "deregistration reason")
 				deregisteredNodes[ev.Node.ID] = ev.Node
 			case <-time.After(recvTimeout):
 				t.Fatalf("failed to receive node deregistration event")
@@ -327,6 +407,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 
 		// Remove the expired nodes from the test driver's view of
 		// registered nodes.
+		expiredNodes = nodes[0]
 		expiredNode := nodes[0][0]
 		nodes = nodes[1:]
 		numNodes -= expectedDeregEvents
@@ -359,6 +440,24 @@ func testRegistryEntityNodes( // nolint: gocyclo
 		// Advance the epoch to trigger 0th entity nodes to be removed.
 		_ = epochtimeTests.MustAdvanceEpoch(t, timeSource, 1)
 
+		// Removal should be reported separately from the deregistration and
+		// with a different reason than expiration.
+		removedNodes := make(map[signature.PublicKey]*node.Node)
+		for i := 0; i < len(expiredNodes); i++ {
+			select {
+			case ev := <-nodeRemovalCh:
+				require.False(ev.IsRegistration, "event is deregistration")
+				require.Equal(api.NodeDeregistrationReasonRemoved, ev.Reason, "removal reason")
+				removedNodes[ev.Node.ID] = ev.Node
+			case <-time.After(recvTimeout):
+				t.Fatalf("failed to receive node removal event")
+			}
+		}
+		for _, v := range expiredNodes {
+			_, ok := removedNodes[v.Node.ID]
+			require.True(ok, "got removal event for node")
+		}
+
 		// At this point it should only be possible to deregister 0th entity nodes.
 		err := entities[0].Deregister(consensus)
 		require.NoError(err, "DeregisterEntity - 0th entity")
@@ -411,6 +510,7 @@ func testRegistryEntityNodes( // nolint: gocyclo
 			select {
 			case ev := <-nodeCh:
 				require.False(ev.IsRegistration, "event is deregistration")
+				require.Equal(api.NodeDeregistrationReasonExpired, ev.Reason, "deregistration reason")
 				deregisteredNodes[ev.Node.ID] = ev.Node
 			case <-time.After(recvTimeout):
 				t.Fatalf("failed to receive node deregistration event")
@@ -481,6 +581,13 @@ func testRegistryRuntime(t *testing.T, backend api.Backend, consensus consensusA
 	}
 	require.Len(rtMap, 0, "all runtimes were registered")
 
+	// Newly registered runtimes should not be suspended.
+	suspended, err := backend.IsRuntimeSuspended(context.Background(), &api.NamespaceQuery{ID: rt.Runtime.ID, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "IsRuntimeSuspended")
+	require.False(suspended, "newly registered runtime should not be suspended")
+	_, err = backend.IsRuntimeSuspended(context.Background(), &api.NamespaceQuery{ID: common.Namespace{0xab}, Height: consensusAPI.HeightLatest})
+	require.Error(err, "IsRuntimeSuspended should fail for unknown runtimes")
+
 	// Test runtime registration failures.
 	// Non-existent key manager.
 	rtWrongKm, err := NewTestRuntime([]byte("testRegistryRuntimeWithWrongKM"), entity, false)
@@ -802,7 +909,7 @@ func (ent *TestEntity) NewTestNodes(nCompute int, nStorage int, runtimes []*node
 			return nil, err
 		}
 
-		// Add invalid Re-Registration with changed Runtimes field.
+		// Add invalid Re-Registration with an unknown runtime added.
 		testRuntimeSigner := memorySigner.NewTestSigner("invalid-registration-runtime-seed")
 		newRuntimes := append([]*node.Runtime(nil), runtimes...)
 		newRuntimes = append(newRuntimes, &node.Runtime{ID: publicKeyToNamespace(testRuntimeSigner.Public(), false)})
@@ -1044,6 +1151,7 @@ func (rt *TestRuntime) Cleanup(t *testing.T, backend api.Backend, consensus cons
 		select {
 		case ev := <-nodeCh:
 			require.False(ev.IsRegistration, "event is deregistration")
+			require.Equal(api.NodeDeregistrationReasonExpired, ev.Reason, "deregistration reason")
 			numDereg++
 		case <-time.After(recvTimeout):
 			t.Fatalf("failed to receive node deregistration event")
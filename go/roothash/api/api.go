@@ -74,6 +74,11 @@ type Backend interface {
 	// the latest state from the storage backend.
 	GetLatestBlock(ctx context.Context, runtimeID common.Namespace, height int64) (*block.Block, error)
 
+	// GetLatestBlockWithProof returns the latest block together with a
+	// proof that it is the latest block committed in the consensus state
+	// at the given height.
+	GetLatestBlockWithProof(ctx context.Context, runtimeID common.Namespace, height int64) (*BlockWithProof, error)
+
 	// WatchBlocks returns a channel that produces a stream of
 	// annotated blocks.
 	//
@@ -91,6 +96,10 @@ type Backend interface {
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
+	// ConsensusParameters returns the roothash consensus parameters at
+	// the specified block height.
+	ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error)
+
 	// Cleanup cleans up the roothash backend.
 	Cleanup()
 }
@@ -133,6 +142,26 @@ type AnnotatedBlock struct {
 	Block *block.Block `json:"block"`
 }
 
+// BlockWithProof is a roothash block together with a proof that it is the
+// latest block committed in the consensus state.
+type BlockWithProof struct {
+	// Height is the consensus height at which the block was queried. The
+	// proof is against the consensus state root (application hash) after
+	// processing the consensus block at this height.
+	Height int64 `json:"consensus_height"`
+
+	// Block is the latest roothash block.
+	Block *block.Block `json:"block"`
+
+	// RuntimeState is the serialized consensus-layer runtime state which
+	// contains the block.
+	RuntimeState []byte `json:"runtime_state"`
+
+	// Proof is the serialized proof of inclusion of RuntimeState in the
+	// consensus state.
+	Proof []byte `json:"proof"`
+}
+
 // ExecutionDiscrepancyDetectedEvent is an execute discrepancy detected event.
 type ExecutionDiscrepancyDetectedEvent struct {
 	// CommitteeID is the identifier of the executor committee where a
@@ -130,6 +130,11 @@ func testGenesisBlock(t *testing.T, backend api.Backend, state *runtimeState) {
 	require.NoError(err, "GetLatestBlock")
 	require.EqualValues(genesisBlock, blk, "retreived block is genesis block")
 
+	bp, err := backend.GetLatestBlockWithProof(context.Background(), id, consensusAPI.HeightLatest)
+	require.NoError(err, "GetLatestBlockWithProof")
+	require.EqualValues(genesisBlock, bp.Block, "retrieved block with proof is genesis block")
+	require.NotEmpty(bp.Proof, "retrieved block with proof should include a proof")
+
 	// We need to wait for the indexer to index the block. We could have a channel
 	// to subscribe to these updates and this would not be needed.
 	time.Sleep(1 * time.Second)
@@ -446,6 +446,14 @@ func (c *runtimeClient) CallEnclave(ctx context.Context, request *enclaverpc.Cal
 	}
 }
 
+// Implements enclaverpc.Transport.
+func (c *runtimeClient) Call(ctx context.Context, method string, request *enclaverpc.CallEnclaveRequest) ([]byte, error) {
+	if method != enclaverpc.MethodCallEnclave {
+		return nil, fmt.Errorf("unsupported EnclaveRPC method: %s", method)
+	}
+	return c.CallEnclave(ctx, request)
+}
+
 // Cleanup stops all running block watchers and waits for them to finish.
 func (c *runtimeClient) Cleanup() {
 	// Watchers.
@@ -7,10 +7,17 @@ import (
 	"github.com/oasislabs/oasis-core/go/common"
 )
 
+// MethodCallEnclave is the name of the default EnclaveRPC method.
+const MethodCallEnclave = "CallEnclave"
+
 // Transport is the EnclaveRPC transport interface.
 type Transport interface {
 	// CallEnclave sends the request bytes to the target enclave.
 	CallEnclave(ctx context.Context, request *CallEnclaveRequest) ([]byte, error)
+
+	// Call sends the request bytes to the target enclave using the given
+	// EnclaveRPC method.
+	Call(ctx context.Context, method string, request *CallEnclaveRequest) ([]byte, error)
 }
 
 // CallEnclaveRequest is a CallEnclave request.
@@ -20,3 +27,14 @@ type CallEnclaveRequest struct {
 
 	Payload []byte `json:"payload"`
 }
+
+// Method is an EnclaveRPC method definition.
+type Method struct {
+	// Name is the (short) method name.
+	Name string
+
+	// AccessControl is an optional function invoked with the runtime ID of
+	// each request before it is dispatched to the transport. If it returns
+	// an error, the request is rejected.
+	AccessControl func(ctx context.Context, method string, runtimeID common.Namespace) error
+}
@@ -2,59 +2,108 @@ package api
 
 import (
 	"context"
+	"fmt"
 
 	"google.golang.org/grpc"
 
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
 )
 
-var (
-	// serviceName is the gRPC service name.
-	serviceName = cmnGrpc.NewServiceName("EnclaveRPC")
-
-	// methodCallEnclave is the name of the CallEnclave method.
-	methodCallEnclave = serviceName.NewMethodName("CallEnclave")
-
-	// serviceDesc is the gRPC service descriptor.
-	serviceDesc = grpc.ServiceDesc{
-		ServiceName: string(serviceName),
-		HandlerType: (*Transport)(nil),
-		Methods: []grpc.MethodDesc{
-			{
-				MethodName: methodCallEnclave.Short(),
-				Handler:    handlerCallEnclave,
-			},
-		},
-		Streams: []grpc.StreamDesc{},
+// serviceName is the gRPC service name.
+var serviceName = cmnGrpc.NewServiceName("EnclaveRPC")
+
+// Service is an EnclaveRPC gRPC service descriptor.
+type Service struct {
+	desc grpc.ServiceDesc
+}
+
+// Register registers the EnclaveRPC transport service with the given gRPC server.
+func (s *Service) Register(server *grpc.Server, service Transport) {
+	server.RegisterService(&s.desc, service)
+}
+
+func newMethodHandler(method Method) grpc.MethodDesc {
+	methodName := serviceName.NewMethodName(method.Name)
+
+	call := func(ctx context.Context, srv Transport, req *CallEnclaveRequest) ([]byte, error) {
+		if method.AccessControl != nil {
+			if err := method.AccessControl(ctx, method.Name, req.RuntimeID); err != nil {
+				return nil, err
+			}
+		}
+		if method.Name == MethodCallEnclave {
+			return srv.CallEnclave(ctx, req)
+		}
+		return srv.Call(ctx, method.Name, req)
 	}
-)
 
-func handlerCallEnclave( // nolint: golint
-	srv interface{},
-	ctx context.Context,
-	dec func(interface{}) error,
-	interceptor grpc.UnaryServerInterceptor,
-) (interface{}, error) {
-	var req CallEnclaveRequest
-	if err := dec(&req); err != nil {
-		return nil, err
+	return grpc.MethodDesc{
+		MethodName: methodName.Short(),
+		Handler: func( // nolint: golint
+			srv interface{},
+			ctx context.Context,
+			dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			var req CallEnclaveRequest
+			if err := dec(&req); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(ctx, srv.(Transport), &req)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: methodName.Full(),
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(ctx, srv.(Transport), req.(*CallEnclaveRequest))
+			}
+			return interceptor(ctx, &req, info, handler)
+		},
 	}
-	if interceptor == nil {
-		return srv.(Transport).CallEnclave(ctx, &req)
+}
+
+// New creates a new EnclaveRPC service descriptor supporting the given methods.
+//
+// The default CallEnclave method is always supported, even when it is not
+// explicitly included in the list of methods.
+func New(methods ...Method) *Service {
+	s := &Service{
+		desc: grpc.ServiceDesc{
+			ServiceName: string(serviceName),
+			HandlerType: (*Transport)(nil),
+			Streams:     []grpc.StreamDesc{},
+		},
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: methodCallEnclave.Full(),
+
+	defs := []Method{{Name: MethodCallEnclave}}
+	for _, m := range methods {
+		if m.Name == MethodCallEnclave {
+			// Allow the default method definition to be overridden (e.g., to
+			// configure access control).
+			defs[0] = m
+			continue
+		}
+		for _, d := range defs {
+			if d.Name == m.Name {
+				panic(fmt.Sprintf("enclaverpc: duplicate method: %s", m.Name))
+			}
+		}
+		defs = append(defs, m)
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(Transport).CallEnclave(ctx, req.(*CallEnclaveRequest))
+	for _, m := range defs {
+		s.desc.Methods = append(s.desc.Methods, newMethodHandler(m))
 	}
-	return interceptor(ctx, &req, info, handler)
+
+	return s
 }
 
 // RegisterService registers a new EnclaveRPC transport service with the given gRPC server.
+//
+// The registered service only supports the default CallEnclave method.
 func RegisterService(server *grpc.Server, service Transport) {
-	server.RegisterService(&serviceDesc, service)
+	New().Register(server, service)
 }
 
 type transportClient struct {
@@ -62,8 +111,12 @@ type transportClient struct {
 }
 
 func (c *transportClient) CallEnclave(ctx context.Context, request *CallEnclaveRequest) ([]byte, error) {
+	return c.Call(ctx, MethodCallEnclave, request)
+}
+
+func (c *transportClient) Call(ctx context.Context, method string, request *CallEnclaveRequest) ([]byte, error) {
 	var rsp []byte
-	if err := c.conn.Invoke(ctx, methodCallEnclave.Full(), request, &rsp); err != nil {
+	if err := c.conn.Invoke(ctx, fmt.Sprintf("/%s/%s", serviceName, method), request, &rsp); err != nil {
 		return nil, err
 	}
 	return rsp, nil
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+)
+
+const methodTest = "TestMethod"
+
+var errAccessDenied = errors.New("access denied")
+
+type testTransport struct{}
+
+func (t *testTransport) CallEnclave(ctx context.Context, request *CallEnclaveRequest) ([]byte, error) {
+	return t.Call(ctx, MethodCallEnclave, request)
+}
+
+func (t *testTransport) Call(ctx context.Context, method string, request *CallEnclaveRequest) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s:%s", method, request.Payload)), nil
+}
+
+func TestMultipleMethods(t *testing.T) {
+	require := require.New(t)
+
+	// Generate temporary filename for the socket.
+	f, err := ioutil.TempFile("", "oasis-enclaverpc-test-socket")
+	require.NoError(err, "TempFile")
+	// Remove the file as we only need the name.
+	f.Close()
+	os.Remove(f.Name())
+
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{Path: f.Name()})
+	require.NoError(err, "NewServer")
+	defer os.Remove(f.Name())
+
+	var deniedID common.Namespace
+	deniedID[0] = 0xff
+
+	New(
+		Method{Name: methodTest},
+		Method{
+			Name: "Restricted",
+			AccessControl: func(ctx context.Context, method string, runtimeID common.Namespace) error {
+				if runtimeID.Equal(&deniedID) {
+					return errAccessDenied
+				}
+				return nil
+			},
+		},
+	).Register(grpcServer.Server(), &testTransport{})
+
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	defer grpcServer.Stop()
+
+	conn, err := cmnGrpc.Dial("unix:"+f.Name(), grpc.WithInsecure())
+	require.NoError(err, "Dial")
+	defer conn.Close()
+	client := NewTransportClient(conn)
+
+	ctx := context.Background()
+	req := &CallEnclaveRequest{Payload: []byte("hello")}
+
+	rsp, err := client.CallEnclave(ctx, req)
+	require.NoError(err, "CallEnclave")
+	require.EqualValues("CallEnclave:hello", rsp, "CallEnclave should be dispatched")
+
+	rsp, err = client.Call(ctx, methodTest, req)
+	require.NoError(err, "Call")
+	require.EqualValues("TestMethod:hello", rsp, "Call should dispatch to the named method")
+
+	rsp, err = client.Call(ctx, "Restricted", req)
+	require.NoError(err, "Call")
+	require.EqualValues("Restricted:hello", rsp, "Call should dispatch to the named method")
+
+	_, err = client.Call(ctx, "Restricted", &CallEnclaveRequest{RuntimeID: deniedID})
+	require.Error(err, "Call should fail when rejected by access control")
+
+	_, err = client.Call(ctx, "Unknown", req)
+	require.Error(err, "Call should fail for unknown methods")
+}
+
+func TestDuplicateMethods(t *testing.T) {
+	require.Panics(t, func() {
+		New(Method{Name: methodTest}, Method{Name: methodTest})
+	}, "duplicate methods should panic")
+	require.NotPanics(t, func() {
+		New(Method{Name: MethodCallEnclave})
+	}, "overriding the default method should be allowed")
+}
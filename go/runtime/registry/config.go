@@ -65,6 +65,8 @@ func newConfig() (*RuntimeConfig, error) {
 		cfg.TagIndexer = tagindexer.NewNopBackend()
 	case tagindexer.BleveBackendName:
 		cfg.TagIndexer = tagindexer.NewBleveBackend()
+	case tagindexer.BadgerBackendName:
+		cfg.TagIndexer = tagindexer.NewBadgerBackend()
 	default:
 		return nil, fmt.Errorf("runtime/registry: unknown tag indexer backend: %s", tagIndexer)
 	}
@@ -4,10 +4,12 @@ import (
 	"context"
 
 	"github.com/oasislabs/oasis-core/go/common"
+	roothash "github.com/oasislabs/oasis-core/go/roothash/api"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
 	"github.com/oasislabs/oasis-core/go/storage/api"
 )
 
-var _ api.Backend = (*storageRouter)(nil)
+var _ api.DiffRangeBackend = (*storageRouter)(nil)
 
 type storageRouter struct {
 	registry Registry
@@ -89,6 +91,88 @@ func (sr *storageRouter) GetCheckpoint(ctx context.Context, request *api.GetChec
 	return rt.Storage().GetCheckpoint(ctx, request)
 }
 
+func (sr *storageRouter) GetDiffRange(ctx context.Context, request *api.GetDiffRangeRequest, fn func(*api.DiffRangeChunk) error) error {
+	rt, err := sr.getRuntime(request.Namespace)
+	if err != nil {
+		return err
+	}
+	return getDiffRange(ctx, rt.Storage(), rt.History(), request, fn)
+}
+
+// getDiffRange serves a GetDiffRange request from the given storage backend
+// by walking the finalized runtime blocks in the given block history.
+func getDiffRange(
+	ctx context.Context,
+	storage api.Backend,
+	history roothash.BlockHistory,
+	request *api.GetDiffRangeRequest,
+	fn func(*api.DiffRangeChunk) error,
+) error {
+	if request.EndRound < request.StartRound {
+		return api.ErrInvalidRoundRange
+	}
+	if request.EndRound-request.StartRound > api.MaxDiffRangeRounds {
+		return api.ErrRoundRangeTooLarge
+	}
+
+	prevBlk, err := history.GetBlock(ctx, request.StartRound)
+	if err != nil {
+		return err
+	}
+	for round := request.StartRound + 1; round <= request.EndRound; round++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		var blk *block.Block
+		if blk, err = history.GetBlock(ctx, round); err != nil {
+			return err
+		}
+
+		// I/O roots are not chained, so the I/O diff always starts from the
+		// empty root.
+		ioStartRoot := api.Root{
+			Namespace: blk.Header.Namespace,
+			Round:     round,
+		}
+		ioStartRoot.Hash.Empty()
+		for _, diff := range []struct {
+			startRoot, endRoot api.Root
+		}{
+			{
+				startRoot: ioStartRoot,
+				endRoot:   api.Root{Namespace: blk.Header.Namespace, Round: round, Hash: blk.Header.IORoot},
+			},
+			{
+				startRoot: api.Root{Namespace: prevBlk.Header.Namespace, Round: prevBlk.Header.Round, Hash: prevBlk.Header.StateRoot},
+				endRoot:   api.Root{Namespace: blk.Header.Namespace, Round: round, Hash: blk.Header.StateRoot},
+			},
+		} {
+			// Roots can be carried over unchanged (e.g., during epoch transitions)
+			// in which case there is nothing to fetch.
+			if diff.startRoot.Hash.Equal(&diff.endRoot.Hash) {
+				if err = fn(&api.DiffRangeChunk{StartRoot: diff.startRoot, EndRoot: diff.endRoot, Final: true}); err != nil {
+					return err
+				}
+				continue
+			}
+
+			var it api.WriteLogIterator
+			it, err = storage.GetDiff(ctx, &api.GetDiffRequest{StartRoot: diff.startRoot, EndRoot: diff.endRoot})
+			if err != nil {
+				return err
+			}
+			if err = api.DiffRangeChunks(it, diff.startRoot, diff.endRoot, fn); err != nil {
+				return err
+			}
+		}
+
+		prevBlk = blk
+	}
+
+	return nil
+}
+
 func (sr *storageRouter) Cleanup() {
 }
 
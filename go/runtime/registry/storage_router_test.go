@@ -0,0 +1,288 @@
+package registry
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests/helpers"
+	"github.com/oasislabs/oasis-core/go/roothash/api/block"
+	"github.com/oasislabs/oasis-core/go/runtime/history"
+	"github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/database"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel"
+)
+
+type testHistory struct {
+	history.History
+
+	blocks map[uint64]*block.Block
+}
+
+func (h *testHistory) GetBlock(ctx context.Context, round uint64) (*block.Block, error) {
+	blk, ok := h.blocks[round]
+	if !ok {
+		return nil, fmt.Errorf("block for round %d not found", round)
+	}
+	return blk, nil
+}
+
+type testRuntime struct {
+	Runtime
+
+	storage api.Backend
+	history history.History
+}
+
+func (rt *testRuntime) Storage() api.Backend {
+	return rt.storage
+}
+
+func (rt *testRuntime) History() history.History {
+	return rt.history
+}
+
+type testRegistry struct {
+	Registry
+
+	runtimes map[common.Namespace]Runtime
+}
+
+func (r *testRegistry) GetRuntime(runtimeID common.Namespace) (Runtime, error) {
+	rt, ok := r.runtimes[runtimeID]
+	if !ok {
+		return nil, fmt.Errorf("runtime %s not found", runtimeID)
+	}
+	return rt, nil
+}
+
+// populateStorage applies a state and an I/O write log for each of the given
+// rounds and returns the resulting block history.
+func populateStorage(t *testing.T, backend api.Backend, ns common.Namespace, rounds []api.WriteLog) *testHistory {
+	require := require.New(t)
+
+	ctx := context.Background()
+	h := &testHistory{blocks: make(map[uint64]*block.Block)}
+
+	genesis := block.NewGenesisBlock(ns, 0)
+	h.blocks[0] = genesis
+
+	stateTree := urkel.New(nil, nil)
+	defer stateTree.Close()
+	prevStateRoot := genesis.Header.StateRoot
+	for i, stateWriteLog := range rounds {
+		round := uint64(i + 1)
+		blk := block.NewEmptyBlock(h.blocks[round-1], 0, block.Normal)
+
+		// State roots are chained.
+		for _, entry := range stateWriteLog {
+			err := stateTree.Insert(ctx, entry.Key, entry.Value)
+			require.NoError(err, "Insert")
+		}
+		_, blk.Header.StateRoot, _ = stateTree.Commit(ctx, ns, round)
+		_, err := backend.Apply(ctx, &api.ApplyRequest{
+			Namespace: ns,
+			SrcRound:  round - 1,
+			SrcRoot:   prevStateRoot,
+			DstRound:  round,
+			DstRoot:   blk.Header.StateRoot,
+			WriteLog:  stateWriteLog,
+		})
+		require.NoError(err, "Apply(state)")
+		prevStateRoot = blk.Header.StateRoot
+
+		// I/O roots always start from the empty root.
+		ioWriteLog := api.WriteLog{{Key: []byte("output"), Value: []byte(fmt.Sprintf("round %d", round))}}
+		ioTree := urkel.New(nil, nil)
+		err = ioTree.Insert(ctx, ioWriteLog[0].Key, ioWriteLog[0].Value)
+		require.NoError(err, "Insert")
+		_, blk.Header.IORoot, _ = ioTree.Commit(ctx, ns, round)
+		ioTree.Close()
+		var emptyRoot api.Root
+		emptyRoot.Hash.Empty()
+		_, err = backend.Apply(ctx, &api.ApplyRequest{
+			Namespace: ns,
+			SrcRound:  round,
+			SrcRoot:   emptyRoot.Hash,
+			DstRound:  round,
+			DstRoot:   blk.Header.IORoot,
+			WriteLog:  ioWriteLog,
+		})
+		require.NoError(err, "Apply(io)")
+
+		h.blocks[round] = blk
+	}
+
+	return h
+}
+
+func TestStorageRouterGetDiffRange(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	ctx := context.Background()
+	ns := common.NewTestNamespaceFromSeed([]byte("storage router diff range test ns"))
+
+	signer, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+	backend, err := database.New(&api.Config{
+		Backend:           database.BackendNameMemory,
+		Signer:            signer,
+		ApplyLockLRUSlots: 100,
+		Namespace:         ns,
+	})
+	require.NoError(err, "database.New")
+	defer backend.Cleanup()
+
+	var rounds []api.WriteLog
+	for round := 1; round <= 5; round++ {
+		var wl api.WriteLog
+		// Leave the state of round 3 unchanged.
+		if round != 3 {
+			for i := 0; i < 2*api.WriteLogIteratorChunkSize; i++ {
+				wl = append(wl, api.LogEntry{
+					Key:   []byte(fmt.Sprintf("key %d", i)),
+					Value: []byte(fmt.Sprintf("value %d at round %d", i, round)),
+				})
+			}
+		}
+		rounds = append(rounds, wl)
+	}
+	h := populateStorage(t, backend, ns, rounds)
+
+	// Serve the storage router over a local socket.
+	dir, err := ioutil.TempDir("", "oasis-storage-router-test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "storage.sock")
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "storage",
+		Path: socketPath,
+	})
+	require.NoError(err, "NewServer")
+	api.RegisterService(grpcServer.Server(), &storageRouter{
+		registry: &testRegistry{
+			runtimes: map[common.Namespace]Runtime{
+				ns: &testRuntime{storage: backend, history: h},
+			},
+		},
+	})
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	defer grpcServer.Stop()
+
+	conn, err := cmnGrpc.Dial("unix:"+socketPath, grpc.WithInsecure())
+	require.NoError(err, "Dial")
+	defer conn.Close()
+	client := api.NewStorageClient(conn).(api.DiffRangeBackend)
+
+	// Collect the multi-round diff.
+	type diff struct {
+		startRoot, endRoot api.Root
+		writeLog           api.WriteLog
+	}
+	var (
+		diffs []*diff
+		cur   *diff
+	)
+	err = client.GetDiffRange(ctx, &api.GetDiffRangeRequest{Namespace: ns, StartRound: 1, EndRound: 5}, func(chunk *api.DiffRangeChunk) error {
+		if cur == nil {
+			cur = &diff{startRoot: chunk.StartRoot, endRoot: chunk.EndRoot, writeLog: api.WriteLog{}}
+			diffs = append(diffs, cur)
+		}
+		require.Equal(cur.startRoot, chunk.StartRoot, "chunk start root should match its diff")
+		require.Equal(cur.endRoot, chunk.EndRoot, "chunk end root should match its diff")
+		require.True(len(chunk.WriteLog) <= api.WriteLogIteratorChunkSize, "chunks should be bounded")
+		cur.writeLog = append(cur.writeLog, chunk.WriteLog...)
+		if chunk.Final {
+			cur = nil
+		}
+		return nil
+	})
+	require.NoError(err, "GetDiffRange")
+	require.Nil(cur, "the last diff should be complete")
+
+	// Compare with repeated single-round diffs.
+	require.Len(diffs, 2*4, "there should be an I/O and a state diff for each round")
+	for i, round := 0, uint64(2); round <= 5; i, round = i+2, round+1 {
+		prev, blk := h.blocks[round-1], h.blocks[round]
+
+		ioStartRoot := api.Root{Namespace: ns, Round: round}
+		ioStartRoot.Hash.Empty()
+		for j, expected := range []diff{
+			{
+				startRoot: ioStartRoot,
+				endRoot:   api.Root{Namespace: ns, Round: round, Hash: blk.Header.IORoot},
+			},
+			{
+				startRoot: api.Root{Namespace: ns, Round: round - 1, Hash: prev.Header.StateRoot},
+				endRoot:   api.Root{Namespace: ns, Round: round, Hash: blk.Header.StateRoot},
+			},
+		} {
+			d := diffs[i+j]
+			require.Equal(expected.startRoot, d.startRoot, "diff start root should match (round %d)", round)
+			require.Equal(expected.endRoot, d.endRoot, "diff end root should match (round %d)", round)
+
+			expected.writeLog = api.WriteLog{}
+			if !expected.startRoot.Hash.Equal(&expected.endRoot.Hash) {
+				it, err := client.GetDiff(ctx, &api.GetDiffRequest{StartRoot: expected.startRoot, EndRoot: expected.endRoot})
+				require.NoError(err, "GetDiff")
+				for {
+					more, err := it.Next()
+					require.NoError(err, "Next")
+					if !more {
+						break
+					}
+					entry, err := it.Value()
+					require.NoError(err, "Value")
+					expected.writeLog = append(expected.writeLog, entry)
+				}
+			}
+			require.Equal(expected.writeLog, d.writeLog, "diff should match single-round diff (round %d)", round)
+		}
+	}
+	require.Empty(diffs[3].writeLog, "unchanged state should result in an empty diff")
+
+	// Aborting stops the stream.
+	var chunks int
+	abortErr := fmt.Errorf("abort")
+	err = client.GetDiffRange(ctx, &api.GetDiffRangeRequest{Namespace: ns, StartRound: 0, EndRound: 5}, func(chunk *api.DiffRangeChunk) error {
+		chunks++
+		return abortErr
+	})
+	require.Equal(abortErr, err, "GetDiffRange should return the callback error")
+	require.Equal(1, chunks, "GetDiffRange should stop after the callback fails")
+
+	// Canceled requests fail.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = getDiffRange(cancelCtx, backend, h, &api.GetDiffRangeRequest{Namespace: ns, StartRound: 0, EndRound: 5}, func(chunk *api.DiffRangeChunk) error {
+		return nil
+	})
+	require.Equal(context.Canceled, err, "GetDiffRange should fail when canceled")
+
+	// Invalid ranges are rejected.
+	err = client.GetDiffRange(ctx, &api.GetDiffRangeRequest{Namespace: ns, StartRound: 5, EndRound: 4}, func(chunk *api.DiffRangeChunk) error {
+		return nil
+	})
+	require.Error(err, "GetDiffRange should reject invalid ranges")
+
+	// Too large ranges are rejected.
+	err = getDiffRange(ctx, backend, h, &api.GetDiffRangeRequest{Namespace: ns, StartRound: 0, EndRound: api.MaxDiffRangeRounds + 1}, func(chunk *api.DiffRangeChunk) error {
+		t.Fatalf("no chunks should be produced")
+		return nil
+	})
+	require.Equal(api.ErrRoundRangeTooLarge, err, "GetDiffRange should reject too large ranges")
+}
@@ -2,6 +2,7 @@ package tagindexer
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -193,3 +194,161 @@ func testBackend(t *testing.T, factory BackendFactory) {
 func TestBleveBackend(t *testing.T) {
 	testBackend(t, NewBleveBackend())
 }
+
+func TestBadgerBackend(t *testing.T) {
+	testBackend(t, NewBadgerBackend())
+}
+
+func TestBackendConformance(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	var id common.Namespace
+
+	factories := map[string]BackendFactory{
+		BleveBackendName:  NewBleveBackend(),
+		BadgerBackendName: NewBadgerBackend(),
+	}
+	backends := make(map[string]Backend)
+	for name, factory := range factories {
+		dataDir, err := ioutil.TempDir("", "oasis-client-indexer-conformance-test_")
+		require.NoError(err, "TempDir")
+		defer os.RemoveAll(dataDir)
+
+		backend, err := factory(dataDir, id)
+		require.NoError(err, "New")
+		defer backend.Close()
+
+		backends[name] = backend
+	}
+
+	// Index the same blocks into all backends. Each block contains some
+	// transactions without tags, which must not be indexed.
+	blockHashes := make(map[uint64]hash.Hash)
+	for round := uint64(1); round <= 10; round++ {
+		var blockHash hash.Hash
+		blockHash.FromBytes([]byte(fmt.Sprintf("conformance block %d", round)))
+		blockHashes[round] = blockHash
+
+		var (
+			txs  []*transaction.Transaction
+			tags transaction.Tags
+		)
+		for i := 0; i < 5; i++ {
+			raw := []byte(fmt.Sprintf("conformance tx %d/%d", round, i))
+			tx := &transaction.Transaction{Input: raw, Output: raw}
+			txs = append(txs, tx)
+			if i == 4 {
+				continue
+			}
+
+			txHash := tx.Hash()
+			tags = append(tags,
+				transaction.Tag{Key: []byte("round"), Value: []byte(fmt.Sprintf("%d", round)), TxHash: txHash},
+				transaction.Tag{Key: []byte("index"), Value: []byte(fmt.Sprintf("%d", i)), TxHash: txHash},
+				transaction.Tag{Key: []byte("parity"), Value: []byte(fmt.Sprintf("%d", (round+uint64(i))%2)), TxHash: txHash},
+			)
+		}
+
+		for name, backend := range backends {
+			err := backend.Index(ctx, round, blockHash, txs, tags)
+			require.NoError(err, "Index (%s)", name)
+		}
+	}
+
+	// Prune a round from all backends.
+	for name, backend := range backends {
+		err := backend.Prune(ctx, 5)
+		require.NoError(err, "Prune (%s)", name)
+	}
+
+	queries := []api.Query{
+		// No conditions.
+		api.Query{},
+		api.Query{RoundMin: 3, RoundMax: 7},
+		// Single condition.
+		api.Query{Conditions: []api.QueryCondition{
+			api.QueryCondition{Key: []byte("index"), Values: [][]byte{[]byte("1")}},
+		}},
+		// Single condition, multiple values.
+		api.Query{RoundMin: 2, Conditions: []api.QueryCondition{
+			api.QueryCondition{Key: []byte("index"), Values: [][]byte{[]byte("1"), []byte("3"), []byte("4")}},
+		}},
+		// Multiple conditions.
+		api.Query{RoundMax: 8, Conditions: []api.QueryCondition{
+			api.QueryCondition{Key: []byte("parity"), Values: [][]byte{[]byte("0")}},
+			api.QueryCondition{Key: []byte("index"), Values: [][]byte{[]byte("0"), []byte("1"), []byte("2")}},
+		}},
+		// Conditions without values are ignored.
+		api.Query{RoundMin: 4, RoundMax: 6, Conditions: []api.QueryCondition{
+			api.QueryCondition{Key: []byte("parity"), Values: [][]byte{[]byte("1")}},
+			api.QueryCondition{Key: []byte("index")},
+		}},
+		// Pruned rounds.
+		api.Query{Conditions: []api.QueryCondition{
+			api.QueryCondition{Key: []byte("round"), Values: [][]byte{[]byte("5")}},
+		}},
+		// Limit.
+		api.Query{RoundMin: 3, Limit: 6},
+		api.Query{Limit: 2, Conditions: []api.QueryCondition{
+			api.QueryCondition{Key: []byte("parity"), Values: [][]byte{[]byte("1")}},
+		}},
+		// No matches.
+		api.Query{Conditions: []api.QueryCondition{
+			api.QueryCondition{Key: []byte("round"), Values: [][]byte{[]byte("1")}},
+			api.QueryCondition{Key: []byte("round"), Values: [][]byte{[]byte("2")}},
+		}},
+	}
+
+	for idx, query := range queries {
+		var expected Results
+		for _, name := range []string{BleveBackendName, BadgerBackendName} {
+			results, err := backends[name].QueryTxns(ctx, query)
+			require.NoError(err, "QueryTxns (%s, query %d)", name, idx)
+			if query.Limit > 0 {
+				var count int
+				for _, txs := range results {
+					count += len(txs)
+				}
+				require.EqualValues(query.Limit, count, "number of results should match the limit (%s, query %d)", name, idx)
+			}
+			if expected == nil {
+				expected = results
+				continue
+			}
+
+			require.Len(results, len(expected), "number of rounds should match (%s, query %d)", name, idx)
+			for round, txs := range expected {
+				require.ElementsMatch(txs, results[round], "results should match (%s, query %d, round %d)", name, idx, round)
+			}
+		}
+	}
+
+	for round, blockHash := range blockHashes {
+		for _, name := range []string{BleveBackendName, BadgerBackendName} {
+			backend := backends[name]
+
+			r, err := backend.QueryBlock(ctx, blockHash)
+			if round == 5 {
+				require.Equal(api.ErrNotFound, err, "QueryBlock should fail for pruned rounds (%s)", name)
+				_, err = backend.QueryTxnByIndex(ctx, round, 0)
+				require.Equal(api.ErrNotFound, err, "QueryTxnByIndex should fail for pruned rounds (%s)", name)
+				continue
+			}
+			require.NoError(err, "QueryBlock (%s)", name)
+			require.EqualValues(round, r, "QueryBlock (%s)", name)
+
+			for i := 0; i < 4; i++ {
+				tr, txHash, ti, err := backend.QueryTxn(ctx, []byte("index"), []byte(fmt.Sprintf("%d", i)))
+				require.NoError(err, "QueryTxn (%s)", name)
+				require.EqualValues(i, ti, "QueryTxn (%s)", name)
+
+				byIndex, err := backend.QueryTxnByIndex(ctx, tr, ti)
+				require.NoError(err, "QueryTxnByIndex (%s)", name)
+				require.Equal(txHash, byIndex, "QueryTxn and QueryTxnByIndex should agree (%s)", name)
+			}
+			_, err = backend.QueryTxnByIndex(ctx, round, 4)
+			require.Equal(api.ErrNotFound, err, "transactions without tags should not be indexed (%s)", name)
+		}
+	}
+}
@@ -0,0 +1,513 @@
+package tagindexer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/options"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	cmnBadger "github.com/oasislabs/oasis-core/go/common/badger"
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/keyformat"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	"github.com/oasislabs/oasis-core/go/runtime/client/api"
+	"github.com/oasislabs/oasis-core/go/runtime/transaction"
+)
+
+const (
+	// BadgerBackendName is the name of the badger backend.
+	BadgerBackendName = "badger"
+
+	badgerIndexFile = "tag-index.badger.db"
+)
+
+var (
+	// badgerBlockKeyFmt is the key format used for mapping block hashes
+	// to rounds.
+	//
+	// Value is the CBOR-serialized round.
+	badgerBlockKeyFmt = keyformat.New(0x01, &hash.Hash{})
+	// badgerRoundKeyFmt is the key format used for indexed rounds.
+	//
+	// Value is the CBOR-serialized block hash.
+	badgerRoundKeyFmt = keyformat.New(0x02, uint64(0))
+	// badgerTxKeyFmt is the key format used for indexed transactions,
+	// keyed by round and transaction index.
+	//
+	// Value is CBOR-serialized badgerTx.
+	badgerTxKeyFmt = keyformat.New(0x03, uint64(0), uint32(0))
+	// badgerTagKeyFmt is the key format used for the inverted tag index,
+	// keyed by tag hash, round, transaction index and transaction hash.
+	//
+	// Value is empty.
+	badgerTagKeyFmt = keyformat.New(0x04, &hash.Hash{}, uint64(0), uint32(0), &hash.Hash{})
+
+	_ Backend = (*badgerBackend)(nil)
+)
+
+// badgerTx is an indexed transaction.
+type badgerTx struct {
+	// TxHash is the transaction hash.
+	TxHash hash.Hash `json:"tx_hash"`
+	// Tags are the hashes of all of the transaction's tags.
+	Tags []hash.Hash `json:"tags"`
+}
+
+// badgerTxRef is a reference to an indexed transaction.
+type badgerTxRef struct {
+	round   uint64
+	txIndex uint32
+}
+
+// tagHash returns the hash used to key a tag in the inverted index.
+func tagHash(key, value []byte) hash.Hash {
+	var h hash.Hash
+	h.From([][]byte{key, value})
+	return h
+}
+
+type badgerBackend struct {
+	logger *logging.Logger
+
+	db *badger.DB
+	gc *cmnBadger.GCWorker
+
+	blockIndexedNotifier *pubsub.Broker
+}
+
+func (b *badgerBackend) Index(
+	ctx context.Context,
+	round uint64,
+	blockHash hash.Hash,
+	txs []*transaction.Transaction,
+	tags transaction.Tags,
+) error {
+	// The only reason why a list of transactions needs to be passed is to
+	// derive the transaction indices.
+	txIndices := make(map[hash.Hash]uint32)
+	for idx, tx := range txs {
+		txIndices[tx.Hash()] = uint32(idx)
+	}
+
+	// Collect the tags of each transaction.
+	indexedTxs := make(map[hash.Hash]*badgerTx)
+	for _, tag := range tags {
+		tx, ok := indexedTxs[tag.TxHash]
+		if !ok {
+			tx = &badgerTx{TxHash: tag.TxHash}
+			indexedTxs[tag.TxHash] = tx
+		}
+		tx.Tags = append(tx.Tags, tagHash(tag.Key, tag.Value))
+	}
+
+	err := b.db.Update(func(tx *badger.Txn) error {
+		for txHash, indexedTx := range indexedTxs {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			txIndex := txIndices[txHash]
+			if err := tx.Set(badgerTxKeyFmt.Encode(round, txIndex), cbor.Marshal(indexedTx)); err != nil {
+				return err
+			}
+			for i := range indexedTx.Tags {
+				if err := tx.Set(badgerTagKeyFmt.Encode(&indexedTx.Tags[i], round, txIndex, &txHash), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Set(badgerBlockKeyFmt.Encode(&blockHash), cbor.Marshal(round)); err != nil {
+			return err
+		}
+		return tx.Set(badgerRoundKeyFmt.Encode(round), cbor.Marshal(blockHash))
+	})
+	if err != nil {
+		return err
+	}
+
+	b.blockIndexedNotifier.Broadcast(round)
+
+	return nil
+}
+
+func (b *badgerBackend) QueryBlock(ctx context.Context, blockHash hash.Hash) (uint64, error) {
+	var round uint64
+	err := b.db.View(func(tx *badger.Txn) error {
+		item, err := tx.Get(badgerBlockKeyFmt.Encode(&blockHash))
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			return api.ErrNotFound
+		default:
+			return err
+		}
+
+		return item.Value(func(val []byte) error {
+			if err := cbor.Unmarshal(val, &round); err != nil {
+				return ErrCorrupted
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return round, nil
+}
+
+// queryTag returns references to all transactions with the given tag in the
+// given (inclusive) round range, ordered by round and transaction index.
+//
+// A zero roundMax means that there is no upper limit.
+func (b *badgerBackend) queryTag(tx *badger.Txn, key, value []byte, roundMin, roundMax uint64) (map[badgerTxRef]hash.Hash, error) {
+	th := tagHash(key, value)
+
+	// NOTE: Do not prefetch values as we are only looking at keys.
+	it := tx.NewIterator(badger.IteratorOptions{
+		Prefix: badgerTagKeyFmt.Encode(&th),
+	})
+	defer it.Close()
+
+	refs := make(map[badgerTxRef]hash.Hash)
+	for it.Seek(badgerTagKeyFmt.Encode(&th, roundMin)); it.Valid(); it.Next() {
+		var (
+			decTagHash hash.Hash
+			ref        badgerTxRef
+			txHash     hash.Hash
+		)
+		if !badgerTagKeyFmt.Decode(it.Item().Key(), &decTagHash, &ref.round, &ref.txIndex, &txHash) {
+			return nil, ErrCorrupted
+		}
+		if roundMax > 0 && ref.round > roundMax {
+			break
+		}
+
+		refs[ref] = txHash
+	}
+	return refs, nil
+}
+
+func (b *badgerBackend) QueryTxn(ctx context.Context, key, value []byte) (uint64, hash.Hash, uint32, error) {
+	var (
+		round   uint64
+		txHash  hash.Hash
+		txIndex uint32
+	)
+	err := b.db.View(func(tx *badger.Txn) error {
+		th := tagHash(key, value)
+
+		// NOTE: Do not prefetch values as we are only looking at keys.
+		it := tx.NewIterator(badger.IteratorOptions{
+			Prefix: badgerTagKeyFmt.Encode(&th),
+		})
+		defer it.Close()
+
+		it.Rewind()
+		if !it.Valid() {
+			return api.ErrNotFound
+		}
+
+		var decTagHash hash.Hash
+		if !badgerTagKeyFmt.Decode(it.Item().Key(), &decTagHash, &round, &txIndex, &txHash) {
+			return ErrCorrupted
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, hash.Hash{}, 0, err
+	}
+
+	return round, txHash, txIndex, nil
+}
+
+func (b *badgerBackend) QueryTxnByIndex(ctx context.Context, round uint64, index uint32) (hash.Hash, error) {
+	var indexedTx badgerTx
+	err := b.db.View(func(tx *badger.Txn) error {
+		item, err := tx.Get(badgerTxKeyFmt.Encode(round, index))
+		switch err {
+		case nil:
+		case badger.ErrKeyNotFound:
+			return api.ErrNotFound
+		default:
+			return err
+		}
+
+		return item.Value(func(val []byte) error {
+			if err := cbor.Unmarshal(val, &indexedTx); err != nil {
+				return ErrCorrupted
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	return indexedTx.TxHash, nil
+}
+
+// queryRounds returns references to all transactions in the given (inclusive)
+// round range.
+//
+// A zero roundMax means that there is no upper limit.
+func (b *badgerBackend) queryRounds(tx *badger.Txn, roundMin, roundMax uint64) (map[badgerTxRef]hash.Hash, error) {
+	it := tx.NewIterator(badger.IteratorOptions{
+		PrefetchValues: true,
+		Prefix:         badgerTxKeyFmt.Encode(),
+	})
+	defer it.Close()
+
+	refs := make(map[badgerTxRef]hash.Hash)
+	for it.Seek(badgerTxKeyFmt.Encode(roundMin)); it.Valid(); it.Next() {
+		item := it.Item()
+
+		var ref badgerTxRef
+		if !badgerTxKeyFmt.Decode(item.Key(), &ref.round, &ref.txIndex) {
+			return nil, ErrCorrupted
+		}
+		if roundMax > 0 && ref.round > roundMax {
+			break
+		}
+
+		var indexedTx badgerTx
+		err := item.Value(func(val []byte) error {
+			return cbor.Unmarshal(val, &indexedTx)
+		})
+		if err != nil {
+			return nil, ErrCorrupted
+		}
+		refs[ref] = indexedTx.TxHash
+	}
+	return refs, nil
+}
+
+func (b *badgerBackend) QueryTxns(ctx context.Context, query api.Query) (Results, error) {
+	var matches map[badgerTxRef]hash.Hash
+	err := b.db.View(func(tx *badger.Txn) error {
+		// Filter by key/value tag conditions. Values of a condition are
+		// combined using OR and conditions are combined using AND.
+		for _, cond := range query.Conditions {
+			if len(cond.Values) == 0 {
+				// No values (strange, but ok).
+				continue
+			}
+
+			condMatches := make(map[badgerTxRef]hash.Hash)
+			for _, v := range cond.Values {
+				refs, err := b.queryTag(tx, cond.Key, v, query.RoundMin, query.RoundMax)
+				if err != nil {
+					return err
+				}
+				for ref, txHash := range refs {
+					condMatches[ref] = txHash
+				}
+			}
+
+			if matches == nil {
+				matches = condMatches
+				continue
+			}
+			for ref := range matches {
+				if _, ok := condMatches[ref]; !ok {
+					delete(matches, ref)
+				}
+			}
+		}
+
+		if matches != nil {
+			return nil
+		}
+
+		// No conditions, match all transactions in the given rounds.
+		var err error
+		matches, err = b.queryRounds(tx, query.RoundMin, query.RoundMax)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(query.Limit)
+	if limit == 0 || limit > maxQueryLimit {
+		limit = maxQueryLimit
+	}
+
+	// Apply the limit in (round, index) order so results are deterministic.
+	refs := make([]badgerTxRef, 0, len(matches))
+	for ref := range matches {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].round != refs[j].round {
+			return refs[i].round < refs[j].round
+		}
+		return refs[i].txIndex < refs[j].txIndex
+	})
+	if len(refs) > limit {
+		refs = refs[:limit]
+	}
+
+	results := make(Results)
+	for _, ref := range refs {
+		results[ref.round] = append(results[ref.round], Result{TxHash: matches[ref], TxIndex: ref.txIndex})
+	}
+
+	return results, nil
+}
+
+func (b *badgerBackend) WaitBlockIndexed(ctx context.Context, round uint64) error {
+	sub := b.blockIndexedNotifier.Subscribe()
+	defer sub.Close()
+
+	ch := make(chan uint64)
+	sub.Unwrap(ch)
+
+	err := b.db.View(func(tx *badger.Txn) error {
+		_, err := tx.Get(badgerRoundKeyFmt.Encode(round))
+		return err
+	})
+	switch err {
+	case nil:
+		return nil
+	case badger.ErrKeyNotFound:
+	default:
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case r := <-ch:
+			if r >= round {
+				return nil
+			}
+		}
+	}
+}
+
+func (b *badgerBackend) Prune(ctx context.Context, round uint64) error {
+	return b.db.Update(func(tx *badger.Txn) error {
+		it := tx.NewIterator(badger.IteratorOptions{
+			PrefetchValues: true,
+			Prefix:         badgerTxKeyFmt.Encode(round),
+		})
+		defer it.Close()
+
+		var itemCount int
+		for it.Rewind(); it.Valid(); it.Next() {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+
+			item := it.Item()
+
+			var txIndex uint32
+			var decRound uint64
+			if !badgerTxKeyFmt.Decode(item.Key(), &decRound, &txIndex) {
+				return ErrCorrupted
+			}
+
+			var indexedTx badgerTx
+			err := item.Value(func(val []byte) error {
+				return cbor.Unmarshal(val, &indexedTx)
+			})
+			if err != nil {
+				return ErrCorrupted
+			}
+
+			for i := range indexedTx.Tags {
+				if err = tx.Delete(badgerTagKeyFmt.Encode(&indexedTx.Tags[i], round, txIndex, &indexedTx.TxHash)); err != nil {
+					return err
+				}
+			}
+			if err = tx.Delete(item.KeyCopy(nil)); err != nil {
+				return err
+			}
+			itemCount++
+		}
+
+		item, err := tx.Get(badgerRoundKeyFmt.Encode(round))
+		switch err {
+		case nil:
+			itemCount++
+		case badger.ErrKeyNotFound:
+			// Nothing else to prune.
+			return nil
+		default:
+			return err
+		}
+
+		var blockHash hash.Hash
+		err = item.Value(func(val []byte) error {
+			return cbor.Unmarshal(val, &blockHash)
+		})
+		if err != nil {
+			return ErrCorrupted
+		}
+
+		b.logger.Debug("pruning items from index",
+			"round", round,
+			"item_count", itemCount,
+		)
+
+		if err = tx.Delete(badgerBlockKeyFmt.Encode(&blockHash)); err != nil {
+			return err
+		}
+		return tx.Delete(badgerRoundKeyFmt.Encode(round))
+	})
+}
+
+func (b *badgerBackend) Close() {
+	b.gc.Close()
+	if err := b.db.Close(); err != nil {
+		b.logger.Error("failed to close index",
+			"err", err,
+		)
+	}
+	b.db = nil
+}
+
+func newBadgerBackend(dataDir string, runtimeID common.Namespace) (Backend, error) {
+	logger := logging.GetLogger("runtime/history/tagindexer/badger").With("runtime_id", runtimeID)
+
+	opts := badger.DefaultOptions(filepath.Join(dataDir, badgerIndexFile))
+	opts = opts.WithLogger(cmnBadger.NewLogAdapter(logger))
+	opts = opts.WithSyncWrites(true)
+	opts = opts.WithCompression(options.None)
+	// Reduce cache size to 10 MiB as the default is 1 GiB.
+	opts = opts.WithMaxCacheSize(10 * 1024 * 1024)
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("tagindexer: failed to open database: %w", err)
+	}
+
+	b := &badgerBackend{
+		logger:               logger,
+		db:                   db,
+		gc:                   cmnBadger.NewGCWorker(logger, db),
+		blockIndexedNotifier: pubsub.NewBroker(true),
+	}
+
+	b.logger.Info("initialized tag indexer backend")
+
+	return b, nil
+}
+
+// NewBadgerBackend creates a new badger indexer backend factory.
+func NewBadgerBackend() BackendFactory {
+	return newBadgerBackend
+}
@@ -296,12 +296,13 @@ func (b *bleveBackend) QueryTxns(ctx context.Context, query api.Query) (Results,
 
 	q := bleve.NewConjunctionQuery(qs...)
 	rq := bleve.NewSearchRequest(q)
-	if query.Limit > 0 {
-		rq.Size = int(query.Limit)
-	}
+	// NOTE: Bleve defaults to a page size of 10 results.
+	rq.Size = int(query.Limit)
 	if rq.Size == 0 || rq.Size > maxQueryLimit {
 		rq.Size = maxQueryLimit
 	}
+	// Apply the limit in (round, index) order so results are deterministic.
+	rq.SortBy([]string{fieldRound, fieldTxIndex})
 
 	result, err := b.index.SearchInContext(ctx, rq)
 	if err != nil {
@@ -20,6 +20,9 @@ const (
 	// LogEventGeneralAdjustment is a log event value that signals adjustment
 	// of an account's general balance due to a roothash message.
 	LogEventGeneralAdjustment = "staking/general_adjustment"
+
+	// MaxNonceWindow is the maximum size of an account's nonce window.
+	MaxNonceWindow = 64
 )
 
 var (
@@ -55,6 +58,8 @@ var (
 	MethodReclaimEscrow = transaction.NewMethodName(ModuleName, "ReclaimEscrow", ReclaimEscrow{})
 	// MethodAmendCommissionSchedule is the method name for amending commission schedules.
 	MethodAmendCommissionSchedule = transaction.NewMethodName(ModuleName, "AmendCommissionSchedule", AmendCommissionSchedule{})
+	// MethodSetNonceWindow is the method name for setting account nonce windows.
+	MethodSetNonceWindow = transaction.NewMethodName(ModuleName, "SetNonceWindow", SetNonceWindow{})
 
 	// Methods is the list of all methods supported by the staking backend.
 	Methods = []transaction.MethodName{
@@ -63,6 +68,7 @@ var (
 		MethodAddEscrow,
 		MethodReclaimEscrow,
 		MethodAmendCommissionSchedule,
+		MethodSetNonceWindow,
 	}
 )
 
@@ -84,13 +90,27 @@ type Backend interface {
 	// AccountInfo returns the account descriptor for the given account.
 	AccountInfo(ctx context.Context, query *OwnerQuery) (*Account, error)
 
+	// EffectiveCommission returns the commission rate and bounds that will
+	// be in effect for the given account at the given epoch according to
+	// the account's commission schedule as of the given block height.
+	EffectiveCommission(ctx context.Context, query *EffectiveCommissionQuery) (*EffectiveCommission, error)
+
 	// DebondingDelegations returns the list of debonding delegations for
 	// the given owner (delegator).
 	DebondingDelegations(ctx context.Context, query *OwnerQuery) (map[signature.PublicKey][]*DebondingDelegation, error)
 
+	// DebondingInfo returns the list of debonding delegations from the
+	// given delegator to the given escrow account, including the epoch
+	// at which each of them completes.
+	DebondingInfo(ctx context.Context, query *DebondingInfoQuery) ([]*DebondingDelegation, error)
+
 	// StateToGenesis returns the genesis state at specified block height.
 	StateToGenesis(ctx context.Context, height int64) (*Genesis, error)
 
+	// ConsensusParameters returns the staking consensus parameters at
+	// the specified block height.
+	ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error)
+
 	// WatchTransfers returns a channel that produces a stream of TranserEvent
 	// on all balance transfers.
 	WatchTransfers(ctx context.Context) (<-chan *TransferEvent, pubsub.ClosableSubscription, error)
@@ -120,6 +140,20 @@ type OwnerQuery struct {
 	Owner  signature.PublicKey `json:"owner"`
 }
 
+// DebondingInfoQuery is a debonding info query.
+type DebondingInfoQuery struct {
+	Height    int64               `json:"height"`
+	Delegator signature.PublicKey `json:"delegator"`
+	Escrow    signature.PublicKey `json:"escrow"`
+}
+
+// EffectiveCommissionQuery is an effective commission query.
+type EffectiveCommissionQuery struct {
+	Height int64               `json:"height"`
+	Owner  signature.PublicKey `json:"owner"`
+	Epoch  epochtime.EpochTime `json:"epoch"`
+}
+
 // TransferEvent is the event emitted when a balance is transfered, either by
 // a call to Transfer or Withdraw.
 type TransferEvent struct {
@@ -128,7 +162,8 @@ type TransferEvent struct {
 	Tokens quantity.Quantity   `json:"tokens"`
 }
 
-// BurnEvent is the event emitted when tokens are destroyed via a call to Burn.
+// BurnEvent is the event emitted when tokens are destroyed via a call to Burn
+// or when transaction fees are burned, in which case Owner is not set.
 type BurnEvent struct {
 	Owner  signature.PublicKey `json:"owner"`
 	Tokens quantity.Quantity   `json:"tokens"`
@@ -217,6 +252,16 @@ func NewAmendCommissionScheduleTx(nonce uint64, fee *transaction.Fee, amend *Ame
 	return transaction.NewTransaction(nonce, fee, MethodAmendCommissionSchedule, amend)
 }
 
+// SetNonceWindow is a change of the signer account's nonce window.
+type SetNonceWindow struct {
+	NonceWindow uint64 `json:"nonce_window"`
+}
+
+// NewSetNonceWindowTx creates a new set nonce window transaction.
+func NewSetNonceWindowTx(nonce uint64, fee *transaction.Fee, set *SetNonceWindow) *transaction.Transaction {
+	return transaction.NewTransaction(nonce, fee, MethodSetNonceWindow, set)
+}
+
 // SharePool is a combined balance of several entries, the relative sizes
 // of which are tracked through shares.
 type SharePool struct {
@@ -354,6 +399,67 @@ func (k ThresholdKind) String() string {
 type GeneralAccount struct {
 	Balance quantity.Quantity `json:"balance"`
 	Nonce   uint64            `json:"nonce"`
+
+	// NonceWindow is the number of nonces starting at Nonce that may be
+	// used to authenticate transactions, in any order. Zero is treated
+	// as one, requiring strictly incrementing nonces.
+	NonceWindow uint64 `json:"nonce_window,omitempty"`
+	// NonceBitmap tracks the nonces within the window that have already
+	// been used, with bit i corresponding to nonce Nonce+i.
+	NonceBitmap uint64 `json:"nonce_bitmap,omitempty"`
+}
+
+func (a *GeneralAccount) nonceWindow() uint64 {
+	if a.NonceWindow == 0 {
+		return 1
+	}
+	return a.NonceWindow
+}
+
+// IsValidNonce returns true iff the given nonce falls within the account's
+// nonce window and has not been used yet.
+func (a *GeneralAccount) IsValidNonce(nonce uint64) bool {
+	if nonce < a.Nonce || nonce-a.Nonce >= a.nonceWindow() {
+		return false
+	}
+	return a.NonceBitmap&(1<<(nonce-a.Nonce)) == 0
+}
+
+// UseNonce marks the given nonce as used, advancing Nonce to the lowest
+// unused nonce.
+func (a *GeneralAccount) UseNonce(nonce uint64) error {
+	if !a.IsValidNonce(nonce) {
+		return transaction.ErrInvalidNonce
+	}
+
+	a.NonceBitmap |= 1 << (nonce - a.Nonce)
+	for a.NonceBitmap&1 != 0 {
+		a.NonceBitmap >>= 1
+		a.Nonce++
+	}
+	return nil
+}
+
+// SetNonceWindow changes the account's nonce window. The window can only be
+// shrunk if no nonces outside of the new window have been used yet, as those
+// could otherwise be replayed.
+func (a *GeneralAccount) SetNonceWindow(window uint64) error {
+	if window > MaxNonceWindow {
+		return ErrInvalidArgument
+	}
+	if window == 0 {
+		window = 1
+	}
+	if a.NonceBitmap>>window != 0 {
+		return ErrInvalidArgument
+	}
+
+	a.NonceWindow = window
+	if window == 1 {
+		// Keep the serialized account unchanged for strict nonces.
+		a.NonceWindow = 0
+	}
+	return nil
 }
 
 // EscrowAccount is an escrow account the balance of which is subject to
@@ -405,6 +511,8 @@ type ConsensusParameters struct {
 	RewardSchedule                    []RewardStep                        `json:"reward_schedule,omitempty"`
 	SigningRewardThresholdNumerator   uint64                              `json:"signing_reward_threshold_numerator,omitempty"`
 	SigningRewardThresholdDenominator uint64                              `json:"signing_reward_threshold_denominator,omitempty"`
+	FeeBurnNumerator                  uint64                              `json:"fee_burn_numerator,omitempty"`
+	FeeBurnDenominator                uint64                              `json:"fee_burn_denominator,omitempty"`
 	CommissionScheduleRules           CommissionScheduleRules             `json:"commission_schedule_rules,omitempty"`
 	Slashing                          map[SlashReason]Slash               `json:"slashing,omitempty"`
 	GasCosts                          transaction.Costs                   `json:"gas_costs,omitempty"`
@@ -426,6 +534,8 @@ const (
 	GasOpReclaimEscrow transaction.Op = "reclaim_escrow"
 	// GasOpAmendCommissionSchedule is the gas operation identifier for amend commission schedule.
 	GasOpAmendCommissionSchedule transaction.Op = "amend_commission_schedule"
+	// GasOpSetNonceWindow is the gas operation identifier for set nonce window.
+	GasOpSetNonceWindow transaction.Op = "set_nonce_window"
 )
 
 // SanityCheck performs a sanity check on the consensus parameters.
@@ -439,6 +549,11 @@ func (p *ConsensusParameters) SanityCheck() error {
 		}
 	}
 
+	// Fee burn split.
+	if p.FeeBurnDenominator != 0 && p.FeeBurnNumerator > p.FeeBurnDenominator {
+		return fmt.Errorf("fee burn numerator must not exceed the denominator")
+	}
+
 	return nil
 }
 
@@ -454,6 +569,12 @@ func SanityCheckAccount(total *quantity.Quantity, parameters *ConsensusParameter
 	if !acct.Escrow.Debonding.Balance.IsValid() {
 		return fmt.Errorf("staking: sanity check failed: escrow account debonding balance is invalid")
 	}
+	if acct.General.NonceWindow > MaxNonceWindow {
+		return fmt.Errorf("staking: sanity check failed: account nonce window exceeds %d", MaxNonceWindow)
+	}
+	if acct.General.NonceBitmap&1 != 0 || acct.General.NonceBitmap>>acct.General.nonceWindow() != 0 {
+		return fmt.Errorf("staking: sanity check failed: account nonce bitmap is invalid")
+	}
 
 	_ = total.Add(&acct.General.Balance)
 	_ = total.Add(&acct.Escrow.Active.Balance)
@@ -291,6 +291,45 @@ func (cs *CommissionSchedule) CurrentRate(now epochtime.EpochTime) *quantity.Qua
 	return &latestStartedStep.Rate
 }
 
+// CurrentBound returns the rate bounds at the latest bound step that has started or nil if no step has started.
+func (cs *CommissionSchedule) CurrentBound(now epochtime.EpochTime) *CommissionRateBoundStep {
+	var latestStartedStep *CommissionRateBoundStep
+	for i := range cs.Bounds {
+		step := &cs.Bounds[i]
+		if step.Start > now {
+			break
+		}
+		latestStartedStep = step
+	}
+	return latestStartedStep
+}
+
+// EffectiveCommission is the commission in effect at a given epoch.
+type EffectiveCommission struct {
+	// Epoch is the epoch at which the commission is in effect.
+	Epoch epochtime.EpochTime `json:"epoch"`
+	// Rate is the commission rate in effect, or nil if no rate step has started.
+	Rate *quantity.Quantity `json:"rate,omitempty"`
+	// Bound is the rate bound step in effect, or nil if no bound step has started.
+	Bound *CommissionRateBoundStep `json:"bound,omitempty"`
+}
+
+// EffectiveCommission validates the schedule the same way as when sanity checking accounts and returns the commission
+// rate and bounds that will be in effect at the given epoch, assuming the schedule is not amended before then.
+func (cs *CommissionSchedule) EffectiveCommission(rules *CommissionScheduleRules, epoch epochtime.EpochTime) (*EffectiveCommission, error) {
+	// Pruning only reslices the steps, so a shallow copy leaves the schedule intact.
+	csShallowCopy := *cs
+	if err := csShallowCopy.PruneAndValidateForGenesis(rules, epoch); err != nil {
+		return nil, err
+	}
+
+	return &EffectiveCommission{
+		Epoch: epoch,
+		Rate:  csShallowCopy.CurrentRate(epoch),
+		Bound: csShallowCopy.CurrentBound(epoch),
+	}, nil
+}
+
 func init() {
 	// Denominated in 1000th of a percent.
 	CommissionRateDenominator = quantity.NewQuantity()
@@ -718,3 +718,85 @@ func TestCommissionSchedule(t *testing.T) {
 	require.Equal(t, epochtime.EpochTime(10), cs.Rates[0].Start, "prune 10 rates start")
 	require.Equal(t, epochtime.EpochTime(10), cs.Bounds[0].Start, "prune 10 bounds start")
 }
+
+func TestEffectiveCommission(t *testing.T) {
+	rules := CommissionScheduleRules{
+		RateChangeInterval: 10,
+		RateBoundLead:      30,
+		MaxRateSteps:       4,
+		MaxBoundSteps:      12,
+	}
+
+	cs := CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{
+				Start: 0,
+				Rate:  mustInitQuantity(t, 50_000),
+			},
+			{
+				Start: 20,
+				Rate:  mustInitQuantity(t, 40_000),
+			},
+			{
+				Start: 50,
+				Rate:  mustInitQuantity(t, 60_000),
+			},
+		},
+		Bounds: []CommissionRateBoundStep{
+			{
+				Start:   0,
+				RateMin: mustInitQuantity(t, 0),
+				RateMax: mustInitQuantity(t, 100_000),
+			},
+			{
+				Start:   50,
+				RateMin: mustInitQuantity(t, 10_000),
+				RateMax: mustInitQuantity(t, 100_000),
+			},
+		},
+	}
+
+	ec, err := cs.EffectiveCommission(&rules, 0)
+	require.NoError(t, err, "EffectiveCommission 0")
+	require.Equal(t, epochtime.EpochTime(0), ec.Epoch, "effective commission 0 epoch")
+	require.Equal(t, mustInitQuantityP(t, 50_000), ec.Rate, "effective commission 0 rate")
+	require.Equal(t, &cs.Bounds[0], ec.Bound, "effective commission 0 bound")
+
+	ec, err = cs.EffectiveCommission(&rules, 25)
+	require.NoError(t, err, "EffectiveCommission 25")
+	require.Equal(t, mustInitQuantityP(t, 40_000), ec.Rate, "effective commission 25 rate")
+	require.Equal(t, &cs.Bounds[0], ec.Bound, "effective commission 25 bound")
+
+	ec, err = cs.EffectiveCommission(&rules, 999)
+	require.NoError(t, err, "EffectiveCommission 999")
+	require.Equal(t, mustInitQuantityP(t, 60_000), ec.Rate, "effective commission 999 rate")
+	require.Equal(t, &cs.Bounds[1], ec.Bound, "effective commission 999 bound")
+
+	// Querying must not prune the schedule.
+	require.Len(t, cs.Rates, 3, "rates after query")
+	require.Len(t, cs.Bounds, 2, "bounds after query")
+
+	cs = CommissionSchedule{}
+	ec, err = cs.EffectiveCommission(&rules, 10)
+	require.NoError(t, err, "EffectiveCommission empty")
+	require.Nil(t, ec.Rate, "effective commission empty rate")
+	require.Nil(t, ec.Bound, "effective commission empty bound")
+
+	cs = CommissionSchedule{
+		Rates: []CommissionRateStep{
+			{
+				Start: 0,
+				Rate:  mustInitQuantity(t, 50_000),
+			},
+		},
+		Bounds: []CommissionRateBoundStep{
+			{
+				Start:   0,
+				RateMin: mustInitQuantity(t, 60_000),
+				RateMax: mustInitQuantity(t, 100_000),
+			},
+		},
+	}
+	_, err = cs.EffectiveCommission(&rules, 0)
+	requireErrorShowDiagnostic(t, err, "rate out of bounds")
+}
@@ -25,10 +25,16 @@ var (
 	methodAccounts = serviceName.NewMethodName("Accounts")
 	// methodAccountInfo is the name of the AccountInfo method.
 	methodAccountInfo = serviceName.NewMethodName("AccountInfo")
+	// methodEffectiveCommission is the name of the EffectiveCommission method.
+	methodEffectiveCommission = serviceName.NewMethodName("EffectiveCommission")
 	// methodDebondingDelegations is the name of the DebondingDelegations method.
 	methodDebondingDelegations = serviceName.NewMethodName("DebondingDelegations")
+	// methodDebondingInfo is the name of the DebondingInfo method.
+	methodDebondingInfo = serviceName.NewMethodName("DebondingInfo")
 	// methodStateToGenesis is the name of the StateToGenesis method.
 	methodStateToGenesis = serviceName.NewMethodName("StateToGenesis")
+	// methodConsensusParameters is the name of the ConsensusParameters method.
+	methodConsensusParameters = serviceName.NewMethodName("ConsensusParameters")
 
 	// methodWatchTransfers is the name of the WatchTransfers method.
 	methodWatchTransfers = serviceName.NewMethodName("WatchTransfers")
@@ -62,14 +68,26 @@ var (
 				MethodName: methodAccountInfo.Short(),
 				Handler:    handlerAccountInfo,
 			},
+			{
+				MethodName: methodEffectiveCommission.Short(),
+				Handler:    handlerEffectiveCommission,
+			},
 			{
 				MethodName: methodDebondingDelegations.Short(),
 				Handler:    handlerDebondingDelegations,
 			},
+			{
+				MethodName: methodDebondingInfo.Short(),
+				Handler:    handlerDebondingInfo,
+			},
 			{
 				MethodName: methodStateToGenesis.Short(),
 				Handler:    handlerStateToGenesis,
 			},
+			{
+				MethodName: methodConsensusParameters.Short(),
+				Handler:    handlerConsensusParameters,
+			},
 		},
 		Streams: []grpc.StreamDesc{
 			{
@@ -206,6 +224,29 @@ func handlerAccountInfo( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerEffectiveCommission( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query EffectiveCommissionQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).EffectiveCommission(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodEffectiveCommission.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).EffectiveCommission(ctx, req.(*EffectiveCommissionQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerDebondingDelegations( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -229,6 +270,29 @@ func handlerDebondingDelegations( // nolint: golint
 	return interceptor(ctx, &query, info, handler)
 }
 
+func handlerDebondingInfo( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var query DebondingInfoQuery
+	if err := dec(&query); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).DebondingInfo(ctx, &query)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodDebondingInfo.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).DebondingInfo(ctx, req.(*DebondingInfoQuery))
+	}
+	return interceptor(ctx, &query, info, handler)
+}
+
 func handlerStateToGenesis( // nolint: golint
 	srv interface{},
 	ctx context.Context,
@@ -252,6 +316,29 @@ func handlerStateToGenesis( // nolint: golint
 	return interceptor(ctx, height, info, handler)
 }
 
+func handlerConsensusParameters( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var height int64
+	if err := dec(&height); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(Backend).ConsensusParameters(ctx, height)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodConsensusParameters.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Backend).ConsensusParameters(ctx, req.(int64))
+	}
+	return interceptor(ctx, height, info, handler)
+}
+
 func handlerWatchTransfers(srv interface{}, stream grpc.ServerStream) error {
 	if err := stream.RecvMsg(nil); err != nil {
 		return err
@@ -385,6 +472,14 @@ func (c *stakingClient) AccountInfo(ctx context.Context, query *OwnerQuery) (*Ac
 	return &rsp, nil
 }
 
+func (c *stakingClient) EffectiveCommission(ctx context.Context, query *EffectiveCommissionQuery) (*EffectiveCommission, error) {
+	var rsp EffectiveCommission
+	if err := c.conn.Invoke(ctx, methodEffectiveCommission.Full(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *stakingClient) DebondingDelegations(ctx context.Context, query *OwnerQuery) (map[signature.PublicKey][]*DebondingDelegation, error) {
 	var rsp map[signature.PublicKey][]*DebondingDelegation
 	if err := c.conn.Invoke(ctx, methodDebondingDelegations.Full(), query, &rsp); err != nil {
@@ -393,6 +488,14 @@ func (c *stakingClient) DebondingDelegations(ctx context.Context, query *OwnerQu
 	return rsp, nil
 }
 
+func (c *stakingClient) DebondingInfo(ctx context.Context, query *DebondingInfoQuery) ([]*DebondingDelegation, error) {
+	var rsp []*DebondingDelegation
+	if err := c.conn.Invoke(ctx, methodDebondingInfo.Full(), query, &rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 func (c *stakingClient) StateToGenesis(ctx context.Context, height int64) (*Genesis, error) {
 	var rsp Genesis
 	if err := c.conn.Invoke(ctx, methodStateToGenesis.Full(), height, &rsp); err != nil {
@@ -401,6 +504,14 @@ func (c *stakingClient) StateToGenesis(ctx context.Context, height int64) (*Gene
 	return &rsp, nil
 }
 
+func (c *stakingClient) ConsensusParameters(ctx context.Context, height int64) (*ConsensusParameters, error) {
+	var rsp ConsensusParameters
+	if err := c.conn.Invoke(ctx, methodConsensusParameters.Full(), height, &rsp); err != nil {
+		return nil, err
+	}
+	return &rsp, nil
+}
+
 func (c *stakingClient) WatchTransfers(ctx context.Context) (<-chan *TransferEvent, pubsub.ClosableSubscription, error) {
 	ctx, sub := pubsub.NewContextSubscription(ctx)
 
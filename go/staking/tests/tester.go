@@ -118,6 +118,12 @@ func testInitialEnv(t *testing.T, backend api.Backend, consensus consensusAPI.Ba
 	require.True(acc.Escrow.Debonding.Balance.IsZero(), "dest: debonding escrow balance")
 	require.EqualValues(0, acc.General.Nonce, "dest: nonce")
 
+	ec, err := backend.EffectiveCommission(context.Background(), &api.EffectiveCommissionQuery{Owner: SrcID, Epoch: 10, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "src: EffectiveCommission")
+	require.EqualValues(10, ec.Epoch, "src: effective commission epoch")
+	require.Nil(ec.Rate, "src: effective commission rate")
+	require.Nil(ec.Bound, "src: effective commission bound")
+
 	commonPool, err := backend.CommonPool(context.Background(), consensusAPI.HeightLatest)
 	require.NoError(err, "CommonPool")
 	require.True(commonPool.IsZero(), "CommonPool - initial value")
@@ -143,6 +149,10 @@ func testTransfer(t *testing.T, backend api.Backend, consensus consensusAPI.Back
 	srcAcc, err := backend.AccountInfo(context.Background(), &api.OwnerQuery{Owner: SrcID, Height: consensusAPI.HeightLatest})
 	require.NoError(err, "src: AccountInfo - before")
 
+	nonce, err := consensus.GetSignerNonce(context.Background(), &consensusAPI.GetSignerNonceRequest{ID: SrcID, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "GetSignerNonce - before")
+	require.Equal(srcAcc.General.Nonce, nonce, "GetSignerNonce - before")
+
 	ch, sub, err := backend.WatchTransfers(context.Background())
 	require.NoError(err, "WatchTransfers")
 	defer sub.Close()
@@ -151,7 +161,7 @@ func testTransfer(t *testing.T, backend api.Backend, consensus consensusAPI.Back
 		To:     DestID,
 		Tokens: debug.QtyFromInt(math.MaxUint8),
 	}
-	tx := api.NewTransferTx(srcAcc.General.Nonce, nil, xfer)
+	tx := api.NewTransferTx(nonce, nil, xfer)
 	err = consensusAPI.SignAndSubmitTx(context.Background(), consensus, srcSigner, tx)
 	require.NoError(err, "Transfer")
 
@@ -170,6 +180,10 @@ func testTransfer(t *testing.T, backend api.Backend, consensus consensusAPI.Back
 	require.Equal(srcAcc.General.Balance, newSrcAcc.General.Balance, "src: general balance - after")
 	require.Equal(tx.Nonce+1, newSrcAcc.General.Nonce, "src: nonce - after")
 
+	nonce, err = consensus.GetSignerNonce(context.Background(), &consensusAPI.GetSignerNonceRequest{ID: SrcID, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "GetSignerNonce - after")
+	require.Equal(tx.Nonce+1, nonce, "GetSignerNonce - after")
+
 	_ = dstAcc.General.Balance.Add(&xfer.Tokens)
 	newDstAcc, err := backend.AccountInfo(context.Background(), &api.OwnerQuery{Owner: DestID, Height: consensusAPI.HeightLatest})
 	require.NoError(err, "dest: AccountInfo - after")
@@ -418,9 +432,18 @@ func testEscrowEx(
 	require.Len(debs, 1, "one debonding delegation after reclaiming escrow")
 	require.Len(debs[dstID], 1, "one debonding delegation after reclaiming escrow")
 
+	// Query debonding info.
+	reclaimEpoch, err := consensus.EpochTime().GetEpoch(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetEpoch - after reclaim")
+	debInfo, err := backend.DebondingInfo(context.Background(), &api.DebondingInfoQuery{Delegator: srcID, Escrow: dstID, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "DebondingInfo - after (in debonding)")
+	require.Len(debInfo, 1, "one debonding delegation after reclaiming escrow")
+	require.Equal(debs[dstID][0].Shares, debInfo[0].Shares, "DebondingInfo: shares")
+	require.Equal(reclaimEpoch+debugGenesisState.Parameters.DebondingInterval, debInfo[0].DebondEndTime, "DebondingInfo: debond end")
+
 	// Advance epoch to trigger debonding.
 	timeSource := consensus.EpochTime().(epochtime.SetableBackend)
-	epochtimeTests.MustAdvanceEpoch(t, timeSource, 1)
+	epochtimeTests.MustAdvanceEpoch(t, timeSource, uint64(debInfo[0].DebondEndTime-reclaimEpoch))
 
 	// Wait for debonding period to pass.
 	select {
@@ -459,6 +482,13 @@ func testEscrowEx(
 	require.NoError(err, "DebondingDelegations - after (debonding completed)")
 	require.Len(debs, 0, "no debonding delegations after debonding has completed")
 
+	debondEpoch, err := consensus.EpochTime().GetEpoch(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetEpoch - after debond")
+	require.Equal(debInfo[0].DebondEndTime, debondEpoch, "debonding completed at the reported epoch")
+	debInfo, err = backend.DebondingInfo(context.Background(), &api.DebondingInfoQuery{Delegator: srcID, Escrow: dstID, Height: consensusAPI.HeightLatest})
+	require.NoError(err, "DebondingInfo - after (debonding completed)")
+	require.Len(debInfo, 0, "no debonding delegations after debonding has completed")
+
 	// Reclaim escrow (without enough shares).
 	reclaim = &api.ReclaimEscrow{
 		Account: dstID,
@@ -530,6 +560,10 @@ func testSlashDoubleSigning(
 	require.NoError(err, "WatchEscrows")
 	defer slashSub.Close()
 
+	// The freeze period starts at the epoch in which the node gets slashed.
+	epoch, err := consensus.EpochTime().GetEpoch(context.Background(), consensusAPI.HeightLatest)
+	require.NoError(err, "GetEpoch")
+
 	// Broadcast evidence. This is Tendermint-specific, if we ever have more than one
 	// consensus backend, we need to change this part.
 	err = consensus.SubmitEvidence(context.Background(), tendermintTests.MakeDoubleSignEvidence(t, ident))
@@ -556,6 +590,8 @@ WaitLoop:
 	require.NoError(err, "GetNodeStatus")
 	require.False(nodeStatus.ExpirationProcessed, "ExpirationProcessed should be false")
 	require.True(nodeStatus.IsFrozen(), "IsFrozen() should return true")
+	freezeInterval := debug.DebugGenesisState.Parameters.Slashing[api.SlashDoubleSigning].FreezeInterval
+	require.Equal(epoch+freezeInterval, nodeStatus.FreezeEndTime, "FreezeEndTime should reflect the freeze interval")
 
 	// Make sure node cannot be unfrozen.
 	tx = registry.NewUnfreezeNodeTx(0, nil, &registry.UnfreezeNode{
@@ -591,4 +627,5 @@ WaitLoop:
 	require.NoError(err, "GetNodeStatus")
 	require.False(nodeStatus.ExpirationProcessed, "ExpirationProcessed should be false")
 	require.False(nodeStatus.IsFrozen(), "IsFrozen() should return false")
+	require.EqualValues(0, nodeStatus.FreezeEndTime, "FreezeEndTime should be cleared")
 }
@@ -40,6 +40,22 @@ var (
 	// ErrNoMergeRoots is the error returned when no other roots are passed
 	// to the Merge operation.
 	ErrNoMergeRoots = errors.New(ModuleName, 5, "storage: no roots to merge")
+	// ErrInvalidResumeToken is the error returned when the checkpoint resume
+	// token does not match the requested root.
+	ErrInvalidResumeToken = errors.New(ModuleName, 6, "storage: invalid checkpoint resume token")
+	// ErrQuotaExceeded is the error returned when an update would make the
+	// namespace exceed its storage quota.
+	ErrQuotaExceeded = errors.New(ModuleName, 7, "storage: namespace quota exceeded")
+	// ErrInvalidRoundRange is the error returned when the end round of a
+	// GetDiffRange request precedes its start round.
+	ErrInvalidRoundRange = errors.New(ModuleName, 8, "storage: invalid round range")
+	// ErrRootPruned is the error returned when the source root of a diff is
+	// no longer available (e.g., because it has been pruned). Clients should
+	// fall back to fetching a checkpoint instead.
+	ErrRootPruned = errors.New(ModuleName, 9, "storage: root has been pruned")
+	// ErrRoundRangeTooLarge is the error returned when a GetDiffRange
+	// request spans more than MaxDiffRangeRounds rounds.
+	ErrRoundRangeTooLarge = errors.New(ModuleName, 10, "storage: round range too large")
 
 	// The following errors are reimports from NodeDB.
 
@@ -66,6 +82,9 @@ var (
 	ErrRootNotFound = nodedb.ErrRootNotFound
 	// ErrRootMustFollowOld indicates that the passed new root does not follow old root.
 	ErrRootMustFollowOld = nodedb.ErrRootMustFollowOld
+	// ErrNodeHashMismatch indicates that a stored node does not hash to the hash it
+	// is stored under.
+	ErrNodeHashMismatch = nodedb.ErrNodeHashMismatch
 
 	// ReceiptSignatureContext is the signature context used for verifying MKVS receipts.
 	ReceiptSignatureContext = signature.NewContext("oasis-core/storage: receipt", signature.WithChainSeparation())
@@ -93,6 +112,10 @@ type Config struct {
 
 	// MaxCacheSize is the maximum in-memory cache size for the database.
 	MaxCacheSize int64
+
+	// Quota is the maximum approximate size (in bytes) of the namespace's
+	// stored state, 0 means unlimited.
+	Quota uint64
 }
 
 // ToNodeDB converts from a Config to a node DB Config.
@@ -175,6 +198,9 @@ type Depth = urkelNode.Depth
 // Node is either an InternalNode or a LeafNode.
 type Node = urkelNode.Node
 
+// VerifyIssue is an integrity issue found while verifying local storage.
+type VerifyIssue = nodedb.VerifyIssue
+
 // Pointer is a pointer to another node.
 type Pointer = urkelNode.Pointer
 
@@ -260,6 +286,11 @@ type MergeBatchRequest struct {
 type SyncOptions struct {
 	OffsetKey []byte `json:"offset_key"`
 	Limit     uint64 `json:"limit"`
+
+	// Compression is the compression that the client would like to be
+	// applied to the streamed write log chunks. Servers that do not
+	// support the requested compression send uncompressed chunks.
+	Compression CompressionType `json:"compression,omitempty"`
 }
 
 // SyncChunk is a chunk of write log entries sent during GetDiff and
@@ -267,6 +298,12 @@ type SyncOptions struct {
 type SyncChunk struct {
 	Final    bool     `json:"final"`
 	WriteLog WriteLog `json:"writelog"`
+
+	// Compression is the compression applied to CompressedWriteLog.
+	Compression CompressionType `json:"compression,omitempty"`
+	// CompressedWriteLog is the compressed serialized write log, used
+	// instead of WriteLog when Compression is set.
+	CompressedWriteLog []byte `json:"compressed_writelog,omitempty"`
 }
 
 // GetDiffRequest is a GetDiff request.
@@ -276,10 +313,62 @@ type GetDiffRequest struct {
 	Options   SyncOptions `json:"options"`
 }
 
+// MaxDiffRangeRounds is the maximum number of rounds (after the start round)
+// that can be requested in a single GetDiffRange request.
+const MaxDiffRangeRounds = 1000
+
+// GetDiffRangeRequest is a GetDiffRange request.
+type GetDiffRangeRequest struct {
+	Namespace common.Namespace `json:"namespace"`
+	// StartRound is the round the diffs start from.
+	StartRound uint64 `json:"start_round"`
+	// EndRound is the (inclusive) round the diffs end at.
+	EndRound uint64 `json:"end_round"`
+}
+
+// DiffRangeChunk is a chunk of write log entries sent during GetDiffRange
+// operations.
+type DiffRangeChunk struct {
+	// StartRoot is the root the write log entries are applied to.
+	StartRoot Root `json:"start_root"`
+	// EndRoot is the root resulting from applying all of the write log
+	// entries of the diff.
+	EndRoot Root `json:"end_root"`
+	// Final is true iff this is the last chunk of the diff between
+	// StartRoot and EndRoot.
+	Final    bool     `json:"final"`
+	WriteLog WriteLog `json:"writelog"`
+}
+
 // GetCheckpointRequest is a GetCheckpoint request.
 type GetCheckpointRequest struct {
 	Root    Root        `json:"root"`
 	Options SyncOptions `json:"options"`
+
+	// ResumeToken is an optional token used to resume an interrupted
+	// checkpoint stream.
+	ResumeToken *CheckpointResumeToken `json:"resume_token,omitempty"`
+}
+
+// VerifyRequest is a Verify request.
+type VerifyRequest struct {
+	Namespace common.Namespace `json:"namespace"`
+	// Round is the round to verify. If nil, all finalized rounds are
+	// verified.
+	Round *uint64 `json:"round,omitempty"`
+}
+
+// CheckpointResumeToken is a token used to resume a checkpoint stream from
+// where it left off.
+//
+// As checkpoint write log entries are streamed in key order, a stream is
+// resumed by skipping all entries up to and including the last received
+// key.
+type CheckpointResumeToken struct {
+	// Root is the root the checkpoint is being fetched for.
+	Root Root `json:"root"`
+	// LastKey is the key of the last received write log entry.
+	LastKey []byte `json:"last_key"`
 }
 
 // Backend is a storage backend implementation.
@@ -330,6 +419,57 @@ type Backend interface {
 	Initialized() <-chan struct{}
 }
 
+// DiffRangeBackend is a storage backend that is able to serve diffs
+// spanning multiple finalized rounds in a single operation.
+type DiffRangeBackend interface {
+	Backend
+
+	// GetDiffRange walks the finalized roots of all rounds in the given
+	// range and calls fn with the chunks of write log entries that must be
+	// applied to get from the roots of the start round to the roots of the
+	// end round. The range may span at most MaxDiffRangeRounds rounds.
+	//
+	// For each round after the start round, the diff of the I/O root (from
+	// the empty root) is followed by the diff of the state root (from the
+	// state root of the previous round). Each diff consists of at least
+	// one chunk, the last of which is marked as final.
+	GetDiffRange(ctx context.Context, request *GetDiffRangeRequest, fn func(*DiffRangeChunk) error) error
+}
+
+// DiffRangeChunks splits the write log entries produced by the given
+// iterator into GetDiffRange chunks for the diff between the given roots.
+func DiffRangeChunks(it WriteLogIterator, startRoot, endRoot Root, fn func(*DiffRangeChunk) error) error {
+	for {
+		chunk := &DiffRangeChunk{
+			StartRoot: startRoot,
+			EndRoot:   endRoot,
+		}
+		for len(chunk.WriteLog) < WriteLogIteratorChunkSize {
+			more, err := it.Next()
+			if err != nil {
+				return err
+			}
+			if !more {
+				chunk.Final = true
+				break
+			}
+
+			entry, err := it.Value()
+			if err != nil {
+				return err
+			}
+			chunk.WriteLog = append(chunk.WriteLog, entry)
+		}
+
+		if err := fn(chunk); err != nil {
+			return err
+		}
+		if chunk.Final {
+			return nil
+		}
+	}
+}
+
 // LocalBackend is a storage implementation with a local backing store.
 type LocalBackend interface {
 	Backend
@@ -337,6 +477,11 @@ type LocalBackend interface {
 	// HasRoot checks if the storage backend contains the specified storage root.
 	HasRoot(root Root) bool
 
+	// HasRoots checks which of the specified storage roots the storage
+	// backend contains. The i-th element of the result is true iff the
+	// i-th root is present.
+	HasRoots(roots []Root) []bool
+
 	// Finalize finalizes the specified round. The passed list of roots are the
 	// roots within the round that have been finalized. All non-finalized roots
 	// can be discarded.
@@ -346,6 +491,13 @@ type LocalBackend interface {
 	//
 	// Returns the number of pruned nodes.
 	Prune(ctx context.Context, namespace common.Namespace, round uint64) (int, error)
+
+	// Verify checks the integrity of the finalized roots stored under the
+	// requested namespace and round by walking all reachable nodes and
+	// recomputing their hashes. The stored state is not modified.
+	//
+	// Returns the list of missing or corrupted nodes.
+	Verify(ctx context.Context, request *VerifyRequest) ([]*VerifyIssue, error)
 }
 
 // ClientBackend is a storage client backend implementation.
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/DataDog/zstd"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+)
+
+// maxDecompressedWriteLogSize is the maximum size of a decompressed write
+// log chunk. It matches the maximum gRPC message size, so compressed chunks
+// can not be larger than uncompressed chunks once decompressed.
+var maxDecompressedWriteLogSize int64 = 100 * 1024 * 1024
+
+// CompressionType is the type of compression applied to write log chunks
+// sent during GetDiff and GetCheckpoint operations.
+type CompressionType uint8
+
+const (
+	// CompressionNone means that write log chunks are not compressed.
+	CompressionNone CompressionType = 0
+	// CompressionZstd means that write log chunks are compressed using
+	// zstd.
+	CompressionZstd CompressionType = 1
+)
+
+// String returns a string representation of the compression type.
+func (c CompressionType) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("[unknown compression type: %d]", c)
+	}
+}
+
+// IsSupported returns true iff the compression type is supported.
+func (c CompressionType) IsSupported() bool {
+	switch c {
+	case CompressionNone, CompressionZstd:
+		return true
+	default:
+		return false
+	}
+}
+
+// compressWriteLog serializes and compresses a write log.
+func compressWriteLog(c CompressionType, writeLog WriteLog) ([]byte, error) {
+	switch c {
+	case CompressionZstd:
+		return zstd.Compress(nil, cbor.Marshal(writeLog))
+	default:
+		return nil, fmt.Errorf("storage: unsupported compression type: %s", c)
+	}
+}
+
+// decompressWriteLog decompresses and deserializes a write log.
+func decompressWriteLog(c CompressionType, data []byte) (WriteLog, error) {
+	var r io.ReadCloser
+	switch c {
+	case CompressionZstd:
+		r = zstd.NewReader(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("storage: unsupported compression type: %s", c)
+	}
+	defer r.Close()
+
+	// Do not trust the sender and bound the size of the decompressed data.
+	raw, err := ioutil.ReadAll(io.LimitReader(r, maxDecompressedWriteLogSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("storage: failed to decompress write log: %w", err)
+	}
+	if int64(len(raw)) > maxDecompressedWriteLogSize {
+		return nil, fmt.Errorf("storage: decompressed write log exceeds %d bytes", maxDecompressedWriteLogSize)
+	}
+
+	var writeLog WriteLog
+	if err := cbor.Unmarshal(raw, &writeLog); err != nil {
+		return nil, fmt.Errorf("storage: malformed compressed write log: %w", err)
+	}
+	return writeLog, nil
+}
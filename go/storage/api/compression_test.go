@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/writelog"
+)
+
+type testSyncBackend struct {
+	Backend
+
+	writeLog WriteLog
+}
+
+func (b *testSyncBackend) GetDiff(ctx context.Context, request *GetDiffRequest) (WriteLogIterator, error) {
+	return writelog.NewStaticIterator(b.writeLog), nil
+}
+
+func (b *testSyncBackend) GetCheckpoint(ctx context.Context, request *GetCheckpointRequest) (WriteLogIterator, error) {
+	return writelog.NewStaticIterator(b.writeLog), nil
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+
+	chunks []*SyncChunk
+}
+
+func (s *testServerStream) SendMsg(m interface{}) error {
+	s.chunks = append(s.chunks, m.(*SyncChunk))
+	return nil
+}
+
+func readWriteLog(t *testing.T, it WriteLogIterator) WriteLog {
+	require := require.New(t)
+
+	writeLog := WriteLog{}
+	for {
+		more, err := it.Next()
+		require.NoError(err, "Next")
+		if !more {
+			break
+		}
+		entry, err := it.Value()
+		require.NoError(err, "Value")
+		writeLog = append(writeLog, entry)
+	}
+	return writeLog
+}
+
+func TestSyncCompression(t *testing.T) {
+	require := require.New(t)
+
+	var writeLog WriteLog
+	for i := 0; i < 2*WriteLogIteratorChunkSize+1; i++ {
+		writeLog = append(writeLog, LogEntry{
+			Key:   []byte(fmt.Sprintf("key %d", i)),
+			Value: []byte(fmt.Sprintf("a fairly compressible value for key %d", i)),
+		})
+	}
+
+	// Compressed chunks should be smaller and carry no plain entries.
+	plainStream := &testServerStream{}
+	err := sendWriteLogIterator(writelog.NewStaticIterator(writeLog), &SyncOptions{}, plainStream)
+	require.NoError(err, "sendWriteLogIterator")
+	compressedStream := &testServerStream{}
+	err = sendWriteLogIterator(writelog.NewStaticIterator(writeLog), &SyncOptions{Compression: CompressionZstd}, compressedStream)
+	require.NoError(err, "sendWriteLogIterator(compressed)")
+	require.Len(compressedStream.chunks, len(plainStream.chunks), "compression should not change the chunking")
+	for i, chunk := range compressedStream.chunks {
+		require.Equal(CompressionZstd, chunk.Compression, "chunks should be compressed")
+		require.Nil(chunk.WriteLog, "compressed chunks should not carry plain entries")
+		if len(plainStream.chunks[i].WriteLog) == WriteLogIteratorChunkSize {
+			require.True(len(chunk.CompressedWriteLog) < len(cbor.Marshal(plainStream.chunks[i].WriteLog)), "compressed full chunks should be smaller")
+		}
+	}
+
+	// Unsupported compression falls back to uncompressed chunks.
+	fallbackStream := &testServerStream{}
+	err = sendWriteLogIterator(writelog.NewStaticIterator(writeLog), &SyncOptions{Compression: CompressionType(42)}, fallbackStream)
+	require.NoError(err, "sendWriteLogIterator(unsupported)")
+	require.Equal(plainStream.chunks, fallbackStream.chunks, "unsupported compression should fall back to uncompressed chunks")
+
+	// Serve the backend over a local socket.
+	dir, err := ioutil.TempDir("", "oasis-storage-compression-test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "storage.sock")
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name: "storage",
+		Path: socketPath,
+	})
+	require.NoError(err, "NewServer")
+	RegisterService(grpcServer.Server(), &testSyncBackend{writeLog: writeLog})
+	err = grpcServer.Start()
+	require.NoError(err, "Start")
+	defer grpcServer.Stop()
+
+	conn, err := cmnGrpc.Dial("unix:"+socketPath, grpc.WithInsecure())
+	require.NoError(err, "Dial")
+	defer conn.Close()
+	client := NewStorageClient(conn)
+
+	// Compressed and uncompressed transfers should reconstruct identical
+	// write logs.
+	ctx := context.Background()
+	for _, compression := range []CompressionType{CompressionNone, CompressionZstd} {
+		opts := SyncOptions{Compression: compression}
+
+		it, err := client.GetDiff(ctx, &GetDiffRequest{Options: opts})
+		require.NoError(err, "GetDiff(%s)", compression)
+		require.Equal(writeLog, readWriteLog(t, it), "GetDiff(%s) should reconstruct the write log", compression)
+
+		it, err = client.GetCheckpoint(ctx, &GetCheckpointRequest{Options: opts})
+		require.NoError(err, "GetCheckpoint(%s)", compression)
+		require.Equal(writeLog, readWriteLog(t, it), "GetCheckpoint(%s) should reconstruct the write log", compression)
+	}
+}
+
+func TestDecompressWriteLogLimit(t *testing.T) {
+	require := require.New(t)
+
+	writeLog := WriteLog{{Key: []byte("key"), Value: make([]byte, 1024*1024)}}
+	compressed, err := compressWriteLog(CompressionZstd, writeLog)
+	require.NoError(err, "compressWriteLog")
+
+	decompressed, err := decompressWriteLog(CompressionZstd, compressed)
+	require.NoError(err, "decompressWriteLog")
+	require.Equal(writeLog, decompressed, "decompressWriteLog should reconstruct the write log")
+
+	// Chunks which decompress to more than the limit should be rejected.
+	oldMax := maxDecompressedWriteLogSize
+	defer func() { maxDecompressedWriteLogSize = oldMax }()
+	maxDecompressedWriteLogSize = 1024
+
+	_, err = decompressWriteLog(CompressionZstd, compressed)
+	require.Error(err, "decompressWriteLog should reject oversized write logs")
+}
@@ -16,11 +16,11 @@ var (
 	serviceName = cmnGrpc.NewServiceName("Storage")
 
 	// methodSyncGet is the name of the SyncGet method.
-	methodSyncGet = serviceName.NewMethodName("SyncGet")
+	methodSyncGet = serviceName.NewMethodName("SyncGet").WithIdempotent(true)
 	// methodSyncGetPrefixes is the name of the SyncGetPrefixes method.
-	methodSyncGetPrefixes = serviceName.NewMethodName("SyncGetPrefixes")
+	methodSyncGetPrefixes = serviceName.NewMethodName("SyncGetPrefixes").WithIdempotent(true)
 	// methodSyncIterate is the name of the SyncIterate method.
-	methodSyncIterate = serviceName.NewMethodName("SyncIterate")
+	methodSyncIterate = serviceName.NewMethodName("SyncIterate").WithIdempotent(true)
 	// methodApply is the name of the Apply method.
 	methodApply = serviceName.NewMethodName("Apply")
 	// methodApplyBatch is the name of the ApplyBatch method.
@@ -31,9 +31,11 @@ var (
 	methodMergeBatch = serviceName.NewMethodName("MergeBatch")
 
 	// methodGetDiff is the name of the GetDiff method.
-	methodGetDiff = serviceName.NewMethodName("GetDiff")
+	methodGetDiff = serviceName.NewMethodName("GetDiff").WithIdempotent(true)
 	// methodGetCheckpoint is the name of the GetCheckpoint method.
-	methodGetCheckpoint = serviceName.NewMethodName("GetCheckpoint")
+	methodGetCheckpoint = serviceName.NewMethodName("GetCheckpoint").WithIdempotent(true)
+	// methodGetDiffRange is the name of the GetDiffRange method.
+	methodGetDiffRange = serviceName.NewMethodName("GetDiffRange").WithIdempotent(true)
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -80,6 +82,11 @@ var (
 				Handler:       handlerGetCheckpoint,
 				ServerStreams: true,
 			},
+			{
+				StreamName:    methodGetDiffRange.Short(),
+				Handler:       handlerGetDiffRange,
+				ServerStreams: true,
+			},
 		},
 	}
 )
@@ -294,6 +301,15 @@ func sendWriteLogIterator(it WriteLogIterator, opts *SyncOptions, stream grpc.Se
 			Final:    final,
 			WriteLog: entryArray,
 		}
+		if opts.Compression != CompressionNone && opts.Compression.IsSupported() {
+			compressed, err := compressWriteLog(opts.Compression, entryArray)
+			if err != nil {
+				return err
+			}
+			chunk.WriteLog = nil
+			chunk.Compression = opts.Compression
+			chunk.CompressedWriteLog = compressed
+		}
 
 		if err := stream.SendMsg(chunk); err != nil {
 			return err
@@ -337,11 +353,30 @@ func handlerGetCheckpoint(srv interface{}, stream grpc.ServerStream) error {
 	return sendWriteLogIterator(it, &req.Options, stream)
 }
 
+func handlerGetDiffRange(srv interface{}, stream grpc.ServerStream) error {
+	var req GetDiffRangeRequest
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+
+	// Only some backends are able to serve diffs spanning multiple rounds.
+	backend, ok := srv.(DiffRangeBackend)
+	if !ok {
+		return ErrUnsupported
+	}
+
+	return backend.GetDiffRange(stream.Context(), &req, func(chunk *DiffRangeChunk) error {
+		return stream.SendMsg(chunk)
+	})
+}
+
 // RegisterService registers a new sentry service with the given gRPC server.
 func RegisterService(server *grpc.Server, service Backend) {
 	server.RegisterService(&serviceDesc, service)
 }
 
+var _ DiffRangeBackend = (*storageClient)(nil)
+
 type storageClient struct {
 	conn *grpc.ClientConn
 }
@@ -418,6 +453,12 @@ func receiveWriteLogIterator(ctx context.Context, stream grpc.ClientStream) Writ
 				_ = pipe.PutError(err)
 				continue
 			}
+			if chunk.Compression != CompressionNone {
+				if chunk.WriteLog, err = decompressWriteLog(chunk.Compression, chunk.CompressedWriteLog); err != nil {
+					_ = pipe.PutError(err)
+					break
+				}
+			}
 
 			for i := range chunk.WriteLog {
 				if err := pipe.Put(&chunk.WriteLog[i]); err != nil {
@@ -464,6 +505,38 @@ func (c *storageClient) GetCheckpoint(ctx context.Context, request *GetCheckpoin
 	return receiveWriteLogIterator(ctx, stream), nil
 }
 
+func (c *storageClient) GetDiffRange(ctx context.Context, request *GetDiffRangeRequest, fn func(*DiffRangeChunk) error) error {
+	// Make sure the stream is torn down in case fn aborts early.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.conn.NewStream(ctx, &serviceDesc.Streams[2], methodGetDiffRange.Full())
+	if err != nil {
+		return err
+	}
+	if err = stream.SendMsg(request); err != nil {
+		return err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		var chunk DiffRangeChunk
+		err = stream.RecvMsg(&chunk)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = fn(&chunk); err != nil {
+			return err
+		}
+	}
+}
+
 func (c *storageClient) Cleanup() {
 }
 
@@ -472,6 +545,8 @@ func (c *storageClient) Initialized() <-chan struct{} {
 }
 
 // NewStorageClient creates a new gRPC storage client service.
+//
+// The returned client also implements DiffRangeBackend.
 func NewStorageClient(c *grpc.ClientConn) Backend {
 	return &storageClient{c}
 }
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+)
+
+func TestMethodIdempotency(t *testing.T) {
+	require := require.New(t)
+
+	for _, m := range []*cmnGrpc.MethodName{
+		methodSyncGet,
+		methodSyncGetPrefixes,
+		methodSyncIterate,
+		methodGetDiff,
+		methodGetCheckpoint,
+		methodGetDiffRange,
+	} {
+		require.True(m.IsIdempotent(), "read-only methods should be idempotent")
+	}
+
+	for _, m := range []*cmnGrpc.MethodName{
+		methodApply,
+		methodApplyBatch,
+		methodMerge,
+		methodMergeBatch,
+	} {
+		require.False(m.IsIdempotent(), "write methods should not be idempotent")
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -129,7 +131,14 @@ func (rc *RootCache) Apply(
 		r = dstRoot
 	} else {
 		// We don't, apply operations.
-		tree := urkel.NewWithRoot(rc.remoteSyncer, rc.localDB, root, rc.persistEverything)
+		var tree *urkel.Tree
+		if root.Hash.IsEmpty() {
+			// Fast path: build the tree from scratch.
+			tree = urkel.NewWithRoot(nil, rc.localDB, root)
+			writeLog = bulkWriteLog(writeLog)
+		} else {
+			tree = urkel.NewWithRoot(rc.remoteSyncer, rc.localDB, root, rc.persistEverything)
+		}
 		defer tree.Close()
 
 		if err := tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog)); err != nil {
@@ -158,6 +167,31 @@ func (rc *RootCache) Apply(
 	return &r, nil
 }
 
+// bulkWriteLog converts a write log that is to be applied to an empty root
+// into an equivalent write log that only contains the final value of each
+// inserted key, sorted by key.
+//
+// As there is nothing to remove from an empty tree, this avoids redundant
+// tree updates, and inserting keys in order keeps the updated paths local.
+func bulkWriteLog(writeLog WriteLog) WriteLog {
+	final := make(map[string][]byte, len(writeLog))
+	for _, entry := range writeLog {
+		final[string(entry.Key)] = entry.Value
+	}
+
+	bulk := make(WriteLog, 0, len(final))
+	for key, value := range final {
+		if len(value) == 0 {
+			continue
+		}
+		bulk = append(bulk, LogEntry{Key: []byte(key), Value: value})
+	}
+	sort.Slice(bulk, func(i, j int) bool {
+		return bytes.Compare(bulk[i].Key, bulk[j].Key) < 0
+	})
+	return bulk
+}
+
 func (rc *RootCache) getApplyLock(root, expectedNewRoot Root) *sync.Mutex {
 	// Lock the Apply call based on (oldRoot, expectedNewRoot), so that when
 	// multiple executor committees commit the same write logs, we only write
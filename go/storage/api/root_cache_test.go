@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel"
+	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/api"
+	memoryDb "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/memory"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/writelog"
+)
+
+func TestRootCacheApplyEmptyRoot(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	ns := common.NewTestNamespaceFromSeed([]byte("root cache apply empty root test ns"))
+	var emptyHash hash.Hash
+	emptyHash.Empty()
+	root := Root{Namespace: ns, Round: 0, Hash: emptyHash}
+
+	// Include overwritten keys and removals to make sure the fast path
+	// only applies the final state.
+	var writeLog WriteLog
+	for i := 0; i < 1000; i++ {
+		writeLog = append(writeLog, LogEntry{
+			Key:   []byte(fmt.Sprintf("key %d", 999-i)),
+			Value: []byte(fmt.Sprintf("value %d", i)),
+		})
+	}
+	for i := 0; i < 100; i++ {
+		writeLog = append(writeLog,
+			LogEntry{Key: []byte(fmt.Sprintf("key %d", i)), Value: []byte("overwritten")},
+			LogEntry{Key: []byte(fmt.Sprintf("key %d", 500+i))},
+			LogEntry{Key: []byte(fmt.Sprintf("missing %d", i))},
+		)
+	}
+
+	// Compute the expected root using the generic path.
+	genericDB, err := memoryDb.New(&nodedb.Config{Namespace: ns})
+	require.NoError(err, "memoryDb.New")
+	tree := urkel.NewWithRoot(nil, genericDB, root)
+	defer tree.Close()
+	err = tree.ApplyWriteLog(ctx, writelog.NewStaticIterator(writeLog))
+	require.NoError(err, "ApplyWriteLog")
+	expectedLog, expectedHash, err := tree.Commit(ctx, ns, 1)
+	require.NoError(err, "Commit")
+
+	localDB, err := memoryDb.New(&nodedb.Config{Namespace: ns})
+	require.NoError(err, "memoryDb.New")
+	rc, err := NewRootCache(localDB, nil, 10, false)
+	require.NoError(err, "NewRootCache")
+
+	newHash, err := rc.Apply(ctx, ns, 0, emptyHash, 1, expectedHash, writeLog)
+	require.NoError(err, "Apply")
+	require.Equal(expectedHash, *newHash, "fast path root should match the generic path")
+
+	newRoot := Root{Namespace: ns, Round: 1, Hash: expectedHash}
+	require.True(rc.HasRoot(newRoot), "new root should be stored")
+	it, err := localDB.GetWriteLog(ctx, Root{Namespace: ns, Round: 1, Hash: emptyHash}, newRoot)
+	require.NoError(err, "GetWriteLog")
+	var storedLog WriteLog
+	for {
+		more, ierr := it.Next()
+		require.NoError(ierr, "Next")
+		if !more {
+			break
+		}
+		entry, ierr := it.Value()
+		require.NoError(ierr, "Value")
+		storedLog = append(storedLog, entry)
+	}
+	require.ElementsMatch(expectedLog, storedLog, "stored write log should match the generic path")
+
+	tree = urkel.NewWithRoot(nil, localDB, newRoot)
+	defer tree.Close()
+	value, err := tree.Get(ctx, []byte("key 0"))
+	require.NoError(err, "Get")
+	require.EqualValues("overwritten", value, "overwritten keys should have the final value")
+	value, err = tree.Get(ctx, []byte("key 500"))
+	require.NoError(err, "Get")
+	require.Nil(value, "removed keys should not exist")
+
+	// A mismatching root must still be rejected.
+	var badHash hash.Hash
+	badHash.FromBytes([]byte("not the expected root"))
+	_, err = rc.Apply(ctx, ns, 0, emptyHash, 1, badHash, writeLog)
+	require.Equal(ErrExpectedRootMismatch, err, "Apply should reject mismatching roots")
+}
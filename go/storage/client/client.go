@@ -29,6 +29,9 @@ var (
 var (
 	// ErrStorageNotAvailable is the error returned when no storage node is available.
 	ErrStorageNotAvailable = errors.New("storage/client: storage not available")
+	// ErrInitTimeout is the error returned when no storage node connection
+	// could be created within the warm-up timeout.
+	ErrInitTimeout = errors.New("storage/client: timed out connecting to storage nodes")
 )
 
 const (
@@ -83,6 +86,9 @@ func (b *storageClientBackend) writeWithClient(
 	expectedNewRoots []hash.Hash,
 ) ([]*api.Receipt, error) {
 	runtimeID := b.getRequestRuntime(ns)
+	if err := b.runtimeWatcher.initError(); err != nil {
+		return nil, err
+	}
 	clientStates := b.runtimeWatcher.getClientStates()
 	n := len(clientStates)
 	if n == 0 {
@@ -289,6 +295,9 @@ func (b *storageClientBackend) readWithClient(
 	fn func(context.Context, api.Backend) (interface{}, error),
 ) (interface{}, error) {
 	runtimeID := b.getRequestRuntime(ns)
+	if err := b.runtimeWatcher.initError(); err != nil {
+		return nil, err
+	}
 	clientStates := b.runtimeWatcher.getClientStates()
 	n := len(clientStates)
 	if n == 0 {
@@ -3,6 +3,9 @@ package client
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -14,6 +17,7 @@ import (
 	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
 	"github.com/oasislabs/oasis-core/go/common/identity"
 	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
 	cmdFlags "github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
@@ -30,6 +34,17 @@ const (
 
 	// CfgDebugClientCert is the path to the certificate file for grpc.
 	CfgDebugClientCert = "storage.debug.client.certificate"
+
+	// CfgStaticNodes is the list of paths to signed storage node descriptors
+	// that the storage client should connect to instead of following the
+	// scheduled storage committees.
+	CfgStaticNodes = "storage.client.static_nodes"
+
+	// CfgInitTimeout is the storage client warm-up timeout after which
+	// initialization is signalled even if no storage node connections could be
+	// created. Requests fail with ErrInitTimeout until a connection is
+	// created. Zero disables the timeout.
+	CfgInitTimeout = "storage.client.init_timeout"
 )
 
 // In debug mode, we connect to the provided node and save it to the fake runtime.
@@ -92,14 +107,39 @@ func New(
 			client: client,
 			conn:   conn,
 		}
-		b.runtimeWatcher = newDebugWatcher(state)
+		b.runtimeWatcher = newStaticWatcher([]*clientState{state})
+		return b, nil
+	}
+
+	if paths := viper.GetStringSlice(CfgStaticNodes); len(paths) > 0 {
+		nodes, err := loadStaticNodes(paths)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("storage client connecting to static storage nodes",
+			"nodes", nodes,
+		)
+
+		watcher, err := newStaticNodeWatcher(ident, nodes)
+		if err != nil {
+			return nil, err
+		}
+
+		b := &storageClientBackend{
+			ctx:            ctx,
+			logger:         logger,
+			runtimeWatcher: watcher,
+		}
+		b.haltCtx, b.cancelFn = context.WithCancel(ctx)
+
 		return b, nil
 	}
 
 	b := &storageClientBackend{
 		ctx:            ctx,
 		logger:         logger,
-		runtimeWatcher: newWatcher(ctx, namespace, ident, schedulerBackend, registryBackend),
+		runtimeWatcher: newWatcher(ctx, namespace, ident, schedulerBackend, registryBackend, viper.GetDuration(CfgInitTimeout)),
 	}
 
 	b.haltCtx, b.cancelFn = context.WithCancel(ctx)
@@ -107,9 +147,36 @@ func New(
 	return b, nil
 }
 
+func loadStaticNodes(paths []string) ([]*node.Node, error) {
+	var nodes []*node.Node
+	for _, path := range paths {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("storage/client: failed to load static node descriptor: %w", err)
+		}
+
+		var signedNode node.SignedNode
+		if err = json.Unmarshal(raw, &signedNode); err != nil {
+			return nil, fmt.Errorf("storage/client: failed to parse static node descriptor %s: %w", path, err)
+		}
+		var n node.Node
+		if err = signedNode.Open(registry.RegisterNodeSignatureContext, &n); err != nil {
+			return nil, fmt.Errorf("storage/client: invalid static node descriptor %s: %w", path, err)
+		}
+		if !n.HasRoles(node.RoleStorageWorker) {
+			return nil, fmt.Errorf("storage/client: static node %s is not a storage node", n.ID)
+		}
+
+		nodes = append(nodes, &n)
+	}
+	return nodes, nil
+}
+
 func init() {
 	Flags.String(CfgDebugClientAddress, "", "Address of node to connect to with the storage client")
 	Flags.String(CfgDebugClientCert, "", "Path to tls certificate for grpc")
+	Flags.StringSlice(CfgStaticNodes, []string{}, "Paths to signed descriptors of static storage nodes to connect to")
+	Flags.Duration(CfgInitTimeout, 0, "Storage node connection warm-up timeout (0 disables)")
 
 	_ = Flags.MarkHidden(CfgDebugClientAddress)
 	_ = Flags.MarkHidden(CfgDebugClientCert)
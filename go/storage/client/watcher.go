@@ -4,7 +4,10 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -24,6 +27,10 @@ import (
 	storage "github.com/oasislabs/oasis-core/go/storage/api"
 )
 
+// nodeRetryMaxAttempts is the maximum number of attempts for idempotent
+// requests to a single storage node.
+const nodeRetryMaxAttempts = 3
+
 // DialOptionForNode creates a grpc.DialOption for communicating under the node's certificate.
 func DialOptionForNode(ourCerts []tls.Certificate, node *node.Node) (grpc.DialOption, error) {
 	nodeCert, err := node.Committee.ParseCertificate()
@@ -44,7 +51,14 @@ func DialOptionForNode(ourCerts []tls.Certificate, node *node.Node) (grpc.DialOp
 func DialNode(node *node.Node, opts grpc.DialOption) (*grpc.ClientConn, func(), error) {
 	manualResolver, address, cleanupCb := manual.NewManualResolver()
 
-	conn, err := cmnGrpc.Dial(address, opts, grpc.WithBalancerName(roundrobin.Name)) //nolint: staticcheck
+	conn, err := cmnGrpc.Dial(
+		address,
+		opts,
+		grpc.WithBalancerName(roundrobin.Name), //nolint: staticcheck
+		// Retry idempotent requests on transient failures, but keep the
+		// number of attempts low as reads fall back to other nodes anyway.
+		cmnGrpc.WithClientRetry(&cmnGrpc.RetryConfig{MaxAttempts: nodeRetryMaxAttempts}),
+	)
 	if err != nil {
 		cleanupCb()
 		return nil, nil, errors.Wrap(err, "failed dialing node")
@@ -63,40 +77,88 @@ type storageWatcher interface {
 	getClientStates() []clientState
 	cleanup()
 	initialized() <-chan struct{}
+	initError() error
 }
 
-// debugWatcherState is a state with a fixed storage node.
-type debugWatcherState struct {
-	clientState *clientState
-	initCh      chan struct{}
+// staticWatcherState is a state with a fixed set of storage nodes.
+type staticWatcherState struct {
+	clientStates []*clientState
+	initCh       chan struct{}
 }
 
-func (w *debugWatcherState) getConnectedNodes() []*node.Node {
-	return []*node.Node{}
+func (w *staticWatcherState) getConnectedNodes() []*node.Node {
+	connectedNodes := []*node.Node{}
+	for _, state := range w.clientStates {
+		// Nodes connected to by address only have no descriptor.
+		if state.node != nil {
+			connectedNodes = append(connectedNodes, state.node)
+		}
+	}
+	return connectedNodes
 }
 
-func (w *debugWatcherState) getClientStates() []clientState {
-	return []clientState{*w.clientState}
+func (w *staticWatcherState) getClientStates() []clientState {
+	clientStates := []clientState{}
+	for _, state := range w.clientStates {
+		clientStates = append(clientStates, *state)
+	}
+	return clientStates
 }
-func (w *debugWatcherState) cleanup() {
+
+func (w *staticWatcherState) cleanup() {
+	for _, state := range w.clientStates {
+		state.close()
+	}
 }
-func (w *debugWatcherState) initialized() <-chan struct{} {
+
+func (w *staticWatcherState) initialized() <-chan struct{} {
 	return w.initCh
 }
 
-func newDebugWatcher(state *clientState) storageWatcher {
+func (w *staticWatcherState) initError() error {
+	return nil
+}
+
+func newStaticWatcher(states []*clientState) storageWatcher {
 	initCh := make(chan struct{})
 	close(initCh)
-	return &debugWatcherState{
-		initCh:      initCh,
-		clientState: state,
+	return &staticWatcherState{
+		initCh:       initCh,
+		clientStates: states,
+	}
+}
+
+// newStaticNodeWatcher creates a watcher connected to the given fixed set of
+// storage nodes instead of following the scheduled storage committee.
+func newStaticNodeWatcher(identity *identity.Identity, nodes []*node.Node) (storageWatcher, error) {
+	var states []*clientState
+	for _, n := range nodes {
+		// But prevent connecting to self.
+		if identity != nil && n.ID.Equal(identity.NodeSigner.Public()) {
+			continue
+		}
+
+		state, err := connectNode(identity, n)
+		if err != nil {
+			for _, state = range states {
+				state.close()
+			}
+			return nil, fmt.Errorf("storage/client: failed to connect to static node %s: %w", n.ID, err)
+		}
+		states = append(states, state)
 	}
+	if len(states) == 0 {
+		return nil, fmt.Errorf("storage/client: no static storage nodes to connect to")
+	}
+
+	return newStaticWatcher(states), nil
 }
 
 // watcherState contains storage watcher state.
 type watcherState struct {
 	sync.RWMutex
 
+	ctx    context.Context
 	logger *logging.Logger
 
 	scheduler scheduler.Backend
@@ -108,9 +170,19 @@ type watcherState struct {
 
 	registeredStorageNodes []*node.Node
 	scheduledNodes         map[signature.PublicKey]bool
-	clientStates           []*clientState
+
+	// updateLock serializes connection updates.
+	updateLock sync.Mutex
+	// clientStates is an immutable snapshot ([]*clientState) of the
+	// current storage node connections. Connection updates build a new
+	// snapshot and atomically swap it in so that they never block
+	// requests.
+	clientStates atomic.Value
+	// connectFn opens a connection to a storage node.
+	connectFn func(*identity.Identity, *node.Node) (*clientState, error)
 
 	initCh       chan struct{}
+	initErr      error
 	signaledInit bool
 }
 
@@ -122,17 +194,52 @@ type clientState struct {
 	resolverCleanupCb func()
 }
 
+func (s *clientState) close() {
+	if callBack := s.resolverCleanupCb; callBack != nil {
+		callBack()
+	}
+	if s.conn != nil {
+		s.conn.Close()
+	}
+}
+
+// connectNode opens a connection to the given storage node.
+func connectNode(identity *identity.Identity, n *node.Node) (*clientState, error) {
+	if n.Committee.Certificate == nil {
+		return nil, fmt.Errorf("node registered without certificate")
+	}
+	if len(n.Committee.Addresses) == 0 {
+		return nil, fmt.Errorf("node does not have any addresses")
+	}
+
+	var ourCerts []tls.Certificate
+	if identity != nil {
+		ourCerts = []tls.Certificate{*identity.TLSCertificate}
+	}
+	opts, err := DialOptionForNode(ourCerts, n)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, cleanupCb, err := DialNode(n, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientState{
+		node:              n,
+		client:            storage.NewStorageClient(conn),
+		conn:              conn,
+		resolverCleanupCb: cleanupCb,
+	}, nil
+}
+
 func (w *watcherState) cleanup() {
-	w.Lock()
-	defer w.Unlock()
+	w.updateLock.Lock()
+	defer w.updateLock.Unlock()
 
-	for _, clientState := range w.clientStates {
-		if callBack := clientState.resolverCleanupCb; callBack != nil {
-			callBack()
-		}
-		if clientState.conn != nil {
-			clientState.conn.Close()
-		}
+	for _, clientState := range w.loadClientStates() {
+		clientState.close()
 	}
 }
 
@@ -140,53 +247,83 @@ func (w *watcherState) initialized() <-chan struct{} {
 	return w.initCh
 }
 
-func (w *watcherState) getConnectedNodes() []*node.Node {
+func (w *watcherState) initError() error {
 	w.RLock()
 	defer w.RUnlock()
+	return w.initErr
+}
 
+// signalInit signals initialization, either because connections to storage
+// nodes have been created (err is nil) or because the warm-up timeout
+// expired without any connections (err is ErrInitTimeout).
+func (w *watcherState) signalInit(err error) {
+	w.Lock()
+	defer w.Unlock()
+
+	if err != nil && w.signaledInit {
+		// Already connected (or timed out) before.
+		return
+	}
+	w.initErr = err
+	if !w.signaledInit {
+		w.signaledInit = true
+		close(w.initCh)
+	}
+}
+
+// awaitInitTimeout signals initialization with an error in case no storage
+// node connections are created within the given timeout.
+func (w *watcherState) awaitInitTimeout(timeout time.Duration) {
+	select {
+	case <-w.initCh:
+	case <-time.After(timeout):
+		w.logger.Error("no storage node connections created within the warm-up timeout",
+			"timeout", timeout,
+		)
+		w.signalInit(ErrInitTimeout)
+	case <-w.ctx.Done():
+	}
+}
+
+// loadClientStates returns the current snapshot of storage node
+// connections, which must not be modified.
+func (w *watcherState) loadClientStates() []*clientState {
+	return w.clientStates.Load().([]*clientState)
+}
+
+func (w *watcherState) getConnectedNodes() []*node.Node {
 	connectedNodes := []*node.Node{}
-	for _, state := range w.clientStates {
+	for _, state := range w.loadClientStates() {
 		connectedNodes = append(connectedNodes, state.node)
 	}
 	return connectedNodes
 }
 
 func (w *watcherState) getClientStates() []clientState {
-	w.RLock()
-	defer w.RUnlock()
 	clientStates := []clientState{}
-	for _, state := range w.clientStates {
+	for _, state := range w.loadClientStates() {
 		clientStates = append(clientStates, *state)
 	}
 	return clientStates
 }
+
 func (w *watcherState) updateStorageNodeConnections() {
-	// XXX: This lock blocks requests to nodes for this runtime.
-	w.Lock()
-	defer w.Unlock()
+	w.updateLock.Lock()
+	defer w.updateLock.Unlock()
 
 	w.logger.Debug("updating connections to storage nodes")
 
+	w.RLock()
 	nodeList := []*node.Node{}
 	for _, node := range w.registeredStorageNodes {
 		if w.scheduledNodes[node.ID] {
 			nodeList = append(nodeList, node)
 		}
 	}
+	w.RUnlock()
 
 	// TODO: Should we only update connections if keys or addresses have changed?
 
-	// Clean-up previous resolvers and connections.
-	for _, states := range w.clientStates {
-		if cleanup := states.resolverCleanupCb; cleanup != nil {
-			cleanup()
-		}
-		if states.conn != nil {
-			states.conn.Close()
-		}
-	}
-	w.clientStates = nil
-
 	connClientStates := []*clientState{}
 	numConnNodes := 0
 
@@ -197,30 +334,7 @@ func (w *watcherState) updateStorageNodeConnections() {
 			continue
 		}
 
-		if node.Committee.Certificate == nil {
-			w.logger.Warn("storage committee member registered without certificate, ignoring node",
-				"member", node)
-			continue
-		}
-
-		var err error
-		opts, err := DialOptionForNode([]tls.Certificate{*w.identity.TLSCertificate}, node)
-		if err != nil {
-			w.logger.Error("failed to get GRPC dial options for storage committee member",
-				"member", node,
-				"err", err,
-			)
-			continue
-		}
-
-		if len(node.Committee.Addresses) == 0 {
-			w.logger.Error("cannot update connection, storage committee member does not have any addresses",
-				"member", node,
-			)
-			continue
-		}
-
-		conn, cleanupCb, err := DialNode(node, opts)
+		state, err := w.connectFn(w.identity, node)
 		if err != nil {
 			w.logger.Error("cannot update connection",
 				"node", node,
@@ -230,30 +344,28 @@ func (w *watcherState) updateStorageNodeConnections() {
 		}
 
 		numConnNodes++
-		connClientStates = append(connClientStates, &clientState{
-			node:              node,
-			client:            storage.NewStorageClient(conn),
-			conn:              conn,
-			resolverCleanupCb: cleanupCb,
-		})
+		connClientStates = append(connClientStates, state)
 		w.logger.Debug("storage node connection updated",
 			"node", node,
 		)
 	}
+	// Swap in the new connections and only then clean-up previous resolvers
+	// and connections.
+	prevClientStates := w.loadClientStates()
+	w.clientStates.Store(connClientStates)
+	for _, state := range prevClientStates {
+		state.close()
+	}
+
+	// Only signal initialization once the new connections are available, so
+	// that callers waiting for it never observe an empty set of connections.
 	if numConnNodes == 0 {
 		w.logger.Error("failed to connect to any of the storage committee members",
 			"nodes", nodeList,
 		)
-		return
+	} else {
+		w.signalInit(nil)
 	}
-
-	if !w.signaledInit {
-		w.signaledInit = true
-		close(w.initCh)
-	}
-
-	// Update client state.
-	w.clientStates = connClientStates
 }
 
 func (w *watcherState) updateRegisteredStorageNodes(nodes []*node.Node) {
@@ -355,10 +467,12 @@ func newWatcher(
 	identity *identity.Identity,
 	schedulerBackend scheduler.Backend,
 	registryBackend registry.Backend,
+	initTimeout time.Duration,
 ) storageWatcher {
 	logger := logging.GetLogger("storage/client/watcher").With("runtime_id", runtimeID.String())
 
 	watcher := &watcherState{
+		ctx:                    ctx,
 		initCh:                 make(chan struct{}),
 		logger:                 logger,
 		runtimeID:              runtimeID,
@@ -367,10 +481,14 @@ func newWatcher(
 		registry:               registryBackend,
 		registeredStorageNodes: []*node.Node{},
 		scheduledNodes:         make(map[signature.PublicKey]bool),
-		clientStates:           []*clientState{},
+		connectFn:              connectNode,
 	}
+	watcher.clientStates.Store([]*clientState{})
 
 	go watcher.watch(ctx)
+	if initTimeout > 0 {
+		go watcher.awaitInitTimeout(initTimeout)
+	}
 
 	return watcher
 }
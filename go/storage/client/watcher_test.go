@@ -0,0 +1,316 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	cryptoTLS "crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	cmnGrpc "github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/identity"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	"github.com/oasislabs/oasis-core/go/common/node"
+	"github.com/oasislabs/oasis-core/go/common/pubsub"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	scheduler "github.com/oasislabs/oasis-core/go/scheduler/api"
+	"github.com/oasislabs/oasis-core/go/storage/api"
+)
+
+// rootBackend is a storage backend that serves proofs for a fixed root.
+type rootBackend struct {
+	api.Backend
+
+	root hash.Hash
+}
+
+func (b *rootBackend) SyncGet(ctx context.Context, request *api.GetRequest) (*api.ProofResponse, error) {
+	return &api.ProofResponse{Proof: api.Proof{UntrustedRoot: b.root}}, nil
+}
+
+// generateCert generates a TLS certificate for identity.CommonName which,
+// unlike tls.Generate, also includes it as a SAN so that it can be verified
+// by Go versions that ignore the common name.
+func generateCert(t *testing.T) *cryptoTLS.Certificate {
+	require := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err, "GenerateKey")
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: identity.CommonName},
+		DNSNames:     []string{identity.CommonName},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	require.NoError(err, "CreateCertificate")
+
+	return &cryptoTLS.Certificate{
+		Certificate: [][]byte{certDER},
+		PrivateKey:  key,
+	}
+}
+
+// startStaticNode starts a storage node serving the given backend and
+// writes its signed descriptor to the given directory.
+func startStaticNode(t *testing.T, dir string, name string, roles node.RolesMask, backend api.Backend) (string, func()) {
+	require := require.New(t)
+
+	cert := generateCert(t)
+	grpcServer, err := cmnGrpc.NewServer(&cmnGrpc.ServerConfig{
+		Name:        name,
+		Certificate: cert,
+	})
+	require.NoError(err, "NewServer")
+	srv := grpcServer.Server()
+	api.RegisterService(srv, backend)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err, "Listen")
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+
+	signer := memorySigner.NewTestSigner("storage client static node test " + name)
+	n := &node.Node{
+		ID:    signer.Public(),
+		Roles: roles,
+		Committee: node.CommitteeInfo{
+			Certificate: cert.Certificate[0],
+			Addresses:   []node.Address{{TCPAddr: *ln.Addr().(*net.TCPAddr)}},
+		},
+	}
+	signedNode, err := node.SignNode(signer, registry.RegisterNodeSignatureContext, n)
+	require.NoError(err, "SignNode")
+	raw, err := json.Marshal(signedNode)
+	require.NoError(err, "Marshal")
+
+	path := filepath.Join(dir, name+".json")
+	err = ioutil.WriteFile(path, raw, 0600)
+	require.NoError(err, "WriteFile")
+	return path, grpcServer.Stop
+}
+
+func TestStaticWatcher(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "oasis-storage-client-static-test")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	var root hash.Hash
+	root.FromBytes([]byte("storage client static watcher test root"))
+	var paths []string
+	for name, roles := range map[string]node.RolesMask{
+		"a": node.RoleStorageWorker,
+		"b": node.RoleStorageWorker | node.RoleComputeWorker,
+	} {
+		path, stop := startStaticNode(t, dir, name, roles, &rootBackend{root: root})
+		defer stop()
+		paths = append(paths, path)
+	}
+
+	viper.Set(CfgStaticNodes, paths)
+	defer viper.Set(CfgStaticNodes, []string{})
+
+	ctx := context.Background()
+	ns := common.NewTestNamespaceFromSeed([]byte("storage client static watcher test ns"))
+	client, err := New(ctx, ns, nil, nil, nil)
+	require.NoError(err, "New")
+	defer client.Cleanup()
+
+	// Static watchers should be initialized immediately.
+	select {
+	case <-client.Initialized():
+	default:
+		t.Fatalf("static watcher should be initialized")
+	}
+	require.Len(client.(api.ClientBackend).GetConnectedNodes(), 2, "all static nodes should be connected")
+
+	for i := 0; i < 4; i++ {
+		rsp, err := client.SyncGet(ctx, &api.GetRequest{
+			Tree: api.TreeID{Root: api.Root{Namespace: ns, Hash: root}, Position: root},
+		})
+		require.NoError(err, "SyncGet")
+		require.Equal(root, rsp.Proof.UntrustedRoot, "SyncGet should be served by a static node")
+	}
+
+	// Nodes without the storage role should be rejected.
+	path, stop := startStaticNode(t, dir, "c", node.RoleComputeWorker, &rootBackend{root: root})
+	defer stop()
+	_, err = loadStaticNodes(append(paths, path))
+	require.Error(err, "loadStaticNodes should reject non-storage nodes")
+}
+
+// fixedRegistry is a registry backend publishing a fixed node list.
+type fixedRegistry struct {
+	registry.Backend
+
+	nodes []*node.Node
+}
+
+func (r *fixedRegistry) WatchNodeList(ctx context.Context) (<-chan *registry.NodeList, pubsub.ClosableSubscription, error) {
+	ch := make(chan *registry.NodeList, 1)
+	ch <- &registry.NodeList{Nodes: r.nodes}
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return ch, sub, nil
+}
+
+// fixedScheduler is a scheduler backend publishing a fixed committee.
+type fixedScheduler struct {
+	scheduler.Backend
+
+	committee *scheduler.Committee
+}
+
+func (s *fixedScheduler) WatchCommittees(ctx context.Context) (<-chan *scheduler.Committee, pubsub.ClosableSubscription, error) {
+	ch := make(chan *scheduler.Committee, 1)
+	ch <- s.committee
+	_, sub := pubsub.NewContextSubscription(ctx)
+	return ch, sub, nil
+}
+
+func TestWatcherInitTimeout(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A storage node that cannot be connected to as it has no addresses.
+	ns := common.NewTestNamespaceFromSeed([]byte("storage client init timeout test ns"))
+	signer := memorySigner.NewTestSigner("storage client init timeout test node")
+	n := &node.Node{
+		ID:    signer.Public(),
+		Roles: node.RoleStorageWorker,
+		Committee: node.CommitteeInfo{
+			Certificate: generateCert(t).Certificate[0],
+		},
+	}
+	reg := &fixedRegistry{nodes: []*node.Node{n}}
+	sched := &fixedScheduler{committee: &scheduler.Committee{
+		Kind:      scheduler.KindStorage,
+		RuntimeID: ns,
+		Members:   []*scheduler.CommitteeNode{{Role: scheduler.Worker, PublicKey: n.ID}},
+	}}
+
+	const initTimeout = 500 * time.Millisecond
+	client := &storageClientBackend{
+		ctx:            ctx,
+		logger:         logging.GetLogger("storage/client/test"),
+		runtimeWatcher: newWatcher(ctx, ns, nil, sched, reg, initTimeout),
+	}
+
+	// Initialization should be signalled after the warm-up timeout.
+	start := time.Now()
+	select {
+	case <-client.Initialized():
+	case <-time.After(10 * initTimeout):
+		t.Fatalf("initialization should be signalled after the warm-up timeout")
+	}
+	require.True(time.Since(start) >= initTimeout, "initialization should not be signalled before the warm-up timeout")
+	require.Empty(client.GetConnectedNodes(), "unreachable storage node should not be connected")
+
+	// Requests should fail fast.
+	_, err := client.SyncGet(ctx, &api.GetRequest{Tree: api.TreeID{Root: api.Root{Namespace: ns}}})
+	require.Equal(ErrInitTimeout, err, "SyncGet should fail after the warm-up timeout")
+
+	client.runtimeWatcher.cleanup()
+}
+
+func TestWatcherUpdateDoesNotBlockReads(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var nodes []*node.Node
+	for _, name := range []string{"a", "b"} {
+		signer := memorySigner.NewTestSigner("storage client update test node " + name)
+		nodes = append(nodes, &node.Node{ID: signer.Public(), Roles: node.RoleStorageWorker})
+	}
+
+	// Connecting to nodes is slow.
+	const connectDelay = 500 * time.Millisecond
+	var delay time.Duration
+	w := &watcherState{
+		ctx:                    ctx,
+		logger:                 logging.GetLogger("storage/client/test"),
+		initCh:                 make(chan struct{}),
+		registeredStorageNodes: nodes,
+		scheduledNodes:         map[signature.PublicKey]bool{nodes[0].ID: true},
+		connectFn: func(identity *identity.Identity, n *node.Node) (*clientState, error) {
+			time.Sleep(delay)
+			// The address is never dialed successfully.
+			conn, err := cmnGrpc.Dial("127.0.0.1:1", grpc.WithInsecure())
+			if err != nil {
+				return nil, err
+			}
+			return &clientState{node: n, conn: conn}, nil
+		},
+	}
+	w.clientStates.Store([]*clientState{})
+	defer w.cleanup()
+
+	// Connections should be available as soon as initialization is signalled.
+	initStatesCh := make(chan []clientState, 1)
+	go func() {
+		<-w.initCh
+		initStatesCh <- w.getClientStates()
+	}()
+
+	w.updateStorageNodeConnections()
+	require.Equal([]*node.Node{nodes[0]}, w.getConnectedNodes(), "scheduled node should be connected")
+	require.Len(<-initStatesCh, 1, "connections should be available once initialization is signalled")
+
+	// Reads should not block while a committee update is in progress.
+	delay = connectDelay
+	w.updateScheduledNodes([]*scheduler.CommitteeNode{{Role: scheduler.Worker, PublicKey: nodes[1].ID}})
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		w.updateStorageNodeConnections()
+	}()
+
+	const maxReadDuration = 50 * time.Millisecond
+	var reads int
+	for updating := true; updating; {
+		select {
+		case <-doneCh:
+			updating = false
+		default:
+		}
+
+		start := time.Now()
+		clientStates := w.getClientStates()
+		require.True(time.Since(start) < maxReadDuration, "reads should not block during connection updates")
+		require.Len(clientStates, 1, "reads should see a complete connection snapshot")
+		reads++
+		time.Sleep(time.Millisecond)
+	}
+	require.True(reads > 1, "reads should be served during connection updates")
+	require.Equal([]*node.Node{nodes[1]}, w.getConnectedNodes(), "newly scheduled node should be connected after the update")
+}
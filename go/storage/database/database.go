@@ -12,12 +12,19 @@ import (
 	"github.com/oasislabs/oasis-core/go/storage/api"
 	nodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/api"
 	badgerNodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/badger"
+	memoryNodedb "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/memory"
 )
 
 const (
 	// BackendNameBadgerDB is the name of the BadgeDB backed database backend.
 	BackendNameBadgerDB = "badger"
 
+	// BackendNameMemory is the name of the in-memory database backend.
+	//
+	// The in-memory backend is not persistent and is only intended to be
+	// used for testing.
+	BackendNameMemory = "memory"
+
 	// DBFileBadgerDB is the default BadgerDB backing store filename.
 	DBFileBadgerDB = "mkvs_storage.badger.db"
 )
@@ -39,6 +46,10 @@ type databaseBackend struct {
 
 	signer signature.Signer
 	initCh chan struct{}
+
+	// quota is the maximum approximate size of the nodes stored in the node
+	// database, zero meaning no quota is enforced.
+	quota uint64
 }
 
 // New constructs a new database backed storage Backend instance.
@@ -52,6 +63,8 @@ func New(cfg *api.Config) (api.Backend, error) {
 	switch cfg.Backend {
 	case BackendNameBadgerDB:
 		ndb, err = badgerNodedb.New(ndbCfg)
+	case BackendNameMemory:
+		ndb, err = memoryNodedb.New(ndbCfg)
 	default:
 		err = errors.New("storage/database: unsupported backend")
 	}
@@ -69,15 +82,58 @@ func New(cfg *api.Config) (api.Backend, error) {
 	initCh := make(chan struct{})
 	close(initCh)
 
-	return &databaseBackend{
+	ba := &databaseBackend{
 		nodedb:    ndb,
 		rootCache: rootCache,
 		signer:    cfg.Signer,
 		initCh:    initCh,
-	}, nil
+		quota:     cfg.Quota,
+	}
+
+	return ba, nil
+}
+
+// updateSize returns the approximate size of the state written by applying
+// the given write log to obtain the given root.
+//
+// The size is the total size of the keys and values in the write log, which
+// is a lower bound on the size of the nodes the update adds to the node
+// database. Updates to roots that already exist are not re-applied (e.g.,
+// client retries) and do not write any state.
+func (ba *databaseBackend) updateSize(ns common.Namespace, round uint64, root hash.Hash, writeLog api.WriteLog) uint64 {
+	if ba.nodedb.HasRoot(api.Root{Namespace: ns, Round: round, Hash: root}) {
+		return 0
+	}
+
+	var size uint64
+	for _, entry := range writeLog {
+		size += uint64(len(entry.Key) + len(entry.Value))
+	}
+	return size
+}
+
+// checkQuota fails with ErrQuotaExceeded if writing an update of the given
+// size would make the node database exceed its quota.
+//
+// The current size is the size of the stored nodes tracked by the node
+// database, which is persisted and decreases as overwritten state is
+// pruned.
+func (ba *databaseBackend) checkQuota(size uint64) error {
+	if ba.quota == 0 || size == 0 {
+		return nil
+	}
+	if uint64(ba.nodedb.Size())+size > ba.quota {
+		return api.ErrQuotaExceeded
+	}
+	return nil
 }
 
 func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest) ([]*api.Receipt, error) {
+	size := ba.updateSize(request.Namespace, request.DstRound, request.DstRoot, request.WriteLog)
+	if err := ba.checkQuota(size); err != nil {
+		return nil, err
+	}
+
 	newRoot, err := ba.rootCache.Apply(
 		ctx,
 		request.Namespace,
@@ -96,6 +152,14 @@ func (ba *databaseBackend) Apply(ctx context.Context, request *api.ApplyRequest)
 }
 
 func (ba *databaseBackend) ApplyBatch(ctx context.Context, request *api.ApplyBatchRequest) ([]*api.Receipt, error) {
+	var size uint64
+	for _, op := range request.Ops {
+		size += ba.updateSize(request.Namespace, request.DstRound, op.DstRoot, op.WriteLog)
+	}
+	if err := ba.checkQuota(size); err != nil {
+		return nil, err
+	}
+
 	newRoots := make([]hash.Hash, 0, len(request.Ops))
 	for _, op := range request.Ops {
 		newRoot, err := ba.rootCache.Apply(ctx, request.Namespace, op.SrcRound, op.SrcRoot, request.DstRound, op.DstRoot, op.WriteLog)
@@ -176,13 +240,31 @@ func (ba *databaseBackend) GetDiff(ctx context.Context, request *api.GetDiffRequ
 }
 
 func (ba *databaseBackend) GetCheckpoint(ctx context.Context, request *api.GetCheckpointRequest) (api.WriteLogIterator, error) {
-	return ba.nodedb.GetCheckpoint(ctx, request.Root)
+	var resumeAfter []byte
+	if token := request.ResumeToken; token != nil {
+		if !token.Root.Equal(&request.Root) {
+			return nil, api.ErrInvalidResumeToken
+		}
+		// Make sure an empty last key (which may have been decoded as nil)
+		// still skips the entry with the empty key.
+		resumeAfter = append([]byte{}, token.LastKey...)
+	}
+
+	return ba.nodedb.GetCheckpoint(ctx, request.Root, resumeAfter)
 }
 
 func (ba *databaseBackend) HasRoot(root api.Root) bool {
 	return ba.nodedb.HasRoot(root)
 }
 
+func (ba *databaseBackend) HasRoots(roots []api.Root) []bool {
+	present := make([]bool, len(roots))
+	for i, root := range roots {
+		present[i] = ba.nodedb.HasRoot(root)
+	}
+	return present
+}
+
 func (ba *databaseBackend) Finalize(ctx context.Context, namespace common.Namespace, round uint64, roots []hash.Hash) error {
 	return ba.nodedb.Finalize(ctx, namespace, round, roots)
 }
@@ -190,3 +272,10 @@ func (ba *databaseBackend) Finalize(ctx context.Context, namespace common.Namesp
 func (ba *databaseBackend) Prune(ctx context.Context, namespace common.Namespace, round uint64) (int, error) {
 	return ba.nodedb.Prune(ctx, namespace, round)
 }
+
+func (ba *databaseBackend) Verify(ctx context.Context, request *api.VerifyRequest) ([]*api.VerifyIssue, error) {
+	if request.Round != nil {
+		return nodedb.VerifyRound(ctx, ba.nodedb, request.Namespace, *request.Round)
+	}
+	return nodedb.VerifyAll(ctx, ba.nodedb, request.Namespace)
+}
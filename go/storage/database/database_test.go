@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"crypto/rand"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -10,7 +12,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests/helpers"
 	"github.com/oasislabs/oasis-core/go/storage/api"
 	"github.com/oasislabs/oasis-core/go/storage/tests"
 )
@@ -18,6 +22,7 @@ import (
 func TestStorageDatabase(t *testing.T) {
 	for _, v := range []string{
 		BackendNameBadgerDB,
+		BackendNameMemory,
 	} {
 		t.Run(v, func(t *testing.T) {
 			doTestImpl(t, v)
@@ -43,14 +48,341 @@ func doTestImpl(t *testing.T, backend string) {
 	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
 	require.NoError(err, "NewSigner()")
 
+	if backend != BackendNameMemory {
+		cfg.DB, err = ioutil.TempDir("", "oasis-storage-database-test")
+		require.NoError(err, "TempDir()")
+		defer os.RemoveAll(cfg.DB)
+
+		cfg.DB = filepath.Join(cfg.DB, DefaultFileName(backend))
+	}
+	impl, err := New(&cfg)
+	require.NoError(err, "New()")
+	defer impl.Cleanup()
+
+	tests.StorageImplementationTests(t, impl, testNs, 0)
+}
+
+func TestStorageDatabaseQuota(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	testNs := common.NewTestNamespaceFromSeed([]byte("database backend quota test ns"))
+
+	cfg := api.Config{
+		Backend:           BackendNameMemory,
+		ApplyLockLRUSlots: 100,
+		Namespace:         testNs,
+		Quota:             128,
+	}
+	var err error
+	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+	impl, err := New(&cfg)
+	require.NoError(err, "New()")
+	defer impl.Cleanup()
+	ndb := impl.(*databaseBackend).nodedb
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+
+	apply := func(wl api.WriteLog) error {
+		_, aerr := impl.Apply(ctx, &api.ApplyRequest{
+			Namespace: testNs,
+			SrcRound:  0,
+			SrcRoot:   emptyRoot,
+			DstRound:  0,
+			DstRoot:   tests.CalculateExpectedNewRoot(t, wl, testNs, 0),
+			WriteLog:  wl,
+		})
+		return aerr
+	}
+
+	// Writes within the quota should be accepted.
+	wl := api.WriteLog{{Key: []byte("key 1"), Value: make([]byte, 27)}}
+	err = apply(wl)
+	require.NoError(err, "Apply() within quota")
+
+	// The remaining quota is based on the size of the node database.
+	size := uint64(ndb.Size())
+	require.True(size > 0 && size < cfg.Quota, "node database size should be within quota")
+	remaining := int(cfg.Quota-size) - len("key 2")
+
+	// Writes crossing the quota should be rejected.
+	wl = api.WriteLog{{Key: []byte("key 2"), Value: make([]byte, remaining+1)}}
+	err = apply(wl)
+	require.Equal(api.ErrQuotaExceeded, err, "Apply() crossing quota")
+
+	_, err = impl.ApplyBatch(ctx, &api.ApplyBatchRequest{
+		Namespace: testNs,
+		DstRound:  0,
+		Ops: []api.ApplyOp{
+			{SrcRound: 0, SrcRoot: emptyRoot, DstRoot: tests.CalculateExpectedNewRoot(t, wl, testNs, 0), WriteLog: wl},
+		},
+	})
+	require.Equal(api.ErrQuotaExceeded, err, "ApplyBatch() crossing quota")
+	require.EqualValues(size, ndb.Size(), "rejected writes should not change the node database size")
+
+	// Writes reaching the quota exactly should be accepted.
+	wl = api.WriteLog{{Key: []byte("key 2"), Value: make([]byte, remaining)}}
+	err = apply(wl)
+	require.NoError(err, "Apply() up to quota")
+}
+
+func TestStorageDatabaseQuotaPrune(t *testing.T) {
+	for _, v := range []string{
+		BackendNameBadgerDB,
+		BackendNameMemory,
+	} {
+		t.Run(v, func(t *testing.T) {
+			doTestQuotaPrune(t, v)
+		})
+	}
+}
+
+func doTestQuotaPrune(t *testing.T, backend string) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	testNs := common.NewTestNamespaceFromSeed([]byte("database backend quota prune test ns"))
+
+	cfg := api.Config{
+		Backend:           backend,
+		ApplyLockLRUSlots: 100,
+		Namespace:         testNs,
+		MaxCacheSize:      16 * 1024 * 1024,
+	}
+	var err error
+	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+	if backend != BackendNameMemory {
+		cfg.DB, err = ioutil.TempDir("", "oasis-storage-database-test")
+		require.NoError(err, "TempDir()")
+		defer os.RemoveAll(cfg.DB)
+
+		cfg.DB = filepath.Join(cfg.DB, DefaultFileName(backend))
+	}
+	impl, err := New(&cfg)
+	require.NoError(err, "New()")
+	defer impl.Cleanup()
+	ba := impl.(*databaseBackend)
+	localImpl := impl.(api.LocalBackend)
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+
+	// Write the same key in two rounds so that the second round overwrites
+	// the first one.
+	var lastRoot hash.Hash
+	for round := uint64(0); round < 2; round++ {
+		wl := api.WriteLog{{Key: []byte("key"), Value: make([]byte, 64)}}
+		wl[0].Value[0] = byte(round)
+		root := tests.CalculateExpectedNewRoot(t, wl, testNs, round)
+		_, err = impl.Apply(ctx, &api.ApplyRequest{
+			Namespace: testNs,
+			SrcRound:  round,
+			SrcRoot:   emptyRoot,
+			DstRound:  round,
+			DstRoot:   root,
+			WriteLog:  wl,
+		})
+		require.NoError(err, "Apply(%d)", round)
+		err = localImpl.Finalize(ctx, testNs, round, []hash.Hash{root})
+		require.NoError(err, "Finalize(%d)", round)
+		lastRoot = root
+	}
+
+	// Only leave room for a single small write.
+	sizeBefore := uint64(ba.nodedb.Size())
+	ba.quota = sizeBefore + 16
+
+	wl := api.WriteLog{{Key: []byte("key"), Value: make([]byte, 32)}}
+	request := &api.ApplyRequest{
+		Namespace: testNs,
+		SrcRound:  1,
+		SrcRoot:   lastRoot,
+		DstRound:  2,
+		DstRoot:   tests.CalculateExpectedNewRoot(t, wl, testNs, 2),
+		WriteLog:  wl,
+	}
+	_, err = impl.Apply(ctx, request)
+	require.Equal(api.ErrQuotaExceeded, err, "Apply() crossing quota")
+
+	// Pruning the overwritten round should free up space.
+	pruned, err := localImpl.Prune(ctx, testNs, 0)
+	require.NoError(err, "Prune()")
+	require.True(pruned > 0, "Prune() should prune nodes")
+	require.True(uint64(ba.nodedb.Size()) < sizeBefore, "Prune() should reduce the node database size")
+
+	_, err = impl.Apply(ctx, request)
+	require.NoError(err, "Apply() after Prune()")
+}
+
+func TestStorageDatabaseQuotaRestart(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	testNs := common.NewTestNamespaceFromSeed([]byte("database backend quota restart test ns"))
+
+	cfg := api.Config{
+		Backend:           BackendNameBadgerDB,
+		ApplyLockLRUSlots: 100,
+		Namespace:         testNs,
+		MaxCacheSize:      16 * 1024 * 1024,
+	}
+	var err error
+	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
 	cfg.DB, err = ioutil.TempDir("", "oasis-storage-database-test")
 	require.NoError(err, "TempDir()")
 	defer os.RemoveAll(cfg.DB)
+	cfg.DB = filepath.Join(cfg.DB, DefaultFileName(cfg.Backend))
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
 
-	cfg.DB = filepath.Join(cfg.DB, DefaultFileName(backend))
 	impl, err := New(&cfg)
 	require.NoError(err, "New()")
+	wl := api.WriteLog{{Key: []byte("key"), Value: make([]byte, 1024)}}
+	_, err = impl.Apply(ctx, &api.ApplyRequest{
+		Namespace: testNs,
+		SrcRound:  0,
+		SrcRoot:   emptyRoot,
+		DstRound:  0,
+		DstRoot:   tests.CalculateExpectedNewRoot(t, wl, testNs, 0),
+		WriteLog:  wl,
+	})
+	require.NoError(err, "Apply()")
+
+	size := impl.(*databaseBackend).nodedb.Size()
+	require.True(size >= 1024, "node database size should include written state")
+	impl.Cleanup()
+
+	// State persisted before a restart should count against the quota.
+	cfg.Quota = 1024
+	impl, err = New(&cfg)
+	require.NoError(err, "New() after restart")
 	defer impl.Cleanup()
+	require.Equal(size, impl.(*databaseBackend).nodedb.Size(), "node database size should be persisted")
 
-	tests.StorageImplementationTests(t, impl, testNs, 0)
+	wl = api.WriteLog{{Key: []byte("other key"), Value: []byte("value")}}
+	_, err = impl.Apply(ctx, &api.ApplyRequest{
+		Namespace: testNs,
+		SrcRound:  0,
+		SrcRoot:   emptyRoot,
+		DstRound:  0,
+		DstRoot:   tests.CalculateExpectedNewRoot(t, wl, testNs, 0),
+		WriteLog:  wl,
+	})
+	require.Equal(api.ErrQuotaExceeded, err, "Apply() after restart crossing quota")
+}
+
+func TestStorageDatabaseApplyRetry(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	testNs := common.NewTestNamespaceFromSeed([]byte("database backend retry test ns"))
+
+	// The quota only allows the write log to be applied once.
+	wl := api.WriteLog{{Key: []byte("key"), Value: []byte("value")}}
+	cfg := api.Config{
+		Backend:           BackendNameMemory,
+		ApplyLockLRUSlots: 100,
+		Namespace:         testNs,
+		Quota:             8,
+	}
+	var err error
+	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+	impl, err := New(&cfg)
+	require.NoError(err, "New()")
+	defer impl.Cleanup()
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+	request := &api.ApplyRequest{
+		Namespace: testNs,
+		SrcRound:  0,
+		SrcRoot:   emptyRoot,
+		DstRound:  0,
+		DstRoot:   tests.CalculateExpectedNewRoot(t, wl, testNs, 0),
+		WriteLog:  wl,
+	}
+
+	receipts, err := impl.Apply(ctx, request)
+	require.NoError(err, "Apply()")
+	retryReceipts, err := impl.Apply(ctx, request)
+	require.NoError(err, "Apply() retry should be a no-op")
+	require.Equal(receipts, retryReceipts, "Apply() retry should return an equivalent receipt")
+
+	batchReceipts, err := impl.ApplyBatch(ctx, &api.ApplyBatchRequest{
+		Namespace: testNs,
+		DstRound:  0,
+		Ops: []api.ApplyOp{
+			{SrcRound: 0, SrcRoot: emptyRoot, DstRoot: request.DstRoot, WriteLog: wl},
+		},
+	})
+	require.NoError(err, "ApplyBatch() retry should be a no-op")
+	require.Equal(receipts, batchReceipts, "ApplyBatch() retry should return an equivalent receipt")
+}
+
+func TestStorageDatabaseHasRoots(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	genesisTestHelpers.SetTestChainContext()
+
+	testNs := common.NewTestNamespaceFromSeed([]byte("database backend has roots test ns"))
+
+	cfg := api.Config{
+		Backend:           BackendNameMemory,
+		ApplyLockLRUSlots: 100,
+		Namespace:         testNs,
+	}
+	var err error
+	cfg.Signer, err = memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner()")
+	impl, err := New(&cfg)
+	require.NoError(err, "New()")
+	defer impl.Cleanup()
+	localImpl := impl.(api.LocalBackend)
+
+	var emptyRoot hash.Hash
+	emptyRoot.Empty()
+
+	var roots []api.Root
+	for round := uint64(0); round < 3; round++ {
+		wl := api.WriteLog{{Key: []byte("key"), Value: []byte(fmt.Sprintf("value %d", round))}}
+		root := api.Root{
+			Namespace: testNs,
+			Round:     round,
+			Hash:      tests.CalculateExpectedNewRoot(t, wl, testNs, round),
+		}
+		// Only apply every other root.
+		if round%2 == 0 {
+			_, err = impl.Apply(ctx, &api.ApplyRequest{
+				Namespace: testNs,
+				SrcRound:  round,
+				SrcRoot:   emptyRoot,
+				DstRound:  round,
+				DstRoot:   root.Hash,
+				WriteLog:  wl,
+			})
+			require.NoError(err, "Apply()")
+		}
+		roots = append(roots, root)
+	}
+	// Also include a root in an unknown namespace.
+	roots = append(roots, api.Root{
+		Namespace: common.NewTestNamespaceFromSeed([]byte("database backend has roots test other ns")),
+		Round:     0,
+		Hash:      roots[0].Hash,
+	})
+
+	present := localImpl.HasRoots(roots)
+	require.Len(present, len(roots), "HasRoots() should return a result for each root")
+	for i, root := range roots {
+		require.Equal(localImpl.HasRoot(root), present[i], "HasRoots() should match HasRoot() for root %d", i)
+	}
+	require.Equal([]bool{true, false, true, false}, present, "HasRoots() should report present roots")
+	require.Empty(localImpl.HasRoots(nil), "HasRoots() should handle no roots")
 }
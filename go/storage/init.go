@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	flag "github.com/spf13/pflag"
@@ -30,6 +31,10 @@ const (
 	// CfgMaxCacheSize configures the maximum in-memory cache size.
 	CfgMaxCacheSize = "storage.max_cache_size"
 
+	// CfgQuota configures per-runtime storage quotas (in the form of
+	// runtime_id=bytes).
+	CfgQuota = "storage.quota"
+
 	cfgCrashEnabled       = "storage.crash.enabled"
 	cfgInsecureSkipChecks = "storage.debug.insecure_skip_checks"
 )
@@ -46,6 +51,11 @@ func New(
 	schedulerBackend scheduler.Backend,
 	registryBackend registry.Backend,
 ) (api.Backend, error) {
+	quotas, err := parseQuotas(viper.GetStringSlice(CfgQuota))
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &api.Config{
 		Backend:            strings.ToLower(viper.GetString(CfgBackend)),
 		DB:                 dataDir,
@@ -54,16 +64,18 @@ func New(
 		InsecureSkipChecks: viper.GetBool(cfgInsecureSkipChecks) && cmdFlags.DebugDontBlameOasis(),
 		Namespace:          namespace,
 		MaxCacheSize:       int64(viper.GetSizeInBytes(CfgMaxCacheSize)),
+		Quota:              quotas[namespace],
 	}
 
-	var (
-		err  error
-		impl api.Backend
-	)
+	var impl api.Backend
 	switch cfg.Backend {
 	case database.BackendNameBadgerDB:
 		cfg.DB = filepath.Join(cfg.DB, database.DefaultFileName(cfg.Backend))
 		impl, err = database.New(cfg)
+	case database.BackendNameMemory:
+		// The in-memory backend does not use the data directory.
+		cfg.DB = ""
+		impl, err = database.New(cfg)
 	case client.BackendName:
 		impl, err = client.New(ctx, namespace, identity, schedulerBackend, registryBackend)
 	default:
@@ -82,11 +94,35 @@ func New(
 	return newMetricsWrapper(impl), nil
 }
 
+// parseQuotas parses per-runtime storage quotas of the form
+// runtime_id=bytes.
+func parseQuotas(entries []string) (map[common.Namespace]uint64, error) {
+	quotas := make(map[common.Namespace]uint64)
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("storage: malformed quota '%s'", entry)
+		}
+
+		var id common.Namespace
+		if err := id.UnmarshalHex(strings.TrimSpace(kv[0])); err != nil {
+			return nil, fmt.Errorf("storage: malformed quota runtime ID '%s': %w", kv[0], err)
+		}
+		quota, err := strconv.ParseUint(strings.TrimSpace(kv[1]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("storage: malformed quota for runtime '%s': %w", id, err)
+		}
+		quotas[id] = quota
+	}
+	return quotas, nil
+}
+
 func init() {
 	Flags.String(CfgBackend, database.BackendNameBadgerDB, "Storage backend")
 	Flags.Bool(cfgCrashEnabled, false, "Enable the crashing storage wrapper")
 	Flags.Int(CfgLRUSlots, 1000, "How many LRU slots to use for Apply call locks in the MKVS tree root cache")
 	Flags.String(CfgMaxCacheSize, "64mb", "Maximum in-memory cache size")
+	Flags.StringSlice(CfgQuota, []string{}, "Per-runtime storage quotas in bytes (runtime_id=bytes)")
 
 	Flags.Bool(cfgInsecureSkipChecks, false, "INSECURE: Skip known root checks")
 
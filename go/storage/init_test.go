@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+)
+
+func TestParseQuotas(t *testing.T) {
+	require := require.New(t)
+
+	nsA := common.NewTestNamespaceFromSeed([]byte("storage quota test ns A"))
+	nsB := common.NewTestNamespaceFromSeed([]byte("storage quota test ns B"))
+
+	quotas, err := parseQuotas([]string{nsA.String() + "=1024", " " + nsB.String() + " = 2048 "})
+	require.NoError(err, "parseQuotas")
+	require.Equal(map[common.Namespace]uint64{
+		nsA: 1024,
+		nsB: 2048,
+	}, quotas, "quotas should be parsed")
+
+	quotas, err = parseQuotas(nil)
+	require.NoError(err, "parseQuotas(nil)")
+	require.Empty(quotas, "no entries should result in no quotas")
+
+	for _, malformed := range []string{
+		nsA.String(),
+		"notanid=1024",
+		nsA.String() + "=",
+		nsA.String() + "=-1",
+		nsA.String() + "=1kb",
+	} {
+		_, err = parseQuotas([]string{malformed})
+		require.Error(err, "malformed quota '%s' should be rejected", malformed)
+	}
+}
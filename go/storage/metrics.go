@@ -72,8 +72,10 @@ var (
 	labelSyncGetPrefixes = prometheus.Labels{"call": "sync_get_prefixes"}
 	labelSyncIterate     = prometheus.Labels{"call": "sync_iterate"}
 	labelHasRoot         = prometheus.Labels{"call": "has_root"}
+	labelHasRoots        = prometheus.Labels{"call": "has_roots"}
 	labelFinalize        = prometheus.Labels{"call": "finalize"}
 	labelPrune           = prometheus.Labels{"call": "prune"}
+	labelVerify          = prometheus.Labels{"call": "verify"}
 
 	_ api.LocalBackend  = (*metricsWrapper)(nil)
 	_ api.ClientBackend = (*metricsWrapper)(nil)
@@ -209,6 +211,18 @@ func (w *metricsWrapper) HasRoot(root api.Root) bool {
 	return flag
 }
 
+func (w *metricsWrapper) HasRoots(roots []api.Root) []bool {
+	localBackend, ok := w.Backend.(api.LocalBackend)
+	if !ok {
+		return make([]bool, len(roots))
+	}
+	start := time.Now()
+	present := localBackend.HasRoots(roots)
+	storageLatency.With(labelHasRoots).Observe(time.Since(start).Seconds())
+	storageCalls.With(labelHasRoots).Inc()
+	return present
+}
+
 func (w *metricsWrapper) Finalize(ctx context.Context, namespace common.Namespace, round uint64, roots []hash.Hash) error {
 	localBackend, ok := w.Backend.(api.LocalBackend)
 	if !ok {
@@ -237,6 +251,18 @@ func (w *metricsWrapper) Prune(ctx context.Context, namespace common.Namespace,
 	return pruned, err
 }
 
+func (w *metricsWrapper) Verify(ctx context.Context, request *api.VerifyRequest) ([]*api.VerifyIssue, error) {
+	localBackend, ok := w.Backend.(api.LocalBackend)
+	if !ok {
+		return nil, api.ErrUnsupported
+	}
+	start := time.Now()
+	issues, err := localBackend.Verify(ctx, request)
+	storageLatency.With(labelVerify).Observe(time.Since(start).Seconds())
+	storageCalls.With(labelVerify).Inc()
+	return issues, err
+}
+
 func newMetricsWrapper(base api.Backend) api.Backend {
 	metricsOnce.Do(func() {
 		prometheus.MustRegister(storageCollectors...)
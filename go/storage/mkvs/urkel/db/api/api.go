@@ -2,6 +2,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
@@ -38,6 +39,9 @@ var (
 	// ErrBadNamespace indicates that the passed namespace does not match what is
 	// actually contained within the database.
 	ErrBadNamespace = errors.New("urkel: bad namespace")
+	// ErrNodeHashMismatch indicates that a node stored in the database does not hash
+	// to the hash it is stored under.
+	ErrNodeHashMismatch = errors.New("urkel: node hash mismatch")
 )
 
 // Config is the node database backend configuration.
@@ -64,7 +68,10 @@ type NodeDB interface {
 	GetWriteLog(ctx context.Context, startRoot node.Root, endRoot node.Root) (writelog.Iterator, error)
 
 	// GetCheckpoint retrieves a write log of entries in root.
-	GetCheckpoint(ctx context.Context, root node.Root) (writelog.Iterator, error)
+	//
+	// If resumeAfter is non-nil, only entries with keys greater than
+	// resumeAfter are returned.
+	GetCheckpoint(ctx context.Context, root node.Root, resumeAfter []byte) (writelog.Iterator, error)
 
 	// NewBatch starts a new batch.
 	NewBatch(namespace common.Namespace, round uint64, oldRoot node.Root) Batch
@@ -72,6 +79,13 @@ type NodeDB interface {
 	// HasRoot checks whether the given root exists.
 	HasRoot(root node.Root) bool
 
+	// GetFinalizedRoots returns the list of finalized roots stored under the
+	// given round.
+	//
+	// Returns ErrNotFinalized in case the given round has not yet been
+	// finalized.
+	GetFinalizedRoots(ctx context.Context, namespace common.Namespace, round uint64) ([]hash.Hash, error)
+
 	// Finalize finalizes the specified round. The passed list of roots are the
 	// roots within the round that have been finalized. All non-finalized roots
 	// can be discarded.
@@ -82,6 +96,12 @@ type NodeDB interface {
 	// Returns the number of pruned nodes.
 	Prune(ctx context.Context, namespace common.Namespace, round uint64) (int, error)
 
+	// Size returns the approximate total size of the stored nodes in bytes.
+	//
+	// The size is updated when nodes are added and when they are removed
+	// by finalization or pruning.
+	Size() int64
+
 	// Close closes the database.
 	Close()
 }
@@ -163,7 +183,7 @@ func (d *nopNodeDB) GetWriteLog(ctx context.Context, startRoot node.Root, endRoo
 	return nil, ErrWriteLogNotFound
 }
 
-func (d *nopNodeDB) GetCheckpoint(ctx context.Context, root node.Root) (writelog.Iterator, error) {
+func (d *nopNodeDB) GetCheckpoint(ctx context.Context, root node.Root, resumeAfter []byte) (writelog.Iterator, error) {
 	return nil, ErrWriteLogNotFound
 }
 
@@ -171,6 +191,10 @@ func (d *nopNodeDB) HasRoot(root node.Root) bool {
 	return false
 }
 
+func (d *nopNodeDB) GetFinalizedRoots(ctx context.Context, namespace common.Namespace, round uint64) ([]hash.Hash, error) {
+	return nil, nil
+}
+
 func (d *nopNodeDB) Finalize(ctx context.Context, namespace common.Namespace, round uint64, roots []hash.Hash) error {
 	return nil
 }
@@ -179,6 +203,10 @@ func (d *nopNodeDB) Prune(ctx context.Context, namespace common.Namespace, round
 	return 0, nil
 }
 
+func (d *nopNodeDB) Size() int64 {
+	return 0
+}
+
 // Close is a no-op.
 func (d *nopNodeDB) Close() {
 }
@@ -233,7 +261,11 @@ func NewCheckpointableDB(db NodeDB) CheckpointableDB {
 }
 
 // GetCheckpoint returns an iterator of write log entries in the provided
-func (b *CheckpointableDB) GetCheckpoint(ctx context.Context, root node.Root) (writelog.Iterator, error) {
+// root, in key order.
+//
+// If resumeAfter is non-nil, only entries with keys greater than resumeAfter
+// are returned.
+func (b *CheckpointableDB) GetCheckpoint(ctx context.Context, root node.Root, resumeAfter []byte) (writelog.Iterator, error) {
 	if !b.db.HasRoot(root) {
 		return nil, ErrNodeNotFound
 	}
@@ -245,13 +277,13 @@ func (b *CheckpointableDB) GetCheckpoint(ctx context.Context, root node.Root) (w
 	go func() {
 		defer pipe.Close()
 
-		b.getNodeWriteLog(ctx, &pipe, root, ptr)
+		b.getNodeWriteLog(ctx, &pipe, root, ptr, 0, node.Key{}, resumeAfter)
 	}()
 
 	return &pipe, nil
 }
 
-func (b *CheckpointableDB) getNodeWriteLog(ctx context.Context, pipe *writelog.PipeIterator, root node.Root, ptr *node.Pointer) {
+func (b *CheckpointableDB) getNodeWriteLog(ctx context.Context, pipe *writelog.PipeIterator, root node.Root, ptr *node.Pointer, bitDepth node.Depth, path node.Key, resumeAfter node.Key) {
 	select {
 	case <-ctx.Done():
 		return
@@ -265,6 +297,9 @@ func (b *CheckpointableDB) getNodeWriteLog(ctx context.Context, pipe *writelog.P
 	}
 	switch n := nod.(type) {
 	case *node.LeafNode:
+		if resumeAfter != nil && bytes.Compare(n.Key, resumeAfter) <= 0 {
+			return
+		}
 		entry := writelog.LogEntry{
 			Key:   n.Key[:],
 			Value: n.Value[:],
@@ -273,16 +308,51 @@ func (b *CheckpointableDB) getNodeWriteLog(ctx context.Context, pipe *writelog.P
 			_ = pipe.PutError(err)
 		}
 	case *node.InternalNode:
+		bitLength := bitDepth + n.LabelBitLength
+		newPath := path.Merge(bitDepth, n.Label, n.LabelBitLength)
+
+		// All keys in this subtree share the same prefix, so the whole
+		// subtree can be skipped if it only contains keys before the resume
+		// point and no further filtering is needed if it only contains keys
+		// after it.
+		if resumeAfter != nil {
+			switch comparePrefix(newPath, bitLength, resumeAfter) {
+			case -1:
+				return
+			case 1:
+				resumeAfter = nil
+			}
+		}
+
 		if n.LeafNode != nil {
-			b.getNodeWriteLog(ctx, pipe, root, n.LeafNode)
+			b.getNodeWriteLog(ctx, pipe, root, n.LeafNode, bitLength, newPath, resumeAfter)
 		}
 		if n.Left != nil {
-			b.getNodeWriteLog(ctx, pipe, root, n.Left)
+			b.getNodeWriteLog(ctx, pipe, root, n.Left, bitLength, newPath, resumeAfter)
 		}
 		if n.Right != nil {
-			b.getNodeWriteLog(ctx, pipe, root, n.Right)
+			b.getNodeWriteLog(ctx, pipe, root, n.Right, bitLength, newPath, resumeAfter)
 		}
 	default:
 		panic("urkel/db/CheckpoitableDB: invalid root node type")
 	}
 }
+
+// comparePrefix compares the first bitLength bits of prefix with the
+// corresponding bits of key. If key is a proper prefix of prefix, prefix
+// is considered greater.
+func comparePrefix(prefix node.Key, bitLength node.Depth, key node.Key) int {
+	for bit := node.Depth(0); bit < bitLength; bit++ {
+		if bit >= key.BitLength() {
+			return 1
+		}
+		switch prefixBit, keyBit := prefix.GetBit(bit), key.GetBit(bit); {
+		case prefixBit == keyBit:
+		case keyBit:
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/node"
+)
+
+// VerifyIssue is an integrity issue found while verifying a node database.
+type VerifyIssue struct {
+	// Root is the root under which the affected node is reachable.
+	Root node.Root
+	// Node is the hash of the affected node.
+	Node hash.Hash
+	// Err describes the issue. It is ErrNodeNotFound for missing nodes and
+	// ErrNodeHashMismatch for nodes which do not hash to their stored hash.
+	Err error
+}
+
+// String returns a string representation of the issue.
+func (i *VerifyIssue) String() string {
+	return fmt.Sprintf("root %s (round %d): node %s: %s", i.Root.Hash, i.Root.Round, i.Node, i.Err)
+}
+
+// VerifyRound checks the integrity of all finalized roots stored under the
+// given namespace and round by walking all nodes reachable from them and
+// recomputing their hashes.
+//
+// The database is not modified. Any missing nodes or hash mismatches are
+// returned as issues, while an error is only returned when the verification
+// itself could not be performed.
+func VerifyRound(ctx context.Context, ndb NodeDB, namespace common.Namespace, round uint64) ([]*VerifyIssue, error) {
+	v := newVerifier(ndb, namespace)
+	if err := v.verifyRound(ctx, round); err != nil {
+		return nil, err
+	}
+	return v.issues, nil
+}
+
+// VerifyAll checks the integrity of all finalized roots stored under the
+// given namespace, see VerifyRound for details.
+func VerifyAll(ctx context.Context, ndb NodeDB, namespace common.Namespace) ([]*VerifyIssue, error) {
+	v := newVerifier(ndb, namespace)
+	for round := uint64(0); ; round++ {
+		err := v.verifyRound(ctx, round)
+		switch err {
+		case nil:
+		case ErrNotFinalized:
+			return v.issues, nil
+		default:
+			return nil, err
+		}
+	}
+}
+
+type verifier struct {
+	ndb       NodeDB
+	namespace common.Namespace
+
+	// visited are the nodes that have already been verified. Nodes are
+	// shared between roots, so this avoids verifying them multiple times.
+	visited map[hash.Hash]bool
+	issues  []*VerifyIssue
+}
+
+func (v *verifier) verifyRound(ctx context.Context, round uint64) error {
+	roots, err := v.ndb.GetFinalizedRoots(ctx, v.namespace, round)
+	if err != nil {
+		return err
+	}
+
+	for _, rootHash := range roots {
+		// An empty root is always implicitly present.
+		if rootHash.IsEmpty() {
+			continue
+		}
+
+		root := node.Root{Namespace: v.namespace, Round: round, Hash: rootHash}
+		if err = v.verifyNode(ctx, root, rootHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *verifier) verifyNode(ctx context.Context, root node.Root, h hash.Hash) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if v.visited[h] {
+		return nil
+	}
+	v.visited[h] = true
+
+	nd, err := v.ndb.GetNode(root, &node.Pointer{Clean: true, Hash: h})
+	if err != nil {
+		// Nodes which fail to be fetched or decoded are reported as issues
+		// as they are either missing or corrupted.
+		v.issues = append(v.issues, &VerifyIssue{Root: root, Node: h, Err: err})
+		return nil
+	}
+
+	// Decoding a node recomputes its hash, so it must match the hash under
+	// which the node is stored.
+	if nh := nd.GetHash(); !nh.Equal(&h) {
+		v.issues = append(v.issues, &VerifyIssue{Root: root, Node: h, Err: ErrNodeHashMismatch})
+		return nil
+	}
+
+	if n, ok := nd.(*node.InternalNode); ok {
+		for _, ptr := range []*node.Pointer{n.LeafNode, n.Left, n.Right} {
+			if ptr == nil {
+				continue
+			}
+			if err = v.verifyNode(ctx, root, ptr.Hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newVerifier(ndb NodeDB, namespace common.Namespace) *verifier {
+	return &verifier{
+		ndb:       ndb,
+		namespace: namespace,
+		visited:   make(map[hash.Hash]bool),
+	}
+}
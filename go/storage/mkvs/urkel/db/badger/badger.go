@@ -65,6 +65,10 @@ var (
 	//
 	// Value is CBOR-serialized metadata.
 	metadataKeyFmt = keyformat.New(0x07)
+	// sizeKeyFmt is the key format for the total size of the stored nodes.
+	//
+	// Value is the CBOR-serialized size in bytes.
+	sizeKeyFmt = keyformat.New(0x08)
 )
 
 // rootGcIndexUpdate is an element of the rootGcUpdates list.
@@ -139,6 +143,10 @@ func New(cfg *api.Config) (api.NodeDB, error) {
 		_ = db.db.Close()
 		return nil, errors.Wrap(err, "urkel/db/badger: failed to load metadata")
 	}
+	if err = db.loadSize(); err != nil {
+		_ = db.db.Close()
+		return nil, errors.Wrap(err, "urkel/db/badger: failed to load size")
+	}
 
 	db.gc = cmnBadger.NewGCWorker(db.logger, db.db)
 
@@ -156,6 +164,11 @@ type badgerNodeDB struct {
 	gc   *cmnBadger.GCWorker
 	meta metadata
 
+	// sizeLock serializes updates of the persisted size.
+	sizeLock sync.Mutex
+	// size is the total size of the stored nodes in bytes.
+	size uint64
+
 	closeOnce sync.Once
 }
 
@@ -211,6 +224,68 @@ func (d *badgerNodeDB) load() error {
 	})
 }
 
+// loadSize loads the total size of the stored nodes.
+func (d *badgerNodeDB) loadSize() error {
+	return d.db.Update(func(tx *badger.Txn) error {
+		item, err := tx.Get(sizeKeyFmt.Encode())
+		switch err {
+		case nil:
+			return item.Value(func(data []byte) error {
+				return cbor.Unmarshal(data, &d.size)
+			})
+		case badger.ErrKeyNotFound:
+		default:
+			return err
+		}
+
+		// The size has not been tracked yet, compute it from the stored nodes.
+		it := tx.NewIterator(badger.IteratorOptions{Prefix: nodeKeyFmt.Encode()})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			d.size += uint64(it.Item().ValueSize())
+		}
+		return tx.Set(sizeKeyFmt.Encode(), cbor.Marshal(d.size))
+	})
+}
+
+// flushWithSize updates the total size of the stored nodes by the given
+// amounts and flushes it together with the given batch.
+func (d *badgerNodeDB) flushWithSize(batch *badger.WriteBatch, added, removed uint64) error {
+	d.sizeLock.Lock()
+	defer d.sizeLock.Unlock()
+
+	size := d.size + added
+	if removed > size {
+		removed = size
+	}
+	size -= removed
+
+	if err := batch.Set(sizeKeyFmt.Encode(), cbor.Marshal(size)); err != nil {
+		return err
+	}
+	if err := batch.Flush(); err != nil {
+		return err
+	}
+	d.size = size
+
+	return nil
+}
+
+// storedNodeSize returns the size of the stored node with the given hash,
+// or zero if the node is not stored.
+func storedNodeSize(tx *badger.Txn, h *hash.Hash) (uint64, error) {
+	item, err := tx.Get(nodeKeyFmt.Encode(h))
+	switch err {
+	case nil:
+		return uint64(item.ValueSize()), nil
+	case badger.ErrKeyNotFound:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
 func (d *badgerNodeDB) sanityCheckNamespace(ns common.Namespace) error {
 	if !ns.Equal(&d.namespace) {
 		return api.ErrBadNamespace
@@ -412,6 +487,51 @@ func (d *badgerNodeDB) HasRoot(root node.Root) bool {
 	}
 }
 
+func (d *badgerNodeDB) GetFinalizedRoots(ctx context.Context, namespace common.Namespace, round uint64) ([]hash.Hash, error) {
+	if err := d.sanityCheckNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	// Make sure that the round has been finalized, so that only the finalized
+	// roots remain.
+	lastFinalizedRound, exists := d.meta.getLastFinalizedRound()
+	if !exists || lastFinalizedRound < round {
+		return nil, api.ErrNotFinalized
+	}
+
+	tx := d.db.NewTransaction(false)
+	defer tx.Discard()
+
+	var roots []hash.Hash
+	prefix := rootLinkKeyFmt.Encode(round)
+	it := tx.NewIterator(badger.IteratorOptions{Prefix: prefix})
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); it.Next() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var decRound uint64
+		var rootHash hash.Hash
+		var nextRoot hash.Hash
+
+		if !rootLinkKeyFmt.Decode(it.Item().Key(), &decRound, &rootHash, &nextRoot) {
+			// This should not happen as the Badger iterator should take care of it.
+			panic("urkel/db/badger: bad iterator")
+		}
+
+		// Every root has a link to the empty root, so only consider those to
+		// avoid duplicates.
+		if !nextRoot.IsEmpty() {
+			continue
+		}
+		roots = append(roots, rootHash)
+	}
+
+	return roots, nil
+}
+
 func (d *badgerNodeDB) Finalize(ctx context.Context, namespace common.Namespace, round uint64, roots []hash.Hash) error { // nolint: gocyclo
 	if err := d.sanityCheckNamespace(namespace); err != nil {
 		return err
@@ -584,11 +704,18 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, namespace common.Namespace,
 	}
 
 	// Clean any lone nodes.
+	var removedSize uint64
 	for h := range maybeLoneNodes {
 		if notLoneNodes[h] {
 			continue
 		}
 
+		size, err := storedNodeSize(tx, &h)
+		if err != nil {
+			return err
+		}
+		removedSize += size
+
 		key := nodeKeyFmt.Encode(&h)
 		if err := batch.Delete(key); err != nil {
 			return err
@@ -602,7 +729,7 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, namespace common.Namespace,
 	}
 
 	// Commit batch.
-	if err := batch.Flush(); err != nil {
+	if err := d.flushWithSize(batch, 0, removedSize); err != nil {
 		return err
 	}
 
@@ -612,6 +739,13 @@ func (d *badgerNodeDB) Finalize(ctx context.Context, namespace common.Namespace,
 	return nil
 }
 
+func (d *badgerNodeDB) Size() int64 {
+	d.sizeLock.Lock()
+	defer d.sizeLock.Unlock()
+
+	return int64(d.size)
+}
+
 func (d *badgerNodeDB) Prune(ctx context.Context, namespace common.Namespace, round uint64) (int, error) {
 	if err := d.sanityCheckNamespace(namespace); err != nil {
 		return 0, err
@@ -732,8 +866,17 @@ func (d *badgerNodeDB) Prune(ctx context.Context, namespace common.Namespace, ro
 	}
 
 	// Prune all collected hashes.
-	var pruned int
+	var (
+		pruned      int
+		removedSize uint64
+	)
 	for h := range pruneHashes {
+		size, err := storedNodeSize(tx, &h)
+		if err != nil {
+			return 0, err
+		}
+		removedSize += size
+
 		if err = batch.Delete(nodeKeyFmt.Encode(&h)); err != nil {
 			return 0, err
 		}
@@ -741,7 +884,7 @@ func (d *badgerNodeDB) Prune(ctx context.Context, namespace common.Namespace, ro
 	}
 
 	// Commit batch.
-	if err := batch.Flush(); err != nil {
+	if err := d.flushWithSize(batch, 0, removedSize); err != nil {
 		return 0, err
 	}
 
@@ -832,6 +975,7 @@ type badgerBatch struct {
 	annotations  writelog.Annotations
 	removedNodes []node.Node
 	addedNodes   rootAddedNodes
+	addedSizes   map[hash.Hash]uint64
 }
 
 func (ba *badgerBatch) MaybeStartSubtree(subtree api.Subtree, depth node.Depth, subtreeRoot *node.Pointer) api.Subtree {
@@ -952,7 +1096,19 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 		}
 	}
 
-	if err := ba.bat.Flush(); err != nil {
+	// Account for the added nodes which are not already stored.
+	var addedSize uint64
+	for h, size := range ba.addedSizes {
+		storedSize, err := storedNodeSize(tx, &h)
+		if err != nil {
+			return err
+		}
+		if storedSize == 0 {
+			addedSize += size
+		}
+	}
+
+	if err := ba.db.flushWithSize(ba.bat, addedSize, 0); err != nil {
 		return err
 	}
 
@@ -960,6 +1116,7 @@ func (ba *badgerBatch) Commit(root node.Root) error {
 	ba.annotations = nil
 	ba.removedNodes = nil
 	ba.addedNodes = nil
+	ba.addedSizes = nil
 
 	return ba.BaseBatch.Commit(root)
 }
@@ -970,6 +1127,7 @@ func (ba *badgerBatch) Reset() {
 	ba.annotations = nil
 	ba.removedNodes = nil
 	ba.addedNodes = nil
+	ba.addedSizes = nil
 }
 
 type badgerSubtree struct {
@@ -984,6 +1142,10 @@ func (s *badgerSubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
 
 	h := ptr.Node.GetHash()
 	s.batch.addedNodes = append(s.batch.addedNodes, h)
+	if s.batch.addedSizes == nil {
+		s.batch.addedSizes = make(map[hash.Hash]uint64)
+	}
+	s.batch.addedSizes[h] = uint64(len(data))
 	if err = s.batch.bat.Set(nodeKeyFmt.Encode(&h), data); err != nil {
 		return err
 	}
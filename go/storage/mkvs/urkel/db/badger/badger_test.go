@@ -0,0 +1,96 @@
+package badger
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/node"
+)
+
+var testNs = common.NewTestNamespaceFromSeed([]byte("oasis urkel badger test ns"))
+
+func TestVerifyCorruption(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "mkvs.test.badger")
+	require.NoError(err, "TempDir")
+	defer os.RemoveAll(dir)
+
+	ndb, err := New(&api.Config{
+		DB:           dir,
+		DebugNoFsync: true,
+		Namespace:    testNs,
+		MaxCacheSize: 16 * 1024 * 1024,
+	})
+	require.NoError(err, "New")
+	defer ndb.Close()
+
+	tree := urkel.New(nil, ndb)
+	for i := 0; i < 10; i++ {
+		err = tree.Insert(ctx, []byte(fmt.Sprintf("key %d", i)), []byte(fmt.Sprintf("value %d", i)))
+		require.NoError(err, "Insert")
+	}
+	_, rootHash, err := tree.Commit(ctx, testNs, 0)
+	require.NoError(err, "Commit")
+	err = ndb.Finalize(ctx, testNs, 0, []hash.Hash{rootHash})
+	require.NoError(err, "Finalize")
+
+	issues, err := api.VerifyAll(ctx, ndb, testNs)
+	require.NoError(err, "VerifyAll")
+	require.Empty(issues, "VerifyAll should not find any issues before corruption")
+
+	// Collect some leaf nodes to corrupt.
+	root := node.Root{Namespace: testNs, Round: 0, Hash: rootHash}
+	var leaves []*node.LeafNode
+	err = api.Visit(ctx, ndb, root, func(ctx context.Context, n node.Node) bool {
+		if leaf, ok := n.(*node.LeafNode); ok {
+			leaves = append(leaves, leaf)
+		}
+		return true
+	})
+	require.NoError(err, "Visit")
+	require.True(len(leaves) >= 2, "there should be at least two leaf nodes")
+
+	// Replace one stored node with a different one and remove another.
+	corrupted := leaves[0].Hash
+	missing := leaves[1].Hash
+	bogus := &node.LeafNode{Key: leaves[0].Key, Value: []byte("corrupted")}
+	data, err := bogus.MarshalBinary()
+	require.NoError(err, "MarshalBinary")
+
+	bdb := ndb.(*badgerNodeDB).db
+	err = bdb.Update(func(tx *badger.Txn) error {
+		if sErr := tx.Set(nodeKeyFmt.Encode(&corrupted), data); sErr != nil {
+			return sErr
+		}
+		return tx.Delete(nodeKeyFmt.Encode(&missing))
+	})
+	require.NoError(err, "Update")
+
+	issues, err = api.VerifyRound(ctx, ndb, testNs, 0)
+	require.NoError(err, "VerifyRound")
+	require.Len(issues, 2, "VerifyRound should find both corrupted nodes")
+
+	found := make(map[hash.Hash]error)
+	for _, issue := range issues {
+		require.Equal(root, issue.Root, "issue root")
+		found[issue.Node] = issue.Err
+	}
+	require.Equal(api.ErrNodeHashMismatch, found[corrupted], "corrupted node should be reported")
+	require.Equal(api.ErrNodeNotFound, found[missing], "missing node should be reported")
+
+	issues, err = api.VerifyAll(ctx, ndb, testNs)
+	require.NoError(err, "VerifyAll")
+	require.Len(issues, 2, "VerifyAll should find both corrupted nodes")
+}
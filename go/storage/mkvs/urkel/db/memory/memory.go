@@ -0,0 +1,587 @@
+// Package memory provides an in-memory node database.
+//
+// The in-memory node database is not persistent and is only intended to be
+// used for testing.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/api"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/node"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/writelog"
+)
+
+var _ api.NodeDB = (*memoryNodeDB)(nil)
+
+// gcIndexEntry is an entry in the garbage collection index.
+type gcIndexEntry struct {
+	startRound uint64
+	node       hash.Hash
+}
+
+// rootGcUpdate is a pending garbage collection index update.
+type rootGcUpdate struct {
+	endRound uint64
+	entry    gcIndexEntry
+}
+
+// rootInfo is the information stored for each root.
+type rootInfo struct {
+	// nextRoots are the roots derived from this root.
+	nextRoots map[hash.Hash]bool
+
+	// The following fields are only needed until the round is finalized.
+	gcUpdates  []rootGcUpdate
+	addedNodes []hash.Hash
+}
+
+type memoryNodeDB struct {
+	api.CheckpointableDB
+
+	sync.RWMutex
+
+	namespace common.Namespace
+
+	// nodes maps node hashes to serialized nodes.
+	nodes map[hash.Hash][]byte
+	// writeLogs maps (round, new root, old root) to write logs.
+	writeLogs map[uint64]map[hash.Hash]map[hash.Hash]api.HashedDBWriteLog
+	// roots maps (round, root) to root information.
+	roots map[uint64]map[hash.Hash]*rootInfo
+	// gcIndex maps end rounds to node lifetimes ending in that round.
+	gcIndex map[uint64]map[gcIndexEntry]bool
+
+	lastFinalizedRound *uint64
+}
+
+// New creates a new in-memory node database.
+func New(cfg *api.Config) (api.NodeDB, error) {
+	db := &memoryNodeDB{
+		namespace: cfg.Namespace,
+		nodes:     make(map[hash.Hash][]byte),
+		writeLogs: make(map[uint64]map[hash.Hash]map[hash.Hash]api.HashedDBWriteLog),
+		roots:     make(map[uint64]map[hash.Hash]*rootInfo),
+		gcIndex:   make(map[uint64]map[gcIndexEntry]bool),
+	}
+	db.CheckpointableDB = api.NewCheckpointableDB(db)
+
+	return db, nil
+}
+
+func (d *memoryNodeDB) sanityCheckNamespace(ns common.Namespace) error {
+	if !ns.Equal(&d.namespace) {
+		return api.ErrBadNamespace
+	}
+	return nil
+}
+
+func (d *memoryNodeDB) GetNode(root node.Root, ptr *node.Pointer) (node.Node, error) {
+	if ptr == nil || !ptr.IsClean() {
+		panic("urkel/db/memory: attempted to get invalid pointer from node database")
+	}
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return nil, err
+	}
+
+	d.RLock()
+	data, ok := d.nodes[ptr.Hash]
+	d.RUnlock()
+	if !ok {
+		return nil, api.ErrNodeNotFound
+	}
+
+	return node.UnmarshalBinary(data)
+}
+
+func (d *memoryNodeDB) GetWriteLog(ctx context.Context, startRoot node.Root, endRoot node.Root) (writelog.Iterator, error) {
+	if !endRoot.Follows(&startRoot) {
+		return nil, api.ErrRootMustFollowOld
+	}
+	if err := d.sanityCheckNamespace(startRoot.Namespace); err != nil {
+		return nil, err
+	}
+
+	// Start at the end root and search towards the start root, refusing to
+	// traverse more than two hops (same as the Badger node database).
+	const maxAllowedHops = 2
+
+	type wlItem struct {
+		depth       uint8
+		endRootHash hash.Hash
+		logs        []api.HashedDBWriteLog
+		logRoots    []hash.Hash
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+
+	queue := []*wlItem{&wlItem{depth: 0, endRootHash: endRoot.Hash}}
+	for len(queue) > 0 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		curItem := queue[0]
+		queue = queue[1:]
+
+		// Iterate over all write logs that result in the current item.
+		for startRootHash, log := range d.writeLogs[endRoot.Round][curItem.endRootHash] {
+			nextItem := wlItem{
+				depth:       curItem.depth + 1,
+				endRootHash: startRootHash,
+				logs:        append(append([]api.HashedDBWriteLog{}, curItem.logs...), log),
+				logRoots:    append(append([]hash.Hash{}, curItem.logRoots...), curItem.endRootHash),
+			}
+			if nextItem.endRootHash.Equal(&startRoot.Hash) {
+				// Path has been found, stream write logs.
+				var index int
+				return api.ReviveHashedDBWriteLogs(ctx,
+					func() (node.Root, api.HashedDBWriteLog, error) {
+						if index >= len(nextItem.logs) {
+							return node.Root{}, nil, nil
+						}
+
+						root := node.Root{
+							Namespace: endRoot.Namespace,
+							Round:     endRoot.Round,
+							Hash:      nextItem.logRoots[index],
+						}
+						log := nextItem.logs[index]
+
+						index++
+						return root, log, nil
+					},
+					func(root node.Root, h hash.Hash) (*node.LeafNode, error) {
+						leaf, err := d.GetNode(root, &node.Pointer{Hash: h, Clean: true})
+						if err != nil {
+							return nil, err
+						}
+						return leaf.(*node.LeafNode), nil
+					},
+					func() {},
+				)
+			}
+
+			if nextItem.depth < maxAllowedHops {
+				queue = append(queue, &nextItem)
+			}
+		}
+	}
+
+	return nil, api.ErrWriteLogNotFound
+}
+
+func (d *memoryNodeDB) HasRoot(root node.Root) bool {
+	if err := d.sanityCheckNamespace(root.Namespace); err != nil {
+		return false
+	}
+
+	// An empty root is always implicitly present.
+	if root.Hash.IsEmpty() {
+		return true
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+
+	_, exists := d.roots[root.Round][root.Hash]
+	return exists
+}
+
+func (d *memoryNodeDB) GetFinalizedRoots(ctx context.Context, namespace common.Namespace, round uint64) ([]hash.Hash, error) {
+	if err := d.sanityCheckNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	d.RLock()
+	defer d.RUnlock()
+
+	// Make sure that the round has been finalized, so that only the finalized
+	// roots remain.
+	if d.lastFinalizedRound == nil || *d.lastFinalizedRound < round {
+		return nil, api.ErrNotFinalized
+	}
+
+	var roots []hash.Hash
+	for rootHash := range d.roots[round] {
+		roots = append(roots, rootHash)
+	}
+	return roots, nil
+}
+
+func (d *memoryNodeDB) Finalize(ctx context.Context, namespace common.Namespace, round uint64, roots []hash.Hash) error {
+	if err := d.sanityCheckNamespace(namespace); err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	// Make sure that the previous round has been finalized.
+	if round > 0 && d.lastFinalizedRound != nil && *d.lastFinalizedRound < (round-1) {
+		return api.ErrNotFinalized
+	}
+	// Make sure that this round has not yet been finalized.
+	if d.lastFinalizedRound != nil && round <= *d.lastFinalizedRound {
+		return api.ErrAlreadyFinalized
+	}
+
+	// Determine a set of finalized roots. Finalization is transitive, so if
+	// a parent root is finalized the child should be consider finalized too.
+	finalizedRoots := make(map[hash.Hash]bool)
+	for _, rootHash := range roots {
+		finalizedRoots[rootHash] = true
+	}
+
+	roundRoots := d.roots[round]
+	for updated := true; updated; {
+		updated = false
+
+		for rootHash, info := range roundRoots {
+			if finalizedRoots[rootHash] {
+				continue
+			}
+			for nextRoot := range info.nextRoots {
+				if finalizedRoots[nextRoot] {
+					finalizedRoots[rootHash] = true
+					updated = true
+					break
+				}
+			}
+		}
+	}
+
+	// Go through all roots and either commit GC updates or prune them based on
+	// whether they are included in the finalized roots or not.
+	maybeLoneNodes := make(map[hash.Hash]bool)
+	notLoneNodes := make(map[hash.Hash]bool)
+
+	for rootHash, info := range roundRoots {
+		if finalizedRoots[rootHash] {
+			// Commit garbage collection index updates for any finalized roots.
+			for _, u := range info.gcUpdates {
+				if d.gcIndex[u.endRound] == nil {
+					d.gcIndex[u.endRound] = make(map[gcIndexEntry]bool)
+				}
+				d.gcIndex[u.endRound][u.entry] = true
+			}
+
+			// Make sure not to remove any nodes shared with finalized roots.
+			for _, h := range info.addedNodes {
+				notLoneNodes[h] = true
+			}
+
+			// GC updates and the set of added nodes are no longer needed
+			// after finalization.
+			info.gcUpdates = nil
+			info.addedNodes = nil
+		} else {
+			// Remove any non-finalized roots. It is safe to remove these nodes
+			// as they can never be resurrected due to the round being part of the
+			// node hash as long as we make sure that these nodes are not shared
+			// with any finalized roots added in the same round.
+			for _, h := range info.addedNodes {
+				maybeLoneNodes[h] = true
+			}
+			delete(roundRoots, rootHash)
+
+			// Remove write logs for the non-finalized root.
+			delete(d.writeLogs[round], rootHash)
+		}
+	}
+	if len(roundRoots) == 0 {
+		delete(d.roots, round)
+	}
+
+	// Clean any lone nodes.
+	for h := range maybeLoneNodes {
+		if notLoneNodes[h] {
+			continue
+		}
+		delete(d.nodes, h)
+	}
+
+	d.lastFinalizedRound = &round
+
+	return nil
+}
+
+func (d *memoryNodeDB) Prune(ctx context.Context, namespace common.Namespace, round uint64) (int, error) {
+	if err := d.sanityCheckNamespace(namespace); err != nil {
+		return 0, err
+	}
+
+	// Determine the lone roots in the given round first as traversing them
+	// requires fetching nodes, which must be done without holding the lock.
+	d.RLock()
+	// Make sure that the round that we try to prune has been finalized.
+	if d.lastFinalizedRound == nil || *d.lastFinalizedRound < round {
+		d.RUnlock()
+		return 0, api.ErrNotFinalized
+	}
+
+	var loneRoots []hash.Hash
+	for rootHash, info := range d.roots[round] {
+		if len(info.nextRoots) == 0 {
+			loneRoots = append(loneRoots, rootHash)
+		}
+	}
+	d.RUnlock()
+
+	pruneHashes := make(map[hash.Hash]bool)
+
+	for _, rootHash := range loneRoots {
+		// Traverse the root and prune all items created in this round.
+		root := node.Root{Namespace: namespace, Round: round, Hash: rootHash}
+		err := api.Visit(ctx, d, root, func(ctx context.Context, n node.Node) bool {
+			if n.GetCreatedRound() == round {
+				pruneHashes[n.GetHash()] = true
+			}
+			return true
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	prevRound := d.getPreviousRound(round)
+
+	// Iterate over all lifetimes that end in the passed round.
+	for entry := range d.gcIndex[round] {
+		if entry.startRound > prevRound || entry.startRound == round {
+			// Either start round is after the previous round or the node starts and
+			// terminates in the same round. Prune the node(s).
+			pruneHashes[entry.node] = true
+		} else {
+			// Since the current round is being pruned, the lifetime ends at the
+			// previous round.
+			if d.gcIndex[prevRound] == nil {
+				d.gcIndex[prevRound] = make(map[gcIndexEntry]bool)
+			}
+			d.gcIndex[prevRound][entry] = true
+		}
+	}
+	delete(d.gcIndex, round)
+
+	// Prune all roots and write logs in round.
+	delete(d.roots, round)
+	delete(d.writeLogs, round)
+
+	// Prune all collected hashes.
+	for h := range pruneHashes {
+		delete(d.nodes, h)
+	}
+
+	return len(pruneHashes), nil
+}
+
+func (d *memoryNodeDB) Size() int64 {
+	d.RLock()
+	defer d.RUnlock()
+
+	var size int64
+	for _, n := range d.nodes {
+		size += int64(len(n))
+	}
+	return size
+}
+
+func (d *memoryNodeDB) NewBatch(namespace common.Namespace, round uint64, oldRoot node.Root) api.Batch {
+	return &memoryBatch{
+		db:      d,
+		round:   round,
+		oldRoot: oldRoot,
+		nodes:   make(map[hash.Hash][]byte),
+	}
+}
+
+// Close is a no-op.
+func (d *memoryNodeDB) Close() {
+}
+
+// getPreviousRound returns the last round before the given round that has
+// any roots. The caller must hold the lock.
+func (d *memoryNodeDB) getPreviousRound(round uint64) uint64 {
+	var prevRound uint64
+	for r := range d.roots {
+		if r < round && r > prevRound {
+			prevRound = r
+		}
+	}
+	return prevRound
+}
+
+type memoryBatch struct {
+	api.BaseBatch
+
+	db *memoryNodeDB
+
+	round   uint64
+	oldRoot node.Root
+
+	nodes        map[hash.Hash][]byte
+	writeLog     writelog.WriteLog
+	annotations  writelog.Annotations
+	removedNodes []node.Node
+	addedNodes   []hash.Hash
+}
+
+func (ba *memoryBatch) MaybeStartSubtree(subtree api.Subtree, depth node.Depth, subtreeRoot *node.Pointer) api.Subtree {
+	if subtree == nil {
+		return &memorySubtree{batch: ba}
+	}
+	return subtree
+}
+
+func (ba *memoryBatch) PutWriteLog(writeLog writelog.WriteLog, annotations writelog.Annotations) error {
+	ba.writeLog = writeLog
+	ba.annotations = annotations
+	return nil
+}
+
+func (ba *memoryBatch) RemoveNodes(nodes []node.Node) error {
+	ba.removedNodes = nodes
+	return nil
+}
+
+func (ba *memoryBatch) Commit(root node.Root) error {
+	if err := ba.db.sanityCheckNamespace(root.Namespace); err != nil {
+		return err
+	}
+	if !root.Follows(&ba.oldRoot) {
+		return api.ErrRootMustFollowOld
+	}
+
+	if err := ba.commit(root); err != nil {
+		return err
+	}
+
+	ba.Reset()
+
+	return ba.BaseBatch.Commit(root)
+}
+
+func (ba *memoryBatch) commit(root node.Root) error {
+	d := ba.db
+	d.Lock()
+	defer d.Unlock()
+
+	// Make sure that the round that we try to commit into has not yet been
+	// finalized.
+	if d.lastFinalizedRound != nil && *d.lastFinalizedRound >= root.Round {
+		return api.ErrAlreadyFinalized
+	}
+
+	prevRound := d.getPreviousRound(root.Round)
+
+	// Make sure the old root exists before making any changes.
+	var oldInfo *rootInfo
+	if !ba.oldRoot.Hash.IsEmpty() {
+		if prevRound != ba.oldRoot.Round && ba.oldRoot.Round != root.Round {
+			return api.ErrPreviousRoundMismatch
+		}
+
+		var ok bool
+		if oldInfo, ok = d.roots[ba.oldRoot.Round][ba.oldRoot.Hash]; !ok {
+			return api.ErrRootNotFound
+		}
+	}
+
+	// Mark removed nodes for garbage collection. Updates against the GC index
+	// are only applied in case this root is finalized.
+	var gcUpdates []rootGcUpdate
+	for _, n := range ba.removedNodes {
+		// Node lives from the round it was created in up to the previous round.
+		//
+		// NOTE: The node will never be resurrected as the round number is part
+		//       of the node hash.
+		endRound := prevRound
+		if ba.oldRoot.Round == root.Round {
+			// If the previous root is in the same round, the node needs to end
+			// in the same round instead.
+			endRound = root.Round
+		}
+
+		gcUpdates = append(gcUpdates, rootGcUpdate{
+			endRound: endRound,
+			entry: gcIndexEntry{
+				startRound: n.GetCreatedRound(),
+				node:       n.GetHash(),
+			},
+		})
+	}
+
+	// Create root (or update the existing one).
+	if d.roots[root.Round] == nil {
+		d.roots[root.Round] = make(map[hash.Hash]*rootInfo)
+	}
+	info := d.roots[root.Round][root.Hash]
+	if info == nil {
+		info = &rootInfo{nextRoots: make(map[hash.Hash]bool)}
+		d.roots[root.Round][root.Hash] = info
+	}
+	info.gcUpdates = gcUpdates
+	info.addedNodes = ba.addedNodes
+
+	// Update the root link for the old root.
+	if oldInfo != nil {
+		oldInfo.nextRoots[root.Hash] = true
+	}
+
+	// Store write log.
+	if ba.writeLog != nil && ba.annotations != nil {
+		if d.writeLogs[root.Round] == nil {
+			d.writeLogs[root.Round] = make(map[hash.Hash]map[hash.Hash]api.HashedDBWriteLog)
+		}
+		if d.writeLogs[root.Round][root.Hash] == nil {
+			d.writeLogs[root.Round][root.Hash] = make(map[hash.Hash]api.HashedDBWriteLog)
+		}
+		d.writeLogs[root.Round][root.Hash][ba.oldRoot.Hash] = api.MakeHashedDBWriteLog(ba.writeLog, ba.annotations)
+	}
+
+	// Store nodes.
+	for h, data := range ba.nodes {
+		d.nodes[h] = data
+	}
+
+	return nil
+}
+
+func (ba *memoryBatch) Reset() {
+	ba.nodes = make(map[hash.Hash][]byte)
+	ba.writeLog = nil
+	ba.annotations = nil
+	ba.removedNodes = nil
+	ba.addedNodes = nil
+}
+
+type memorySubtree struct {
+	batch *memoryBatch
+}
+
+func (s *memorySubtree) PutNode(depth node.Depth, ptr *node.Pointer) error {
+	data, err := ptr.Node.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	h := ptr.Node.GetHash()
+	s.batch.addedNodes = append(s.batch.addedNodes, h)
+	s.batch.nodes[h] = data
+	return nil
+}
+
+func (s *memorySubtree) VisitCleanNode(depth node.Depth, ptr *node.Pointer) error {
+	return nil
+}
+
+func (s *memorySubtree) Commit() error {
+	return nil
+}
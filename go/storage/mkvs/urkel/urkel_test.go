@@ -16,6 +16,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	db "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/api"
 	badgerDb "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/badger"
+	memoryDb "github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/db/memory"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/node"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/syncer"
 	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel/writelog"
@@ -1738,6 +1739,46 @@ func testPruneLoneRoots(t *testing.T, ndb db.NodeDB) {
 	}
 }
 
+func testVerify(t *testing.T, ndb db.NodeDB) {
+	ctx := context.Background()
+	tree := New(nil, ndb)
+
+	// Verifying an empty database should succeed.
+	issues, err := db.VerifyAll(ctx, ndb, testNs)
+	require.NoError(t, err, "VerifyAll")
+	require.Empty(t, issues, "VerifyAll should not find any issues in an empty database")
+
+	for r := 0; r < 3; r++ {
+		for i := 0; i < 10; i++ {
+			err = tree.Insert(ctx, []byte(fmt.Sprintf("key %d %d", r, i)), []byte(fmt.Sprintf("value %d", i)))
+			require.NoError(t, err, "Insert")
+		}
+		var rootHash hash.Hash
+		_, rootHash, err = tree.Commit(ctx, testNs, uint64(r))
+		require.NoError(t, err, "Commit")
+
+		// Unfinalized rounds cannot be verified.
+		_, err = db.VerifyRound(ctx, ndb, testNs, uint64(r))
+		require.Error(t, err, "VerifyRound should fail for non-finalized rounds")
+		require.Equal(t, db.ErrNotFinalized, err)
+
+		err = ndb.Finalize(ctx, testNs, uint64(r), []hash.Hash{rootHash})
+		require.NoError(t, err, "Finalize")
+
+		roots, err := ndb.GetFinalizedRoots(ctx, testNs, uint64(r))
+		require.NoError(t, err, "GetFinalizedRoots")
+		require.EqualValues(t, []hash.Hash{rootHash}, roots, "GetFinalizedRoots should return the finalized root")
+
+		issues, err = db.VerifyRound(ctx, ndb, testNs, uint64(r))
+		require.NoError(t, err, "VerifyRound")
+		require.Empty(t, issues, "VerifyRound should not find any issues")
+	}
+
+	issues, err = db.VerifyAll(ctx, ndb, testNs)
+	require.NoError(t, err, "VerifyAll")
+	require.Empty(t, issues, "VerifyAll should not find any issues")
+}
+
 func testErrors(t *testing.T, ndb db.NodeDB) {
 	ctx := context.Background()
 
@@ -1855,6 +1896,7 @@ func testBackend(
 		{"PruneLoneRootsShared2", testPruneLoneRootsShared2},
 		{"PruneForkedRoots", testPruneForkedRoots},
 		{"PruneCheckpoints", testPruneCheckpoints},
+		{"Verify", testVerify},
 		{"Errors", testErrors},
 	}
 
@@ -1903,6 +1945,20 @@ func TestUrkelBadgerBackend(t *testing.T) {
 		}, nil)
 }
 
+func TestUrkelMemoryBackend(t *testing.T) {
+	testBackend(t, func(t *testing.T) (db.NodeDB, interface{}) {
+		ndb, err := memoryDb.New(&db.Config{
+			Namespace: testNs,
+		})
+		require.NoError(t, err, "New")
+
+		return ndb, nil
+	},
+		func(t *testing.T, ndb db.NodeDB, custom interface{}) {
+			ndb.Close()
+		}, nil)
+}
+
 func BenchmarkInsertCommitBatch1(b *testing.B) {
 	benchmarkInsertBatch(b, 1, true)
 }
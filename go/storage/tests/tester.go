@@ -245,6 +245,63 @@ func testBasic(t *testing.T, backend api.Backend, namespace common.Namespace, ro
 	logsRootHash := CalculateExpectedNewRoot(t, logs, namespace, round)
 	require.EqualValues(t, logsRootHash, receiptBody.Roots[0])
 
+	// Test resuming GetCheckpoint.
+	require.True(t, len(logs) > 1, "checkpoint should contain multiple entries")
+	partialCtx, cancelPartial := context.WithCancel(ctx)
+	logsIter, err = backend.GetCheckpoint(partialCtx, &api.GetCheckpointRequest{Root: newRoot})
+	require.NoError(t, err, "GetCheckpoint()")
+	var partialLogs api.WriteLog
+	for len(partialLogs) < len(logs)/2 {
+		more, nextErr := logsIter.Next()
+		require.NoError(t, nextErr, "error iterating over WriteLogIterator")
+		require.True(t, more, "partial checkpoint should have more entries")
+		val, valErr := logsIter.Value()
+		require.NoError(t, valErr, "error iterating over WriteLogIterator")
+		partialLogs = append(partialLogs, val)
+	}
+	cancelPartial()
+
+	logsIter, err = backend.GetCheckpoint(ctx, &api.GetCheckpointRequest{
+		Root: newRoot,
+		ResumeToken: &api.CheckpointResumeToken{
+			Root:    newRoot,
+			LastKey: partialLogs[len(partialLogs)-1].Key,
+		},
+	})
+	require.NoError(t, err, "GetCheckpoint(resumed)")
+	resumedLogs := foldWriteLogIterator(t, logsIter)
+	require.Equal(t, logs, append(partialLogs, resumedLogs...), "resumed checkpoint should match full checkpoint")
+
+	// Resuming after any entry should return exactly the remaining entries.
+	for i := range logs {
+		logsIter, err = backend.GetCheckpoint(ctx, &api.GetCheckpointRequest{
+			Root: newRoot,
+			ResumeToken: &api.CheckpointResumeToken{
+				Root:    newRoot,
+				LastKey: logs[i].Key,
+			},
+		})
+		require.NoError(t, err, "GetCheckpoint(resumed)")
+		resumedLogs = foldWriteLogIterator(t, logsIter)
+		require.Equal(t, logs[i+1:], resumedLogs, "checkpoint resumed after entry %d should match", i)
+	}
+
+	// Resuming with a token for a different root should fail.
+	invalidRoot := newRoot
+	invalidRoot.Hash = rootHash
+	logsIter, err = backend.GetCheckpoint(ctx, &api.GetCheckpointRequest{
+		Root: newRoot,
+		ResumeToken: &api.CheckpointResumeToken{
+			Root:    invalidRoot,
+			LastKey: partialLogs[len(partialLogs)-1].Key,
+		},
+	})
+	if err == nil {
+		// Errors may be deferred until the stream is read.
+		_, err = logsIter.Next()
+	}
+	require.Error(t, err, "GetCheckpoint with invalid resume token should fail")
+
 	// Single node tree.
 	root.Empty()
 	wl3 := prepareWriteLog([][]byte{testValues[0]})
@@ -30,3 +30,12 @@ func (w *Worker) CallEnclave(ctx context.Context, request *api.CallEnclaveReques
 
 	return w.callLocal(ctx, request.Payload)
 }
+
+// Call sends the request bytes to the target enclave using the given
+// EnclaveRPC method.
+func (w *Worker) Call(ctx context.Context, method string, request *api.CallEnclaveRequest) ([]byte, error) {
+	if method != api.MethodCallEnclave {
+		return nil, fmt.Errorf("unsupported method: %s", method)
+	}
+	return w.CallEnclave(ctx, request)
+}
@@ -103,6 +103,7 @@ func (w *Worker) Stop() {
 	w.logger.Info("stopping key manager service")
 
 	if !w.enabled {
+		close(w.quitCh)
 		return
 	}
 
@@ -21,6 +21,7 @@ import (
 	"github.com/oasislabs/oasis-core/go/common/node"
 	"github.com/oasislabs/oasis-core/go/common/persistent"
 	consensus "github.com/oasislabs/oasis-core/go/consensus/api"
+	control "github.com/oasislabs/oasis-core/go/control/api"
 	epochtime "github.com/oasislabs/oasis-core/go/epochtime/api"
 	"github.com/oasislabs/oasis-core/go/oasis-node/cmd/common/flags"
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
@@ -56,6 +57,8 @@ var (
 	Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
 	allowUnroutableAddresses bool
+
+	_ control.RegistrationStatusProvider = (*Worker)(nil)
 )
 
 // RegisterNodeHook is a function that is used to update the node descriptor.
@@ -339,6 +342,37 @@ func (w *Worker) InitialRegistrationCh() chan struct{} {
 	return w.initialRegCh
 }
 
+// GetRegistrationStatus returns the node registration status.
+//
+// In case the node will never register, either because no entity is
+// configured or because no workers provide any roles, nil is returned.
+func (w *Worker) GetRegistrationStatus(ctx context.Context) (*control.RegistrationStatus, error) {
+	w.RLock()
+	defer w.RUnlock()
+
+	if !w.entityID.IsValid() || w.registrationSigner == nil || len(w.roleProviders) == 0 {
+		return nil, nil
+	}
+
+	status := &control.RegistrationStatus{}
+	select {
+	case <-w.initialRegCh:
+		status.Registered = true
+	default:
+	}
+	for _, rp := range w.roleProviders {
+		rp.Lock()
+		status.Roles = append(status.Roles, control.RoleStatus{
+			Role:      rp.role,
+			RuntimeID: rp.runtimeID,
+			Available: rp.hook != nil,
+		})
+		rp.Unlock()
+	}
+
+	return status, nil
+}
+
 // NewRoleProvider creates a new role provider slot.
 //
 // Each part of the code that wishes to contribute something to the node descriptor can use this
@@ -11,8 +11,27 @@ import (
 // ModuleName is the storage worker module name.
 const ModuleName = "worker/storage"
 
-// ErrRuntimeNotFound is the error returned when the called references an unknown runtime.
-var ErrRuntimeNotFound = errors.New(ModuleName, 1, "worker/storage: runtime not found")
+var (
+	// ErrRuntimeNotFound is the error returned when the called references an unknown runtime.
+	ErrRuntimeNotFound = errors.New(ModuleName, 1, "worker/storage: runtime not found")
+
+	// ErrWriteLogTooLarge is the error returned when an update request
+	// exceeds the configured write log limits.
+	ErrWriteLogTooLarge = errors.New(ModuleName, 2, "worker/storage: write log too large")
+
+	// ErrNodeDraining is the error returned when an update request is
+	// received while the storage worker is in maintenance mode.
+	ErrNodeDraining = errors.New(ModuleName, 3, "worker/storage: node draining, rejecting updates")
+
+	// ErrInconsistentMergeBatch is the error returned when the operations
+	// in a merge batch contradict each other.
+	ErrInconsistentMergeBatch = errors.New(ModuleName, 4, "worker/storage: inconsistent merge batch")
+
+	// ErrTooManyUpdates is the error returned when an update request is
+	// received while the maximum number of concurrent and queued update
+	// requests has been reached.
+	ErrTooManyUpdates = errors.New(ModuleName, 5, "worker/storage: too many concurrent update requests")
+)
 
 // StorageWorker is the storage worker control API interface.
 type StorageWorker interface {
@@ -21,6 +40,10 @@ type StorageWorker interface {
 
 	// ForceFinalize forces finalization of a specific round.
 	ForceFinalize(ctx context.Context, request *ForceFinalizeRequest) error
+
+	// SetMaintenanceMode enters or exits read-only maintenance mode. While
+	// in maintenance mode, all update requests are rejected.
+	SetMaintenanceMode(ctx context.Context, request *SetMaintenanceModeRequest) error
 }
 
 // GetLastSyncedRoundRequest is a GetLastSyncedRound request.
@@ -40,3 +63,8 @@ type ForceFinalizeRequest struct {
 	RuntimeID common.Namespace `json:"runtime_id"`
 	Round     uint64           `json:"round"`
 }
+
+// SetMaintenanceModeRequest is a SetMaintenanceMode request.
+type SetMaintenanceModeRequest struct {
+	Enabled bool `json:"enabled"`
+}
@@ -16,6 +16,8 @@ var (
 	methodGetLastSyncedRound = serviceName.NewMethodName("GetLastSyncedRound")
 	// methodForceFinalize is the name of the ForceFinalize method.
 	methodForceFinalize = serviceName.NewMethodName("ForceFinalize")
+	// methodSetMaintenanceMode is the name of the SetMaintenanceMode method.
+	methodSetMaintenanceMode = serviceName.NewMethodName("SetMaintenanceMode")
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -30,6 +32,10 @@ var (
 				MethodName: methodForceFinalize.Short(),
 				Handler:    handlerForceFinalize,
 			},
+			{
+				MethodName: methodSetMaintenanceMode.Short(),
+				Handler:    handlerSetMaintenanceMode,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -81,6 +87,29 @@ func handlerForceFinalize( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerSetMaintenanceMode( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(SetMaintenanceModeRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return nil, srv.(StorageWorker).SetMaintenanceMode(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodSetMaintenanceMode.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, srv.(StorageWorker).SetMaintenanceMode(ctx, req.(*SetMaintenanceModeRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 // RegisterService registers a new storage worker service with the given gRPC server.
 func RegisterService(server *grpc.Server, service StorageWorker) {
 	server.RegisterService(&serviceDesc, service)
@@ -102,6 +131,10 @@ func (c *storageWorkerClient) ForceFinalize(ctx context.Context, req *ForceFinal
 	return c.conn.Invoke(ctx, methodForceFinalize.Full(), req, nil)
 }
 
+func (c *storageWorkerClient) SetMaintenanceMode(ctx context.Context, req *SetMaintenanceModeRequest) error {
+	return c.conn.Invoke(ctx, methodSetMaintenanceMode.Full(), req, nil)
+}
+
 // NewStorageWorkerClient creates a new gRPC transaction scheduler
 // client service.
 func NewStorageWorkerClient(c *grpc.ClientConn) StorageWorker {
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/accessctl"
+	"github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+)
+
+var (
+	accessAllowedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_access_allowed_count",
+			Help: "Number of allowed storage accesses",
+		},
+		[]string{"method", "runtime"},
+	)
+	accessDeniedCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_access_denied_count",
+			Help: "Number of denied storage accesses",
+		},
+		[]string{"method", "runtime"},
+	)
+	auditCollectors = []prometheus.Collector{
+		accessAllowedCount,
+		accessDeniedCount,
+	}
+
+	auditMetricsOnce sync.Once
+)
+
+// accessAuditFunc is a function called with the outcome of each storage
+// access control check. A nil err means that access was allowed.
+type accessAuditFunc func(ctx context.Context, subject accessctl.Subject, method accessctl.Action, ns common.Namespace, err error)
+
+// newAccessAuditor creates an access audit function which logs all accesses
+// and records them in Prometheus metrics.
+func newAccessAuditor(logger *logging.Logger) accessAuditFunc {
+	auditMetricsOnce.Do(func() {
+		prometheus.MustRegister(auditCollectors...)
+	})
+
+	return func(ctx context.Context, subject accessctl.Subject, method accessctl.Action, ns common.Namespace, err error) {
+		labels := prometheus.Labels{
+			"method":  string(method),
+			"runtime": ns.String(),
+		}
+
+		if err != nil {
+			accessDeniedCount.With(labels).Inc()
+			logger.Warn("storage access denied",
+				"method", method,
+				"runtime_id", ns,
+				"subject", subject,
+				"err", err,
+			)
+			return
+		}
+
+		accessAllowedCount.With(labels).Inc()
+		logger.Debug("storage access allowed",
+			"method", method,
+			"runtime_id", ns,
+			"subject", subject,
+		)
+	}
+}
+
+// subjectFromContext returns the access control subject of the connected
+// peer or an empty subject if it cannot be determined.
+func subjectFromContext(ctx context.Context) accessctl.Subject {
+	peerCert, err := grpc.PeerCertificateFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return accessctl.SubjectFromX509Certificate(peerCert)
+}
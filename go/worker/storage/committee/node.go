@@ -384,7 +384,14 @@ func (n *Node) fetchDiff(round uint64, prevRoot *urkelNode.Root, thisRoot *urkel
 				"fetch_mask", fetchMask,
 			)
 
-			it, err := n.storageClient.GetDiff(n.ctx, &storageApi.GetDiffRequest{StartRoot: *prevRoot, EndRoot: *thisRoot})
+			it, err := n.storageClient.GetDiff(n.ctx, &storageApi.GetDiffRequest{
+				StartRoot: *prevRoot,
+				EndRoot:   *thisRoot,
+				Options: storageApi.SyncOptions{
+					// Diffs can be large, so save bandwidth.
+					Compression: storageApi.CompressionZstd,
+				},
+			})
 			if err != nil {
 				result.err = err
 				return
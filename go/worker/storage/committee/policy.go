@@ -26,12 +26,13 @@ var (
 			"MergeBatch",
 		},
 	}
-	// NOTE: GetDiff/GetCheckpoint need to be accessible to all storage nodes,
-	// not just the ones in the current storage committee so that new nodes can
-	// sync-up.
+	// NOTE: GetDiff/GetDiffRange/GetCheckpoint need to be accessible to all
+	// storage nodes, not just the ones in the current storage committee so
+	// that new nodes can sync-up.
 	storageNodesPolicy = &committee.AccessPolicy{
 		Actions: []accessctl.Action{
 			"GetDiff",
+			"GetDiffRange",
 			"GetCheckpoint",
 		},
 	}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	storageWorkerAPI "github.com/oasislabs/oasis-core/go/worker/storage/api"
+)
+
+var (
+	updatesInFlight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "oasis_worker_storage_updates_in_flight",
+			Help: "Number of storage update requests currently being processed",
+		},
+	)
+	updatesRejectedCount = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_storage_updates_rejected_count",
+			Help: "Number of storage update requests rejected due to the concurrency limit",
+		},
+	)
+	limiterCollectors = []prometheus.Collector{
+		updatesInFlight,
+		updatesRejectedCount,
+	}
+
+	limiterMetricsOnce sync.Once
+)
+
+// tooManyUpdatesError wraps storageWorkerAPI.ErrTooManyUpdates so that it is
+// reported to gRPC clients with the ResourceExhausted status code.
+type tooManyUpdatesError struct{}
+
+func (tooManyUpdatesError) Error() string {
+	return storageWorkerAPI.ErrTooManyUpdates.Error()
+}
+
+func (tooManyUpdatesError) Unwrap() error {
+	return storageWorkerAPI.ErrTooManyUpdates
+}
+
+func (tooManyUpdatesError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, storageWorkerAPI.ErrTooManyUpdates.Error())
+}
+
+// updateLimiter limits the number of concurrently processed update requests.
+// Requests beyond the limit are queued until a slot becomes available, up to
+// a bounded number of queued requests, after which they are rejected.
+type updateLimiter struct {
+	// slots holds a token for each request being processed.
+	slots chan struct{}
+	// pending holds a token for each request being processed or queued.
+	pending chan struct{}
+}
+
+// acquire waits for a free processing slot. It fails immediately if the
+// queue of waiting requests is full.
+//
+// Each successful call must be followed by a call to release.
+func (l *updateLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	select {
+	case l.pending <- struct{}{}:
+	default:
+		updatesRejectedCount.Inc()
+		return tooManyUpdatesError{}
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		updatesInFlight.Inc()
+		return nil
+	case <-ctx.Done():
+		<-l.pending
+		return ctx.Err()
+	}
+}
+
+// release frees a processing slot obtained via acquire.
+func (l *updateLimiter) release() {
+	if l == nil {
+		return
+	}
+
+	updatesInFlight.Dec()
+	<-l.slots
+	<-l.pending
+}
+
+// newUpdateLimiter creates a new update limiter allowing up to maxConcurrent
+// requests to be processed at once with up to maxQueued additional requests
+// waiting for a slot. A zero maxConcurrent disables the limit.
+func newUpdateLimiter(maxConcurrent, maxQueued uint64) *updateLimiter {
+	if maxConcurrent == 0 {
+		return nil
+	}
+
+	limiterMetricsOnce.Do(func() {
+		prometheus.MustRegister(limiterCollectors...)
+	})
+
+	return &updateLimiter{
+		slots:   make(chan struct{}, maxConcurrent),
+		pending: make(chan struct{}, maxConcurrent+maxQueued),
+	}
+}
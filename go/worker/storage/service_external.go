@@ -3,26 +3,129 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/oasislabs/oasis-core/go/common"
 	"github.com/oasislabs/oasis-core/go/common/accessctl"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/storage/api"
+	storageWorkerAPI "github.com/oasislabs/oasis-core/go/worker/storage/api"
 )
 
+var _ api.DiffRangeBackend = (*storageService)(nil)
+
 // storageService is the service exposed to external clients via gRPC.
 type storageService struct {
 	w       *Worker
 	storage api.Backend
+	// localStorage returns the local storage backend of the given runtime.
+	localStorage func(ns common.Namespace) (api.LocalBackend, error)
+
+	maxWriteLogEntries uint64
+	maxWriteLogSize    uint64
+
+	// updateLimiter is an optional limiter of concurrent update requests.
+	updateLimiter *updateLimiter
+
+	// auditFn is an optional function called with the outcome of each
+	// access control check.
+	auditFn accessAuditFunc
 
 	debugRejectUpdates bool
 }
 
+func (s *storageService) checkAccessAllowed(ctx context.Context, method string, ns common.Namespace) error {
+	err := s.w.grpcPolicy.CheckAccessAllowed(ctx, accessctl.Action(method), ns)
+	if s.auditFn != nil {
+		s.auditFn(ctx, subjectFromContext(ctx), accessctl.Action(method), ns, err)
+	}
+	return err
+}
+
 func (s *storageService) checkUpdateAllowed(ctx context.Context, method string, ns common.Namespace) error {
+	if err := s.checkAccessAllowed(ctx, method, ns); err != nil {
+		return err
+	}
 	if s.debugRejectUpdates {
 		return errors.New("storage: rejecting update operations")
 	}
-	if err := s.w.grpcPolicy.CheckAccessAllowed(ctx, accessctl.Action(method), ns); err != nil {
-		return err
+	if s.w.inMaintenanceMode() {
+		return storageWorkerAPI.ErrNodeDraining
+	}
+	return nil
+}
+
+// checkWriteLogLimits checks that the given write logs do not exceed the
+// configured limits, so that oversized requests are rejected before being
+// handed to the backend.
+func (s *storageService) checkWriteLogLimits(writeLogs ...api.WriteLog) error {
+	var entries, size uint64
+	for _, wl := range writeLogs {
+		for _, entry := range wl {
+			entries++
+			size += uint64(len(entry.Key) + len(entry.Value))
+		}
+	}
+	if entries > s.maxWriteLogEntries || size > s.maxWriteLogSize {
+		return storageWorkerAPI.ErrWriteLogTooLarge
+	}
+	return nil
+}
+
+// checkMergeBatch checks that the operations in a merge batch are
+// consistent, so that contradictory batches are rejected as a whole before
+// any of the operations are handed to the backend.
+//
+// A root may only be derived from a single base, and bases and derived
+// roots must not form a cycle. The empty root is ignored as it can appear
+// in unrelated trees.
+func checkMergeBatch(ops []api.MergeOp) error {
+	baseOf := make(map[hash.Hash]hash.Hash)
+	for _, op := range ops {
+		for _, other := range op.Others {
+			if other.IsEmpty() || other.Equal(&op.Base) {
+				continue
+			}
+			if base, ok := baseOf[other]; ok && !base.Equal(&op.Base) {
+				return fmt.Errorf("%w: root %s derived from multiple bases (%s, %s)",
+					storageWorkerAPI.ErrInconsistentMergeBatch, other, base, op.Base,
+				)
+			}
+			baseOf[other] = op.Base
+		}
+	}
+
+	// Each root has at most one base, so any cycle can be found by
+	// following the bases.
+	for root := range baseOf {
+		visited := map[hash.Hash]bool{root: true}
+		for base, ok := baseOf[root]; ok && !base.IsEmpty(); base, ok = baseOf[base] {
+			if visited[base] {
+				return fmt.Errorf("%w: cyclic merge involving root %s",
+					storageWorkerAPI.ErrInconsistentMergeBatch, base,
+				)
+			}
+			visited[base] = true
+		}
+	}
+
+	return nil
+}
+
+// checkRootAvailable makes sure that the given root is still available in
+// local storage so that requests for pruned roots fail early with an error
+// telling the client to fall back to a checkpoint.
+func (s *storageService) checkRootAvailable(root api.Root) error {
+	if s.localStorage == nil {
+		return nil
+	}
+	localStorage, err := s.localStorage(root.Namespace)
+	if err != nil {
+		// Let the backend handle unknown runtimes.
+		return nil
+	}
+	if !localStorage.HasRoot(root) {
+		return fmt.Errorf("%w: namespace %s round %d", api.ErrRootPruned, root.Namespace, root.Round)
 	}
 	return nil
 }
@@ -61,9 +164,16 @@ func (s *storageService) Apply(ctx context.Context, request *api.ApplyRequest) (
 	if err := s.checkUpdateAllowed(ctx, "Apply", request.Namespace); err != nil {
 		return nil, err
 	}
+	if err := s.checkWriteLogLimits(request.WriteLog); err != nil {
+		return nil, err
+	}
 	if err := s.ensureInitialized(ctx); err != nil {
 		return nil, err
 	}
+	if err := s.updateLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.updateLimiter.release()
 	return s.storage.Apply(ctx, request)
 }
 
@@ -71,9 +181,20 @@ func (s *storageService) ApplyBatch(ctx context.Context, request *api.ApplyBatch
 	if err := s.checkUpdateAllowed(ctx, "ApplyBatch", request.Namespace); err != nil {
 		return nil, err
 	}
+	writeLogs := make([]api.WriteLog, 0, len(request.Ops))
+	for _, op := range request.Ops {
+		writeLogs = append(writeLogs, op.WriteLog)
+	}
+	if err := s.checkWriteLogLimits(writeLogs...); err != nil {
+		return nil, err
+	}
 	if err := s.ensureInitialized(ctx); err != nil {
 		return nil, err
 	}
+	if err := s.updateLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.updateLimiter.release()
 	return s.storage.ApplyBatch(ctx, request)
 }
 
@@ -84,6 +205,10 @@ func (s *storageService) Merge(ctx context.Context, request *api.MergeRequest) (
 	if err := s.ensureInitialized(ctx); err != nil {
 		return nil, err
 	}
+	if err := s.updateLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.updateLimiter.release()
 	return s.storage.Merge(ctx, request)
 }
 
@@ -91,24 +216,34 @@ func (s *storageService) MergeBatch(ctx context.Context, request *api.MergeBatch
 	if err := s.checkUpdateAllowed(ctx, "MergeBatch", request.Namespace); err != nil {
 		return nil, err
 	}
+	if err := checkMergeBatch(request.Ops); err != nil {
+		return nil, err
+	}
 	if err := s.ensureInitialized(ctx); err != nil {
 		return nil, err
 	}
+	if err := s.updateLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer s.updateLimiter.release()
 	return s.storage.MergeBatch(ctx, request)
 }
 
 func (s *storageService) GetDiff(ctx context.Context, request *api.GetDiffRequest) (api.WriteLogIterator, error) {
-	if err := s.w.grpcPolicy.CheckAccessAllowed(ctx, accessctl.Action("GetDiff"), request.StartRoot.Namespace); err != nil {
+	if err := s.checkAccessAllowed(ctx, "GetDiff", request.StartRoot.Namespace); err != nil {
 		return nil, err
 	}
 	if err := s.ensureInitialized(ctx); err != nil {
 		return nil, err
 	}
+	if err := s.checkRootAvailable(request.StartRoot); err != nil {
+		return nil, err
+	}
 	return s.storage.GetDiff(ctx, request)
 }
 
 func (s *storageService) GetCheckpoint(ctx context.Context, request *api.GetCheckpointRequest) (api.WriteLogIterator, error) {
-	if err := s.w.grpcPolicy.CheckAccessAllowed(ctx, accessctl.Action("GetCheckpoint"), request.Root.Namespace); err != nil {
+	if err := s.checkAccessAllowed(ctx, "GetCheckpoint", request.Root.Namespace); err != nil {
 		return nil, err
 	}
 	if err := s.ensureInitialized(ctx); err != nil {
@@ -117,6 +252,20 @@ func (s *storageService) GetCheckpoint(ctx context.Context, request *api.GetChec
 	return s.storage.GetCheckpoint(ctx, request)
 }
 
+func (s *storageService) GetDiffRange(ctx context.Context, request *api.GetDiffRangeRequest, fn func(*api.DiffRangeChunk) error) error {
+	if err := s.checkAccessAllowed(ctx, "GetDiffRange", request.Namespace); err != nil {
+		return err
+	}
+	if err := s.ensureInitialized(ctx); err != nil {
+		return err
+	}
+	backend, ok := s.storage.(api.DiffRangeBackend)
+	if !ok {
+		return api.ErrUnsupported
+	}
+	return backend.GetDiffRange(ctx, request, fn)
+}
+
 func (s *storageService) Cleanup() {
 }
 
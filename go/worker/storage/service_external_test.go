@@ -0,0 +1,546 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	cryptoTLS "crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasislabs/oasis-core/go/common"
+	"github.com/oasislabs/oasis-core/go/common/accessctl"
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	memorySigner "github.com/oasislabs/oasis-core/go/common/crypto/signature/signers/memory"
+	"github.com/oasislabs/oasis-core/go/common/crypto/tls"
+	"github.com/oasislabs/oasis-core/go/common/grpc"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	genesisTestHelpers "github.com/oasislabs/oasis-core/go/genesis/tests/helpers"
+	"github.com/oasislabs/oasis-core/go/storage/api"
+	"github.com/oasislabs/oasis-core/go/storage/database"
+	"github.com/oasislabs/oasis-core/go/storage/mkvs/urkel"
+	storageWorkerAPI "github.com/oasislabs/oasis-core/go/worker/storage/api"
+)
+
+// unreachableBackend is a storage backend that fails the test if any of
+// its methods are called.
+type unreachableBackend struct {
+	api.Backend
+
+	t *testing.T
+}
+
+func (b *unreachableBackend) MergeBatch(ctx context.Context, request *api.MergeBatchRequest) ([]*api.Receipt, error) {
+	b.t.Fatalf("MergeBatch should not reach the backend")
+	return nil, nil
+}
+
+func (b *unreachableBackend) ApplyBatch(ctx context.Context, request *api.ApplyBatchRequest) ([]*api.Receipt, error) {
+	b.t.Fatalf("ApplyBatch should not reach the backend")
+	return nil, nil
+}
+
+// readOnlyBackend is a storage backend that only supports reads.
+type readOnlyBackend struct {
+	api.Backend
+
+	initCh chan struct{}
+}
+
+func (b *readOnlyBackend) SyncGet(ctx context.Context, request *api.GetRequest) (*api.ProofResponse, error) {
+	return &api.ProofResponse{}, nil
+}
+
+func (b *readOnlyBackend) Merge(ctx context.Context, request *api.MergeRequest) ([]*api.Receipt, error) {
+	return nil, api.ErrUnsupported
+}
+
+func (b *readOnlyBackend) Initialized() <-chan struct{} {
+	return b.initCh
+}
+
+// noDiffBackend is a local storage backend that fails the test if a diff
+// is requested from it.
+type noDiffBackend struct {
+	api.LocalBackend
+
+	t *testing.T
+}
+
+func (b *noDiffBackend) GetDiff(ctx context.Context, request *api.GetDiffRequest) (api.WriteLogIterator, error) {
+	b.t.Fatalf("GetDiff should not reach the backend")
+	return nil, nil
+}
+
+// blockingBackend is a storage backend whose applies block until released.
+type blockingBackend struct {
+	api.Backend
+
+	initCh    chan struct{}
+	enteredCh chan struct{}
+	releaseCh chan struct{}
+}
+
+func (b *blockingBackend) Apply(ctx context.Context, request *api.ApplyRequest) ([]*api.Receipt, error) {
+	b.enteredCh <- struct{}{}
+	<-b.releaseCh
+	return nil, nil
+}
+
+func (b *blockingBackend) Initialized() <-chan struct{} {
+	return b.initCh
+}
+
+func TestStorageServiceWriteLogLimits(t *testing.T) {
+	require := require.New(t)
+
+	cert, err := tls.Generate("oasis-node")
+	require.NoError(err, "Generate")
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: cryptoTLS.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			},
+		},
+	})
+
+	ns := common.NewTestNamespaceFromSeed([]byte("worker storage write log limits test ns"))
+	policy := accessctl.NewPolicy()
+	w := &Worker{grpcPolicy: grpc.NewDynamicRuntimePolicyChecker()}
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	s := &storageService{
+		w:                  w,
+		storage:            &unreachableBackend{t: t},
+		maxWriteLogEntries: 2,
+		maxWriteLogSize:    16,
+	}
+
+	entry := api.LogEntry{Key: []byte("key"), Value: []byte("value")}
+
+	// Within limits.
+	err = s.checkWriteLogLimits(api.WriteLog{entry}, api.WriteLog{entry})
+	require.NoError(err, "write logs within limits should be allowed")
+
+	// Just over the entry count limit.
+	overEntries := &api.ApplyBatchRequest{
+		Namespace: ns,
+		Ops: []api.ApplyOp{
+			{WriteLog: api.WriteLog{entry}},
+			{WriteLog: api.WriteLog{entry, entry}},
+		},
+	}
+
+	// Unauthorized requests should be rejected before their body is validated.
+	_, err = s.ApplyBatch(ctx, overEntries)
+	require.Error(err, "ApplyBatch should be denied without access")
+	require.NotEqual(storageWorkerAPI.ErrWriteLogTooLarge, err, "ApplyBatch should be denied before checking the limits")
+
+	policy.Allow(accessctl.SubjectFromX509Certificate(x509Cert), accessctl.Action("ApplyBatch"))
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+
+	_, err = s.ApplyBatch(ctx, overEntries)
+	require.Equal(storageWorkerAPI.ErrWriteLogTooLarge, err, "ApplyBatch over entry limit should be rejected")
+
+	// Just over the size limit.
+	_, err = s.ApplyBatch(ctx, &api.ApplyBatchRequest{
+		Namespace: ns,
+		Ops: []api.ApplyOp{
+			{WriteLog: api.WriteLog{entry}},
+			{WriteLog: api.WriteLog{{Key: []byte("key"), Value: []byte("value!")}}},
+		},
+	})
+	require.Equal(storageWorkerAPI.ErrWriteLogTooLarge, err, "ApplyBatch over size limit should be rejected")
+}
+
+func TestStorageServiceMaintenanceMode(t *testing.T) {
+	require := require.New(t)
+
+	cert, err := tls.Generate("oasis-node")
+	require.NoError(err, "Generate")
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: cryptoTLS.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			},
+		},
+	})
+
+	var ns common.Namespace
+	policy := accessctl.NewPolicy()
+	w := &Worker{
+		logger:     logging.GetLogger("worker/storage/test"),
+		grpcPolicy: grpc.NewDynamicRuntimePolicyChecker(),
+	}
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	backend := &readOnlyBackend{initCh: make(chan struct{})}
+	close(backend.initCh)
+	s := &storageService{
+		w:                  w,
+		storage:            backend,
+		maxWriteLogEntries: 1,
+		maxWriteLogSize:    1,
+	}
+
+	err = w.SetMaintenanceMode(ctx, &storageWorkerAPI.SetMaintenanceModeRequest{Enabled: true})
+	require.NoError(err, "SetMaintenanceMode(true)")
+
+	// Unauthorized requests should be denied before revealing the maintenance mode.
+	_, err = s.Apply(ctx, &api.ApplyRequest{Namespace: ns})
+	require.Error(err, "Apply should be denied without access")
+	require.NotEqual(storageWorkerAPI.ErrNodeDraining, err, "Apply should be denied before checking the maintenance mode")
+
+	subject := accessctl.SubjectFromX509Certificate(x509Cert)
+	policy.Allow(subject, accessctl.Action("Apply"))
+	policy.Allow(subject, accessctl.Action("Merge"))
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+
+	_, err = s.Apply(ctx, &api.ApplyRequest{})
+	require.Equal(storageWorkerAPI.ErrNodeDraining, err, "Apply should be rejected in maintenance mode")
+	_, err = s.Merge(ctx, &api.MergeRequest{})
+	require.Equal(storageWorkerAPI.ErrNodeDraining, err, "Merge should be rejected in maintenance mode")
+	_, err = s.SyncGet(ctx, &api.GetRequest{})
+	require.NoError(err, "SyncGet should work in maintenance mode")
+
+	err = w.SetMaintenanceMode(ctx, &storageWorkerAPI.SetMaintenanceModeRequest{Enabled: false})
+	require.NoError(err, "SetMaintenanceMode(false)")
+
+	_, err = s.Merge(ctx, &api.MergeRequest{})
+	require.Equal(api.ErrUnsupported, err, "Merge should reach the backend after exiting maintenance mode")
+}
+
+func TestStorageServiceAccessAudit(t *testing.T) {
+	require := require.New(t)
+
+	cert, err := tls.Generate("oasis-node")
+	require.NoError(err, "Generate")
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	subject := accessctl.SubjectFromX509Certificate(x509Cert)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: cryptoTLS.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			},
+		},
+	})
+
+	ns := common.NewTestNamespaceFromSeed([]byte("worker storage access audit test ns"))
+	policy := accessctl.NewPolicy()
+	policy.Allow(subject, accessctl.Action("GetDiff"))
+	w := &Worker{grpcPolicy: grpc.NewDynamicRuntimePolicyChecker()}
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+
+	type auditEvent struct {
+		subject accessctl.Subject
+		method  accessctl.Action
+		ns      common.Namespace
+		allowed bool
+	}
+	var events []auditEvent
+	s := &storageService{
+		w: w,
+		auditFn: func(ctx context.Context, subject accessctl.Subject, method accessctl.Action, ns common.Namespace, err error) {
+			events = append(events, auditEvent{subject, method, ns, err == nil})
+		},
+	}
+
+	err = s.checkAccessAllowed(ctx, "GetDiff", ns)
+	require.NoError(err, "GetDiff access should be allowed")
+	err = s.checkAccessAllowed(ctx, "GetCheckpoint", ns)
+	require.Error(err, "GetCheckpoint access should be denied")
+	require.Equal([]auditEvent{
+		{subject, accessctl.Action("GetDiff"), ns, true},
+		{subject, accessctl.Action("GetCheckpoint"), ns, false},
+	}, events, "audit events should be recorded")
+
+	// Auditing is optional.
+	s.auditFn = nil
+	err = s.checkAccessAllowed(ctx, "GetDiff", ns)
+	require.NoError(err, "GetDiff access should be allowed without auditing")
+}
+
+func TestStorageServiceMergeBatchConsistency(t *testing.T) {
+	require := require.New(t)
+
+	var rootA, rootB, rootC, rootD, emptyRoot hash.Hash
+	rootA.FromBytes([]byte("root A"))
+	rootB.FromBytes([]byte("root B"))
+	rootC.FromBytes([]byte("root C"))
+	rootD.FromBytes([]byte("root D"))
+	emptyRoot.Empty()
+
+	for _, tc := range []struct {
+		name  string
+		ops   []api.MergeOp
+		valid bool
+	}{
+		{"Independent", []api.MergeOp{{Base: rootA, Others: []hash.Hash{rootB, rootC}}, {Base: emptyRoot, Others: []hash.Hash{rootD}}}, true},
+		{"Unchanged", []api.MergeOp{{Base: rootA, Others: []hash.Hash{rootA, rootB}}}, true},
+		{"SameBase", []api.MergeOp{{Base: rootA, Others: []hash.Hash{rootB}}, {Base: rootA, Others: []hash.Hash{rootB}}}, true},
+		{"EmptyRoot", []api.MergeOp{{Base: rootA, Others: []hash.Hash{emptyRoot}}, {Base: emptyRoot, Others: []hash.Hash{emptyRoot}}}, true},
+		{"Conflicting", []api.MergeOp{{Base: rootA, Others: []hash.Hash{rootC}}, {Base: rootB, Others: []hash.Hash{rootC}}}, false},
+		{"Cyclic", []api.MergeOp{{Base: rootA, Others: []hash.Hash{rootB}}, {Base: rootB, Others: []hash.Hash{rootA}}}, false},
+		{"CyclicLong", []api.MergeOp{{Base: rootA, Others: []hash.Hash{rootB}}, {Base: rootB, Others: []hash.Hash{rootC}}, {Base: rootC, Others: []hash.Hash{rootA}}}, false},
+	} {
+		err := checkMergeBatch(tc.ops)
+		if tc.valid {
+			require.NoError(err, "checkMergeBatch(%s)", tc.name)
+		} else {
+			require.True(errors.Is(err, storageWorkerAPI.ErrInconsistentMergeBatch), "checkMergeBatch(%s) should fail", tc.name)
+		}
+	}
+
+	cert, err := tls.Generate("oasis-node")
+	require.NoError(err, "Generate")
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: cryptoTLS.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			},
+		},
+	})
+
+	ns := common.NewTestNamespaceFromSeed([]byte("worker storage merge batch consistency test ns"))
+	policy := accessctl.NewPolicy()
+	w := &Worker{grpcPolicy: grpc.NewDynamicRuntimePolicyChecker()}
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	s := &storageService{w: w, storage: &unreachableBackend{t: t}}
+	request := &api.MergeBatchRequest{
+		Namespace: ns,
+		Ops:       []api.MergeOp{{Base: rootA, Others: []hash.Hash{rootC}}, {Base: rootB, Others: []hash.Hash{rootC}}},
+	}
+
+	// Unauthorized requests should be rejected before the batch is validated.
+	_, err = s.MergeBatch(ctx, request)
+	require.Error(err, "MergeBatch should be denied without access")
+	require.False(errors.Is(err, storageWorkerAPI.ErrInconsistentMergeBatch), "MergeBatch should be denied before checking the batch")
+
+	// Inconsistent batches should be rejected before reaching the backend.
+	policy.Allow(accessctl.SubjectFromX509Certificate(x509Cert), accessctl.Action("MergeBatch"))
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	_, err = s.MergeBatch(ctx, request)
+	require.True(errors.Is(err, storageWorkerAPI.ErrInconsistentMergeBatch), "MergeBatch should reject inconsistent batches")
+}
+
+func TestStorageServiceGetDiffRangeAccess(t *testing.T) {
+	require := require.New(t)
+
+	cert, err := tls.Generate("oasis-node")
+	require.NoError(err, "Generate")
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: cryptoTLS.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			},
+		},
+	})
+
+	ns := common.NewTestNamespaceFromSeed([]byte("worker storage diff range access test ns"))
+	policy := accessctl.NewPolicy()
+	w := &Worker{grpcPolicy: grpc.NewDynamicRuntimePolicyChecker()}
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	backend := &readOnlyBackend{initCh: make(chan struct{})}
+	close(backend.initCh)
+	s := &storageService{w: w, storage: backend}
+
+	request := &api.GetDiffRangeRequest{Namespace: ns, StartRound: 1, EndRound: 2}
+	noChunks := func(chunk *api.DiffRangeChunk) error {
+		t.Fatalf("no chunks should be produced")
+		return nil
+	}
+
+	err = s.GetDiffRange(ctx, request, noChunks)
+	require.Error(err, "GetDiffRange should be denied without access")
+	require.NotEqual(api.ErrUnsupported, err, "GetDiffRange should be denied before reaching the backend")
+
+	policy.Allow(accessctl.SubjectFromX509Certificate(x509Cert), accessctl.Action("GetDiffRange"))
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	err = s.GetDiffRange(ctx, request, noChunks)
+	require.Equal(api.ErrUnsupported, err, "GetDiffRange should reach the backend with access")
+}
+
+func TestStorageServiceGetDiffPrunedRoot(t *testing.T) {
+	require := require.New(t)
+
+	genesisTestHelpers.SetTestChainContext()
+
+	cert, err := tls.Generate("oasis-node")
+	require.NoError(err, "Generate")
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: cryptoTLS.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			},
+		},
+	})
+
+	ns := common.NewTestNamespaceFromSeed([]byte("worker storage pruned diff test ns"))
+	signer, err := memorySigner.NewSigner(rand.Reader)
+	require.NoError(err, "NewSigner")
+	localBackend, err := database.New(&api.Config{
+		Backend:           database.BackendNameMemory,
+		Signer:            signer,
+		ApplyLockLRUSlots: 100,
+		Namespace:         ns,
+	})
+	require.NoError(err, "database.New")
+	defer localBackend.Cleanup()
+	localStorage := localBackend.(api.LocalBackend)
+
+	// Populate two rounds and prune the first one.
+	var roots []api.Root
+	prevRoot := api.Root{Namespace: ns}
+	prevRoot.Hash.Empty()
+	tree := urkel.New(nil, nil)
+	defer tree.Close()
+	for round := uint64(1); round <= 2; round++ {
+		wl := api.WriteLog{{Key: []byte("key"), Value: []byte(fmt.Sprintf("value %d", round))}}
+		err = tree.Insert(ctx, wl[0].Key, wl[0].Value)
+		require.NoError(err, "Insert")
+		root := api.Root{Namespace: ns, Round: round}
+		_, root.Hash, err = tree.Commit(ctx, ns, round)
+		require.NoError(err, "Commit")
+		_, err = localStorage.Apply(ctx, &api.ApplyRequest{
+			Namespace: ns,
+			SrcRound:  prevRoot.Round,
+			SrcRoot:   prevRoot.Hash,
+			DstRound:  round,
+			DstRoot:   root.Hash,
+			WriteLog:  wl,
+		})
+		require.NoError(err, "Apply")
+		err = localStorage.Finalize(ctx, ns, round, []hash.Hash{root.Hash})
+		require.NoError(err, "Finalize")
+		roots = append(roots, root)
+		prevRoot = root
+	}
+	_, err = localStorage.Prune(ctx, ns, 1)
+	require.NoError(err, "Prune")
+	require.False(localStorage.HasRoot(roots[0]), "start root should be pruned")
+
+	policy := accessctl.NewPolicy()
+	policy.Allow(accessctl.SubjectFromX509Certificate(x509Cert), accessctl.Action("GetDiff"))
+	w := &Worker{grpcPolicy: grpc.NewDynamicRuntimePolicyChecker()}
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	s := &storageService{
+		w:       w,
+		storage: &noDiffBackend{LocalBackend: localStorage, t: t},
+		localStorage: func(common.Namespace) (api.LocalBackend, error) {
+			return localStorage, nil
+		},
+	}
+
+	_, err = s.GetDiff(ctx, &api.GetDiffRequest{StartRoot: roots[0], EndRoot: roots[1]})
+	require.True(errors.Is(err, api.ErrRootPruned), "GetDiff should fail with ErrRootPruned for a pruned start root")
+	require.Contains(err.Error(), "round 1", "error should contain the round of the pruned root")
+}
+
+func TestStorageServiceUpdateLimiter(t *testing.T) {
+	require := require.New(t)
+
+	cert, err := tls.Generate("oasis-node")
+	require.NoError(err, "Generate")
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(err, "ParseCertificate")
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: cryptoTLS.ConnectionState{
+				PeerCertificates: []*x509.Certificate{x509Cert},
+			},
+		},
+	})
+
+	ns := common.NewTestNamespaceFromSeed([]byte("worker storage update limiter test ns"))
+	policy := accessctl.NewPolicy()
+	policy.Allow(accessctl.SubjectFromX509Certificate(x509Cert), accessctl.Action("Apply"))
+	w := &Worker{grpcPolicy: grpc.NewDynamicRuntimePolicyChecker()}
+	w.grpcPolicy.SetAccessPolicy(policy, ns)
+	backend := &blockingBackend{
+		initCh:    make(chan struct{}),
+		enteredCh: make(chan struct{}),
+		releaseCh: make(chan struct{}),
+	}
+	close(backend.initCh)
+	const (
+		maxConcurrent = 2
+		maxQueued     = 1
+	)
+	s := &storageService{
+		w:                  w,
+		storage:            backend,
+		maxWriteLogEntries: 1,
+		maxWriteLogSize:    1,
+		updateLimiter:      newUpdateLimiter(maxConcurrent, maxQueued),
+	}
+
+	// Fill all processing slots and the queue.
+	errCh := make(chan error, maxConcurrent+maxQueued)
+	apply := func() {
+		_, applyErr := s.Apply(ctx, &api.ApplyRequest{Namespace: ns})
+		errCh <- applyErr
+	}
+	for i := 0; i < maxConcurrent+maxQueued; i++ {
+		go apply()
+	}
+	for i := 0; i < maxConcurrent; i++ {
+		<-backend.enteredCh
+	}
+	for len(s.updateLimiter.pending) < maxConcurrent+maxQueued {
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case <-backend.enteredCh:
+		t.Fatalf("queued applies should not reach the backend")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Further requests are rejected.
+	_, err = s.Apply(ctx, &api.ApplyRequest{Namespace: ns})
+	require.True(errors.Is(err, storageWorkerAPI.ErrTooManyUpdates), "Apply should be rejected when the queue is full")
+	require.Equal(codes.ResourceExhausted, status.Code(err), "rejected applies should be reported as ResourceExhausted")
+
+	// Queued requests are processed once slots are freed.
+	backend.releaseCh <- struct{}{}
+	<-backend.enteredCh
+	close(backend.releaseCh)
+	for i := 0; i < maxConcurrent+maxQueued; i++ {
+		require.NoError(<-errCh, "Apply")
+	}
+	require.Len(s.updateLimiter.slots, 0, "all slots should be released")
+	require.Len(s.updateLimiter.pending, 0, "all pending requests should be released")
+
+	// Canceled requests give up their place in the queue.
+	backend.releaseCh = make(chan struct{})
+	for i := 0; i < maxConcurrent; i++ {
+		go apply()
+		<-backend.enteredCh
+	}
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = s.Apply(cancelCtx, &api.ApplyRequest{Namespace: ns})
+	require.Equal(context.Canceled, err, "queued Apply should fail when canceled")
+	close(backend.releaseCh)
+	for i := 0; i < maxConcurrent; i++ {
+		require.NoError(<-errCh, "Apply")
+	}
+	require.Len(s.updateLimiter.pending, 0, "canceled requests should be released")
+}
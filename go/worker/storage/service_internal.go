@@ -30,3 +30,28 @@ func (w *Worker) ForceFinalize(ctx context.Context, request *api.ForceFinalizeRe
 
 	return node.ForceFinalize(ctx, request.Round)
 }
+
+func (w *Worker) SetMaintenanceMode(ctx context.Context, request *api.SetMaintenanceModeRequest) error {
+	w.maintenanceLock.Lock()
+	defer w.maintenanceLock.Unlock()
+
+	if w.maintenanceMode == request.Enabled {
+		return nil
+	}
+	w.maintenanceMode = request.Enabled
+
+	if request.Enabled {
+		w.logger.Info("entering maintenance mode, rejecting updates")
+	} else {
+		w.logger.Info("exiting maintenance mode, accepting updates")
+	}
+
+	return nil
+}
+
+func (w *Worker) inMaintenanceMode() bool {
+	w.maintenanceLock.RLock()
+	defer w.maintenanceLock.RUnlock()
+
+	return w.maintenanceMode
+}
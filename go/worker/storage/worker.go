@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -30,6 +31,13 @@ const (
 	CfgWorkerEnabled      = "worker.storage.enabled"
 	cfgWorkerFetcherCount = "worker.storage.fetcher_count"
 
+	cfgWorkerMaxWriteLogEntries = "worker.storage.max_write_log_entries"
+	cfgWorkerMaxWriteLogSize    = "worker.storage.max_write_log_size"
+	cfgWorkerAuditAccess        = "worker.storage.audit_access"
+
+	cfgWorkerMaxConcurrentUpdates = "worker.storage.max_concurrent_updates"
+	cfgWorkerMaxQueuedUpdates     = "worker.storage.max_queued_updates"
+
 	// CfgWorkerDebugIgnoreApply is a debug option that makes the worker ignore
 	// all apply operations.
 	CfgWorkerDebugIgnoreApply = "worker.debug.storage.ignore_apply"
@@ -63,6 +71,9 @@ type Worker struct {
 	fetchPool  *workerpool.Pool
 
 	grpcPolicy *grpc.DynamicRuntimePolicyChecker
+
+	maintenanceLock sync.RWMutex
+	maintenanceMode bool
 }
 
 // New constructs a new storage worker.
@@ -115,11 +126,22 @@ func New(
 
 		// Attach storage interface to gRPC server.
 		s.grpcPolicy = grpc.NewDynamicRuntimePolicyChecker()
-		api.RegisterService(s.commonWorker.Grpc.Server(), &storageService{
+		svc := &storageService{
 			w:                  s,
 			storage:            s.commonWorker.RuntimeRegistry.StorageRouter(),
+			localStorage:       s.getLocalStorage,
+			maxWriteLogEntries: viper.GetUint64(cfgWorkerMaxWriteLogEntries),
+			maxWriteLogSize:    uint64(viper.GetSizeInBytes(cfgWorkerMaxWriteLogSize)),
 			debugRejectUpdates: viper.GetBool(CfgWorkerDebugIgnoreApply) && flags.DebugDontBlameOasis(),
-		})
+			updateLimiter: newUpdateLimiter(
+				viper.GetUint64(cfgWorkerMaxConcurrentUpdates),
+				viper.GetUint64(cfgWorkerMaxQueuedUpdates),
+			),
+		}
+		if viper.GetBool(cfgWorkerAuditAccess) {
+			svc.auditFn = newAccessAuditor(logging.GetLogger("worker/storage/audit"))
+		}
+		api.RegisterService(s.commonWorker.Grpc.Server(), svc)
 
 		// Start storage node for every runtime.
 		for _, rt := range s.commonWorker.GetRuntimes() {
@@ -135,6 +157,18 @@ func New(
 	return s, nil
 }
 
+func (s *Worker) getLocalStorage(ns common.Namespace) (api.LocalBackend, error) {
+	rt, err := s.commonWorker.RuntimeRegistry.GetRuntime(ns)
+	if err != nil {
+		return nil, err
+	}
+	localStorage, ok := rt.Storage().(api.LocalBackend)
+	if !ok {
+		return nil, committee.ErrNonLocalBackend
+	}
+	return localStorage, nil
+}
+
 func (s *Worker) registerRuntime(commonNode *committeeCommon.Node) error {
 	id := commonNode.Runtime.ID()
 	s.logger.Info("registering new runtime",
@@ -301,6 +335,11 @@ func (s *Worker) initGenesis(gen *genesis.Document) error {
 func init() {
 	Flags.Bool(CfgWorkerEnabled, false, "Enable storage worker")
 	Flags.Uint(cfgWorkerFetcherCount, 4, "Number of concurrent storage diff fetchers")
+	Flags.Uint64(cfgWorkerMaxWriteLogEntries, 1000000, "Maximum number of write log entries in a single update request")
+	Flags.String(cfgWorkerMaxWriteLogSize, "64mb", "Maximum total size of write logs in a single update request")
+	Flags.Bool(cfgWorkerAuditAccess, false, "Log and record metrics for all storage access control checks")
+	Flags.Uint64(cfgWorkerMaxConcurrentUpdates, 32, "Maximum number of concurrently processed update requests (0 = unlimited)")
+	Flags.Uint64(cfgWorkerMaxQueuedUpdates, 128, "Maximum number of update requests waiting for processing")
 	Flags.Bool(CfgWorkerDebugIgnoreApply, false, "Ignore Apply operations (for debugging purposes)")
 	_ = Flags.MarkHidden(CfgWorkerDebugIgnoreApply)
 
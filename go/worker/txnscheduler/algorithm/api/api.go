@@ -2,7 +2,11 @@
 package api
 
 import (
+	"context"
+
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	consensusTx "github.com/oasislabs/oasis-core/go/consensus/api/transaction"
 	"github.com/oasislabs/oasis-core/go/runtime/transaction"
 	"github.com/oasislabs/oasis-core/go/worker/common/committee"
 )
@@ -46,3 +50,11 @@ type TransactionDispatcher interface {
 	// Dispatch attempts to dispatch a batch to a executor committee.
 	Dispatch(committeeID hash.Hash, batch transaction.RawBatch) error
 }
+
+// GasEstimator estimates the amount of gas required to execute a consensus
+// transaction.
+type GasEstimator interface {
+	// EstimateGas calculates the amount of gas required to execute the given
+	// transaction.
+	EstimateGas(ctx context.Context, caller signature.PublicKey, tx *consensusTx.Transaction) (consensusTx.Gas, error)
+}
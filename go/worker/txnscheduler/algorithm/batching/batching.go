@@ -4,6 +4,7 @@ package batching
 import (
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
 	flag "github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
@@ -12,6 +13,7 @@ import (
 	registry "github.com/oasislabs/oasis-core/go/registry/api"
 	"github.com/oasislabs/oasis-core/go/worker/common/committee"
 	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/algorithm/api"
+	txnscheduler "github.com/oasislabs/oasis-core/go/worker/txnscheduler/api"
 )
 
 const (
@@ -21,9 +23,36 @@ const (
 	cfgMaxQueueSize = "worker.txnscheduler.batching.max_queue_size"
 )
 
+const (
+	dispatchReasonSize    = "size"
+	dispatchReasonTimeout = "timeout"
+)
+
 // Flags has the configuration flag for the batching algorithm.
 var Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
+var (
+	queueFullRejections = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_txnscheduler_batching_queue_full_rejections",
+			Help: "Number of transactions rejected due to the incoming queue being full",
+		},
+	)
+	dispatchedBatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oasis_worker_txnscheduler_batching_dispatched_batches",
+			Help: "Number of dispatched batches by reason (size or timeout)",
+		},
+		[]string{"reason"},
+	)
+	batchingCollectors = []prometheus.Collector{
+		queueFullRejections,
+		dispatchedBatches,
+	}
+
+	metricsOnce sync.Once
+)
+
 type batchingState struct {
 	sync.RWMutex
 
@@ -86,6 +115,12 @@ func (s *batchingState) scheduleBatch(force bool) error {
 			}
 			return err
 		}
+
+		reason := dispatchReasonSize
+		if force {
+			reason = dispatchReasonTimeout
+		}
+		dispatchedBatches.With(prometheus.Labels{"reason": reason}).Inc()
 	}
 
 	return nil
@@ -103,11 +138,15 @@ func (s *batchingState) ScheduleTx(tx []byte) error {
 	if err := s.incomingQueue.Add(tx); err != nil {
 		// Return success in case of duplicate calls to avoid the client
 		// mistaking this for an actual error.
-		if err == errCallAlreadyExists {
+		switch err {
+		case errCallAlreadyExists:
 			s.logger.Warn("ignoring duplicate call",
 				"batch", tx,
 			)
-		} else {
+		case txnscheduler.ErrQueueFull:
+			queueFullRejections.Inc()
+			return err
+		default:
 			return err
 		}
 	}
@@ -158,6 +197,10 @@ func (s *batchingState) IsInitialized() bool {
 
 // New creates a new batching algorithm.
 func New(maxBatchSize, maxBatchSizeBytes uint64) (api.Algorithm, error) {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(batchingCollectors...)
+	})
+
 	cfg := config{
 		maxQueueSize:      uint64(viper.GetInt(cfgMaxQueueSize)),
 		maxBatchSize:      maxBatchSize,
@@ -174,6 +217,9 @@ func New(maxBatchSize, maxBatchSizeBytes uint64) (api.Algorithm, error) {
 
 func init() {
 	Flags.Uint64(cfgMaxQueueSize, 10000, "Maximum size of the batching queue")
+	Flags.Uint64(cfgWeightedMaxBatchWeight, 1000, "Maximum total weight of a batch (weighted algorithm)")
+	Flags.Uint64(cfgWeightedDefaultWeight, 1, "Weight of transactions whose gas cannot be estimated (weighted algorithm)")
+	Flags.StringSlice(cfgWeightedMethodWeights, []string{}, "Per-method transaction weight overrides in method=weight format (weighted algorithm)")
 
 	_ = viper.BindPFlags(Flags)
 }
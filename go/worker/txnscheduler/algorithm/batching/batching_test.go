@@ -3,12 +3,27 @@ package batching
 import (
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
+	"github.com/oasislabs/oasis-core/go/runtime/transaction"
+	"github.com/oasislabs/oasis-core/go/worker/common/committee"
 	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/algorithm/tests"
+	txnscheduler "github.com/oasislabs/oasis-core/go/worker/txnscheduler/api"
 )
 
+type countingDispatcher struct {
+	batches int
+}
+
+func (d *countingDispatcher) Dispatch(committeeID hash.Hash, batch transaction.RawBatch) error {
+	d.batches++
+	return nil
+}
+
 func TestBatchingAlgorithm(t *testing.T) {
 	viper.Set(cfgMaxQueueSize, 100)
 
@@ -17,3 +32,62 @@ func TestBatchingAlgorithm(t *testing.T) {
 
 	tests.AlgorithmImplementationTests(t, algo)
 }
+
+func TestQueueFull(t *testing.T) {
+	require := require.New(t)
+
+	viper.Set(cfgMaxQueueSize, 10)
+
+	algo, err := New(100, 16*1024*1024)
+	require.NoError(err, "New()")
+	err = algo.Initialize(&countingDispatcher{})
+	require.NoError(err, "Initialize")
+
+	rejections := testutil.ToFloat64(queueFullRejections)
+
+	// Without an epoch transition, nothing gets dispatched so the queue fills up.
+	for i := 0; i < 10; i++ {
+		err = algo.ScheduleTx([]byte{byte(i)})
+		require.NoError(err, "ScheduleTx")
+	}
+	require.Equal(10, algo.UnscheduledSize(), "queue should be full")
+
+	err = algo.ScheduleTx([]byte("one too many"))
+	require.Equal(txnscheduler.ErrQueueFull, err, "ScheduleTx should fail with a full queue")
+	require.Equal(10, algo.UnscheduledSize(), "rejected transaction should not be queued")
+	require.Equal(rejections+1, testutil.ToFloat64(queueFullRejections), "rejection should be counted")
+}
+
+func TestDispatchReasons(t *testing.T) {
+	require := require.New(t)
+
+	viper.Set(cfgMaxQueueSize, 100)
+
+	algo, err := New(2, 16*1024*1024)
+	require.NoError(err, "New()")
+	var td countingDispatcher
+	err = algo.Initialize(&td)
+	require.NoError(err, "Initialize")
+	err = algo.EpochTransition(committee.NewMockEpochSnapshot())
+	require.NoError(err, "EpochTransition")
+
+	bySize := dispatchedBatches.With(prometheus.Labels{"reason": dispatchReasonSize})
+	byTimeout := dispatchedBatches.With(prometheus.Labels{"reason": dispatchReasonTimeout})
+	sizeCount, timeoutCount := testutil.ToFloat64(bySize), testutil.ToFloat64(byTimeout)
+
+	// Filling a batch should dispatch it due to size.
+	err = algo.ScheduleTx([]byte("one"))
+	require.NoError(err, "ScheduleTx")
+	err = algo.ScheduleTx([]byte("two"))
+	require.NoError(err, "ScheduleTx")
+	require.Equal(1, td.batches, "full batch should be dispatched")
+	require.Equal(sizeCount+1, testutil.ToFloat64(bySize), "size dispatch should be counted")
+
+	// Flushing a partial batch should dispatch it due to timeout.
+	err = algo.ScheduleTx([]byte("three"))
+	require.NoError(err, "ScheduleTx")
+	err = algo.Flush()
+	require.NoError(err, "Flush")
+	require.Equal(2, td.batches, "flushed batch should be dispatched")
+	require.Equal(timeoutCount+1, testutil.ToFloat64(byTimeout), "timeout dispatch should be counted")
+}
@@ -6,25 +6,43 @@ import (
 
 	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/runtime/transaction"
+	txnscheduler "github.com/oasislabs/oasis-core/go/worker/txnscheduler/api"
 )
 
 var (
-	errQueueFull         = errors.New("queue is full")
 	errCallTooLarge      = errors.New("call too large")
+	errCallTooHeavy      = errors.New("call too heavy")
 	errCallAlreadyExists = errors.New("call already exists in queue")
 	errNoBatchAvailable  = errors.New("no batch available in incoming queue")
 )
 
+// weightFunc computes the weight of a call.
+type weightFunc func(call []byte) uint64
+
 type incomingQueue struct {
 	sync.Mutex
 
-	queue          transaction.RawBatch
-	queueSizeBytes uint64
-	callHashes     map[hash.Hash]bool
+	queue           transaction.RawBatch
+	queueSizeBytes  uint64
+	queueSizeWeight uint64
+	// callHashes maps hashes of queued calls to their weights.
+	callHashes map[hash.Hash]uint64
 
 	maxQueueSize      uint64
 	maxBatchSize      uint64
 	maxBatchSizeBytes uint64
+
+	// If weightFn is non-nil, batches are additionally limited to a total
+	// weight of at most maxBatchWeight.
+	weightFn       weightFunc
+	maxBatchWeight uint64
+}
+
+func (q *incomingQueue) callWeight(call []byte) uint64 {
+	if q.weightFn == nil {
+		return 0
+	}
+	return q.weightFn(call)
 }
 
 // Size returns the size of the incoming queue.
@@ -42,7 +60,8 @@ func (q *incomingQueue) Clear() {
 
 	q.queue = make(transaction.RawBatch, 0)
 	q.queueSizeBytes = 0
-	q.callHashes = make(map[hash.Hash]bool)
+	q.queueSizeWeight = 0
+	q.callHashes = make(map[hash.Hash]uint64)
 }
 
 // NOTE: Assumes lock is held.
@@ -60,12 +79,15 @@ func (q *incomingQueue) IsQueued(callHash hash.Hash) bool {
 }
 
 // NOTE: Assumes lock is held.
-func (q *incomingQueue) checkCallLocked(call []byte, callHash hash.Hash) error {
+func (q *incomingQueue) checkCallLocked(call []byte, callHash hash.Hash, callWeight uint64) error {
 	callSize := uint64(len(call))
 
 	if callSize > q.maxBatchSizeBytes {
 		return errCallTooLarge
 	}
+	if q.weightFn != nil && callWeight > q.maxBatchWeight {
+		return errCallTooHeavy
+	}
 	if q.isQueuedLocked(callHash) {
 		return errCallAlreadyExists
 	}
@@ -74,7 +96,7 @@ func (q *incomingQueue) checkCallLocked(call []byte, callHash hash.Hash) error {
 }
 
 // NOTE: Assumes lock is held and that checkCallLocked has been called.
-func (q *incomingQueue) addCallLocked(call []byte, callHash hash.Hash) {
+func (q *incomingQueue) addCallLocked(call []byte, callHash hash.Hash, callWeight uint64) {
 	// Assuming checkCallLocked has been called before, this can happen if
 	// duplicate calls are in the same batch -- just ignore them.
 	if _, exists := q.callHashes[callHash]; exists {
@@ -82,40 +104,46 @@ func (q *incomingQueue) addCallLocked(call []byte, callHash hash.Hash) {
 	}
 
 	q.queue = append(q.queue, call)
-	q.callHashes[callHash] = true
+	q.callHashes[callHash] = callWeight
 	q.queueSizeBytes += uint64(len(call))
+	q.queueSizeWeight += callWeight
 }
 
 // Add adds a call to the incoming queue.
 func (q *incomingQueue) Add(call []byte) error {
 	var callHash hash.Hash
 	callHash.FromBytes(call)
+	callWeight := q.callWeight(call)
 
 	q.Lock()
 	defer q.Unlock()
 
 	// Check if there is room in the queue.
 	if uint64(len(q.queue)) >= q.maxQueueSize {
-		return errQueueFull
+		return txnscheduler.ErrQueueFull
 	}
 
-	if err := q.checkCallLocked(call, callHash); err != nil {
+	if err := q.checkCallLocked(call, callHash, callWeight); err != nil {
 		return err
 	}
 
-	q.addCallLocked(call, callHash)
+	q.addCallLocked(call, callHash, callWeight)
 
 	return nil
 }
 
 // AddBatch adds a batch of calls to the queue.
 func (q *incomingQueue) AddBatch(batch transaction.RawBatch) error {
-	// Compute all hashes before taking the lock.
-	var callHashes []hash.Hash
+	// Compute all hashes and weights before taking the lock.
+	var (
+		callHashes  []hash.Hash
+		callWeights []uint64
+	)
 	for _, call := range batch {
 		var callHash hash.Hash
 		callHash.FromBytes(call)
 		callHashes = append(callHashes, callHash)
+		callWeights = append(callWeights, q.callWeight(call))
 	}
 
 	q.Lock()
@@ -123,19 +151,19 @@ func (q *incomingQueue) AddBatch(batch transaction.RawBatch) error {
 
 	// Check if there is room in the queue.
 	if uint64(len(q.queue)+len(batch)) >= q.maxQueueSize {
-		return errQueueFull
+		return txnscheduler.ErrQueueFull
 	}
 
 	// First check all calls.
 	for i, call := range batch {
-		if err := q.checkCallLocked(call, callHashes[i]); err != nil {
+		if err := q.checkCallLocked(call, callHashes[i], callWeights[i]); err != nil {
 			return err
 		}
 	}
 
 	// Then add all calls if checks passed.
 	for i, call := range batch {
-		q.addCallLocked(call, callHashes[i])
+		q.addCallLocked(call, callHashes[i], callWeights[i])
 	}
 
 	return nil
@@ -151,7 +179,8 @@ func (q *incomingQueue) Take(force bool) (transaction.RawBatch, error) {
 	if queueSize == 0 {
 		return nil, errNoBatchAvailable
 	}
-	if queueSize < q.maxBatchSize && q.queueSizeBytes < q.maxBatchSizeBytes && !force {
+	weightReached := q.weightFn != nil && q.queueSizeWeight >= q.maxBatchWeight
+	if queueSize < q.maxBatchSize && q.queueSizeBytes < q.maxBatchSizeBytes && !weightReached && !force {
 		return nil, errNoBatchAvailable
 	}
 
@@ -159,14 +188,19 @@ func (q *incomingQueue) Take(force bool) (transaction.RawBatch, error) {
 	//       to the queue, not what will be returned from the function.
 	var returned transaction.RawBatch
 	var returnedSizeBytes uint64
-	returnedCallHashes := make(map[hash.Hash]bool)
+	var returnedSizeWeight uint64
+	returnedCallHashes := make(map[hash.Hash]uint64)
 
 	var batch transaction.RawBatch
 	var batchSizeBytes uint64
+	var batchSizeWeight uint64
 
 	for _, call := range q.queue[:] {
 		shouldReturn := false
 		callSize := uint64(len(call))
+		var callHash hash.Hash
+		callHash.FromBytes(call)
+		callWeight := q.callHashes[callHash]
 
 		// Check if the batch already has enough calls.
 		if uint64(len(batch)) >= q.maxBatchSize {
@@ -176,24 +210,28 @@ func (q *incomingQueue) Take(force bool) (transaction.RawBatch, error) {
 		if batchSizeBytes+callSize > q.maxBatchSizeBytes {
 			shouldReturn = true
 		}
+		// Check if the call does not fit into the batch weight limit.
+		if q.weightFn != nil && batchSizeWeight+callWeight > q.maxBatchWeight {
+			shouldReturn = true
+		}
 
 		if shouldReturn {
 			returned = append(returned, call)
 			returnedSizeBytes += callSize
-
-			var callHash hash.Hash
-			callHash.FromBytes(call)
-			returnedCallHashes[callHash] = true
+			returnedSizeWeight += callWeight
+			returnedCallHashes[callHash] = callWeight
 			continue
 		}
 
 		// Take call.
 		batch = append(batch, call)
 		batchSizeBytes += callSize
+		batchSizeWeight += callWeight
 	}
 
 	q.queue = returned
 	q.queueSizeBytes = returnedSizeBytes
+	q.queueSizeWeight = returnedSizeWeight
 	q.callHashes = returnedCallHashes
 
 	return batch, nil
@@ -201,9 +239,19 @@ func (q *incomingQueue) Take(force bool) (transaction.RawBatch, error) {
 
 func newIncomingQueue(maxQueueSize, maxBatchSize, maxBatchSizeBytes uint64) *incomingQueue {
 	return &incomingQueue{
-		callHashes:        make(map[hash.Hash]bool),
+		callHashes:        make(map[hash.Hash]uint64),
 		maxQueueSize:      maxQueueSize,
 		maxBatchSize:      maxBatchSize,
 		maxBatchSizeBytes: maxBatchSizeBytes,
 	}
 }
+
+func newWeightedIncomingQueue(
+	maxQueueSize, maxBatchSize, maxBatchSizeBytes, maxBatchWeight uint64,
+	weightFn weightFunc,
+) *incomingQueue {
+	q := newIncomingQueue(maxQueueSize, maxBatchSize, maxBatchSizeBytes)
+	q.weightFn = weightFn
+	q.maxBatchWeight = maxBatchWeight
+	return q
+}
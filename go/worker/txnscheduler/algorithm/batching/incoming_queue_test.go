@@ -95,3 +95,30 @@ func TestAddBatch(t *testing.T) {
 	}
 	require.True(t, queue.Size() <= 51, "queue must not overflow")
 }
+
+func TestWeightedBatch(t *testing.T) {
+	// Weight of a call is its length.
+	weightFn := func(call []byte) uint64 { return uint64(len(call)) }
+	queue := newWeightedIncomingQueue(51, 10, 100, 10, weightFn)
+
+	err := queue.Add([]byte("way too heavy"))
+	require.Error(t, err, "Add error on calls heavier than the batch weight")
+
+	err = queue.Add([]byte("aaaa"))
+	require.NoError(t, err, "Add")
+
+	_, err = queue.Take(false)
+	require.Error(t, err, "Take error when batch weight not reached and not forced")
+
+	err = queue.Add([]byte("bbbb"))
+	require.NoError(t, err, "Add")
+	err = queue.Add([]byte("cccc"))
+	require.NoError(t, err, "Add")
+
+	batch, err := queue.Take(false)
+	require.NoError(t, err, "Take")
+	require.EqualValues(t, 2, len(batch), "Batch size")
+	require.EqualValues(t, 1, queue.Size(), "Size")
+	require.EqualValues(t, batch[0], []byte("aaaa"))
+	require.EqualValues(t, batch[1], []byte("bbbb"))
+}
@@ -0,0 +1,124 @@
+package batching
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	"github.com/oasislabs/oasis-core/go/common/logging"
+	consensusTx "github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	registry "github.com/oasislabs/oasis-core/go/registry/api"
+	"github.com/oasislabs/oasis-core/go/runtime/transaction"
+	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/algorithm/api"
+)
+
+const (
+	// WeightedName is the name of the weighted batching scheduling algorithm.
+	WeightedName = registry.TxnSchedulerAlgorithmWeighted
+
+	cfgWeightedMaxBatchWeight = "worker.txnscheduler.weighted.max_batch_weight"
+	cfgWeightedDefaultWeight  = "worker.txnscheduler.weighted.default_weight"
+	cfgWeightedMethodWeights  = "worker.txnscheduler.weighted.method_weights"
+)
+
+// txWeights computes transaction weights based on their estimated gas.
+type txWeights struct {
+	estimator     api.GasEstimator
+	defaultWeight uint64
+	overrides     map[string]uint64
+}
+
+func (w *txWeights) weight(tx []byte) uint64 {
+	var call transaction.TxnCall
+	if err := cbor.Unmarshal(tx, &call); err != nil {
+		return w.defaultWeight
+	}
+	if weight, ok := w.overrides[call.Method]; ok {
+		return weight
+	}
+	if w.estimator == nil {
+		return w.defaultWeight
+	}
+
+	// Runtime calls are not signed by a consensus account, so the gas is
+	// estimated on behalf of an empty caller.
+	gas, err := w.estimator.EstimateGas(context.Background(), signature.PublicKey{}, &consensusTx.Transaction{
+		Method: consensusTx.MethodName(call.Method),
+		Body:   cbor.Marshal(call.Args),
+	})
+	if err != nil || gas == 0 {
+		return w.defaultWeight
+	}
+	return uint64(gas)
+}
+
+func newTxWeights(estimator api.GasEstimator, defaultWeight uint64, rawOverrides []string) (*txWeights, error) {
+	w := &txWeights{
+		estimator:     estimator,
+		defaultWeight: defaultWeight,
+		overrides:     make(map[string]uint64),
+	}
+	for _, entry := range rawOverrides {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("batching: malformed method weight: '%s'", entry)
+		}
+		weight, err := strconv.ParseUint(kv[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("batching: malformed method weight: '%s': %w", entry, err)
+		}
+		w.overrides[kv[0]] = weight
+	}
+	return w, nil
+}
+
+// NewWeighted creates a new weighted batching algorithm.
+//
+// In addition to the limits imposed by the batching algorithm, the weighted
+// algorithm accumulates transactions until the total batch weight reaches a
+// configured limit. The weight of each transaction is its gas as estimated by
+// the given gas estimator, unless overridden for the called method via the
+// method weights flag.
+func NewWeighted(maxBatchSize, maxBatchSizeBytes uint64, gasEstimator api.GasEstimator) (api.Algorithm, error) {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(batchingCollectors...)
+	})
+
+	maxBatchWeight := viper.GetUint64(cfgWeightedMaxBatchWeight)
+	if maxBatchWeight < 1 {
+		return nil, fmt.Errorf("batching: max batch weight must be >= 1")
+	}
+	weights, err := newTxWeights(
+		gasEstimator,
+		viper.GetUint64(cfgWeightedDefaultWeight),
+		viper.GetStringSlice(cfgWeightedMethodWeights),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := config{
+		maxQueueSize:      uint64(viper.GetInt(cfgMaxQueueSize)),
+		maxBatchSize:      maxBatchSize,
+		maxBatchSizeBytes: maxBatchSizeBytes,
+	}
+	batching := batchingState{
+		cfg: cfg,
+		incomingQueue: newWeightedIncomingQueue(
+			cfg.maxQueueSize,
+			cfg.maxBatchSize,
+			cfg.maxBatchSizeBytes,
+			maxBatchWeight,
+			weights.weight,
+		),
+		logger: logging.GetLogger("txn_scheduler/algo/weighted"),
+	}
+
+	return &batching, nil
+}
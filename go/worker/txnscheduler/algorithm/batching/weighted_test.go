@@ -0,0 +1,50 @@
+package batching
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/common/cbor"
+	"github.com/oasislabs/oasis-core/go/common/crypto/signature"
+	consensusTx "github.com/oasislabs/oasis-core/go/consensus/api/transaction"
+	"github.com/oasislabs/oasis-core/go/runtime/transaction"
+)
+
+type testGasEstimator map[consensusTx.MethodName]consensusTx.Gas
+
+func (e testGasEstimator) EstimateGas(ctx context.Context, caller signature.PublicKey, tx *consensusTx.Transaction) (consensusTx.Gas, error) {
+	gas, ok := e[tx.Method]
+	if !ok {
+		return 0, errors.New("unknown method")
+	}
+	return gas, nil
+}
+
+func TestTxWeights(t *testing.T) {
+	estimator := testGasEstimator{
+		"transfer": 5,
+		"deploy":   50,
+		"noop":     0,
+	}
+
+	_, err := newTxWeights(estimator, 1, []string{"transfer"})
+	require.Error(t, err, "malformed entry should fail")
+	_, err = newTxWeights(estimator, 1, []string{"transfer=heavy"})
+	require.Error(t, err, "non-numeric weight should fail")
+
+	w, err := newTxWeights(estimator, 1, []string{"deploy=20"})
+	require.NoError(t, err, "newTxWeights")
+
+	require.EqualValues(t, 5, w.weight(cbor.Marshal(&transaction.TxnCall{Method: "transfer"})), "weight should be the estimated gas")
+	require.EqualValues(t, 20, w.weight(cbor.Marshal(&transaction.TxnCall{Method: "deploy"})), "configured weights should override the estimate")
+	require.EqualValues(t, 1, w.weight(cbor.Marshal(&transaction.TxnCall{Method: "noop"})), "zero estimates use the default weight")
+	require.EqualValues(t, 1, w.weight(cbor.Marshal(&transaction.TxnCall{Method: "other"})), "failed estimates use the default weight")
+	require.EqualValues(t, 1, w.weight([]byte("not a call")), "undecodable calls use the default weight")
+
+	w, err = newTxWeights(nil, 1, nil)
+	require.NoError(t, err, "newTxWeights")
+	require.EqualValues(t, 1, w.weight(cbor.Marshal(&transaction.TxnCall{Method: "transfer"})), "calls use the default weight without an estimator")
+}
@@ -4,24 +4,48 @@ import (
 	"fmt"
 
 	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
 
 	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/algorithm/api"
 	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/algorithm/batching"
 )
 
+// CfgLeaderAlgorithm configures the transaction scheduling algorithm used by
+// the transaction scheduler leader, overriding the runtime descriptor.
+const CfgLeaderAlgorithm = "worker.txnscheduler.leader.algo"
+
 // Flags has the configuration flags.
 var Flags = flag.NewFlagSet("", flag.ContinueOnError)
 
+// LeaderAlgorithm returns the name of the configured leader transaction
+// scheduling algorithm or the given runtime descriptor algorithm if none is
+// configured.
+func LeaderAlgorithm(runtimeAlgorithm string) string {
+	if name := viper.GetString(CfgLeaderAlgorithm); name != "" {
+		return name
+	}
+	return runtimeAlgorithm
+}
+
 // New creates a new algorithm.
-func New(name string, maxBatchSize, maxBatchSizeBytes uint64) (api.Algorithm, error) {
+//
+// The gas estimator is used by algorithms that schedule transactions based on
+// their estimated cost.
+func New(name string, maxBatchSize, maxBatchSizeBytes uint64, gasEstimator api.GasEstimator) (api.Algorithm, error) {
 	switch name {
 	case batching.Name:
 		return batching.New(maxBatchSize, maxBatchSizeBytes)
+	case batching.WeightedName:
+		return batching.NewWeighted(maxBatchSize, maxBatchSizeBytes, gasEstimator)
 	default:
 		return nil, fmt.Errorf("invalid transaction scheduler algorithm: %s", name)
 	}
 }
 
 func init() {
+	Flags.String(CfgLeaderAlgorithm, "", "Transaction scheduling algorithm (default: runtime descriptor algorithm)")
+
+	_ = viper.BindPFlags(Flags)
+
 	Flags.AddFlagSet(batching.Flags)
 }
@@ -0,0 +1,25 @@
+package algorithm
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/algorithm/batching"
+)
+
+func TestLeaderAlgorithm(t *testing.T) {
+	require := require.New(t)
+
+	viper.Set(CfgLeaderAlgorithm, "")
+	require.Equal(batching.Name, LeaderAlgorithm(batching.Name), "runtime descriptor algorithm should be used by default")
+
+	viper.Set(CfgLeaderAlgorithm, batching.WeightedName)
+	defer viper.Set(CfgLeaderAlgorithm, "")
+	require.Equal(batching.WeightedName, LeaderAlgorithm(batching.Name), "configured algorithm should override the runtime descriptor")
+
+	algo, err := New(LeaderAlgorithm(batching.Name), 10, 1024, nil)
+	require.NoError(err, "New")
+	require.NotNil(algo, "weighted algorithm should be created")
+}
@@ -26,6 +26,11 @@ var (
 
 	// ErrCheckTxFailed is the error returned when CheckTx fails.
 	ErrCheckTxFailed = errors.New(ModuleName, 4, "txnscheduler: CheckTx failed")
+
+	// ErrQueueFull is the error returned when the transaction scheduler queue
+	// is full and the transaction cannot be accepted. Clients should back off
+	// and retry later.
+	ErrQueueFull = errors.New(ModuleName, 5, "txnscheduler: queue is full")
 )
 
 // TransactionScheduler is the transaction scheduler API interface.
@@ -37,6 +42,11 @@ type TransactionScheduler interface {
 	// transaction scheduler queue and is waiting to be dispatched to an
 	// executor committee.
 	IsTransactionQueued(context.Context, *IsTransactionQueuedRequest) (*IsTransactionQueuedResponse, error)
+
+	// AreTransactionsQueued checks which of the given transactions are
+	// present in the transaction scheduler queue and are waiting to be
+	// dispatched to an executor committee.
+	AreTransactionsQueued(context.Context, *AreTransactionsQueuedRequest) (*AreTransactionsQueuedResponse, error)
 }
 
 // SubmitTxRequest is a SubmitTx request.
@@ -47,6 +57,9 @@ type SubmitTxRequest struct {
 
 // SubmitTxResponse is a SubmitTx response.
 type SubmitTxResponse struct {
+	// TxHash is the hash of the queued transaction. It can be used to track
+	// the transaction via IsTransactionQueued and in the resulting batch.
+	TxHash hash.Hash `json:"tx_hash,omitempty"`
 }
 
 // IsTransactionQueuedRequest is an IsTransactionQueued request.
@@ -59,3 +72,17 @@ type IsTransactionQueuedRequest struct {
 type IsTransactionQueuedResponse struct {
 	IsQueued bool `json:"is_queued"`
 }
+
+// AreTransactionsQueuedRequest is an AreTransactionsQueued request.
+type AreTransactionsQueuedRequest struct {
+	RuntimeID common.Namespace `json:"runtime_id"`
+	TxHashes  []hash.Hash      `json:"tx_hashes"`
+}
+
+// AreTransactionsQueuedResponse is an AreTransactionsQueued response.
+//
+// The i-th element of IsQueued corresponds to the i-th requested transaction
+// hash.
+type AreTransactionsQueuedResponse struct {
+	IsQueued []bool `json:"is_queued"`
+}
@@ -16,6 +16,8 @@ var (
 	methodSubmitTx = serviceName.NewMethodName("SubmitTx")
 	// methodIsTransactionQueued is the name of the IsTransactionQueued method.
 	methodIsTransactionQueued = serviceName.NewMethodName("IsTransactionQueued")
+	// methodAreTransactionsQueued is the name of the AreTransactionsQueued method.
+	methodAreTransactionsQueued = serviceName.NewMethodName("AreTransactionsQueued")
 
 	// serviceDesc is the gRPC service descriptor.
 	serviceDesc = grpc.ServiceDesc{
@@ -30,6 +32,10 @@ var (
 				MethodName: methodIsTransactionQueued.Short(),
 				Handler:    handlerIsTransactionQueued,
 			},
+			{
+				MethodName: methodAreTransactionsQueued.Short(),
+				Handler:    handlerAreTransactionsQueued,
+			},
 		},
 		Streams: []grpc.StreamDesc{},
 	}
@@ -81,6 +87,29 @@ func handlerIsTransactionQueued( // nolint: golint
 	return interceptor(ctx, rq, info, handler)
 }
 
+func handlerAreTransactionsQueued( // nolint: golint
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	rq := new(AreTransactionsQueuedRequest)
+	if err := dec(rq); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionScheduler).AreTransactionsQueued(ctx, rq)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: methodAreTransactionsQueued.Full(),
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionScheduler).AreTransactionsQueued(ctx, req.(*AreTransactionsQueuedRequest))
+	}
+	return interceptor(ctx, rq, info, handler)
+}
+
 // RegisterService registers a new transaction scheduler service with the
 // given gRPC server.
 func RegisterService(server *grpc.Server, service TransactionScheduler) {
@@ -107,6 +136,14 @@ func (c *transactionSchedulerClient) IsTransactionQueued(ctx context.Context, re
 	return rsp, nil
 }
 
+func (c *transactionSchedulerClient) AreTransactionsQueued(ctx context.Context, req *AreTransactionsQueuedRequest) (*AreTransactionsQueuedResponse, error) {
+	rsp := new(AreTransactionsQueuedResponse)
+	if err := c.conn.Invoke(ctx, methodAreTransactionsQueued.Full(), req, rsp); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
 // NewTransactionSchedulerClient creates a new gRPC transaction scheduler
 // client service.
 func NewTransactionSchedulerClient(c *grpc.ClientConn) TransactionScheduler {
@@ -252,6 +252,30 @@ func (n *Node) IsTransactionQueued(ctx context.Context, id hash.Hash) (bool, err
 	return n.algorithm.IsQueued(id), nil
 }
 
+// AreTransactionsQueued checks which of the given transactions are present in
+// the transaction scheduler queue and are waiting to be dispatched to a
+// executor committee.
+func (n *Node) AreTransactionsQueued(ctx context.Context, ids []hash.Hash) ([]bool, error) {
+	// Check if we are a leader. Note that we may be in the middle of a
+	// transition, but this shouldn't matter as the client will retry.
+	if !n.commonNode.Group.GetEpochSnapshot().IsTransactionSchedulerLeader() {
+		return nil, api.ErrNotLeader
+	}
+
+	n.algorithmMutex.RLock()
+	defer n.algorithmMutex.RUnlock()
+
+	if n.algorithm == nil || !n.algorithm.IsInitialized() {
+		return nil, api.ErrNotReady
+	}
+
+	isQueued := make([]bool, len(ids))
+	for i, id := range ids {
+		isQueued[i] = n.algorithm.IsQueued(id)
+	}
+	return isQueued, nil
+}
+
 // Guarded by n.commonNode.CrossNode.
 func (n *Node) transitionLocked(state NodeState) {
 	n.logger.Info("state transition",
@@ -505,10 +529,15 @@ func (n *Node) worker() {
 		)
 		return
 	}
+	algorithmName := txnSchedulerAlgorithm.LeaderAlgorithm(runtime.TxnScheduler.Algorithm)
+	n.logger.Info("initializing transaction scheduler algorithm",
+		"algorithm", algorithmName,
+	)
 	txnAlgorithm, err := txnSchedulerAlgorithm.New(
-		runtime.TxnScheduler.Algorithm,
+		algorithmName,
 		runtime.TxnScheduler.MaxBatchSize,
 		runtime.TxnScheduler.MaxBatchSizeBytes,
+		n.commonNode.Consensus,
 	)
 	if err != nil {
 		n.logger.Error("failed to create new transaction scheduler algorithm",
@@ -2,12 +2,33 @@ package txnscheduler
 
 import (
 	"context"
+	"errors"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasislabs/oasis-core/go/common/crypto/hash"
 	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/api"
 )
 
 var _ api.TransactionScheduler = (*Worker)(nil)
 
+// queueFullError wraps api.ErrQueueFull so that it is reported to gRPC clients
+// with the ResourceExhausted status code.
+type queueFullError struct{}
+
+func (queueFullError) Error() string {
+	return api.ErrQueueFull.Error()
+}
+
+func (queueFullError) Unwrap() error {
+	return api.ErrQueueFull
+}
+
+func (queueFullError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, api.ErrQueueFull.Error())
+}
+
 // SubmitTx submits a new transaction to the transaction scheduler.
 func (w *Worker) SubmitTx(ctx context.Context, rq *api.SubmitTxRequest) (*api.SubmitTxResponse, error) {
 	runtime, ok := w.runtimes[rq.RuntimeID]
@@ -15,10 +36,21 @@ func (w *Worker) SubmitTx(ctx context.Context, rq *api.SubmitTxRequest) (*api.Su
 		return nil, api.ErrUnknownRuntime
 	}
 
-	if err := runtime.QueueCall(ctx, rq.Data); err != nil {
+	err := runtime.QueueCall(ctx, rq.Data)
+	switch {
+	case err == nil:
+	case errors.Is(err, api.ErrQueueFull):
+		return nil, queueFullError{}
+	default:
 		return nil, err
 	}
-	return &api.SubmitTxResponse{}, nil
+
+	var txHash hash.Hash
+	txHash.FromBytes(rq.Data)
+
+	return &api.SubmitTxResponse{
+		TxHash: txHash,
+	}, nil
 }
 
 // IsTransactionQueued checks if the given transaction is present in the
@@ -39,3 +71,22 @@ func (w *Worker) IsTransactionQueued(ctx context.Context, rq *api.IsTransactionQ
 		IsQueued: isQueued,
 	}, nil
 }
+
+// AreTransactionsQueued checks which of the given transactions are present in
+// the transaction scheduler queue and are waiting to be dispatched to a
+// executor committee.
+func (w *Worker) AreTransactionsQueued(ctx context.Context, rq *api.AreTransactionsQueuedRequest) (*api.AreTransactionsQueuedResponse, error) {
+	runtime, ok := w.runtimes[rq.RuntimeID]
+	if !ok {
+		return nil, api.ErrUnknownRuntime
+	}
+
+	isQueued, err := runtime.AreTransactionsQueued(ctx, rq.TxHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AreTransactionsQueuedResponse{
+		IsQueued: isQueued,
+	}, nil
+}
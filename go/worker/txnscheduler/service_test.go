@@ -0,0 +1,20 @@
+package txnscheduler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/oasislabs/oasis-core/go/worker/txnscheduler/api"
+)
+
+func TestQueueFullError(t *testing.T) {
+	require := require.New(t)
+
+	var err error = queueFullError{}
+	require.True(errors.Is(err, api.ErrQueueFull), "error should wrap ErrQueueFull")
+	require.Equal(codes.ResourceExhausted, status.Code(err), "error should map to ResourceExhausted")
+}
@@ -133,6 +133,13 @@ blockLoop:
 			var stateRoot hash.Hash
 			stateRoot.Empty()
 			require.EqualValues(t, stateRoot, blk.Header.StateRoot)
+
+			// The dispatched call should no longer be queued.
+			var callHash hash.Hash
+			callHash.FromBytes(testCall)
+			isQueued, err := rtNode.AreTransactionsQueued(ctx, []hash.Hash{callHash})
+			require.NoError(t, err, "AreTransactionsQueued")
+			require.Equal(t, []bool{false}, isQueued, "dispatched call should not be queued")
 			break blockLoop
 		case <-time.After(recvTimeout):
 			t.Fatalf("failed to receive block")